@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	addTemplate      string
+	addListTemplates bool
+	addCommand       string
+	addImage         string
+	addEnv           []string
+	addProfile       string
+	addDescription   string
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add an MCP server to the compose file",
+	Long: `Add an MCP server to the mcp-compose.yml file, creating the file if needed.
+With the --template flag, the server is generated from the built-in catalog of
+common servers, including its command, environment placeholders, and description.
+The name defaults to the template name.
+Otherwise, specify the server with --command or --image.
+With the --list-templates flag, it lists the built-in templates.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if addListTemplates {
+			displayTemplates(os.Stdout)
+			return nil
+		}
+
+		var name string
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		service, name, err := buildAddService(name)
+		if err != nil {
+			return err
+		}
+
+		return addServer(os.Stdout, composeFile, name, service)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	addCmd.Flags().StringVar(&addTemplate, "template", "", "Generate the server from a built-in template")
+	addCmd.Flags().BoolVar(&addListTemplates, "list-templates", false, "List the built-in server templates")
+	addCmd.Flags().StringVar(&addCommand, "command", "", "Command that starts the server, or its URL for remote servers")
+	addCmd.Flags().StringVar(&addImage, "image", "", "Container image that runs the server")
+	addCmd.Flags().StringArrayVarP(&addEnv, "env", "e", nil, "Environment variable as KEY=VALUE (repeatable)")
+	addCmd.Flags().StringVar(&addProfile, "profile", "", "Comma-separated profiles for the mcp.profile label")
+	addCmd.Flags().StringVar(&addDescription, "description", "", "Description for the mcp.description label")
+	addCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
+}
+
+// buildAddService assembles the service to add from the template and flags
+// Returns the service and its name, which defaults to the template name
+func buildAddService(name string) (Service, string, error) {
+	var service Service
+
+	if addTemplate != "" {
+		template, ok := getTemplate(addTemplate)
+		if !ok {
+			return Service{}, "", newValidationError("unknown template: %s (available: %s)", addTemplate, strings.Join(getTemplateNames(), ", "))
+		}
+		service = template
+		if name == "" {
+			name = addTemplate
+		}
+	}
+
+	if name == "" {
+		return Service{}, "", newValidationError("a server name is required unless --template is used")
+	}
+
+	// Flags override the template
+	if addCommand != "" {
+		service.Command = addCommand
+		service.Image = ""
+	}
+	if addImage != "" {
+		service.Image = addImage
+		service.Command = ""
+	}
+	if service.Command == "" && service.Image == "" {
+		return Service{}, "", newValidationError("one of --template, --command, or --image is required")
+	}
+
+	for _, env := range addEnv {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok || key == "" {
+			return Service{}, "", newValidationError("invalid --env value '%s' (expected KEY=VALUE)", env)
+		}
+		if service.Environment == nil {
+			service.Environment = make(map[string]string)
+		}
+		service.Environment[key] = value
+	}
+
+	if addProfile != "" {
+		setServiceLabel(&service, "mcp.profile", addProfile)
+	}
+	if addDescription != "" {
+		setServiceLabel(&service, "mcp.description", addDescription)
+	}
+
+	if err := ValidateProfileLabel(name, service); err != nil {
+		return Service{}, "", err
+	}
+
+	return service, name, nil
+}
+
+// setServiceLabel sets a label on a service, creating the labels map if needed
+func setServiceLabel(service *Service, key, value string) {
+	if service.Labels == nil {
+		service.Labels = make(map[string]string)
+	}
+	service.Labels[key] = value
+}
+
+// addServer appends a service to the compose file, creating the file if it doesn't exist
+func addServer(w io.Writer, composePath, name string, service Service) error {
+	doc, err := loadComposeDocument(composePath)
+	if os.IsNotExist(err) {
+		doc, err = parseComposeDocument(nil)
+	}
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+
+	if !addComposeService(doc, name, service) {
+		return newValidationError("server '%s' already exists in %s", name, composePath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(composePath), 0755); err != nil {
+		return newConfigError("create config directory", filepath.Dir(composePath), err)
+	}
+	if err := saveComposeDocument(composePath, doc); err != nil {
+		return newConfigError("write compose file", composePath, err)
+	}
+
+	fmt.Fprintf(w, "Added %s to %s\n", name, composePath)
+
+	// Point out the variables that need a value before deploying
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return nil
+	}
+	var missing []string
+	for _, ref := range unresolvedEnvVars(service, envVars) {
+		missing = append(missing, ref.name)
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(w, "Set %s in your environment or in %s before deploying\n",
+			strings.Join(missing, ", "), filepath.Join(filepath.Dir(composePath), ".env"))
+	}
+	return nil
+}
+
+// displayTemplates prints the built-in server templates
+func displayTemplates(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "TEMPLATE\tPROFILES\tDESCRIPTION")
+	fmt.Fprintln(tw, "--------\t--------\t-----------")
+	for _, name := range getTemplateNames() {
+		template := serverTemplates[name]
+		profiles := GetProfiles(template)
+		if len(profiles) == 0 {
+			profiles = []string{"default"}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, strings.Join(profiles, ", "), GetDescription(template))
+	}
+	tw.Flush()
+}
+
+// completeTemplateNames completes built-in template names with their descriptions
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, name := range getTemplateNames() {
+		completions = append(completions, serverCompletion(name, serverTemplates[name]))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
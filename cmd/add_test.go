@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// resetAddFlags restores the add command flags after a test
+func resetAddFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		addTemplate, addCommand, addImage, addProfile, addDescription = "", "", "", "", ""
+		addEnv = nil
+	})
+}
+
+func TestServerTemplates(t *testing.T) {
+	for _, name := range getTemplateNames() {
+		template, _ := getTemplate(name)
+		if template.Command == "" && template.Image == "" {
+			t.Errorf("Template '%s' has neither command nor image", name)
+		}
+		if GetDescription(template) == "" {
+			t.Errorf("Template '%s' has no description", name)
+		}
+		if err := ValidateProfileLabel(name, template); err != nil {
+			t.Errorf("Template '%s': %v", name, err)
+		}
+	}
+}
+
+func TestBuildAddService(t *testing.T) {
+	t.Run("template", func(t *testing.T) {
+		resetAddFlags(t)
+		addTemplate = "github"
+		addProfile = "work"
+
+		service, name, err := buildAddService("")
+		if err != nil {
+			t.Fatalf("buildAddService failed: %v", err)
+		}
+		if name != "github" {
+			t.Errorf("Expected name to default to template name, got %s", name)
+		}
+		if service.Environment["GITHUB_PERSONAL_ACCESS_TOKEN"] != "${GITHUB_PERSONAL_ACCESS_TOKEN}" {
+			t.Errorf("Expected env placeholder, got %v", service.Environment)
+		}
+		if service.Labels["mcp.profile"] != "work" {
+			t.Errorf("Expected profile override, got %v", service.Labels)
+		}
+		// The catalog itself must not be modified
+		if serverTemplates["github"].Labels["mcp.profile"] != "programming" {
+			t.Error("Template catalog was modified")
+		}
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		resetAddFlags(t)
+		addTemplate = "nope"
+		if _, _, err := buildAddService(""); err == nil {
+			t.Error("Expected error for unknown template")
+		}
+	})
+
+	t.Run("command without name", func(t *testing.T) {
+		resetAddFlags(t)
+		addCommand = "uvx my-server"
+		if _, _, err := buildAddService(""); err == nil {
+			t.Error("Expected error when no name is given")
+		}
+	})
+
+	t.Run("invalid env", func(t *testing.T) {
+		resetAddFlags(t)
+		addCommand = "uvx my-server"
+		addEnv = []string{"NOVALUE"}
+		if _, _, err := buildAddService("mine"); err == nil {
+			t.Error("Expected error for invalid --env value")
+		}
+	})
+}
+
+func TestAddServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	composePath := filepath.Join(t.TempDir(), "nested", "mcp-compose.yml")
+
+	template, _ := getTemplate("github")
+	var out bytes.Buffer
+	if err := addServer(&out, composePath, "github", template); err != nil {
+		t.Fatalf("addServer failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Set GITHUB_PERSONAL_ACCESS_TOKEN") {
+		t.Errorf("Expected hint about unset variable, got:\n%s", out.String())
+	}
+
+	template, _ = getTemplate("time")
+	if err := addServer(&out, composePath, "time", template); err != nil {
+		t.Fatalf("addServer failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(composePath)
+	expected := `services:
+  github:
+    command: npx -y @modelcontextprotocol/server-github
+    environment:
+      GITHUB_PERSONAL_ACCESS_TOKEN: ${GITHUB_PERSONAL_ACCESS_TOKEN}
+    labels:
+      mcp.description: GitHub repository, issue, and pull request management
+      mcp.profile: programming
+
+  time:
+    command: uvx mcp-server-time
+    labels:
+      mcp.description: Current time and timezone conversion
+`
+	if string(data) != expected {
+		t.Errorf("Unexpected compose file:\n%s", data)
+	}
+
+	if err := addServer(&out, composePath, "time", template); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected validation error for duplicate server, got %v", err)
+	}
+}
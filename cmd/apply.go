@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultManifestFile is the deploy manifest 'mcp apply' reads without an argument
+const defaultManifestFile = "deploy.yml"
+
+// deployManifest declares every tool config to deploy from a compose file
+type deployManifest struct {
+	// Compose is the compose file, relative to the manifest; defaults to --file
+	Compose string         `yaml:"compose"`
+	Targets []deployTarget `yaml:"targets"`
+}
+
+// deployTarget is one tool config to write: a tool, its config scope, and the
+// profile whose servers it gets
+type deployTarget struct {
+	Tool    string `yaml:"tool"`
+	Scope   string `yaml:"scope"`
+	Profile string `yaml:"profile"`
+}
+
+// UnmarshalYAML accepts the tool:scope:profile shorthand, e.g. kiro:user:work,
+// as well as a mapping; scope defaults to user and profile to default
+func (t *deployTarget) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		parts := strings.Split(node.Value, ":")
+		if len(parts) > 3 {
+			return fmt.Errorf("line %d: target '%s' should be tool[:scope[:profile]]", node.Line, node.Value)
+		}
+		parts = append(parts, "", "")
+		*t = deployTarget{Tool: parts[0], Scope: parts[1], Profile: parts[2]}
+		return nil
+	}
+
+	type plain deployTarget
+	return node.Decode((*plain)(t))
+}
+
+// String returns the target in its shorthand form
+func (t deployTarget) String() string {
+	return t.Tool + ":" + t.Scope + ":" + t.Profile
+}
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply [manifest]",
+	Short: "Deploy every tool config declared in a manifest",
+	Long: `Deploy the tool configs declared in a deploy manifest (deploy.yml by default)
+in one step. Each target names a tool, a config scope, and a profile:
+
+  compose: mcp-compose.yml   # optional, relative to the manifest
+  targets:
+    - kiro:user:work
+    - cursor:project:default
+    - tool: claude-desktop
+      profile: writing
+
+Scope defaults to user and profile to default. The manifest is passed as an
+argument; -f still selects the compose file when the manifest doesn't.
+Every target is validated and checked to be writable before any OAuth token is
+acquired or file is written, and if writing any config fails the configs
+already written are restored, so a manifest is applied completely or not at all.
+Prints a summary of added, updated, and removed servers for each target.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manifestPath := defaultManifestFile
+		if len(args) > 0 {
+			manifestPath = args[0]
+		}
+
+		manifest, err := loadDeployManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+
+		composePath := composeFile
+		if manifest.Compose != "" {
+			composePath = filepath.Join(filepath.Dir(manifestPath), manifest.Compose)
+		}
+
+		return applyManifest(cmd.Context(), os.Stdout, composePath, manifest)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+}
+
+// loadDeployManifest reads a deploy manifest and fills in target defaults
+func loadDeployManifest(path string) (deployManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return deployManifest{}, newConfigError("load deploy manifest", path, err)
+	}
+
+	var manifest deployManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return deployManifest{}, newConfigError("load deploy manifest", path, err)
+	}
+	if len(manifest.Targets) == 0 {
+		return deployManifest{}, newValidationError("%s has no targets", path)
+	}
+
+	for i := range manifest.Targets {
+		target := &manifest.Targets[i]
+		if target.Tool == "" {
+			return deployManifest{}, newValidationError("%s: target %d has no tool", path, i+1)
+		}
+		if target.Scope == "" {
+			target.Scope = scopeUser
+		}
+		if target.Scope != scopeUser && target.Scope != scopeProject {
+			return deployManifest{}, newValidationError("%s: target %s has unsupported scope '%s' (expected user or project)", path, target, target.Scope)
+		}
+		if target.Profile == "" {
+			target.Profile = "default"
+		}
+	}
+	return manifest, nil
+}
+
+// plannedTarget is a target resolved to the file it writes and its contents
+type plannedTarget struct {
+	deployTarget
+	Path     string
+	Config   MCPConfig
+	Existing MCPConfig
+}
+
+// applyManifest validates, converts, and writes every target of a manifest
+func applyManifest(ctx context.Context, w io.Writer, composePath string, manifest deployManifest) error {
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return newConfigError("load environment variables", composePath, err)
+	}
+
+	// Validate every target before acquiring tokens or writing anything
+	plans := make([]plannedTarget, 0, len(manifest.Targets))
+	paths := make(map[string]deployTarget)
+	for _, target := range manifest.Targets {
+		path, err := getScopedToolPath(target.Tool, target.Scope)
+		if err != nil {
+			return newValidationError("target %s: %v", target, err)
+		}
+		if path == "" {
+			return newValidationError("target %s: unknown tool shortcut: %s", target, target.Tool)
+		}
+		if format := getToolFormat(target.Tool); !isSupportedFormat(format) {
+			return newValidationError("target %s: tool uses unsupported format '%s'", target, format)
+		}
+		if other, exists := paths[path]; exists {
+			return newValidationError("targets %s and %s both write %s", other, target, path)
+		}
+		paths[path] = target
+
+		servers := serversForTool(enabledServers(filterServers(config, deployProfile(target), false)), target.Tool)
+		for name, service := range servers {
+			if IsRemoteServerWithEnvExpansion(service, envVars) {
+				if err := ValidateRemoteServerAuth(name, service); err != nil {
+					return newValidationError("target %s: %v", target, err)
+				}
+			}
+		}
+		if err := ValidateToolSupportWithEnvExpansion(target.Tool, servers, envVars); err != nil {
+			return newValidationError("target %s: %v", target, err)
+		}
+		if err := checkConfigWritable(path); err != nil {
+			return newConfigError("write MCP config", path, fmt.Errorf("target %s: %s is not writable (%w)", target, path, err))
+		}
+
+		existing, err := readMCPConfig(path)
+		if err != nil {
+			return newConfigError("load tool config", path, err)
+		}
+		plans = append(plans, plannedTarget{deployTarget: target, Path: path, Existing: existing})
+	}
+
+	// Convert each profile once so OAuth tokens are only acquired once
+	converted := make(map[string]MCPConfig)
+	for i := range plans {
+		profile := deployProfile(plans[i].deployTarget)
+		if _, done := converted[profile]; !done {
+			mcpConfig, err := convertToMCPConfig(ctx, enabledServers(filterServers(config, profile, false)), envVars)
+			if err != nil {
+				return err
+			}
+			converted[profile] = mcpConfig
+		}
+		// Each target keeps its own disabled servers off
+		prepared, err := prepareToolConfig(converted[profile], plans[i].Tool, plans[i].Path, config.Services, envVars, false)
+		if err != nil {
+			return err
+		}
+		plans[i].Config = prepared.Config
+	}
+
+	if err := writePlannedTargets(plans); err != nil {
+		return err
+	}
+
+	for _, plan := range plans {
+		changes := compareMCPConfigs(plan.Existing, plan.Config)
+		fmt.Fprintf(w, "Applied %s (%s): %d added, %d updated, %d removed\n",
+			plan.deployTarget, plan.Path, len(changes.Added), len(changes.Updated), len(changes.Removed))
+		printSyncChanges(w, "+", changes.Added)
+		printSyncChanges(w, "~", changes.Updated)
+		printSyncChanges(w, "-", changes.Removed)
+	}
+	fmt.Fprintf(w, "\nApplied %s\n", pluralize(len(plans), "target"))
+	return nil
+}
+
+// deployProfile maps a target's profile to the one filterServers expects
+func deployProfile(target deployTarget) string {
+	if target.Profile == "default" {
+		return ""
+	}
+	return target.Profile
+}
+
+// writePlannedTargets writes every target, restoring the files already
+// written if one fails so the tool configs are never left half-applied
+func writePlannedTargets(plans []plannedTarget) error {
+	type original struct {
+		path    string
+		data    []byte
+		existed bool
+	}
+	var written []original
+
+	rollback := func() {
+		for i := len(written) - 1; i >= 0; i-- {
+			if written[i].existed {
+				os.WriteFile(written[i].path, written[i].data, 0644)
+			} else {
+				os.Remove(written[i].path)
+			}
+		}
+	}
+
+	for _, plan := range plans {
+		data, err := os.ReadFile(plan.Path)
+		existed := err == nil
+		if err != nil && !os.IsNotExist(err) {
+			rollback()
+			return newConfigError("load tool config", plan.Path, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(plan.Path), 0755); err != nil {
+			rollback()
+			return newConfigError("create config directory", filepath.Dir(plan.Path), err)
+		}
+
+		// Record the original first, since a failed write may leave the file truncated
+		written = append(written, original{path: plan.Path, data: data, existed: existed})
+		if err := writeToolConfig(plan.Config, plan.Path, plan.Tool); err != nil {
+			rollback()
+			return newConfigError("write MCP config", plan.Path, fmt.Errorf("%w (restored the configs already written)", err))
+		}
+	}
+	for _, plan := range plans {
+		if err := recordManagedServers(plan.Path, plan.Config, nil); err != nil {
+			return newConfigError("save state", "", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadDeployManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "deploy.yml")
+	os.WriteFile(path, []byte(`compose: mcp-compose.yml
+targets:
+  - kiro:user:work
+  - cursor:project
+  - tool: claude-desktop
+    profile: writing
+`), 0644)
+
+	manifest, err := loadDeployManifest(path)
+	if err != nil {
+		t.Fatalf("loadDeployManifest failed: %v", err)
+	}
+	expected := []deployTarget{
+		{Tool: "kiro", Scope: "user", Profile: "work"},
+		{Tool: "cursor", Scope: "project", Profile: "default"},
+		{Tool: "claude-desktop", Scope: "user", Profile: "writing"},
+	}
+	if manifest.Compose != "mcp-compose.yml" || !reflect.DeepEqual(manifest.Targets, expected) {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+
+	for name, content := range map[string]string{
+		"bad scope":  "targets:\n  - kiro:global\n",
+		"no targets": "compose: mcp-compose.yml\n",
+		"no tool":    "targets:\n  - profile: work\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			os.WriteFile(path, []byte(content), 0644)
+			if _, err := loadDeployManifest(path); ExitCode(err) != exitCodeValidation {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyManifest(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+  github:
+    command: npx -y @modelcontextprotocol/server-github
+    labels:
+      mcp.profile: work
+`), 0644)
+
+	manifest := deployManifest{Targets: []deployTarget{
+		{Tool: "kiro", Scope: "user", Profile: "work"},
+		{Tool: "cursor", Scope: "user", Profile: "default"},
+	}}
+
+	var out bytes.Buffer
+	if err := applyManifest(context.Background(), &out, composePath, manifest); err != nil {
+		t.Fatalf("applyManifest failed: %v", err)
+	}
+
+	kiro, _ := readMCPConfig(filepath.Join(home, ".kiro", "settings", "mcp.json"))
+	cursor, _ := readMCPConfig(filepath.Join(home, ".cursor", "mcp.json"))
+	if len(kiro.MCPServers) != 2 || len(cursor.MCPServers) != 1 {
+		t.Errorf("Expected 2 kiro and 1 cursor servers, got %v and %v", kiro.MCPServers, cursor.MCPServers)
+	}
+	for _, want := range []string{"Applied kiro:user:work", "+ github", "Applied 2 targets"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+
+	t.Run("duplicate path", func(t *testing.T) {
+		manifest := deployManifest{Targets: []deployTarget{
+			{Tool: "kiro", Scope: "user", Profile: "work"},
+			{Tool: "kiro", Scope: "user", Profile: "default"},
+		}}
+		err := applyManifest(context.Background(), &bytes.Buffer{}, composePath, manifest)
+		if ExitCode(err) != exitCodeValidation {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+}
+
+func TestWritePlannedTargetsRollsBack(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.json")
+	os.WriteFile(existing, []byte(`{"mcpServers":{}}`), 0644)
+	created := filepath.Join(dir, "new", "mcp.json")
+	blocker := filepath.Join(dir, "blocker")
+	os.WriteFile(blocker, nil, 0644)
+
+	config := MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx"}}}
+	err := writePlannedTargets([]plannedTarget{
+		{Path: existing, Config: config},
+		{Path: created, Config: config},
+		{Path: filepath.Join(blocker, "mcp.json"), Config: config},
+	})
+	if ExitCode(err) != exitCodeConfig {
+		t.Fatalf("Expected config error, got %v", err)
+	}
+
+	if data, _ := os.ReadFile(existing); string(data) != `{"mcpServers":{}}` {
+		t.Errorf("Expected existing config to be restored, got %s", data)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("Expected new config to be removed")
+	}
+}
+
+func TestApplyManifestVSCodeInputs(t *testing.T) {
+	composePath, path := setupVSCodeInputs(t)
+	manifest := deployManifest{Targets: []deployTarget{{Tool: "vscode", Scope: "user", Profile: "default"}}}
+
+	if err := applyManifest(context.Background(), &bytes.Buffer{}, composePath, manifest); err != nil {
+		t.Fatalf("applyManifest failed: %v", err)
+	}
+	checkVSCodeInputs(t, path)
+}
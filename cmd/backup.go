@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// backupManifestName is the archive entry that lists the configs in a backup
+const backupManifestName = "manifest.json"
+
+// backupTimeFormat names backups by when they were taken, so they sort in order
+const backupTimeFormat = "20060102-150405"
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Snapshot every tool config into a backup archive",
+	Long: `Copy the MCP config of every known tool (built-in and custom, including
+project-scoped configs in the current directory) into a timestamped archive
+under ~/.config/mcp/backups. Restore it with 'mcp restore'.
+Set 'mcp config set auto-backup true' to also back up a tool's config each
+time 'mcp set' or 'mcp clear' overwrites it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sources := backupSources(getAllTools())
+		if len(sources) == 0 {
+			fmt.Println("No tool configs found to back up")
+			return nil
+		}
+
+		name, err := createBackup(sources)
+		if err != nil {
+			return err
+		}
+		for _, source := range sources {
+			fmt.Printf("  %s (%s)\n", source.Target, source.Path)
+		}
+		fmt.Printf("Backed up %s to %s\n", pluralize(len(sources), "config"), name)
+		return nil
+	},
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore [backup]",
+	Short: "Restore tool configs from a backup",
+	Long: `Write the tool configs saved in a backup (a name from the list, or "latest")
+back to where they were taken from. Use -t to restore a single tool's config in
+the scope selected with --scope. Without arguments, the available backups are listed.`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, _ := listBackups()
+		return append(names, "latest"), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return displayBackups(os.Stdout)
+		}
+		return restoreBackup(os.Stdout, args[0], toolShortcut)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Restore only this tool's config")
+	restoreCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// backupSource is a config file to back up, with the status key of its tool
+// ("" for configs that aren't a known tool's)
+type backupSource struct {
+	Target string
+	Path   string
+}
+
+// backupEntry records where a config in a backup archive came from
+type backupEntry struct {
+	Target string `json:"target,omitempty"`
+	Path   string `json:"path"`
+	File   string `json:"file"`
+}
+
+// backupManifest lists the configs in a backup archive
+type backupManifest struct {
+	Created time.Time     `json:"created"`
+	Entries []backupEntry `json:"entries"`
+}
+
+// backupSources returns the existing configs of the given tools, in both scopes
+func backupSources(tools []string) []backupSource {
+	var sources []backupSource
+	for _, tool := range tools {
+		for _, scope := range []string{scopeUser, scopeProject} {
+			if scope == scopeProject && (tool == "q-ide" || !supportsProjectScope(tool)) {
+				continue
+			}
+			path, err := getScopedToolPath(tool, scope)
+			if err != nil || path == "" || !fileExists(path) {
+				continue
+			}
+			target := tool
+			if scope == scopeProject {
+				target = projectTarget(tool)
+			}
+			sources = append(sources, backupSource{Target: target, Path: path})
+		}
+	}
+	return sources
+}
+
+// getBackupDir returns the directory backups are kept in
+func getBackupDir() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "backups"), nil
+}
+
+// createBackup writes the sources into a new archive and returns its name
+func createBackup(sources []backupSource) (string, error) {
+	dir, err := getBackupDir()
+	if err != nil {
+		return "", newConfigError("locate backup directory", "", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", newConfigError("create backup directory", dir, err)
+	}
+
+	now := time.Now()
+	manifest := backupManifest{Created: now}
+	files := make(map[string][]byte)
+	for i, source := range sources {
+		data, err := os.ReadFile(source.Path)
+		if err != nil {
+			return "", newConfigError("read tool config", source.Path, err)
+		}
+		file := fmt.Sprintf("%02d-%s", i+1, filepath.Base(source.Path))
+		manifest.Entries = append(manifest.Entries, backupEntry{Target: source.Target, Path: source.Path, File: file})
+		files[file] = data
+	}
+
+	// Backups taken within the same second get a numbered suffix
+	name := now.Format(backupTimeFormat)
+	for n := 2; fileExists(filepath.Join(dir, name+".tar.gz")); n++ {
+		name = fmt.Sprintf("%s-%d", now.Format(backupTimeFormat), n)
+	}
+	path := filepath.Join(dir, name+".tar.gz")
+
+	if err := writeBackupArchive(path, manifest, files); err != nil {
+		os.Remove(path)
+		return "", newConfigError("write backup", path, err)
+	}
+	return name, nil
+}
+
+// writeBackupArchive writes the manifest followed by each file as a gzipped tar
+func writeBackupArchive(path string, manifest backupManifest, files map[string][]byte) error {
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: manifest.Created}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if err := add(backupManifestName, manifestData); err != nil {
+		return err
+	}
+	for _, entry := range manifest.Entries {
+		if err := add(entry.File, files[entry.File]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// readBackup reads a backup archive's manifest and files
+func readBackup(name string) (backupManifest, map[string][]byte, error) {
+	var manifest backupManifest
+
+	dir, err := getBackupDir()
+	if err != nil {
+		return manifest, nil, newConfigError("locate backup directory", "", err)
+	}
+	path := filepath.Join(dir, name+".tar.gz")
+
+	in, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil, newValidationError("backup '%s' not found (run 'mcp restore' to list backups)", name)
+		}
+		return manifest, nil, newConfigError("read backup", path, err)
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return manifest, nil, newConfigError("read backup", path, err)
+	}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, nil, newConfigError("read backup", path, err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return manifest, nil, newConfigError("read backup", path, err)
+		}
+		files[header.Name] = data
+	}
+
+	if err := json.Unmarshal(files[backupManifestName], &manifest); err != nil {
+		return manifest, nil, newConfigError("read backup", path, fmt.Errorf("invalid %s: %w", backupManifestName, err))
+	}
+	return manifest, files, nil
+}
+
+// listBackups returns the names of the available backups, oldest first
+func listBackups() ([]string, error) {
+	dir, err := getBackupDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".tar.gz"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// displayBackups prints the available backups with the tools each contains
+func displayBackups(w io.Writer) error {
+	names, err := listBackups()
+	if err != nil {
+		return newConfigError("list backups", "", err)
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(w, "No backups found (create one with 'mcp backup')")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BACKUP\tCREATED\tCONFIGS")
+	fmt.Fprintln(tw, "------\t-------\t-------")
+	for _, name := range names {
+		manifest, _, err := readBackup(name)
+		if err != nil {
+			fmt.Fprintf(tw, "%s\t-\t%v\n", name, err)
+			continue
+		}
+		var targets []string
+		for _, entry := range manifest.Entries {
+			targets = append(targets, backupLabel(entry))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, manifest.Created.Local().Format(time.DateTime), strings.Join(targets, ", "))
+	}
+	return tw.Flush()
+}
+
+// backupLabel names an entry by its tool, or by its path if it has none
+func backupLabel(entry backupEntry) string {
+	if entry.Target != "" {
+		return entry.Target
+	}
+	return entry.Path
+}
+
+// restoreBackup writes a backup's configs back to their paths, only the
+// given tool's config in the selected scope if tool is set
+func restoreBackup(w io.Writer, name, tool string) error {
+	if name == "latest" {
+		names, err := listBackups()
+		if err != nil {
+			return newConfigError("list backups", "", err)
+		}
+		if len(names) == 0 {
+			return newValidationError("no backups found (create one with 'mcp backup')")
+		}
+		name = names[len(names)-1]
+	}
+
+	manifest, files, err := readBackup(name)
+	if err != nil {
+		return err
+	}
+
+	var entries []backupEntry
+	for _, entry := range manifest.Entries {
+		entryTool, entryScope := splitTarget(entry.Target)
+		if tool == "" || (entry.Target != "" && entryTool == tool && entryScope == configScope) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return newValidationError("backup '%s' has no %s config in %s scope", name, tool, configScope)
+	}
+
+	for _, entry := range entries {
+		if err := os.MkdirAll(filepath.Dir(entry.Path), 0755); err != nil {
+			return newConfigError("create config directory", filepath.Dir(entry.Path), err)
+		}
+		if err := writeFileAtomic(entry.Path, files[entry.File]); err != nil {
+			return newConfigError("restore tool config", entry.Path, err)
+		}
+		fmt.Fprintf(w, "Restored %s (%s)\n", backupLabel(entry), entry.Path)
+	}
+	return nil
+}
+
+// autoBackup backs up the config at path before 'mcp set' or 'mcp clear'
+// overwrites it, when auto-backup is turned on and the file exists
+func autoBackup(w io.Writer, path string) error {
+	tool := ""
+	if configFile == "" {
+		tool = toolShortcut
+	}
+	return autoBackupTool(w, path, tool)
+}
+
+// autoBackupTool is autoBackup for the config of a tool, or of a file given
+// with -c if tool is empty
+func autoBackupTool(w io.Writer, path, tool string) error {
+	config, err := loadCLIConfig()
+	if err != nil || !config.AutoBackup || !fileExists(path) {
+		return nil
+	}
+
+	target := tool
+	if tool != "" && configScope == scopeProject {
+		target = projectTarget(tool)
+	}
+
+	name, err := createBackup([]backupSource{{Target: target, Path: path}})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Backed up %s to %s\n", path, name)
+	return nil
+}
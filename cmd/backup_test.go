@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	originalScope := configScope
+	defer func() { configScope = originalScope }()
+	configScope = scopeUser
+
+	kiroPath := filepath.Join(home, ".kiro", "settings", "mcp.json")
+	cursorPath := filepath.Join(home, ".cursor", "mcp.json")
+	os.MkdirAll(filepath.Dir(kiroPath), 0755)
+	os.MkdirAll(filepath.Dir(cursorPath), 0755)
+	os.WriteFile(kiroPath, []byte(`{"mcpServers":{"time":{"command":"uvx"}}}`), 0644)
+	os.WriteFile(cursorPath, []byte(`{"mcpServers":{}}`), 0644)
+
+	sources := backupSources(getAllTools())
+	if len(sources) != 2 || sources[0].Target != "cursor" || sources[1].Target != "kiro" {
+		t.Fatalf("Expected cursor and kiro configs, got %+v", sources)
+	}
+
+	first, err := createBackup(sources)
+	if err != nil {
+		t.Fatalf("createBackup failed: %v", err)
+	}
+	second, err := createBackup(sources)
+	if err != nil {
+		t.Fatalf("createBackup failed: %v", err)
+	}
+	if first == second {
+		t.Errorf("Expected backups taken in the same second to get distinct names, got %s twice", first)
+	}
+
+	os.WriteFile(kiroPath, []byte(`{"mcpServers":{}}`), 0644)
+	os.WriteFile(cursorPath, []byte(`changed`), 0644)
+
+	var out bytes.Buffer
+	if err := restoreBackup(&out, "latest", "kiro"); err != nil {
+		t.Fatalf("restoreBackup failed: %v", err)
+	}
+	if data, _ := os.ReadFile(kiroPath); !strings.Contains(string(data), `"time"`) {
+		t.Errorf("Expected kiro's config to be restored, got %s", data)
+	}
+	if data, _ := os.ReadFile(cursorPath); string(data) != "changed" {
+		t.Errorf("Expected cursor's config to be left alone, got %s", data)
+	}
+
+	out.Reset()
+	if err := restoreBackup(&out, first, ""); err != nil {
+		t.Fatalf("restoreBackup failed: %v", err)
+	}
+	if data, _ := os.ReadFile(cursorPath); string(data) != `{"mcpServers":{}}` {
+		t.Errorf("Expected cursor's config to be restored, got %s", data)
+	}
+	if !strings.Contains(out.String(), "Restored cursor") || !strings.Contains(out.String(), "Restored kiro") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	displayBackups(&out)
+	if !strings.Contains(out.String(), first) || !strings.Contains(out.String(), "cursor, kiro") {
+		t.Errorf("Unexpected backup list:\n%s", out.String())
+	}
+}
+
+func TestRestoreErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := restoreBackup(&bytes.Buffer{}, "latest", ""); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error without backups, got %v", err)
+	}
+	if err := restoreBackup(&bytes.Buffer{}, "20000101-000000", ""); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error for an unknown backup, got %v", err)
+	}
+}
+
+func TestAutoBackup(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	originalConfig, originalTool := configFile, toolShortcut
+	defer func() { configFile, toolShortcut = originalConfig, originalTool }()
+
+	configFile = filepath.Join(t.TempDir(), "mcp.json")
+	toolShortcut = ""
+	os.WriteFile(configFile, []byte(`{"mcpServers":{}}`), 0644)
+
+	// Off by default
+	var out bytes.Buffer
+	autoBackup(&out, configFile)
+	if names, _ := listBackups(); len(names) != 0 {
+		t.Fatalf("Expected no backup without auto-backup, got %v", names)
+	}
+
+	os.MkdirAll(filepath.Join(home, ".config", "mcp"), 0755)
+	saveCLIConfig(CLIConfig{AutoBackup: true})
+	if err := autoBackup(&out, configFile); err != nil {
+		t.Fatalf("autoBackup failed: %v", err)
+	}
+	names, _ := listBackups()
+	if len(names) != 1 || !strings.Contains(out.String(), "Backed up "+configFile) {
+		t.Fatalf("Expected one backup, got %v (output %q)", names, out.String())
+	}
+	manifest, _, err := readBackup(names[0])
+	if err != nil || len(manifest.Entries) != 1 || manifest.Entries[0].Path != configFile {
+		t.Errorf("Unexpected manifest %+v (%v)", manifest, err)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"os"
@@ -119,7 +120,10 @@ API_KEY=test-api-key-123
 
 	// Test convertToMCPConfig function
 	defaultServers := filterServers(config, "", false)
-	mcpConfig := convertToMCPConfig(defaultServers, envVars)
+	mcpConfig, err := convertToMCPConfig(context.Background(), defaultServers, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
 
 	if len(mcpConfig.MCPServers) != 2 {
 		t.Errorf("Expected 2 MCP servers, got %d", len(mcpConfig.MCPServers))
@@ -336,7 +340,10 @@ func TestConfigurationFileFormatsBackwardCompatibility(t *testing.T) {
 			}
 
 			servers := filterServers(config, "", false)
-			mcpConfig := convertToMCPConfig(servers, envVars)
+			mcpConfig, err := convertToMCPConfig(context.Background(), servers, envVars)
+			if err != nil {
+				t.Fatalf("convertToMCPConfig failed: %v", err)
+			}
 
 			// Should generate valid MCP configuration
 			if len(mcpConfig.MCPServers) == 0 {
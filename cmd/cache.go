@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultCacheTTL is how long cached HTTP responses are used without refetching
+	defaultCacheTTL = time.Hour
+
+	// cacheMinInterval spaces out requests to the same host within one invocation
+	cacheMinInterval = 250 * time.Millisecond
+
+	// cacheRequestTimeout bounds each fetch so a flaky network doesn't block the CLI
+	cacheRequestTimeout = 10 * time.Second
+
+	// cacheMaxBodySize caps how much of a response is read and cached
+	cacheMaxBodySize = 10 << 20
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the HTTP metadata cache",
+	Long: `Manage the on-disk cache of registry queries, version checks, and remote
+catalog fetches in ~/.config/mcp/cache.
+Responses are reused for an hour by default; change this with
+'mcp config set cache-ttl <duration>' (e.g. 15m, 24h, or 0 to disable caching).
+When a refetch fails, the last cached response is used instead, unless caching
+is disabled.`,
+}
+
+// cacheClearCmd represents the cache clear command
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all cached HTTP responses",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := newHTTPCache()
+		if err != nil {
+			return err
+		}
+		if err := cache.Clear(); err != nil {
+			return newConfigError("clear cache", cache.dir, err)
+		}
+		fmt.Printf("Cleared %s\n", cache.dir)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+// httpCache fetches URLs through an on-disk cache with a TTL, rate limits
+// requests per host, and falls back to stale entries when a fetch fails
+type httpCache struct {
+	dir         string
+	ttl         time.Duration
+	minInterval time.Duration
+	client      *http.Client
+	now         func() time.Time // replaced in tests
+
+	mu       sync.Mutex
+	lastCall map[string]time.Time // by host
+}
+
+// cacheEntry is a cached response as stored on disk
+type cacheEntry struct {
+	URL       string    `json:"url"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Body      []byte    `json:"body"`
+}
+
+// newHTTPCache creates a cache in the config directory using the configured TTL
+func newHTTPCache() (*httpCache, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, newConfigError("locate config directory", "", err)
+	}
+	return &httpCache{
+		dir:         filepath.Join(configDir, "cache"),
+		ttl:         getCacheTTL(),
+		minInterval: cacheMinInterval,
+		client:      &http.Client{Timeout: cacheRequestTimeout},
+		now:         time.Now,
+		lastCall:    make(map[string]time.Time),
+	}, nil
+}
+
+// getCacheTTL returns the configured cache TTL, defaulting to defaultCacheTTL
+func getCacheTTL() time.Duration {
+	config, err := loadCLIConfig()
+	if err != nil || config.CacheTTL == "" {
+		return defaultCacheTTL
+	}
+	ttl, err := time.ParseDuration(config.CacheTTL)
+	if err != nil || ttl < 0 {
+		return defaultCacheTTL
+	}
+	return ttl
+}
+
+// Get returns the body of a GET request to rawURL, from the cache when the
+// entry is fresh. If the request fails with a network or server error, a
+// stale entry is returned instead when there is one, unless the TTL is 0 and
+// caching is disabled.
+func (c *httpCache) Get(ctx context.Context, rawURL string) ([]byte, error) {
+	var entry cacheEntry
+	var cached bool
+	if c.ttl > 0 {
+		entry, cached = c.load(rawURL)
+	}
+	if cached && c.now().Sub(entry.FetchedAt) < c.ttl {
+		return entry.Body, nil
+	}
+
+	body, retryable, err := c.fetch(ctx, rawURL)
+	if err != nil {
+		if cached && retryable {
+			return entry.Body, nil
+		}
+		return nil, err
+	}
+
+	if c.ttl > 0 {
+		// A failed write only costs a refetch next time
+		c.store(cacheEntry{URL: rawURL, FetchedAt: c.now(), Body: body})
+	}
+	return body, nil
+}
+
+// Clear deletes every cached entry
+func (c *httpCache) Clear() error {
+	return os.RemoveAll(c.dir)
+}
+
+// fetch performs the GET request once the host's rate limit allows it
+// retryable reports whether a stale entry may stand in for the failure
+func (c *httpCache) fetch(ctx context.Context, rawURL string) ([]byte, bool, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := c.wait(ctx, parsed.Host); err != nil {
+		return nil, false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+		return nil, resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, cacheMaxBodySize))
+	if err != nil {
+		return nil, true, err
+	}
+	return body, false, nil
+}
+
+// wait blocks until at least minInterval has passed since the last request to host
+func (c *httpCache) wait(ctx context.Context, host string) error {
+	c.mu.Lock()
+	next := c.lastCall[host].Add(c.minInterval)
+	delay := next.Sub(c.now())
+	if delay < 0 {
+		delay = 0
+	}
+	c.lastCall[host] = c.now().Add(delay)
+	c.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// entryPath returns the cache file for a URL
+func (c *httpCache) entryPath(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load reads the cached entry for a URL, reporting whether there is one
+func (c *httpCache) load(rawURL string) (cacheEntry, bool) {
+	data, err := os.ReadFile(c.entryPath(rawURL))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != rawURL {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// store writes an entry, replacing the file atomically so concurrent
+// invocations never read a partial entry
+func (c *httpCache) store(entry cacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.entryPath(entry.URL))
+}
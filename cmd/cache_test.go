@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// newTestHTTPCache creates a cache in a temporary directory with a fake clock
+func newTestHTTPCache(t *testing.T, now *time.Time) *httpCache {
+	t.Setenv("HOME", t.TempDir())
+	cache, err := newHTTPCache()
+	if err != nil {
+		t.Fatalf("newHTTPCache failed: %v", err)
+	}
+	cache.minInterval = 0
+	cache.now = func() time.Time { return *now }
+	return cache
+}
+
+func TestHTTPCacheGet(t *testing.T) {
+	requests := 0
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+		w.Write([]byte(`{"version":"1.0.0"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := newTestHTTPCache(t, &now)
+	ctx := context.Background()
+
+	get := func() string {
+		t.Helper()
+		body, err := cache.Get(ctx, server.URL+"/latest")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		return string(body)
+	}
+
+	get()
+	if body := get(); body != `{"version":"1.0.0"}` || requests != 1 {
+		t.Errorf("Expected a cached response after one request, got %q after %d requests", body, requests)
+	}
+
+	// Once the TTL has passed the entry is refetched
+	now = now.Add(defaultCacheTTL)
+	get()
+	if requests != 2 {
+		t.Errorf("Expected a refetch after the TTL, got %d requests", requests)
+	}
+
+	// Server errors fall back to the stale entry
+	now = now.Add(defaultCacheTTL)
+	status = http.StatusServiceUnavailable
+	if body := get(); body != `{"version":"1.0.0"}` {
+		t.Errorf("Expected the stale entry, got %q", body)
+	}
+
+	// Client errors don't
+	now = now.Add(defaultCacheTTL)
+	status = http.StatusNotFound
+	if _, err := cache.Get(ctx, server.URL+"/latest"); err == nil {
+		t.Error("Expected an error for a 404 response")
+	}
+
+	// Without a cached entry the error is returned
+	status = http.StatusServiceUnavailable
+	if _, err := cache.Get(ctx, server.URL+"/other"); err == nil {
+		t.Error("Expected an error without a cached entry")
+	}
+}
+
+func TestHTTPCacheDisabled(t *testing.T) {
+	requests := 0
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	cache := newTestHTTPCache(t, &now)
+	cache.ttl = 0
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Get(context.Background(), server.URL); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected every Get to fetch with a TTL of 0, got %d requests", requests)
+	}
+
+	// An entry cached before caching was disabled isn't used when a fetch fails
+	cache.ttl = defaultCacheTTL
+	if _, err := cache.Get(context.Background(), server.URL); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	cache.ttl = 0
+	status = http.StatusServiceUnavailable
+	if body, err := cache.Get(context.Background(), server.URL); err == nil {
+		t.Errorf("Expected the error with a TTL of 0, got %q", body)
+	}
+}
+
+func TestHTTPCacheRateLimit(t *testing.T) {
+	now := time.Now()
+	cache := newTestHTTPCache(t, &now)
+	cache.minInterval = time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := cache.wait(ctx, "registry.example.com"); err != nil {
+		t.Fatalf("First request should not wait: %v", err)
+	}
+	if err := cache.wait(ctx, "other.example.com"); err != nil {
+		t.Fatalf("Requests to other hosts should not wait: %v", err)
+	}
+
+	// A second request to the same host waits, so cancellation interrupts it
+	cancel()
+	if err := cache.wait(ctx, "registry.example.com"); err == nil {
+		t.Error("Expected the second request to the same host to wait")
+	}
+}
+
+func TestGetCacheTTL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if ttl := getCacheTTL(); ttl != defaultCacheTTL {
+		t.Errorf("Expected default TTL, got %v", ttl)
+	}
+
+	configDir, _ := getConfigDir()
+	os.MkdirAll(configDir, 0755)
+	saveCLIConfig(CLIConfig{CacheTTL: "15m"})
+	if ttl := getCacheTTL(); ttl != 15*time.Minute {
+		t.Errorf("Expected 15m, got %v", ttl)
+	}
+}
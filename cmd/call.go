@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	callArgs    string
+	callJSON    bool
+	callTimeout time.Duration
+)
+
+// callCmd represents the call command
+var callCmd = &cobra.Command{
+	Use:   "call <server> <tool>",
+	Short: "Invoke a tool on a server",
+	Long: `Connect to a server from the compose file, invoke one of its tools, and print
+the content blocks of the result. Pass the tool's arguments as a JSON object
+with --args; see them with 'mcp tools <server>'.
+Text content is printed as is; images, audio, and resources are summarized.
+Use --json to print the raw tools/call result.
+Exits 1 if the tool reports an error.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeServerNames(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		arguments, err := parseToolArguments(callArgs)
+		if err != nil {
+			return err
+		}
+
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		servers, err := resolveServers(cmd.Context(), composeFile, config, args[:1])
+		if err != nil {
+			return err
+		}
+
+		var result mcpToolResult
+		err = withMCPSession(cmd.Context(), servers[args[0]], timeoutFlag(cmd, callTimeout), func(ctx context.Context, client *mcpClient, info mcpInitializeResult) error {
+			result, err = client.CallTool(ctx, args[1], arguments)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		if callJSON {
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		} else {
+			printToolResult(os.Stdout, result)
+		}
+
+		if result.IsError {
+			return &ExitError{Code: exitCodeError}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(callCmd)
+	callCmd.Flags().StringVar(&callArgs, "args", "{}", "Tool arguments as a JSON object")
+	callCmd.Flags().BoolVar(&callJSON, "json", false, "Print the raw result as JSON")
+	callCmd.Flags().DurationVar(&callTimeout, "timeout", defaultMCPTimeout, "How long the server gets to respond")
+}
+
+// parseToolArguments parses the --args JSON object
+func parseToolArguments(raw string) (map[string]any, error) {
+	var arguments map[string]any
+	if err := json.Unmarshal([]byte(raw), &arguments); err != nil {
+		return nil, newValidationError("--args must be a JSON object: %v", err)
+	}
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	return arguments, nil
+}
+
+// printToolResult prints text content as is and summarizes binary content,
+// flagging results the tool reported as errors
+func printToolResult(w io.Writer, result mcpToolResult) {
+	if result.IsError {
+		fmt.Fprintln(w, "Tool returned an error:")
+	}
+
+	for i, content := range result.Content {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		switch content.Type {
+		case "text":
+			fmt.Fprintln(w, strings.TrimRight(content.Text, "\n"))
+		case "image", "audio":
+			size := base64.StdEncoding.DecodedLen(len(content.Data))
+			if data, err := base64.StdEncoding.DecodeString(content.Data); err == nil {
+				size = len(data)
+			}
+			fmt.Fprintf(w, "[%s %s, %s]\n", content.Type, content.MimeType, formatBytes(size))
+		case "resource":
+			if content.Resource == nil {
+				fmt.Fprintln(w, "[resource]")
+				continue
+			}
+			fmt.Fprintf(w, "[resource %s]\n", content.Resource.URI)
+			if content.Resource.Text != "" {
+				fmt.Fprintln(w, strings.TrimRight(content.Resource.Text, "\n"))
+			}
+		case "resource_link":
+			fmt.Fprintf(w, "[link %s]\n", content.URI)
+		default:
+			fmt.Fprintf(w, "[%s content]\n", content.Type)
+		}
+	}
+
+	// Tools that only return structured content still show something
+	if len(result.Content) == 0 && result.StructuredContent != nil {
+		data, _ := json.MarshalIndent(result.StructuredContent, "", "  ")
+		fmt.Fprintln(w, string(data))
+	}
+}
+
+// formatBytes formats a size in bytes for humans, e.g. 12.3 KB
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d bytes", n)
+	}
+}
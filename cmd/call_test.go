@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseToolArguments(t *testing.T) {
+	arguments, err := parseToolArguments(`{"path": "/tmp", "limit": 5}`)
+	if err != nil {
+		t.Fatalf("parseToolArguments failed: %v", err)
+	}
+	if arguments["path"] != "/tmp" || arguments["limit"] != float64(5) {
+		t.Errorf("Unexpected arguments: %v", arguments)
+	}
+
+	if arguments, err := parseToolArguments("null"); err != nil || arguments == nil {
+		t.Errorf("Expected null to give empty arguments, got %v, %v", arguments, err)
+	}
+
+	for _, raw := range []string{`["/tmp"]`, `{"path":`, `path=/tmp`} {
+		if _, err := parseToolArguments(raw); ExitCode(err) != exitCodeValidation {
+			t.Errorf("%s: expected validation error, got %v", raw, err)
+		}
+	}
+}
+
+func TestPrintToolResult(t *testing.T) {
+	var out bytes.Buffer
+	printToolResult(&out, mcpToolResult{Content: []mcpContent{
+		{Type: "text", Text: "2 files\n"},
+		{Type: "image", MimeType: "image/png", Data: "aGVsbG8="},
+		{Type: "resource", Resource: &mcpResource{URI: "file:///tmp/a.txt", Text: "hello"}},
+		{Type: "resource_link", URI: "file:///tmp/b.txt"},
+	}})
+
+	expected := `2 files
+
+[image image/png, 5 bytes]
+
+[resource file:///tmp/a.txt]
+hello
+
+[link file:///tmp/b.txt]
+`
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	printToolResult(&out, mcpToolResult{IsError: true, Content: []mcpContent{{Type: "text", Text: "path not allowed"}}})
+	if out.String() != "Tool returned an error:\npath not allowed\n" {
+		t.Errorf("Unexpected error output: %q", out.String())
+	}
+
+	out.Reset()
+	printToolResult(&out, mcpToolResult{StructuredContent: map[string]any{"count": 2}})
+	if out.String() != "{\n  \"count\": 2\n}\n" {
+		t.Errorf("Unexpected structured output: %q", out.String())
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, expected := range map[int]string{512: "512 bytes", 2048: "2.0 KB", 3 << 20: "3.0 MB"} {
+		if s := formatBytes(n); s != expected {
+			t.Errorf("formatBytes(%d): expected %s, got %s", n, expected, s)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var catShowSecrets bool
+
+// catCmd represents the cat command
+var catCmd = &cobra.Command{
+	Use:   "cat",
+	Short: "Print a tool's deployed MCP config",
+	Long: `Print the MCP configuration deployed to a tool (-t) or file (-c) as indented
+JSON, without having to remember where the tool keeps it. Use -s to print a
+single server. Secrets in environment variables, container arguments, and
+headers are masked; use --show-secrets to reveal them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+		path, err := resolveOutputPath(envVars)
+		if err != nil {
+			return err
+		}
+
+		config, err := readMCPConfigFile(path)
+		if err != nil {
+			return err
+		}
+		return printDeployedConfig(os.Stdout, config, singleServer, catShowSecrets, path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(catCmd)
+	catCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to the MCP JSON configuration file")
+	catCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Tool shortcut (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	catCmd.Flags().StringVarP(&singleServer, "server", "s", "", "Print only this server")
+	catCmd.Flags().BoolVar(&catShowSecrets, "show-secrets", false, "Reveal secret values instead of masking them")
+	catCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+	catCmd.RegisterFlagCompletionFunc("server", completeServerNames)
+}
+
+// printDeployedConfig prints a deployed config, or one of its servers, with
+// secrets masked unless showSecrets is set
+func printDeployedConfig(w io.Writer, config MCPConfig, server string, showSecrets bool, path string) error {
+	servers := config.MCPServers
+	if server != "" {
+		entry, exists := config.MCPServers[server]
+		if !exists {
+			return newValidationError("server '%s' not found in %s", server, path)
+		}
+		servers = map[string]MCPServer{server: entry}
+	}
+
+	printed := MCPConfig{MCPServers: make(map[string]MCPServer, len(servers))}
+	for name, entry := range servers {
+		if !showSecrets {
+			entry = maskServer(entry, serverSecretValues(entry))
+		}
+		printed.MCPServers[name] = entry
+	}
+	return printMCPConfig(w, printed)
+}
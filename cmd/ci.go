@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// CI check results
+const (
+	ciPass = "pass"
+	ciFail = "fail"
+	ciSkip = "skip"
+)
+
+var ciJSON bool
+
+// secretKeyPattern matches variable and header names that usually hold secrets
+var secretKeyPattern = regexp.MustCompile(`(?i)token|secret|passw(or)?d|api[_-]?key|access[_-]?key|private[_-]?key|credential|authorization`)
+
+// secretValuePattern matches the prefixes of well-known credential formats
+var secretValuePattern = regexp.MustCompile(`^(ghp_|gho_|ghs_|github_pat_|glpat-|sk-|xox[abprs]-|AKIA[0-9A-Z]{12})`)
+
+// ciCmd represents the ci command
+var ciCmd = &cobra.Command{
+	Use:   "ci [profile]",
+	Short: "Run every compose file check for CI pipelines",
+	Long: `Run the checks a CI pipeline needs in one command:
+  validate  the compose file is structurally valid (like 'mcp validate')
+  lint      no lint rule marked as an error is broken (like 'mcp lint');
+            warnings of the other rules are listed without failing
+  secrets   no secrets are hard-coded instead of referenced as ${VARS}
+  env       every variable the profile references is set (like 'mcp env check')
+  status    deployed tool configs match the profile (like 'mcp status');
+            skipped when no tool config is deployed and -t isn't given
+Exits 0 when every check passes, 1 when the only failure is drift, and 2 when
+the compose file has problems. Use --json for machine-readable output.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+
+		report, err := runCI(composeFile, profile)
+		if err != nil {
+			return err
+		}
+
+		if ciJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		} else {
+			printCIReport(os.Stdout, report)
+		}
+
+		if report.ExitCode != 0 {
+			return &ExitError{Code: report.ExitCode}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ciCmd)
+	ciCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Check drift and remote support for this tool (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	ciCmd.Flags().BoolVar(&ciJSON, "json", false, "Print the result as JSON")
+	ciCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// ciReport is the result of the ci command
+type ciReport struct {
+	OK       bool      `json:"ok"`
+	ExitCode int       `json:"exitCode"`
+	Checks   []ciCheck `json:"checks"`
+}
+
+// ciCheck is the result of one CI check
+type ciCheck struct {
+	Name     string   `json:"name"`
+	Status   string   `json:"status"` // ciPass, ciFail, or ciSkip
+	Summary  string   `json:"summary"`
+	Problems []string `json:"problems,omitempty"`
+	exitCode int
+}
+
+// runCI runs every check against the compose file and summarizes the exit code
+// Returns an error only if the compose file or environment can't be loaded
+func runCI(composePath, profile string) (ciReport, error) {
+	data, err := os.ReadFile(composePath)
+	if err != nil {
+		return ciReport{}, newConfigError("load compose file", composePath, err)
+	}
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return ciReport{}, newConfigError("load environment variables", composePath, err)
+	}
+
+	checks := []ciCheck{ciValidate(composePath, data, envVars)}
+
+	// The remaining checks need a compose file that parses
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		for _, name := range []string{"lint", "secrets", "env", "status"} {
+			checks = append(checks, ciCheck{Name: name, Status: ciSkip, Summary: "compose file doesn't parse"})
+		}
+	} else {
+		servers := filterServers(config, profile, false)
+		checks = append(checks,
+			ciLint(composePath, envVars),
+			ciSecrets(config.Services),
+			ciEnv(servers, envVars),
+			ciStatus(servers, envVars),
+		)
+	}
+
+	report := ciReport{OK: true, Checks: checks}
+	for _, check := range checks {
+		if check.Status == ciFail {
+			report.OK = false
+		}
+		if check.exitCode > report.ExitCode {
+			report.ExitCode = check.exitCode
+		}
+	}
+	return report, nil
+}
+
+// ciValidate runs the validate checks, leaving unset variables to the env check
+func ciValidate(composePath string, data []byte, envVars map[string]string) ciCheck {
+	// Unset variables stand in for themselves, which is how they expand when unset
+	withRefs := make(map[string]string, len(envVars))
+	for k, v := range envVars {
+		withRefs[k] = v
+	}
+	if config, err := loadComposeFile(composePath); err == nil {
+		for _, service := range config.Services {
+			for _, ref := range unresolvedEnvVars(service, envVars) {
+				withRefs[ref.name] = "${" + ref.name + "}"
+			}
+		}
+	}
+
+	problems := validateComposeData(data, withRefs, toolShortcut)
+	if len(problems) == 0 {
+		return ciCheck{Name: "validate", Status: ciPass, Summary: composePath + " is valid"}
+	}
+
+	check := ciCheck{Name: "validate", Status: ciFail, Summary: pluralize(len(problems), "problem"), exitCode: exitCodeValidation}
+	for _, p := range problems {
+		check.Problems = append(check.Problems, fmt.Sprintf("%s:%d: %s", composePath, p.Line, p.Message))
+	}
+	return check
+}
+
+// ciLint checks the compose file against the lint rules that aren't
+// disabled, failing only on rules with error severity
+func ciLint(composePath string, envVars map[string]string) ciCheck {
+	warnings, err := lintComposeFile(composePath, envVars, lintDisabledRules())
+	if err != nil {
+		return ciCheck{Name: "lint", Status: ciFail, Summary: err.Error(), exitCode: exitCodeValidation}
+	}
+
+	check := ciCheck{Name: "lint", Status: ciPass, Summary: "no lint errors"}
+	failing := 0
+	for _, warning := range warnings {
+		severity := lintRuleSeverity(warning.Rule)
+		if severity == lintSeverityError {
+			failing++
+		}
+		check.Problems = append(check.Problems, fmt.Sprintf("%s:%d: %s: %s (%s)", warning.Path, warning.Line, severity, warning.Message, warning.Rule))
+	}
+	if failing > 0 {
+		check.Status, check.exitCode = ciFail, exitCodeValidation
+		check.Summary = pluralize(failing, "lint error")
+	} else if len(warnings) > 0 {
+		check.Summary = "no lint errors, " + pluralize(len(warnings), "warning")
+	}
+	return check
+}
+
+// ciSecrets checks every service for hard-coded secrets
+func ciSecrets(services map[string]Service) ciCheck {
+	problems := findHardcodedSecrets(services)
+	if len(problems) == 0 {
+		return ciCheck{Name: "secrets", Status: ciPass, Summary: "no hard-coded secrets"}
+	}
+	return ciCheck{Name: "secrets", Status: ciFail, Summary: pluralize(len(problems), "hard-coded secret"), Problems: problems, exitCode: exitCodeValidation}
+}
+
+// ciEnv checks that every variable the servers reference is set
+func ciEnv(servers map[string]Service, envVars map[string]string) ciCheck {
+	usages := collectEnvVarUsages(servers, envVars)
+	missing := missingEnvVarUsages(usages)
+	if len(missing) == 0 {
+		return ciCheck{Name: "env", Status: ciPass, Summary: fmt.Sprintf("all %d referenced variables are set", len(usages))}
+	}
+
+	check := ciCheck{Name: "env", Status: ciFail, Summary: pluralize(len(missing), "variable") + " not set", exitCode: exitCodeValidation}
+	for _, usage := range missing {
+		problem := fmt.Sprintf("%s is not set (used by %s)", usage.Name, strings.Join(usage.Servers, ", "))
+		if usage.Doc != "" {
+			problem += ": " + usage.Doc
+		}
+		check.Problems = append(check.Problems, problem)
+	}
+	return check
+}
+
+// ciStatus checks the deployed tool configs for drift
+func ciStatus(servers map[string]Service, envVars map[string]string) ciCheck {
+	targets, err := statusCheckTargets()
+	if err != nil {
+		if toolShortcut == "" {
+			// Pipelines usually have no tools installed
+			return ciCheck{Name: "status", Status: ciSkip, Summary: "no deployed tool configs found"}
+		}
+		return ciCheck{Name: "status", Status: ciFail, Summary: err.Error(), exitCode: statusExitError}
+	}
+
+	report, err := buildStatusReport(targets, servers, envVars)
+	if err != nil {
+		return ciCheck{Name: "status", Status: ciFail, Summary: err.Error(), exitCode: statusExitError}
+	}
+	if report.InSync {
+		return ciCheck{Name: "status", Status: ciPass, Summary: fmt.Sprintf("%s in sync", pluralize(len(report.Tools), "tool config"))}
+	}
+
+	check := ciCheck{Name: "status", Status: ciFail, Summary: "drift detected", exitCode: statusExitDrift}
+	for _, tool := range report.Tools {
+		for _, server := range tool.Servers {
+			if !serverInSync(server.Status, server.Differences) {
+				check.Problems = append(check.Problems, fmt.Sprintf("%s: %s: %s", tool.Tool, server.Name, server.Status))
+			}
+		}
+	}
+	return check
+}
+
+// findHardcodedSecrets returns a problem for each environment value or auth
+// label that looks like a secret but doesn't reference a variable
+func findHardcodedSecrets(services map[string]Service) []string {
+	var problems []string
+	for _, secret := range scanHardcodedSecrets(services) {
+		problems = append(problems, secret.String())
+	}
+	return problems
+}
+
+// hardcodedSecret is a secret-looking value written inline in a service
+type hardcodedSecret struct {
+	Server   string
+	Path     []string // keys leading to the value, e.g. ["environment", "API_KEY"]
+	Value    string
+	Variable string // suggested variable to reference instead
+}
+
+// String describes the secret and what to reference instead
+func (s hardcodedSecret) String() string {
+	field := s.Path[0] + "." + s.Path[1]
+	if s.Path[0] == "labels" {
+		field = "label " + s.Path[1]
+	}
+	return fmt.Sprintf("service '%s': %s has a hard-coded value; reference a variable such as ${%s} instead", s.Server, field, s.Variable)
+}
+
+// scanHardcodedSecrets returns the environment values and auth labels of
+// each service that look like secrets but don't reference a variable
+func scanHardcodedSecrets(services map[string]Service) []hardcodedSecret {
+	var names []string
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var secrets []hardcodedSecret
+	for _, name := range names {
+		service := services[name]
+		check := func(key, value string, path ...string) {
+			if value == "" || envVarReference.MatchString(value) {
+				return
+			}
+			if secretKeyPattern.MatchString(key) || secretValuePattern.MatchString(value) {
+				secrets = append(secrets, hardcodedSecret{Server: name, Path: path, Value: value, Variable: envVarName(key)})
+			}
+		}
+
+		for _, key := range sortedKeys(service.Environment) {
+			check(key, service.Environment[key], "environment", key)
+		}
+		for _, label := range sortedKeys(service.Labels) {
+			if strings.HasPrefix(label, "mcp.header.") || label == "mcp.client-secret" {
+				check(strings.TrimPrefix(label, "mcp."), service.Labels[label], "labels", label)
+			}
+		}
+	}
+	return secrets
+}
+
+// envVarName suggests a variable name for a key, e.g. "header.X-Api-Key" -> "X_API_KEY"
+func envVarName(key string) string {
+	key = key[strings.LastIndex(key, ".")+1:]
+	return strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// pluralize formats a count with a noun, adding an s unless the count is 1
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// printCIReport prints each check with its problems and a summary line
+func printCIReport(w io.Writer, report ciReport) {
+	failed := 0
+	for _, check := range report.Checks {
+		symbol := "✓"
+		switch check.Status {
+		case ciFail:
+			symbol = "✗"
+			failed++
+		case ciSkip:
+			symbol = "-"
+		}
+
+		fmt.Fprintf(w, "%s %s: %s\n", symbol, check.Name, check.Summary)
+		for _, problem := range check.Problems {
+			fmt.Fprintf(w, "    %s\n", problem)
+		}
+	}
+
+	if failed == 0 {
+		fmt.Fprintln(w, "\nAll checks passed")
+		return
+	}
+	fmt.Fprintf(w, "\n%s failed\n", pluralize(failed, "check"))
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindHardcodedSecrets(t *testing.T) {
+	services := map[string]Service{
+		"github": {
+			Environment: map[string]string{
+				"GITHUB_TOKEN": "ghp_abcdef123456",
+				"LOG_LEVEL":    "debug",
+			},
+		},
+		"search": {
+			Environment: map[string]string{
+				"BRAVE_API_KEY": "${BRAVE_API_KEY}",
+				"ENDPOINT":      "sk-live-123",
+			},
+		},
+		"api": {
+			Labels: map[string]string{
+				"mcp.header.Authorization": "Bearer ${API_TOKEN}",
+				"mcp.header.X-API-Key":     "abc123",
+				"mcp.description":          "API token manager",
+			},
+		},
+	}
+
+	problems := findHardcodedSecrets(services)
+	expected := []string{
+		"service 'api': label mcp.header.X-API-Key has a hard-coded value; reference a variable such as ${X_API_KEY} instead",
+		"service 'github': environment.GITHUB_TOKEN has a hard-coded value; reference a variable such as ${GITHUB_TOKEN} instead",
+		"service 'search': environment.ENDPOINT has a hard-coded value; reference a variable such as ${ENDPOINT} instead",
+	}
+	if strings.Join(problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(problems, "\n"))
+	}
+}
+
+func TestRunCI(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalTool := toolShortcut
+	defer func() { toolShortcut = originalTool }()
+	toolShortcut = ""
+
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+  github:
+    command: npx -y @modelcontextprotocol/server-github
+    environment:
+      GITHUB_PERSONAL_ACCESS_TOKEN: ${CI_TEST_GITHUB_TOKEN}
+    labels:
+      mcp.env-doc.CI_TEST_GITHUB_TOKEN: GitHub PAT with repo scope
+`), 0644)
+
+	report, err := runCI(composePath, "")
+	if err != nil {
+		t.Fatalf("runCI failed: %v", err)
+	}
+
+	statuses := make(map[string]string)
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	expected := map[string]string{"validate": ciPass, "lint": ciPass, "secrets": ciPass, "env": ciFail, "status": ciSkip}
+	for name, status := range expected {
+		if statuses[name] != status {
+			t.Errorf("Check %s: expected %s, got %s", name, status, statuses[name])
+		}
+	}
+	if report.OK || report.ExitCode != exitCodeValidation {
+		t.Errorf("Expected a failed report with exit code %d, got %+v", exitCodeValidation, report)
+	}
+
+	var out bytes.Buffer
+	printCIReport(&out, report)
+	if !strings.Contains(out.String(), "CI_TEST_GITHUB_TOKEN is not set (used by github): GitHub PAT with repo scope") {
+		t.Errorf("Expected the missing variable with its documentation, got:\n%s", out.String())
+	}
+
+	t.Run("drift only exits 1", func(t *testing.T) {
+		t.Setenv("CI_TEST_GITHUB_TOKEN", "token")
+		toolShortcut = "cursor"
+		defer func() { toolShortcut = "" }()
+
+		report, err := runCI(composePath, "")
+		if err != nil {
+			t.Fatalf("runCI failed: %v", err)
+		}
+		if report.ExitCode != statusExitDrift {
+			t.Errorf("Expected exit code %d for drift, got %+v", statusExitDrift, report)
+		}
+	})
+}
+
+func TestRunCILint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalTool := toolShortcut
+	defer func() { toolShortcut = originalTool }()
+	toolShortcut = ""
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+`), 0644)
+
+	// Warnings are listed without failing the check
+	report, err := runCI(composePath, "")
+	if err != nil {
+		t.Fatalf("runCI failed: %v", err)
+	}
+	lint := report.Checks[1]
+	if lint.Name != "lint" || lint.Status != ciPass || len(lint.Problems) != 1 || !strings.Contains(lint.Problems[0], "warning: ") {
+		t.Errorf("Expected a passing lint check listing the missing description, got %+v", lint)
+	}
+
+	// A rule with error severity fails it
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+    environment:
+      API_TOKEN: abc123
+    labels:
+      mcp.description: Time tools
+`), 0644)
+	report, err = runCI(composePath, "")
+	if err != nil {
+		t.Fatalf("runCI failed: %v", err)
+	}
+	lint = report.Checks[1]
+	if lint.Status != ciFail || lint.Summary != "1 lint error" || report.ExitCode != exitCodeValidation {
+		t.Errorf("Expected the hard-coded secret to fail the lint check, got %+v", lint)
+	}
+	if len(lint.Problems) != 1 || !strings.Contains(lint.Problems[0], "error: ") || !strings.HasSuffix(lint.Problems[0], "(hardcoded-secret)") {
+		t.Errorf("Expected the lint error with its rule, got %v", lint.Problems)
+	}
+}
@@ -3,46 +3,106 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	clearTools    []string
+	clearAllTools bool
+)
+
 var clearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear all MCP servers from configuration",
-	Long:  `Remove all MCP servers from the output MCP JSON configuration file.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Remove all MCP servers from the output MCP JSON configuration file.
+Servers added to the file by hand, rather than written by mcp, are kept.
+Repeat -t (or use --all-tools for every tool found on this machine) to clear
+several tools at once, with a table of the result for each.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Load environment variables
 		envVars, err := loadEnvVars(composeFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading environment variables: %v\n", err)
-			os.Exit(1)
+			return newConfigError("load environment variables", composeFile, err)
+		}
+
+		tools, err := selectTools(clearTools, clearAllTools)
+		if err != nil {
+			return err
+		}
+		toolShortcut = ""
+		if len(tools) > 1 || clearAllTools {
+			if err := checkMultipleTools(false); err != nil {
+				return err
+			}
+			targets := resolveToolTargets(tools)
+			err := writeToolTargets(os.Stdout, targets, false, func(target toolTarget, existing MCPConfig) (MCPConfig, []string, string, error) {
+				cleared, unmanaged := clearedConfig(existing, target.Path)
+				result := fmt.Sprintf("cleared %s", pluralize(len(existing.MCPServers)-len(unmanaged), "server"))
+				if len(unmanaged) > 0 {
+					result += fmt.Sprintf(", %d unmanaged kept", len(unmanaged))
+				}
+				return cleared, unmanaged, result, nil
+			})
+			if err != nil {
+				return err
+			}
+			return printToolTargets(os.Stdout, targets)
+		}
+		if len(tools) == 1 {
+			toolShortcut = tools[0]
 		}
 
 		// Determine the output file path
 		outputPath, err := getOutputPath(envVars)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error determining output path: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
-		// Create an empty MCP configuration
-		emptyConfig := MCPConfig{
-			MCPServers: make(map[string]MCPServer),
+		existing, err := readMCPConfig(outputPath)
+		if err != nil {
+			return newConfigError("load tool config", outputPath, err)
+		}
+		cleared, unmanaged := clearedConfig(existing, outputPath)
+
+		if err := autoBackup(os.Stdout, outputPath); err != nil {
+			return err
 		}
 
-		// Write the empty configuration to file
-		if err := writeMCPConfig(emptyConfig, outputPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing MCP config: %v\n", err)
-			os.Exit(1)
+		// Write the configuration without mcp's servers to file
+		if err := writeToolConfig(cleared, outputPath, toolShortcut); err != nil {
+			return newConfigError("write MCP config", outputPath, err)
+		}
+		if err := recordManagedServers(outputPath, cleared, unmanaged); err != nil {
+			return newConfigError("save state", "", err)
 		}
 
-		fmt.Printf("Cleared all servers from %s\n", outputPath)
+		if len(unmanaged) == 0 {
+			fmt.Printf("Cleared all servers from %s\n", outputPath)
+			return nil
+		}
+		fmt.Printf("Cleared %s from %s\n", pluralize(len(existing.MCPServers)-len(unmanaged), "server"), outputPath)
+		fmt.Printf("Kept unmanaged: %s\n", strings.Join(unmanaged, ", "))
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(clearCmd)
 	clearCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to write the MCP JSON configuration file")
-	clearCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Tool shortcut (q-cli, claude-desktop, cursor, kiro)")
+	clearCmd.Flags().StringSliceVarP(&clearTools, "tool", "t", nil, "Tool shortcut (q-cli, q-ide, claude-desktop, cursor, kiro); repeat to clear several tools")
+	clearCmd.Flags().BoolVar(&clearAllTools, "all-tools", false, "Clear every tool found on this machine")
+	clearCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// clearedConfig returns what clearing a tool config leaves of it: the servers
+// mcp didn't write, whose sorted names are also returned
+func clearedConfig(existing MCPConfig, path string) (MCPConfig, []string) {
+	unmanaged := unmanagedServers(existing, path)
+	config := MCPConfig{MCPServers: make(map[string]MCPServer, len(unmanaged))}
+	for _, name := range unmanaged {
+		config.MCPServers[name] = existing.MCPServers[name]
+	}
+	return config, unmanaged
 }
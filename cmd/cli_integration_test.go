@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"os"
@@ -97,7 +98,10 @@ API_KEY=test-api-key-123
 	}
 
 	// Test MCP configuration generation
-	mcpConfig := convertToMCPConfig(defaultServers, envVars)
+	mcpConfig, err := convertToMCPConfig(context.Background(), defaultServers, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
 	if len(mcpConfig.MCPServers) != 2 {
 		t.Errorf("Expected 2 MCP servers, got %d", len(mcpConfig.MCPServers))
 	}
@@ -124,7 +128,7 @@ API_KEY=test-api-key-123
 		t.Errorf("Local server should not have Headers field")
 	}
 
-	// Test JSON marshaling (what writeMCPConfig does)
+	// Test JSON marshaling (what writeToolConfig does)
 	jsonData, err := json.MarshalIndent(mcpConfig, "", "  ")
 	if err != nil {
 		t.Fatalf("Failed to marshal MCP config: %v", err)
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// completeServerNames completes service names from the compose file
+// Each name carries its mcp.description, which zsh and fish show next to it
+func completeServerNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	config, err := loadComposeFile(composeFile)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Don't offer servers that were already given as arguments
+	used := make(map[string]bool)
+	for _, arg := range args {
+		used[arg] = true
+	}
+
+	var names []string
+	for name := range config.Services {
+		if !used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	completions := make([]string, 0, len(names))
+	for _, name := range names {
+		completions = append(completions, serverCompletion(name, config.Services[name]))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// serverCompletion formats a server name with its description as a cobra
+// completion ("name\tdescription"), or just the name if it has none
+func serverCompletion(name string, service Service) string {
+	desc := GetDescription(service)
+	if desc == "" {
+		return name
+	}
+	return name + "\t" + TruncateDescription(desc, MaxDescriptionLength)
+}
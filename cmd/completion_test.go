@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteServerNames(t *testing.T) {
+	originalComposeFile := composeFile
+	defer func() { composeFile = originalComposeFile }()
+
+	composeFile = filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(composeFile, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+    labels:
+      mcp.description: Current time and timezone conversion
+  github:
+    image: mcp/github
+`), 0644)
+
+	completions, directive := completeServerNames(rmCmd, nil, "")
+	expected := []string{"github", "time\tCurrent time and timezone conversion"}
+	if !reflect.DeepEqual(completions, expected) {
+		t.Errorf("Expected %q, got %q", expected, completions)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected NoFileComp directive, got %v", directive)
+	}
+
+	// Servers already given as arguments are not offered again
+	completions, _ = completeServerNames(rmCmd, []string{"github"}, "")
+	if !reflect.DeepEqual(completions, expected[1:]) {
+		t.Errorf("Expected %q, got %q", expected[1:], completions)
+	}
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadComposeDocument parses the compose file into a YAML node tree
+// so it can be edited without losing comments or key order
+func loadComposeDocument(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseComposeDocument(data)
+}
+
+// parseComposeDocument parses compose file contents into a YAML node tree
+// Empty input yields a document with an empty services mapping
+func parseComposeDocument(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Kind == 0 {
+		doc = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("compose file must contain a mapping at the top level")
+	}
+
+	return &doc, nil
+}
+
+// saveComposeDocument writes a YAML node tree back to the compose file
+func saveComposeDocument(path string, doc *yaml.Node) error {
+	data, err := encodeComposeDocument(doc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// encodeComposeDocument renders a YAML node tree with two-space indentation
+// and a blank line between services, matching the style of mcp-compose.yml
+func encodeComposeDocument(doc *yaml.Node) ([]byte, error) {
+	// The parser marks a blank line after a head comment with a trailing
+	// newline, but the encoder needs two to write the blank line back
+	restore := spaceHeadComments(doc)
+	defer restore()
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return separateServices(buf.Bytes()), nil
+}
+
+// spaceHeadComments doubles the trailing newline of head comments that were
+// followed by a blank line and returns a function that undoes the change
+func spaceHeadComments(node *yaml.Node) func() {
+	var changed []*yaml.Node
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if strings.HasSuffix(n.HeadComment, "\n") && !strings.HasSuffix(n.HeadComment, "\n\n") {
+			n.HeadComment += "\n"
+			changed = append(changed, n)
+		}
+		for _, child := range n.Content {
+			walk(child)
+		}
+	}
+	walk(node)
+
+	return func() {
+		for _, n := range changed {
+			n.HeadComment = strings.TrimSuffix(n.HeadComment, "\n")
+		}
+	}
+}
+
+// separateServices inserts a blank line before each service entry (and its
+// head comment) after the first, since the YAML encoder drops blank lines
+func separateServices(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	var out []string
+
+	inServices := false
+	first := true
+	for i, line := range lines {
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "#") {
+			inServices = strings.HasPrefix(line, "services:")
+			first = true
+		}
+
+		if inServices && isServiceStart(lines, i) {
+			if !first && len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+			first = false
+		}
+
+		out = append(out, line)
+	}
+
+	return []byte(strings.Join(out, "\n"))
+}
+
+// isServiceStart reports whether line i begins a service entry: either the
+// two-space indented service key or the first line of the comment above it
+func isServiceStart(lines []string, i int) bool {
+	isServiceKey := func(line string) bool {
+		return strings.HasPrefix(line, "  ") && !strings.HasPrefix(line, "   ") && !strings.HasPrefix(line, "  #")
+	}
+	isServiceComment := func(line string) bool {
+		return strings.HasPrefix(line, "  #")
+	}
+
+	line := lines[i]
+	if isServiceComment(line) && (i == 0 || !isServiceComment(lines[i-1])) {
+		// Only a comment block above a service key starts a service
+		for j := i + 1; j < len(lines); j++ {
+			if isServiceComment(lines[j]) || lines[j] == "" {
+				continue
+			}
+			return isServiceKey(lines[j])
+		}
+		return false
+	}
+
+	return isServiceKey(line) && (i == 0 || !isServiceComment(lines[i-1]))
+}
+
+// mappingIndex returns the index of key in a mapping node's Content, or -1
+func mappingIndex(mapping *yaml.Node, key string) int {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(mapping, key); i >= 0 {
+		return mapping.Content[i+1]
+	}
+	return nil
+}
+
+// servicesNode returns the services mapping of a compose document, creating it if create is set
+func servicesNode(doc *yaml.Node, create bool) *yaml.Node {
+	root := doc.Content[0]
+	services := mappingValue(root, "services")
+	if services != nil || !create {
+		return services
+	}
+
+	services = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	root.Content = append(root.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "services"},
+		services,
+	)
+	return services
+}
+
+// composeServiceNames returns the service names of a compose document in file order
+func composeServiceNames(doc *yaml.Node) []string {
+	services := servicesNode(doc, false)
+	if services == nil {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		names = append(names, services.Content[i].Value)
+	}
+	return names
+}
+
+// removeComposeService deletes a service from a compose document
+// Returns false if the service doesn't exist
+func removeComposeService(doc *yaml.Node, name string) bool {
+	services := servicesNode(doc, false)
+	i := mappingIndex(services, name)
+	if i < 0 {
+		return false
+	}
+
+	services.Content = append(services.Content[:i], services.Content[i+2:]...)
+	return true
+}
+
+// addComposeService appends a service to a compose document, creating the
+// services mapping if needed. Returns false if the service already exists.
+func addComposeService(doc *yaml.Node, name string, service Service) bool {
+	services := servicesNode(doc, true)
+	if mappingIndex(services, name) >= 0 {
+		return false
+	}
+
+	services.Content = append(services.Content, scalarNode(name), serviceNode(service))
+	return true
+}
+
+// serviceNode renders a service as a YAML mapping in the conventional key
+// order, omitting empty fields
+func serviceNode(service Service) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	add := func(key string, value *yaml.Node) {
+		node.Content = append(node.Content, scalarNode(key), value)
+	}
+
+	if service.Command != "" {
+		add("command", scalarNode(service.Command))
+	}
+	if service.Image != "" {
+		add("image", scalarNode(service.Image))
+	}
+	if len(service.Entrypoint) > 0 {
+		entrypoint := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, arg := range service.Entrypoint {
+			entrypoint.Content = append(entrypoint.Content, scalarNode(arg))
+		}
+		add("entrypoint", entrypoint)
+	}
+	if service.WorkingDir != "" {
+		add("working_dir", scalarNode(service.WorkingDir))
+	}
+	if service.User != "" {
+		add("user", scalarNode(service.User))
+	}
+	if len(service.Environment) > 0 {
+		add("environment", stringMapNode(service.Environment))
+	}
+	if len(service.Volumes) > 0 {
+		volumes := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, volume := range service.Volumes {
+			volumes.Content = append(volumes.Content, scalarNode(volume))
+		}
+		add("volumes", volumes)
+	}
+	if len(service.Ports) > 0 {
+		ports := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, port := range service.Ports {
+			ports.Content = append(ports.Content, scalarNode(port))
+		}
+		add("ports", ports)
+	}
+	if len(service.Profiles) > 0 {
+		profiles := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, profile := range service.Profiles {
+			profiles.Content = append(profiles.Content, scalarNode(profile))
+		}
+		add("profiles", profiles)
+	}
+	if len(service.Labels) > 0 {
+		add("labels", stringMapNode(service.Labels))
+	}
+
+	return node
+}
+
+// stringMapNode renders a string map as a YAML mapping with sorted keys
+func stringMapNode(m map[string]string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range sortedKeys(m) {
+		node.Content = append(node.Content, scalarNode(key), scalarNode(m[key]))
+	}
+	return node
+}
+
+// scalarNode creates a string scalar node
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
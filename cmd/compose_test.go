@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestComposeDocumentRoundTrip(t *testing.T) {
+	input := `# MCP servers
+services:
+  # File system access
+  filesystem:
+    image: mcp/filesystem
+    command: ["/data"]
+    labels:
+      mcp.profile: default
+
+  github:
+    image: mcp/github
+    environment:
+      GITHUB_TOKEN: ${GITHUB_TOKEN}
+`
+
+	doc, err := parseComposeDocument([]byte(input))
+	if err != nil {
+		t.Fatalf("parseComposeDocument failed: %v", err)
+	}
+
+	output, err := encodeComposeDocument(doc)
+	if err != nil {
+		t.Fatalf("encodeComposeDocument failed: %v", err)
+	}
+
+	if string(output) != input {
+		t.Errorf("Round trip changed the document:\n--- got ---\n%s\n--- want ---\n%s", output, input)
+	}
+}
+
+func TestComposeDocumentRoundTripSample(t *testing.T) {
+	// The sample compose file has section comments followed by blank lines
+	input, err := os.ReadFile("../mcp-compose.yml")
+	if err != nil {
+		t.Fatalf("Failed to read sample compose file: %v", err)
+	}
+
+	doc, err := parseComposeDocument(input)
+	if err != nil {
+		t.Fatalf("parseComposeDocument failed: %v", err)
+	}
+
+	output, err := encodeComposeDocument(doc)
+	if err != nil {
+		t.Fatalf("encodeComposeDocument failed: %v", err)
+	}
+
+	if string(output) != string(input) {
+		t.Errorf("Round trip changed the sample document:\n%s", output)
+	}
+}
+
+func TestRemoveComposeService(t *testing.T) {
+	input := `services:
+  a:
+    image: a
+
+  # Service b
+  b:
+    image: b
+
+  c:
+    image: c
+`
+	want := `services:
+  a:
+    image: a
+
+  c:
+    image: c
+`
+
+	doc, err := parseComposeDocument([]byte(input))
+	if err != nil {
+		t.Fatalf("parseComposeDocument failed: %v", err)
+	}
+
+	if !removeComposeService(doc, "b") {
+		t.Fatal("Expected service 'b' to be removed")
+	}
+	if removeComposeService(doc, "missing") {
+		t.Error("Expected removing a missing service to return false")
+	}
+
+	if names := composeServiceNames(doc); !reflect.DeepEqual(names, []string{"a", "c"}) {
+		t.Errorf("Expected services [a c], got %v", names)
+	}
+
+	output, err := encodeComposeDocument(doc)
+	if err != nil {
+		t.Fatalf("encodeComposeDocument failed: %v", err)
+	}
+	if string(output) != want {
+		t.Errorf("Unexpected output:\n--- got ---\n%s\n--- want ---\n%s", output, want)
+	}
+}
+
+func TestParseComposeDocumentErrors(t *testing.T) {
+	if _, err := parseComposeDocument([]byte("- a\n- b\n")); err == nil {
+		t.Error("Expected error for a non-mapping document")
+	}
+
+	doc, err := parseComposeDocument([]byte(""))
+	if err != nil {
+		t.Fatalf("Expected empty document to parse, got %v", err)
+	}
+	if names := composeServiceNames(doc); len(names) != 0 {
+		t.Errorf("Expected no services, got %v", names)
+	}
+}
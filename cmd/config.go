@@ -3,8 +3,15 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -16,87 +23,414 @@ var configCmd = &cobra.Command{
 }
 
 var configSetCmd = &cobra.Command{
-	Use:   "set [key] [value]",
-	Short: "Set a configuration value",
-	Long:  `Set a configuration value in the MCP CLI config file.`,
-	Args:  cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
-		key := args[0]
+	Use:               "set [key] [value]",
+	Short:             "Set a configuration value",
+	Long:              `Set a configuration value in the MCP CLI config file.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeConfigKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := lookupConfigKey(args[0])
+		if err != nil {
+			return err
+		}
 		value := args[1]
-
-		if key != "tool" && key != "container-tool" {
-			fmt.Fprintf(os.Stderr, "Error: unsupported configuration key: %s\n", key)
-			os.Exit(1)
+		if value == "" {
+			return newValidationError("empty value for %s (use 'mcp config unset %s' to remove it)", key.name, key.name)
 		}
 
 		// Expand ~ to home directory if present
-		if value[:1] == "~" {
-			homeDir, err := os.UserHomeDir()
+		if strings.HasPrefix(value, "~") {
+			homeDir, err := getHomeDir()
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error getting user home directory: %v\n", err)
-				os.Exit(1)
+				return newConfigError("expand ~ in value", "", err)
 			}
 			value = filepath.Join(homeDir, value[1:])
 		}
 
 		// Ensure the config directory exists
-		configDir := getConfigDir()
+		configDir, err := getConfigDir()
+		if err != nil {
+			return newConfigError("locate config directory", "", err)
+		}
 		if err := os.MkdirAll(configDir, 0755); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating config directory: %v\n", err)
-			os.Exit(1)
+			return newConfigError("create config directory", configDir, err)
 		}
 
 		configPath := filepath.Join(configDir, "config.json")
 
 		// Load existing config if it exists
-		config := CLIConfig{}
-		if _, err := os.Stat(configPath); err == nil {
-			data, err := os.ReadFile(configPath)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading config file: %v\n", err)
-				os.Exit(1)
-			}
-			if err := json.Unmarshal(data, &config); err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing config file: %v\n", err)
-				os.Exit(1)
-			}
+		config, err := loadCLIConfig()
+		if err != nil {
+			return newConfigError("load config file", configPath, err)
 		}
 
 		// Update the config
-		switch key {
-		case "tool":
-			config.Tool = value
-		case "container-tool":
-			config.ContainerTool = value
+		if err := key.set(&config, value); err != nil {
+			return err
 		}
 
 		// Write the updated config
-		data, err := json.MarshalIndent(config, "", "  ")
+		if err := saveCLIConfig(config); err != nil {
+			return newConfigError("write config file", configPath, err)
+		}
+
+		fmt.Printf("Set %s to %s in %s\n", key.name, value, configPath)
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Short:             "Print a configuration value",
+	Long:              `Print a configuration value, or its default if it isn't set.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := lookupConfigKey(args[0])
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error encoding config: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
-		if err := os.WriteFile(configPath, data, 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing config file: %v\n", err)
-			os.Exit(1)
+		config, err := loadCLIConfig()
+		if err != nil {
+			return newConfigError("load config file", "", err)
 		}
 
-		fmt.Printf("Set %s to %s in %s\n", key, value, configPath)
+		value, _ := key.resolve(config)
+		fmt.Println(value)
+		return nil
 	},
 }
 
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print every configuration value",
+	Long: `Print every configuration key with its value, marking the defaults used for
+keys that aren't set, followed by the custom tool shortcuts.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadCLIConfig()
+		if err != nil {
+			return newConfigError("load config file", "", err)
+		}
+		displayCLIConfig(os.Stdout, config)
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:               "unset <key>",
+	Short:             "Remove a configuration value",
+	Long:              `Remove a configuration value from the MCP CLI config file, restoring its default.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := lookupConfigKey(args[0])
+		if err != nil {
+			return err
+		}
+
+		configPath, err := getCLIConfigPath()
+		if err != nil {
+			return newConfigError("locate config directory", "", err)
+		}
+		config, err := loadCLIConfig()
+		if err != nil {
+			return newConfigError("load config file", configPath, err)
+		}
+
+		if _, set := key.resolve(config); !set {
+			fmt.Printf("%s is not set\n", key.name)
+			return nil
+		}
+		key.set(&config, "")
+		if err := saveCLIConfig(config); err != nil {
+			return newConfigError("write config file", configPath, err)
+		}
+
+		fmt.Printf("Unset %s in %s\n", key.name, configPath)
+		return nil
+	},
+}
+
+// configKey is a setting in the MCP CLI config file
+type configKey struct {
+	name         string
+	defaultValue string
+	get          func(config CLIConfig) string
+	// set validates and stores a value; the empty value unsets the key
+	set func(config *CLIConfig, value string) error
+}
+
+// resolve returns the key's value, or its default and false if it isn't set
+func (k configKey) resolve(config CLIConfig) (string, bool) {
+	if value := k.get(config); value != "" {
+		return value, true
+	}
+	return k.defaultValue, false
+}
+
+// configKeys lists the settings 'mcp config' manages, in display order
+var configKeys = []configKey{
+	{
+		name: "tool",
+		get:  func(c CLIConfig) string { return c.Tool },
+		set: func(c *CLIConfig, value string) error {
+			c.Tool = value
+			return nil
+		},
+	},
+	{
+		name:         "container-tool",
+		defaultValue: "docker",
+		get:          func(c CLIConfig) string { return c.ContainerTool },
+		set: func(c *CLIConfig, value string) error {
+			c.ContainerTool = value
+			return nil
+		},
+	},
+	{
+		name:         "compose-file",
+		defaultValue: composeFileNames[0],
+		get:          func(c CLIConfig) string { return c.ComposeFile },
+		set: func(c *CLIConfig, value string) error {
+			if filepath.Base(value) != value && value != "" {
+				return newValidationError("compose-file must be a file name, not a path: %s", value)
+			}
+			c.ComposeFile = value
+			return nil
+		},
+	},
+	{
+		name:         "cache-ttl",
+		defaultValue: defaultCacheTTL.String(),
+		get:          func(c CLIConfig) string { return c.CacheTTL },
+		set: func(c *CLIConfig, value string) error {
+			if ttl, err := time.ParseDuration(value); value != "" && (err != nil || ttl < 0) {
+				return newValidationError("cache-ttl must be a duration such as 30m or 24h, or 0 to disable caching: %s", value)
+			}
+			c.CacheTTL = value
+			return nil
+		},
+	},
+	{
+		name:         "registry-url",
+		defaultValue: defaultRegistryURL,
+		get:          func(c CLIConfig) string { return c.RegistryURL },
+		set: func(c *CLIConfig, value string) error {
+			if value != "" && !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") {
+				return newValidationError("registry-url must be an http:// or https:// URL: %s", value)
+			}
+			c.RegistryURL = value
+			return nil
+		},
+	},
+	{
+		name:         "auto-backup",
+		defaultValue: "false",
+		get: func(c CLIConfig) string {
+			if c.AutoBackup {
+				return "true"
+			}
+			return ""
+		},
+		set: func(c *CLIConfig, value string) error {
+			if value == "" {
+				c.AutoBackup = false
+				return nil
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return newValidationError("auto-backup must be true or false: %s", value)
+			}
+			c.AutoBackup = enabled
+			return nil
+		},
+	},
+	{
+		name: "lint-disable",
+		get:  func(c CLIConfig) string { return c.LintDisable },
+		set: func(c *CLIConfig, value string) error {
+			for _, rule := range strings.Split(value, ",") {
+				if rule = strings.TrimSpace(rule); rule != "" && !slices.Contains(lintRuleNames(), rule) {
+					return newValidationError("lint-disable must list lint rules (%s): %s", strings.Join(lintRuleNames(), ", "), rule)
+				}
+			}
+			c.LintDisable = value
+			return nil
+		},
+	},
+	{
+		name:         "merge",
+		defaultValue: "false",
+		get: func(c CLIConfig) string {
+			if c.Merge {
+				return "true"
+			}
+			return ""
+		},
+		set: func(c *CLIConfig, value string) error {
+			if value == "" {
+				c.Merge = false
+				return nil
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return newValidationError("merge must be true or false: %s", value)
+			}
+			c.Merge = enabled
+			return nil
+		},
+	},
+	{
+		name:         "pull",
+		defaultValue: "false",
+		get: func(c CLIConfig) string {
+			if c.Pull {
+				return "true"
+			}
+			return ""
+		},
+		set: func(c *CLIConfig, value string) error {
+			if value == "" {
+				c.Pull = false
+				return nil
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return newValidationError("pull must be true or false: %s", value)
+			}
+			c.Pull = enabled
+			return nil
+		},
+	},
+}
+
+// configKeyNames returns the names of the supported configuration keys
+func configKeyNames() []string {
+	names := make([]string, 0, len(configKeys))
+	for _, key := range configKeys {
+		names = append(names, key.name)
+	}
+	return names
+}
+
+// lookupConfigKey finds a configuration key, rejecting unknown keys with the supported ones
+func lookupConfigKey(name string) (configKey, error) {
+	for _, key := range configKeys {
+		if key.name == name {
+			return key, nil
+		}
+	}
+	return configKey{}, newValidationError("unsupported configuration key: %s (supported: %s)",
+		name, strings.Join(configKeyNames(), ", "))
+}
+
+// displayCLIConfig prints every key's resolved value and the custom tools
+func displayCLIConfig(w io.Writer, config CLIConfig) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE")
+	fmt.Fprintln(tw, "---\t-----")
+	for _, key := range configKeys {
+		value, set := key.resolve(config)
+		switch {
+		case !set && value == "":
+			value = "(not set)"
+		case !set:
+			value += " (default)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", key.name, value)
+	}
+
+	names := make([]string, 0, len(config.Tools))
+	for name := range config.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(tw, "tools.%s\t%s\n", name, config.Tools[name].Path)
+	}
+	tw.Flush()
+}
+
+// completeConfigKeys provides shell completion for configuration key arguments
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return configKeyNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
 // getConfigDir returns the path to the MCP CLI config directory
-func getConfigDir() string {
-	homeDir, err := os.UserHomeDir()
+func getConfigDir() (string, error) {
+	homeDir, err := getHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "mcp"), nil
+}
+
+// getCLIConfigPath returns the path to the MCP CLI config file
+func getCLIConfigPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "config.json"), nil
+}
+
+// loadCLIConfig reads the MCP CLI config file
+// Returns an empty config if the file doesn't exist
+func loadCLIConfig() (CLIConfig, error) {
+	var config CLIConfig
+
+	configPath, err := getCLIConfigPath()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting user home directory: %v\n", err)
-		os.Exit(1)
+		return config, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("error parsing config file: %w", err)
+	}
+
+	return config, nil
+}
+
+// saveCLIConfig writes the MCP CLI config file
+func saveCLIConfig(config CLIConfig) error {
+	configPath, err := getCLIConfigPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, data, 0644)
+}
+
+// getContainerTool returns the configured container tool, defaulting to "docker"
+func getContainerTool() string {
+	config, err := loadCLIConfig()
+	if err != nil || config.ContainerTool == "" {
+		return "docker"
 	}
-	return filepath.Join(homeDir, ".config", "mcp")
+	return config.ContainerTool
 }
 
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configUnsetCmd)
 }
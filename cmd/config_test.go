@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
 func TestGetConfigDir(t *testing.T) {
-	result := getConfigDir()
+	result, err := getConfigDir()
+	if err != nil {
+		t.Fatalf("getConfigDir failed: %v", err)
+	}
 
 	// Should return a path under the user's home directory
 	homeDir, err := os.UserHomeDir()
@@ -25,3 +30,118 @@ func TestGetConfigDir(t *testing.T) {
 		t.Errorf("Expected absolute path, got %s", result)
 	}
 }
+
+func TestLoadCLIConfig(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	t.Run("missing file returns empty config", func(t *testing.T) {
+		config, err := loadCLIConfig()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if config.Tool != "" || config.ContainerTool != "" || len(config.Tools) != 0 {
+			t.Errorf("Expected empty config, got %+v", config)
+		}
+		if got := getContainerTool(); got != "docker" {
+			t.Errorf("Expected default container tool docker, got %s", got)
+		}
+	})
+
+	t.Run("save and reload preserves custom tools", func(t *testing.T) {
+		configDir, err := getConfigDir()
+		if err != nil {
+			t.Fatalf("getConfigDir failed: %v", err)
+		}
+		if err := os.MkdirAll(configDir, 0755); err != nil {
+			t.Fatalf("Failed to create config dir: %v", err)
+		}
+		config := CLIConfig{
+			ContainerTool: "podman",
+			Tools:         map[string]CustomTool{"mytool": {Path: "/tmp/mcp.json"}},
+		}
+		if err := saveCLIConfig(config); err != nil {
+			t.Fatalf("Failed to save config: %v", err)
+		}
+
+		loaded, err := loadCLIConfig()
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if loaded.Tools["mytool"].Path != "/tmp/mcp.json" {
+			t.Errorf("Expected custom tool to be preserved, got %+v", loaded.Tools)
+		}
+		if got := getContainerTool(); got != "podman" {
+			t.Errorf("Expected container tool podman, got %s", got)
+		}
+	})
+
+	t.Run("invalid JSON returns error", func(t *testing.T) {
+		configPath, err := getCLIConfigPath()
+		if err != nil {
+			t.Fatalf("getCLIConfigPath failed: %v", err)
+		}
+		if err := os.WriteFile(configPath, []byte("{invalid"), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		if _, err := loadCLIConfig(); err == nil {
+			t.Error("Expected error for invalid JSON")
+		}
+	})
+}
+
+func TestConfigKeys(t *testing.T) {
+	t.Run("unknown key lists supported keys", func(t *testing.T) {
+		_, err := lookupConfigKey("colour")
+		if ExitCode(err) != exitCodeValidation {
+			t.Fatalf("Expected a validation error, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "supported: tool, container-tool, compose-file, cache-ttl, registry-url, auto-backup") {
+			t.Errorf("Expected the supported keys in the error, got %v", err)
+		}
+	})
+
+	t.Run("set, resolve, and unset", func(t *testing.T) {
+		key, err := lookupConfigKey("cache-ttl")
+		if err != nil {
+			t.Fatalf("lookupConfigKey failed: %v", err)
+		}
+
+		var config CLIConfig
+		if value, set := key.resolve(config); set || value != "1h0m0s" {
+			t.Errorf("Expected the default, got %q (set %v)", value, set)
+		}
+		if err := key.set(&config, "soon"); ExitCode(err) != exitCodeValidation {
+			t.Errorf("Expected a validation error for an invalid duration, got %v", err)
+		}
+		key.set(&config, "24h")
+		if value, set := key.resolve(config); !set || value != "24h" {
+			t.Errorf("Expected 24h, got %q (set %v)", value, set)
+		}
+		key.set(&config, "")
+		if config.CacheTTL != "" {
+			t.Errorf("Expected the key to be unset, got %q", config.CacheTTL)
+		}
+	})
+
+	t.Run("list shows defaults and custom tools", func(t *testing.T) {
+		var out bytes.Buffer
+		displayCLIConfig(&out, CLIConfig{
+			ContainerTool: "podman",
+			AutoBackup:    true,
+			Tools:         map[string]CustomTool{"mytool": {Path: "/tmp/mcp.json"}},
+		})
+		for _, want := range []string{
+			"tool            (not set)",
+			"container-tool  podman\n",
+			"registry-url    " + defaultRegistryURL + " (default)",
+			"auto-backup     true\n",
+			"tools.mytool    /tmp/mcp.json",
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("Expected %q in:\n%s", want, out.String())
+			}
+		}
+	})
+}
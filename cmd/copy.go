@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	copyFrom string
+	copyTo   string
+)
+
+// copyCmd represents the copy command
+var copyCmd = &cobra.Command{
+	Use:   "copy --from <tool> --to <tool>",
+	Short: "Copy the MCP servers of one tool's config to another",
+	Long: `Read the MCP servers configured in one tool and write them to another, without
+going through the compose file, e.g. when onboarding a new editor.
+Each tool's config layout is translated, so servers can be copied between the
+standard mcpServers layout, VS Code's "servers" (format vscode), and Zed's
+"context_servers" (format zed); register VS Code or Zed as custom tools with
+that format. The servers in the destination are replaced, its other settings
+are kept, and remote servers are skipped for tools that don't support them.
+Fields the destination doesn't support are left out, and servers referencing
+VS Code ${input:...} prompts are skipped unless it prompts for inputs too, in
+which case the inputs are copied with them.
+Both tools use the scope selected with --scope.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if copyFrom == "" || copyTo == "" {
+			return newValidationError("specify the tools to copy between with --from and --to")
+		}
+		return copyToolConfig(os.Stdout, copyFrom, copyTo)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+	copyCmd.Flags().StringVar(&copyFrom, "from", "", "Tool to copy the servers from")
+	copyCmd.Flags().StringVar(&copyTo, "to", "", "Tool to copy the servers to")
+	copyCmd.RegisterFlagCompletionFunc("from", completeToolNames)
+	copyCmd.RegisterFlagCompletionFunc("to", completeToolNames)
+}
+
+// copyTool resolves a tool shortcut to its config path and format
+func copyTool(tool string) (string, string, error) {
+	path, err := getScopedToolPath(tool, configScope)
+	if err != nil {
+		return "", "", &ValidationError{Err: err}
+	}
+	if path == "" {
+		return "", "", newValidationError("unknown tool shortcut: %s", tool)
+	}
+	format := getToolFormat(tool)
+	if _, ok := configFormats[format]; !ok {
+		return "", "", newValidationError("tool '%s' uses unsupported format '%s' (supported: %s)",
+			tool, format, strings.Join(configFormatNames(), ", "))
+	}
+	return path, format, nil
+}
+
+// copyToolConfig replaces the servers in to's config with those in from's,
+// translating between their formats
+func copyToolConfig(w io.Writer, from, to string) error {
+	fromPath, fromFormat, err := copyTool(from)
+	if err != nil {
+		return err
+	}
+	toPath, toFormat, err := copyTool(to)
+	if err != nil {
+		return err
+	}
+	if fromPath == toPath {
+		return newValidationError("%s and %s both use %s", from, to, fromPath)
+	}
+
+	if !fileExists(fromPath) {
+		return newConfigError("load tool config", fromPath, fmt.Errorf("%s has no config at %s", from, fromPath))
+	}
+	source, err := readToolConfigFile(fromPath, fromFormat)
+	if err != nil {
+		return newConfigError("load tool config", fromPath, err)
+	}
+	if len(source.MCPServers) == 0 {
+		return newValidationError("%s has no servers to copy in %s", from, fromPath)
+	}
+
+	if err := checkConfigWritable(toPath); err != nil {
+		return newConfigError("write MCP config", toPath, fmt.Errorf("%s is not writable (%w); %s", toPath, err, notWritableHint))
+	}
+	existing, err := readToolConfigFile(toPath, toFormat)
+	if err != nil {
+		return newConfigError("load tool config", toPath, err)
+	}
+
+	// Inputs are copied along when the destination prompts for them too
+	inputs := configFormats[toFormat].SupportsInputs()
+	var sourceInputs []ConfigInput
+	if inputs && configFormats[fromFormat].SupportsInputs() {
+		if sourceInputs, err = readConfigInputs(fromPath, configFormats[fromFormat]); err != nil {
+			return newConfigError("load tool config", fromPath, err)
+		}
+	}
+
+	copied := MCPConfig{MCPServers: make(map[string]MCPServer)}
+	var skipped, prompted []string
+	for name, server := range source.MCPServers {
+		if server.URL != "" && !toolSupportsRemote(to) {
+			skipped = append(skipped, name)
+			continue
+		}
+		ids := serverInputIDs(server)
+		if len(ids) > 0 && !inputs {
+			prompted = append(prompted, name)
+			continue
+		}
+		for _, input := range sourceInputs {
+			if containsString(ids, input.ID) && !slices.Contains(copied.Inputs, input) {
+				copied.Inputs = append(copied.Inputs, input)
+			}
+		}
+		copied.MCPServers[name] = server
+	}
+	sort.Strings(skipped)
+	sort.Strings(prompted)
+	sort.Slice(copied.Inputs, func(i, j int) bool { return copied.Inputs[i].ID < copied.Inputs[j].ID })
+
+	// Leave out what the destination doesn't understand, as 'mcp set' does
+	copied, stripped := stripUnsupportedFields(copied, to)
+
+	if err := writeToolConfigFile(toPath, toFormat, copied); err != nil {
+		return newConfigError("write MCP config", toPath, err)
+	}
+
+	changes := compareMCPConfigs(existing, copied)
+	fmt.Fprintf(w, "Copied %s to %s (%s): %d added, %d updated, %d removed\n",
+		pluralize(len(copied.MCPServers), "server"), to, toPath, len(changes.Added), len(changes.Updated), len(changes.Removed))
+	printSyncChanges(w, "+", changes.Added)
+	printSyncChanges(w, "~", changes.Updated)
+	printSyncChanges(w, "-", changes.Removed)
+	for _, name := range skipped {
+		fmt.Fprintf(w, "Skipped %s: %s does not support remote servers\n", name, to)
+	}
+	for _, name := range prompted {
+		fmt.Fprintf(w, "Skipped %s: it references VS Code inputs, which %s can't prompt for; write it with 'mcp set' instead\n", name, to)
+	}
+	printStrippedFields(w, to, stripped)
+	return nil
+}
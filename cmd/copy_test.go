@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// setupCopyTools registers VS Code and Zed as custom tools in a temporary home
+func setupCopyTools(t *testing.T) (string, string, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	originalScope := configScope
+	t.Cleanup(func() { configScope = originalScope })
+	configScope = scopeUser
+
+	vscodePath := filepath.Join(home, "vscode", "mcp.json")
+	zedPath := filepath.Join(home, "zed", "settings.json")
+	os.MkdirAll(filepath.Join(home, ".config", "mcp"), 0755)
+	saveCLIConfig(CLIConfig{Tools: map[string]CustomTool{
+		"vscode": {Path: vscodePath, Format: "vscode", SupportsRemote: true},
+		"zed":    {Path: zedPath, Format: "zed"},
+	}})
+	return home, vscodePath, zedPath
+}
+
+func TestCopyVSCodeToZed(t *testing.T) {
+	_, vscodePath, zedPath := setupCopyTools(t)
+	os.MkdirAll(filepath.Dir(vscodePath), 0755)
+	os.WriteFile(vscodePath, []byte(`{
+  "inputs": [],
+  "servers": {
+    "time": {"type": "stdio", "command": "uvx", "args": ["mcp-server-time"], "env": {"TZ": "UTC"}},
+    "docs": {"type": "sse", "url": "https://example.com/mcp"}
+  }
+}`), 0644)
+	os.MkdirAll(filepath.Dir(zedPath), 0755)
+	os.WriteFile(zedPath, []byte(`{"theme": "One Dark", "context_servers": {"old": {"command": {"path": "old"}}}}`), 0644)
+
+	var out bytes.Buffer
+	if err := copyToolConfig(&out, "vscode", "zed"); err != nil {
+		t.Fatalf("copyToolConfig failed: %v", err)
+	}
+	for _, want := range []string{"Copied 1 server to zed", "  + time\n", "  - old\n", "Skipped docs: zed does not support remote servers"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output:\n%s", want, out.String())
+		}
+	}
+
+	var settings map[string]any
+	data, _ := os.ReadFile(zedPath)
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("Invalid settings written: %v\n%s", err, data)
+	}
+	if settings["theme"] != "One Dark" {
+		t.Errorf("Expected Zed's other settings to be kept, got %v", settings)
+	}
+	want := map[string]any{
+		"time": map[string]any{"source": "custom", "command": "uvx", "args": []any{"mcp-server-time"}, "env": map[string]any{"TZ": "UTC"}},
+	}
+	if !reflect.DeepEqual(settings["context_servers"], want) {
+		t.Errorf("Expected context_servers %v, got %v", want, settings["context_servers"])
+	}
+}
+
+func TestCopyZedToStandard(t *testing.T) {
+	home, _, zedPath := setupCopyTools(t)
+	os.MkdirAll(filepath.Dir(zedPath), 0755)
+	os.WriteFile(zedPath, []byte(`{"context_servers": {
+  "legacy": {"command": {"path": "npx", "args": ["-y", "server"], "env": {"A": "1"}}, "settings": {}},
+  "remote": {"url": "https://example.com/mcp", "headers": {"Authorization": "Bearer x"}}
+}}`), 0644)
+
+	if err := copyToolConfig(&bytes.Buffer{}, "zed", "cursor"); err != nil {
+		t.Fatalf("copyToolConfig failed: %v", err)
+	}
+	config, err := readMCPConfig(filepath.Join(home, ".cursor", "mcp.json"))
+	if err != nil {
+		t.Fatalf("readMCPConfig failed: %v", err)
+	}
+	want := map[string]MCPServer{
+		"legacy": {Command: "npx", Args: []string{"-y", "server"}, Env: map[string]string{"A": "1"}},
+		"remote": {Type: "http", URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "Bearer x"}},
+	}
+	if !reflect.DeepEqual(config.MCPServers, want) {
+		t.Errorf("Expected %+v, got %+v", want, config.MCPServers)
+	}
+
+	// And back to VS Code, which needs a type on every server
+	copyToolConfig(&bytes.Buffer{}, "cursor", "vscode")
+	vscodePath, _ := getScopedToolPath("vscode", scopeUser)
+	data, _ := os.ReadFile(vscodePath)
+	if !strings.Contains(string(data), `"type": "stdio"`) || !strings.Contains(string(data), `"servers"`) {
+		t.Errorf("Expected a VS Code config, got:\n%s", data)
+	}
+}
+
+func TestCopyErrors(t *testing.T) {
+	home, _, _ := setupCopyTools(t)
+
+	if err := copyToolConfig(&bytes.Buffer{}, "cursor", "kiro"); ExitCode(err) != exitCodeConfig {
+		t.Errorf("Expected a config error for a missing source, got %v", err)
+	}
+	if err := copyToolConfig(&bytes.Buffer{}, "nope", "kiro"); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error for an unknown tool, got %v", err)
+	}
+	if err := copyToolConfig(&bytes.Buffer{}, "cursor", "cursor"); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error for the same tool, got %v", err)
+	}
+
+	cursorPath := filepath.Join(home, ".cursor", "mcp.json")
+	os.MkdirAll(filepath.Dir(cursorPath), 0755)
+	os.WriteFile(cursorPath, []byte(`{"mcpServers": {}}`), 0644)
+	if err := copyToolConfig(&bytes.Buffer{}, "cursor", "kiro"); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error for a source without servers, got %v", err)
+	}
+}
+
+func TestCopyStripsUnsupportedFields(t *testing.T) {
+	setupCopyTools(t)
+	kiroPath, _ := getPlatformToolPath("kiro")
+	cursorPath, _ := getPlatformToolPath("cursor")
+	os.MkdirAll(filepath.Dir(kiroPath), 0755)
+	writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{
+		"time": {Command: "uvx", Args: []string{"mcp-server-time"}, Disabled: true, AutoApprove: []string{"*"}},
+	}}, kiroPath, "kiro")
+
+	var out bytes.Buffer
+	if err := copyToolConfig(&out, "kiro", "cursor"); err != nil {
+		t.Fatalf("copyToolConfig failed: %v", err)
+	}
+	for _, want := range []string{"Warning: cursor doesn't support autoApprove (server 'time')", "Warning: cursor doesn't support disabled (server 'time')"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output:\n%s", want, out.String())
+		}
+	}
+	copied, _ := readMCPConfig(cursorPath)
+	want := MCPServer{Command: "uvx", Args: []string{"mcp-server-time"}}
+	if !reflect.DeepEqual(copied.MCPServers["time"], want) {
+		t.Errorf("Expected the fields cursor doesn't support to be left out, got %+v", copied.MCPServers["time"])
+	}
+}
+
+func TestCopyVSCodeInputs(t *testing.T) {
+	home, vscodePath, _ := setupCopyTools(t)
+	os.MkdirAll(filepath.Dir(vscodePath), 0755)
+	os.WriteFile(vscodePath, []byte(`{
+  "inputs": [
+    {"type": "promptString", "id": "github-token", "password": true},
+    {"type": "promptString", "id": "unused"}
+  ],
+  "servers": {
+    "github": {"type": "stdio", "command": "npx", "env": {"GITHUB_TOKEN": "${input:github-token}"}},
+    "time": {"type": "stdio", "command": "uvx"}
+  }
+}`), 0644)
+
+	t.Run("skipped for tools without inputs", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := copyToolConfig(&out, "vscode", "cursor"); err != nil {
+			t.Fatalf("copyToolConfig failed: %v", err)
+		}
+		if !strings.Contains(out.String(), "Skipped github: it references VS Code inputs, which cursor can't prompt for") {
+			t.Errorf("Expected github to be skipped, got:\n%s", out.String())
+		}
+		cursorPath, _ := getPlatformToolPath("cursor")
+		copied, _ := readMCPConfig(cursorPath)
+		if _, ok := copied.MCPServers["github"]; ok || len(copied.MCPServers) != 1 {
+			t.Errorf("Expected only time to be copied, got %+v", copied.MCPServers)
+		}
+	})
+
+	t.Run("copied with the server", func(t *testing.T) {
+		insidersPath := filepath.Join(home, "vscode-insiders", "mcp.json")
+		config, _ := loadCLIConfig()
+		config.Tools["vscode-insiders"] = CustomTool{Path: insidersPath, Format: "vscode"}
+		saveCLIConfig(config)
+
+		if err := copyToolConfig(&bytes.Buffer{}, "vscode", "vscode-insiders"); err != nil {
+			t.Fatalf("copyToolConfig failed: %v", err)
+		}
+		if env := readVSCodeServers(t, insidersPath)["github"].Env; env["GITHUB_TOKEN"] != "${input:github-token}" {
+			t.Errorf("Expected the input reference to be copied, got %v", env)
+		}
+		inputs, err := readConfigInputs(insidersPath, configFormats["vscode"])
+		want := []ConfigInput{{Type: "promptString", ID: "github-token", Password: true}}
+		if err != nil || !reflect.DeepEqual(inputs, want) {
+			t.Errorf("Expected only the referenced input to be copied, got %+v (%v)", inputs, err)
+		}
+	})
+}
@@ -0,0 +1,494 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// oauthTokenPlaceholder stands in for OAuth access tokens, which are only
+// acquired at deploy time and can't be compared with the deployed value
+const oauthTokenPlaceholder = "Bearer <acquired at deploy time>"
+
+var (
+	diffFromFile string
+	diffProfiles bool
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff [profile | file | --profiles <profile> <profile>]",
+	Short: "Show drift between the compose file and a deployed tool config",
+	Long: `Show a field-level diff between the MCP configuration the compose file would
+generate for a profile and what is currently in a tool's config file.
+Lines starting with - are in the deployed config, lines starting with + are what
+'mcp set' would write. Only servers that differ are shown, and with the merge
+setting, servers added to the tool by hand are left out.
+OAuth access tokens are not compared; any deployed Bearer token is accepted.
+With the --from-file flag, it compares two MCP JSON files instead, such as a
+teammate's exported config against your own: lines starting with - are in the
+--from-file file, lines starting with + are in the file given as argument, or the
+tool's config file when there is none. Key order and formatting are ignored.
+With the --profiles flag, it compares the servers of two profiles in the compose
+file instead, e.g. 'mcp diff --profiles work personal': lines starting with -
+are in the first profile and lines starting with + in the second. Servers in
+only one of them are marked as such, except that servers running the same
+package, image, or URL under different names (e.g. github-work and
+github-personal) are compared field by field.
+Exits 0 when the configs match and 1 when they differ, like git diff --exit-code.
+WARNING: output may include sensitive values such as API keys and secrets.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if diffProfiles {
+			return cobra.ExactArgs(2)(cmd, args)
+		}
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diffProfiles {
+			return runProfileDiff(cmd.Context(), os.Stdout, composeFile, args[0], args[1])
+		}
+		if diffFromFile != "" {
+			var target string
+			if len(args) > 0 {
+				target = args[0]
+			}
+			return runFileDiff(os.Stdout, diffFromFile, target)
+		}
+
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+		return runDiff(cmd.Context(), os.Stdout, composeFile, profile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to the MCP JSON configuration file to compare")
+	diffCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Tool shortcut (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	diffCmd.Flags().StringVar(&diffFromFile, "from-file", "", "Compare this MCP JSON file instead of the compose file")
+	diffCmd.Flags().BoolVar(&diffProfiles, "profiles", false, "Compare the servers of two profiles in the compose file")
+	addEnvOverrideFlag(diffCmd)
+	diffCmd.MarkFlagsMutuallyExclusive("profiles", "from-file")
+	diffCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+	diffCmd.MarkFlagFilename("from-file", "json")
+}
+
+// runDiff compares the servers of a profile with the deployed config and
+// writes a unified-diff style report to w
+// Returns a silent ExitError with exitCodeDifferent if they differ
+func runDiff(ctx context.Context, w io.Writer, composePath, profile string) error {
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return newConfigError("load environment variables", composePath, err)
+	}
+
+	deployedPath, err := resolveOutputPath(envVars)
+	if err != nil {
+		return err
+	}
+
+	deployed, err := readMCPConfig(deployedPath)
+	if err != nil {
+		return newConfigError("load tool config", deployedPath, err)
+	}
+	// 'mcp set' leaves the servers added by hand alone when merging
+	if mergeByDefault() {
+		for _, name := range unmanagedServers(deployed, deployedPath) {
+			delete(deployed.MCPServers, name)
+		}
+	}
+
+	expected, err := buildExpectedConfig(ctx, enabledServers(filterServers(config, profile, false)), envVars)
+	if err != nil {
+		return err
+	}
+	// Compare with what set writes for the tool
+	expected = configForTool(expected, config.Services, toolShortcut)
+	expected, _ = stripUnsupportedFields(expected, toolShortcut)
+	expected = applyToolInputs(expected, toolShortcut, config.Services, envVars)
+
+	label := composePath
+	if profile != "" {
+		label = fmt.Sprintf("%s (%s)", composePath, profile)
+	}
+
+	hunks := diffMCPConfigs(deployed, expected)
+	if len(hunks) == 0 {
+		fmt.Fprintf(w, "No differences between %s and %s\n", label, deployedPath)
+		return nil
+	}
+
+	fmt.Fprintf(w, "--- %s\n", deployedPath)
+	fmt.Fprintf(w, "+++ %s\n", label)
+	for _, hunk := range hunks {
+		fmt.Fprint(w, hunk)
+	}
+
+	// Like git diff --exit-code, differences exit 1 so scripts can use diff as a check
+	return &ExitError{Code: exitCodeDifferent}
+}
+
+// runFileDiff compares the servers of two MCP JSON files, using the tool's
+// config file when target is empty
+func runFileDiff(w io.Writer, fromPath, target string) error {
+	if target == "" {
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+		if target, err = resolveOutputPath(envVars); err != nil {
+			return err
+		}
+	}
+
+	from, err := readMCPConfigFile(fromPath)
+	if err != nil {
+		return err
+	}
+	to, err := readMCPConfigFile(target)
+	if err != nil {
+		return err
+	}
+
+	hunks := diffConfigs(from, to, "only in "+target, "only in "+fromPath)
+	if len(hunks) == 0 {
+		fmt.Fprintf(w, "No differences between %s and %s\n", fromPath, target)
+		return nil
+	}
+
+	fmt.Fprintf(w, "--- %s\n", fromPath)
+	fmt.Fprintf(w, "+++ %s\n", target)
+	for _, hunk := range hunks {
+		fmt.Fprint(w, hunk)
+	}
+	return &ExitError{Code: exitCodeDifferent}
+}
+
+// runProfileDiff compares the servers two profiles of the compose file resolve to
+func runProfileDiff(ctx context.Context, w io.Writer, composePath, from, to string) error {
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return newConfigError("load environment variables", composePath, err)
+	}
+
+	for _, profile := range []string{from, to} {
+		if !hasProfile(config, profile) {
+			return newValidationError("profile '%s' not found in %s", profile, composePath)
+		}
+	}
+
+	fromServers := enabledServers(filterServers(config, from, false))
+	toServers := enabledServers(filterServers(config, to, false))
+	fromConfig, err := buildExpectedConfig(ctx, fromServers, envVars)
+	if err != nil {
+		return err
+	}
+	toConfig, err := buildExpectedConfig(ctx, toServers, envVars)
+	if err != nil {
+		return err
+	}
+
+	// Servers in both profiles are the same service, so only servers unique
+	// to one profile can differ
+	var onlyFrom, onlyTo []string
+	for name := range fromServers {
+		if _, ok := toServers[name]; !ok {
+			onlyFrom = append(onlyFrom, name)
+		}
+	}
+	for name := range toServers {
+		if _, ok := fromServers[name]; !ok {
+			onlyTo = append(onlyTo, name)
+		}
+	}
+	sort.Strings(onlyFrom)
+	sort.Strings(onlyTo)
+
+	// Pair up servers that run the same thing under different names
+	paired := make(map[string]string)
+	for _, fromName := range onlyFrom {
+		for _, toName := range onlyTo {
+			if _, taken := paired[toName]; !taken && serverIdentity(fromName, fromServers[fromName]) == serverIdentity(toName, toServers[toName]) {
+				paired[fromName], paired[toName] = toName, fromName
+				break
+			}
+		}
+	}
+
+	var hunks []string
+	for _, name := range onlyFrom {
+		header := fmt.Sprintf("@@ %s (only in %s) @@\n", name, from)
+		toFields := []serverField(nil)
+		if toName, ok := paired[name]; ok {
+			header = fmt.Sprintf("@@ %s -> %s @@\n", name, toName)
+			toFields = serverFields(toConfig.MCPServers[toName])
+		}
+		body, _ := diffServerFields(serverFields(fromConfig.MCPServers[name]), toFields)
+		hunks = append(hunks, header+body)
+	}
+	for _, name := range onlyTo {
+		if _, ok := paired[name]; ok {
+			continue
+		}
+		body, _ := diffServerFields(nil, serverFields(toConfig.MCPServers[name]))
+		hunks = append(hunks, fmt.Sprintf("@@ %s (only in %s) @@\n", name, to)+body)
+	}
+
+	if len(hunks) == 0 {
+		fmt.Fprintf(w, "No differences between profiles %s and %s\n", from, to)
+		return nil
+	}
+
+	fmt.Fprintf(w, "--- %s (%s)\n", composePath, from)
+	fmt.Fprintf(w, "+++ %s (%s)\n", composePath, to)
+	for _, hunk := range hunks {
+		fmt.Fprint(w, hunk)
+	}
+	return &ExitError{Code: exitCodeDifferent}
+}
+
+// serverIdentity describes what a server runs regardless of its name and
+// settings: its package or image without the version, its URL, or its command
+func serverIdentity(name string, service Service) string {
+	if pkg, ok := findPackage(name, service); ok {
+		return pkg.Kind + " " + pkg.Name
+	}
+	return serviceSource(service)
+}
+
+// hasProfile reports whether any server of the compose file is in profile
+func hasProfile(config *ComposeConfig, profile string) bool {
+	for _, service := range config.Services {
+		if slices.Contains(docsProfiles(service), profile) {
+			return true
+		}
+	}
+	return false
+}
+
+// readMCPConfigFile reads an MCP JSON file that must exist, unlike a tool
+// config that may not have been deployed yet
+func readMCPConfigFile(path string) (MCPConfig, error) {
+	if _, err := os.Stat(path); err != nil {
+		return MCPConfig{}, newConfigError("load MCP config", path, err)
+	}
+	config, err := readMCPConfig(path)
+	if err != nil {
+		return MCPConfig{}, newConfigError("load MCP config", path, err)
+	}
+	return config, nil
+}
+
+// buildExpectedConfig converts servers to the MCP JSON format like 'mcp set',
+// except that OAuth servers get a placeholder instead of a freshly acquired token
+func buildExpectedConfig(ctx context.Context, servers map[string]Service, envVars map[string]string) (MCPConfig, error) {
+	local := make(map[string]Service)
+	oauth := make(map[string]MCPServer)
+	for name, service := range servers {
+		if IsRemoteServerWithEnvExpansion(service, envVars) && !UsesHeadersAuth(service) {
+			oauth[name] = MCPServer{
+				Type:    "http",
+				URL:     expandEnvVars(service.Command, envVars),
+				Headers: map[string]string{"Authorization": oauthTokenPlaceholder},
+			}
+			continue
+		}
+		local[name] = service
+	}
+
+	config, err := convertToMCPConfig(ctx, local, envVars)
+	if err != nil {
+		return MCPConfig{}, err
+	}
+	for name, server := range oauth {
+		config.MCPServers[name] = server
+	}
+	return config, nil
+}
+
+// diffMCPConfigs returns one unified-diff style hunk per server that differs
+// between the deployed and expected configs, sorted by server name
+func diffMCPConfigs(deployed, expected MCPConfig) []string {
+	return diffConfigs(deployed, expected, "not deployed", "not in compose file")
+}
+
+// diffConfigs returns one hunk per server that differs between two configs,
+// noting servers only in the new config with onlyNew and servers only in the
+// old config with onlyOld
+func diffConfigs(deployed, expected MCPConfig, onlyNew, onlyOld string) []string {
+	names := make(map[string]bool)
+	for name := range deployed.MCPServers {
+		names[name] = true
+	}
+	for name := range expected.MCPServers {
+		names[name] = true
+	}
+
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var hunks []string
+	for _, name := range sorted {
+		oldServer, inDeployed := deployed.MCPServers[name]
+		newServer, inExpected := expected.MCPServers[name]
+
+		var header string
+		switch {
+		case !inDeployed:
+			header = fmt.Sprintf("@@ %s (%s) @@\n", name, onlyNew)
+		case !inExpected:
+			header = fmt.Sprintf("@@ %s (%s) @@\n", name, onlyOld)
+		default:
+			header = fmt.Sprintf("@@ %s @@\n", name)
+		}
+
+		var oldFields, newFields []serverField
+		if inDeployed {
+			oldFields = serverFields(oldServer)
+		}
+		if inExpected {
+			newFields = serverFields(newServer)
+		}
+
+		if body, changed := diffServerFields(oldFields, newFields); changed {
+			hunks = append(hunks, header+body)
+		}
+	}
+
+	return hunks
+}
+
+// serverField is one comparable line of a server config, e.g. "env.API_KEY"
+type serverField struct {
+	key   string
+	value string
+}
+
+// serverFields flattens a server config into fields in a stable display order
+func serverFields(server MCPServer) []serverField {
+	var fields []serverField
+	add := func(key, value string) {
+		fields = append(fields, serverField{key: key, value: value})
+	}
+
+	if server.Type != "" {
+		add("type", server.Type)
+	}
+	if server.URL != "" {
+		add("url", server.URL)
+	}
+	if server.Command != "" {
+		add("command", server.Command)
+	}
+	for i, arg := range server.Args {
+		add(fmt.Sprintf("args[%d]", i), arg)
+	}
+	for _, key := range sortedKeys(server.Env) {
+		add("env."+key, server.Env[key])
+	}
+	for _, key := range sortedKeys(server.Headers) {
+		add("headers."+key, server.Headers[key])
+	}
+	if server.Disabled {
+		add("disabled", "true")
+	}
+	if len(server.AutoApprove) > 0 {
+		add("autoApprove", strings.Join(server.AutoApprove, ","))
+	}
+	if len(server.AlwaysAllow) > 0 {
+		add("alwaysAllow", strings.Join(server.AlwaysAllow, ","))
+	}
+	if server.Timeout > 0 {
+		add("timeout", strconv.Itoa(server.Timeout))
+	}
+
+	return fields
+}
+
+// diffServerFields renders fields present in both configs as context lines and
+// changed, added, or removed fields as -/+ lines
+func diffServerFields(oldFields, newFields []serverField) (string, bool) {
+	oldValues := make(map[string]string)
+	for _, f := range oldFields {
+		oldValues[f.key] = f.value
+	}
+	newValues := make(map[string]string)
+	for _, f := range newFields {
+		newValues[f.key] = f.value
+	}
+
+	// Walk the expected fields in order, then any fields only deployed
+	order := make([]string, 0, len(newFields)+len(oldFields))
+	for _, f := range newFields {
+		order = append(order, f.key)
+	}
+	for _, f := range oldFields {
+		if _, ok := newValues[f.key]; !ok {
+			order = append(order, f.key)
+		}
+	}
+
+	var b strings.Builder
+	changed := false
+	for _, key := range order {
+		oldValue, inOld := oldValues[key]
+		newValue, inNew := newValues[key]
+
+		switch {
+		case inOld && inNew && fieldsMatch(oldValue, newValue):
+			fmt.Fprintf(&b, "  %s: %s\n", key, newValue)
+		case inOld && inNew:
+			fmt.Fprintf(&b, "- %s: %s\n", key, oldValue)
+			fmt.Fprintf(&b, "+ %s: %s\n", key, newValue)
+			changed = true
+		case inOld:
+			fmt.Fprintf(&b, "- %s: %s\n", key, oldValue)
+			changed = true
+		default:
+			fmt.Fprintf(&b, "+ %s: %s\n", key, newValue)
+			changed = true
+		}
+	}
+
+	return b.String(), changed
+}
+
+// fieldsMatch compares a deployed value with an expected one, accepting any
+// Bearer token where an OAuth token is expected
+func fieldsMatch(deployed, expected string) bool {
+	if expected == oauthTokenPlaceholder {
+		return strings.HasPrefix(deployed, "Bearer ")
+	}
+	return deployed == expected
+}
+
+// sortedKeys returns the keys of a string map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
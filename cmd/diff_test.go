@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffMCPConfigs(t *testing.T) {
+	deployed := MCPConfig{MCPServers: map[string]MCPServer{
+		"time": {Command: "uvx", Args: []string{"mcp-server-time"}},
+		"github": {
+			Command: "npx",
+			Args:    []string{"-y", "server-github"},
+			Env:     map[string]string{"GITHUB_TOKEN": "old"},
+		},
+		"stale": {Command: "stale-server"},
+		"api": {
+			Type:    "http",
+			URL:     "https://api.example.com/mcp",
+			Headers: map[string]string{"Authorization": "Bearer abc123"},
+		},
+	}}
+	expected := MCPConfig{MCPServers: map[string]MCPServer{
+		"time": {Command: "uvx", Args: []string{"mcp-server-time"}},
+		"github": {
+			Command: "npx",
+			Args:    []string{"-y", "server-github"},
+			Env:     map[string]string{"GITHUB_TOKEN": "new"},
+		},
+		"fresh": {Command: "fresh-server"},
+		"api": {
+			Type:    "http",
+			URL:     "https://api.example.com/mcp",
+			Headers: map[string]string{"Authorization": oauthTokenPlaceholder},
+		},
+	}}
+
+	hunks := diffMCPConfigs(deployed, expected)
+	output := strings.Join(hunks, "")
+
+	if len(hunks) != 3 {
+		t.Fatalf("Expected 3 hunks (fresh, github, stale), got %d:\n%s", len(hunks), output)
+	}
+
+	for _, want := range []string{
+		"@@ fresh (not deployed) @@\n+ command: fresh-server\n",
+		"@@ github @@\n  command: npx\n  args[0]: -y\n  args[1]: server-github\n- env.GITHUB_TOKEN: old\n+ env.GITHUB_TOKEN: new\n",
+		"@@ stale (not in compose file) @@\n- command: stale-server\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain:\n%s\ngot:\n%s", want, output)
+		}
+	}
+
+	if strings.Contains(output, "time") || strings.Contains(output, "api") {
+		t.Errorf("Unchanged servers should not be shown, got:\n%s", output)
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	originalConfig, originalTool := configFile, toolShortcut
+	defer func() { configFile, toolShortcut = originalConfig, originalTool }()
+	toolShortcut = ""
+
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+`), 0644)
+
+	configFile = filepath.Join(dir, "mcp.json")
+
+	t.Run("missing config shows everything as added", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runDiff(context.Background(), &out, composePath, "")
+		if ExitCode(err) != exitCodeDifferent {
+			t.Fatalf("Expected exit code %d for differences, got %v", exitCodeDifferent, err)
+		}
+		printError(&out, err)
+		if !strings.Contains(out.String(), "+ command: uvx") || strings.Contains(out.String(), "Error") {
+			t.Errorf("Expected added command, got:\n%s", out.String())
+		}
+	})
+
+	t.Run("in sync", func(t *testing.T) {
+		writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{
+			"time": {Command: "uvx", Args: []string{"mcp-server-time"}},
+		}}, configFile, "")
+
+		var out bytes.Buffer
+		if err := runDiff(context.Background(), &out, composePath, ""); err != nil {
+			t.Fatalf("runDiff failed: %v", err)
+		}
+		if !strings.HasPrefix(out.String(), "No differences") {
+			t.Errorf("Expected no differences, got:\n%s", out.String())
+		}
+	})
+}
+
+func TestRunFileDiff(t *testing.T) {
+	dir := t.TempDir()
+	theirs := filepath.Join(dir, "theirs.json")
+	mine := filepath.Join(dir, "mine.json")
+	os.WriteFile(theirs, []byte(`{"mcpServers":{"time":{"args":["mcp-server-time"],"command":"uvx"},"fetch":{"command":"uvx","args":["mcp-server-fetch"]}}}`), 0644)
+	os.WriteFile(mine, []byte(`{
+  "mcpServers": {
+    "time": {
+      "command": "uvx",
+      "args": ["mcp-server-time"]
+    }
+  }
+}`), 0644)
+
+	var out bytes.Buffer
+	err := runFileDiff(&out, theirs, mine)
+	if ExitCode(err) != exitCodeDifferent {
+		t.Errorf("Expected exit code %d, got %v", exitCodeDifferent, err)
+	}
+	want := "--- " + theirs + "\n+++ " + mine + "\n@@ fetch (only in " + theirs + ") @@\n- command: uvx\n- args[0]: mcp-server-fetch\n"
+	if out.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out.String())
+	}
+
+	t.Run("formatting is ignored", func(t *testing.T) {
+		os.WriteFile(theirs, []byte(`{"mcpServers":{"time":{"args":["mcp-server-time"],"command":"uvx"}}}`), 0644)
+		var out bytes.Buffer
+		if err := runFileDiff(&out, theirs, mine); err != nil {
+			t.Errorf("Expected no differences, got %v:\n%s", err, out.String())
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := runFileDiff(&bytes.Buffer{}, filepath.Join(dir, "missing.json"), mine)
+		if ExitCode(err) != exitCodeConfig {
+			t.Errorf("Expected config error, got %v", err)
+		}
+	})
+}
+
+func TestRunProfileDiff(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	compose := `services:
+  time:
+    command: uvx mcp-server-time
+  github-work:
+    command: npx -y @modelcontextprotocol/server-github
+    environment:
+      GITHUB_TOKEN: work-token
+    labels:
+      mcp.profile: work
+  github-personal:
+    command: npx -y @modelcontextprotocol/server-github@1.2.0
+    environment:
+      GITHUB_TOKEN: personal-token
+    labels:
+      mcp.profile: personal
+  jira:
+    command: npx -y jira-mcp
+    labels:
+      mcp.profile: work
+`
+	if err := os.WriteFile(composePath, []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := runProfileDiff(context.Background(), &out, composePath, "work", "personal")
+	if ExitCode(err) != exitCodeDifferent {
+		t.Fatalf("Expected exit code %d, got %v", exitCodeDifferent, err)
+	}
+	for _, want := range []string{
+		"@@ github-work -> github-personal @@\n  command: npx\n  args[0]: -y\n- args[1]: @modelcontextprotocol/server-github\n+ args[1]: @modelcontextprotocol/server-github@1.2.0\n- env.GITHUB_TOKEN: work-token\n+ env.GITHUB_TOKEN: personal-token\n",
+		"@@ jira (only in work) @@\n- command: npx\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain:\n%s\ngot:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "time") {
+		t.Errorf("Servers in both profiles should not be shown, got:\n%s", out.String())
+	}
+
+	if err := runProfileDiff(context.Background(), &out, composePath, "work", "missing"); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error for an unknown profile, got %v", err)
+	}
+}
+
+func TestRunDiffVSCodeInputs(t *testing.T) {
+	composePath, path := setupVSCodeInputs(t)
+	originalConfig, originalTool := configFile, toolShortcut
+	defer func() { configFile, toolShortcut = originalConfig, originalTool }()
+	configFile, toolShortcut = "", "vscode"
+
+	setVSCodeInputs(t, composePath, path)
+	var out bytes.Buffer
+	if err := runDiff(context.Background(), &out, composePath, ""); err != nil {
+		t.Fatalf("Expected no differences after 'mcp set', got %v:\n%s", err, out.String())
+	}
+}
+
+func TestRunDiffMergeSkipsUnmanaged(t *testing.T) {
+	composePath, _ := setupHandAddedServer(t)
+	originalConfig, originalTool := configFile, toolShortcut
+	defer func() { configFile, toolShortcut = originalConfig, originalTool }()
+	configFile, toolShortcut = "", "cursor"
+
+	var out bytes.Buffer
+	if err := runDiff(context.Background(), &out, composePath, ""); err != nil {
+		t.Fatalf("Expected no differences for a hand-added server kept by merging, got %v:\n%s", err, out.String())
+	}
+}
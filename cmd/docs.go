@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	docsFormat     string
+	docsOutput     string
+	docsAllServers bool
+)
+
+// docsFormats are the formats 'mcp docs' can generate
+var docsFormats = []string{"markdown"}
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs [profile]",
+	Short: "Generate documentation of the servers in the compose file",
+	Long: `Generate a single document describing each server in the compose file: its
+profiles, description, type (local, container, or remote), required environment
+variables, and the tool configs it is currently deployed to.
+Commit it next to the mcp-compose.yml file to document a team's MCP setup.
+Variable values are never written.
+Without arguments, it documents the default servers. With the -a flag, it
+documents all servers. The document is printed unless --output is set.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(docsFormats, docsFormat) {
+			return newValidationError("unsupported docs format: %s (supported: %s)", docsFormat, strings.Join(docsFormats, ", "))
+		}
+
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+		servers := filterServers(config, profile, docsAllServers)
+		doc := serversMarkdown(filepath.Base(composeFile), servers, deployedTools(getStatusTargets(getAllTools())))
+
+		if docsOutput == "" {
+			fmt.Print(doc)
+			return nil
+		}
+		if err := os.WriteFile(docsOutput, []byte(doc), 0644); err != nil {
+			return newConfigError("write docs", docsOutput, err)
+		}
+		fmt.Printf("Wrote docs for %s to %s\n", pluralize(len(servers), "server"), docsOutput)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.Flags().StringVar(&docsFormat, "format", "markdown", "Document format ("+strings.Join(docsFormats, ", ")+")")
+	docsCmd.Flags().StringVarP(&docsOutput, "output", "o", "", "Write the document to this file instead of printing it")
+	docsCmd.Flags().BoolVarP(&docsAllServers, "all", "a", false, "Document all servers")
+	docsCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return docsFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// deployedTools maps each server name to the tool config targets containing it
+// Targets whose configs can't be read are skipped.
+func deployedTools(targets []string) map[string][]string {
+	deployed := make(map[string][]string)
+	for _, target := range targets {
+		config, _, err := loadToolConfig(target)
+		if err != nil {
+			continue
+		}
+		for name := range config.MCPServers {
+			deployed[name] = append(deployed[name], target)
+		}
+	}
+	return deployed
+}
+
+// serversMarkdown renders the markdown document of servers: a summary table
+// followed by a section per server
+func serversMarkdown(composeName string, servers map[string]Service, deployed map[string][]string) string {
+	var b strings.Builder
+	b.WriteString("# MCP Servers\n\n")
+	fmt.Fprintf(&b, "Generated from `%s` by `mcp docs`.\n\n", composeName)
+
+	if len(servers) == 0 {
+		b.WriteString("No servers found.\n")
+		return b.String()
+	}
+
+	var names []string
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString("| Server | Type | Profiles | Deployed to | Description |\n")
+	b.WriteString("| ------ | ---- | -------- | ----------- | ----------- |\n")
+	for _, name := range names {
+		service := servers[name]
+		fmt.Fprintf(&b, "| [%s](#%s) | %s | %s | %s | %s |\n", name, markdownAnchor(name), serverType(service),
+			strings.Join(docsProfiles(service), ", "), docsDeployedTo(deployed[name]), markdownCell(GetDescription(service)))
+	}
+
+	for _, name := range names {
+		service := servers[name]
+		fmt.Fprintf(&b, "\n## %s\n\n", name)
+		if desc := GetDescription(service); desc != "" {
+			fmt.Fprintf(&b, "%s\n\n", desc)
+		}
+		fmt.Fprintf(&b, "- **Type:** %s\n", serverType(service))
+		fmt.Fprintf(&b, "- **Profiles:** %s\n", strings.Join(docsProfiles(service), ", "))
+		fmt.Fprintf(&b, "- **Deployed to:** %s\n", docsDeployedTo(deployed[name]))
+		if IsRemoteServer(service) {
+			fmt.Fprintf(&b, "- **URL:** `%s`\n", service.Command)
+		}
+
+		b.WriteString("\n### Required environment\n\n")
+		usages := collectEnvVarUsages(map[string]Service{name: service}, nil)
+		if len(usages) == 0 {
+			b.WriteString("None\n")
+			continue
+		}
+		b.WriteString("| Variable | Description |\n")
+		b.WriteString("| -------- | ----------- |\n")
+		for _, usage := range usages {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", usage.Name, markdownCell(usage.Doc))
+		}
+	}
+	return b.String()
+}
+
+// docsProfiles returns a service's profiles, or "default" if it has none
+func docsProfiles(service Service) []string {
+	if profiles := GetProfiles(service); len(profiles) > 0 {
+		return profiles
+	}
+	return []string{"default"}
+}
+
+// docsDeployedTo lists the targets a server is deployed to
+func docsDeployedTo(targets []string) string {
+	if len(targets) == 0 {
+		return "not deployed"
+	}
+	return strings.Join(targets, ", ")
+}
+
+// markdownAnchor returns the heading anchor GitHub generates for a name
+func markdownAnchor(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServersMarkdown(t *testing.T) {
+	servers := map[string]Service{
+		"github": {
+			Command: "npx -y @modelcontextprotocol/server-github",
+			Environment: map[string]string{
+				"GITHUB_TOKEN": "${GITHUB_TOKEN}",
+			},
+			Labels: map[string]string{
+				"mcp.profile":              "programming",
+				"mcp.description":          "GitHub | issues and PRs",
+				"mcp.env-doc.GITHUB_TOKEN": "Personal access token",
+			},
+		},
+		"filesystem": {Image: "mcp/filesystem"},
+		"api":        {Command: "https://api.example.com/mcp"},
+	}
+	deployed := map[string][]string{"github": {"kiro", "cursor"}}
+
+	doc := serversMarkdown("mcp-compose.yml", servers, deployed)
+
+	for _, want := range []string{
+		"Generated from `mcp-compose.yml` by `mcp docs`.",
+		"| [api](#api) | remote | default | not deployed |  |",
+		"| [filesystem](#filesystem) | container | default | not deployed |  |",
+		"| [github](#github) | local | programming | kiro, cursor | GitHub \\| issues and PRs |",
+		"- **URL:** `https://api.example.com/mcp`",
+		"| `GITHUB_TOKEN` | Personal access token |",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected docs to contain %q, got:\n%s", want, doc)
+		}
+	}
+
+	// Sections follow the table in name order
+	if a, f, g := strings.Index(doc, "## api"), strings.Index(doc, "## filesystem"), strings.Index(doc, "## github"); !(a < f && f < g) {
+		t.Errorf("Expected server sections sorted by name, got:\n%s", doc)
+	}
+}
+
+func TestDeployedTools(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	kiroPath, err := getPlatformToolPath("kiro")
+	if err != nil {
+		t.Fatalf("Failed to resolve kiro path: %v", err)
+	}
+	os.MkdirAll(filepath.Dir(kiroPath), 0755)
+	config := MCPConfig{MCPServers: map[string]MCPServer{"github": {Command: "npx"}}}
+	if err := writeToolConfig(config, kiroPath, ""); err != nil {
+		t.Fatalf("Failed to write kiro config: %v", err)
+	}
+
+	deployed := deployedTools([]string{"kiro", "cursor"})
+	want := map[string][]string{"github": {"kiro"}}
+	if !reflect.DeepEqual(deployed, want) {
+		t.Errorf("Expected %v, got %v", want, deployed)
+	}
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Doctor check results
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// doctorEndpointTimeout bounds how long doctor waits for each OAuth token endpoint
+const doctorEndpointTimeout = 5 * time.Second
+
+// lookPath finds executables on PATH; tests replace it
+var lookPath = exec.LookPath
+
+// binaryFixes suggests how to install commonly used server launchers
+var binaryFixes = map[string]string{
+	"docker": "Install Docker Desktop, or use another container tool with 'mcp config set container-tool podman'",
+	"podman": "Install Podman, or use another container tool with 'mcp config set container-tool docker'",
+	"uvx":    "Install uv (https://docs.astral.sh/uv/), which provides uvx",
+	"npx":    "Install Node.js (https://nodejs.org/), which provides npx",
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose problems with the compose file and environment",
+	Long: `Check the environment end to end: the compose file parses, the .env file
+exists and referenced variables resolve, the binaries servers are launched with
+(docker, podman, uvx, npx, ...) are on PATH, tool config directories are writable,
+and OAuth token endpoints are reachable.
+Each finding comes with a suggested fix. Exits non-zero if any check fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		checks := runDoctor(cmd.Context(), composeFile)
+		return reportChecks(os.Stdout, checks)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// doctorCheck is the result of one diagnostic
+type doctorCheck struct {
+	Name    string
+	Status  string // checkOK, checkWarn, or checkFail
+	Message string
+	Fix     string // suggested fix when Status isn't checkOK
+}
+
+// runDoctor runs every diagnostic against a compose file
+func runDoctor(ctx context.Context, composePath string) []doctorCheck {
+	var checks []doctorCheck
+
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		checks = append(checks, doctorCheck{
+			Name:    "Compose file",
+			Status:  checkFail,
+			Message: fmt.Sprintf("%s could not be loaded: %v", composePath, err),
+			Fix:     "Create it with 'mcp add --template <name>', or fix the YAML and run 'mcp validate'",
+		})
+		return checks
+	}
+	checks = append(checks, doctorCheck{
+		Name:    "Compose file",
+		Status:  checkOK,
+		Message: fmt.Sprintf("%s parses (%d servers)", composePath, len(config.Services)),
+	})
+
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		checks = append(checks, doctorCheck{
+			Name:    "Environment",
+			Status:  checkFail,
+			Message: err.Error(),
+			Fix:     "Check that the .env file next to the compose file is readable",
+		})
+		return checks
+	}
+
+	checks = append(checks, checkEnvFile(composePath, config.Services, envVars)...)
+	checks = append(checks, checkBinaries(config.Services, envVars)...)
+	checks = append(checks, checkToolDirs(detectInstalledTools())...)
+	checks = append(checks, checkTokenEndpoints(ctx, config.Services, envVars)...)
+
+	return checks
+}
+
+// checkEnvFile checks for the .env file and that every referenced variable resolves
+func checkEnvFile(composePath string, services map[string]Service, envVars map[string]string) []doctorCheck {
+	var checks []doctorCheck
+
+	envPath := filepath.Join(filepath.Dir(composePath), ".env")
+	if fileExists(envPath) {
+		checks = append(checks, doctorCheck{Name: ".env file", Status: checkOK, Message: envPath + " found"})
+	} else {
+		checks = append(checks, doctorCheck{
+			Name:    ".env file",
+			Status:  checkWarn,
+			Message: envPath + " not found; only exported variables will be used",
+			Fix:     "Create " + envPath + " to keep secrets out of your shell profile",
+		})
+	}
+
+	missing := missingEnvVarUsages(collectEnvVarUsages(services, envVars))
+	if len(missing) == 0 {
+		checks = append(checks, doctorCheck{Name: "Environment", Status: checkOK, Message: "all referenced variables are set"})
+		return checks
+	}
+
+	for _, usage := range missing {
+		value := "..."
+		if usage.Doc != "" {
+			value = "<" + usage.Doc + ">"
+		}
+		checks = append(checks, doctorCheck{
+			Name:    "Environment",
+			Status:  checkFail,
+			Message: fmt.Sprintf("%s is not set (used by %s)", usage.Name, strings.Join(usage.Servers, ", ")),
+			Fix:     fmt.Sprintf("Add %s=%s to %s or export it in your shell", usage.Name, value, envPath),
+		})
+	}
+	return checks
+}
+
+// checkBinaries checks that the executables servers are launched with are on PATH
+func checkBinaries(services map[string]Service, envVars map[string]string) []doctorCheck {
+	usedBy := make(map[string][]string)
+	for name, service := range services {
+		var binary string
+		switch {
+		case IsRemoteServerWithEnvExpansion(service, envVars):
+			continue
+		case service.Image != "":
+			binary = getContainerTool()
+		default:
+			parts := strings.Fields(expandEnvVars(service.Command, envVars))
+			if len(parts) == 0 {
+				continue
+			}
+			binary = parts[0]
+		}
+		usedBy[binary] = append(usedBy[binary], name)
+	}
+
+	var binaries []string
+	for binary := range usedBy {
+		binaries = append(binaries, binary)
+	}
+	sort.Strings(binaries)
+
+	var checks []doctorCheck
+	for _, binary := range binaries {
+		servers := usedBy[binary]
+		sort.Strings(servers)
+
+		if path, err := lookPath(binary); err == nil {
+			checks = append(checks, doctorCheck{Name: "Binary", Status: checkOK, Message: fmt.Sprintf("%s found at %s", binary, path)})
+			continue
+		}
+
+		fix, ok := binaryFixes[filepath.Base(binary)]
+		if !ok {
+			fix = fmt.Sprintf("Install %s or add it to your PATH", binary)
+		}
+		checks = append(checks, doctorCheck{
+			Name:    "Binary",
+			Status:  checkFail,
+			Message: fmt.Sprintf("%s not found on PATH (used by %s)", binary, strings.Join(servers, ", ")),
+			Fix:     fix,
+		})
+	}
+	return checks
+}
+
+// checkToolDirs checks that each tool's config directory can be written
+func checkToolDirs(tools []string) []doctorCheck {
+	if len(tools) == 0 {
+		return []doctorCheck{{
+			Name:    "Tool configs",
+			Status:  checkWarn,
+			Message: "no supported tools found on this machine",
+			Fix:     "Install a supported tool, or deploy to a file with 'mcp set -c <path>'",
+		}}
+	}
+
+	var checks []doctorCheck
+	for _, tool := range tools {
+		path, err := getScopedToolPath(tool, configScope)
+		if err != nil || path == "" {
+			continue
+		}
+
+		dir := existingParent(filepath.Dir(path))
+		if err := checkWritable(dir); err != nil {
+			checks = append(checks, doctorCheck{
+				Name:    "Tool configs",
+				Status:  checkFail,
+				Message: fmt.Sprintf("%s: %s is not writable: %v", tool, dir, err),
+				Fix:     fmt.Sprintf("Fix the permissions of %s", dir),
+			})
+			continue
+		}
+		checks = append(checks, doctorCheck{Name: "Tool configs", Status: checkOK, Message: fmt.Sprintf("%s: %s is writable", tool, filepath.Dir(path))})
+	}
+	return checks
+}
+
+// existingParent returns dir or its closest existing ancestor, which is where
+// writing the config would first need permissions
+func existingParent(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// checkWritable checks that a file can be created in dir
+func checkWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".mcp-doctor-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// checkTokenEndpoints checks that the OAuth token endpoint of every remote server responds
+func checkTokenEndpoints(ctx context.Context, services map[string]Service, envVars map[string]string) []doctorCheck {
+	var names []string
+	for name, service := range services {
+		if IsRemoteServerWithEnvExpansion(service, envVars) && service.Labels["mcp.token-endpoint"] != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	client := &http.Client{Timeout: doctorEndpointTimeout}
+	var checks []doctorCheck
+	for _, name := range names {
+		endpoint := expandEnvVars(services[name].Labels["mcp.token-endpoint"], envVars)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+		if err == nil {
+			var resp *http.Response
+			resp, err = client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}
+
+		// Any HTTP response means the endpoint is reachable; credentials aren't checked
+		if err != nil {
+			checks = append(checks, doctorCheck{
+				Name:    "OAuth",
+				Status:  checkFail,
+				Message: fmt.Sprintf("%s: token endpoint %s is not reachable: %v", name, endpoint, err),
+				Fix:     "Check the mcp.token-endpoint label, your network connection, and any VPN or proxy settings",
+			})
+			continue
+		}
+		checks = append(checks, doctorCheck{Name: "OAuth", Status: checkOK, Message: fmt.Sprintf("%s: token endpoint %s is reachable", name, endpoint)})
+	}
+	return checks
+}
+
+// reportChecks prints each check with its fix and returns a silent
+// ExitError if any check failed
+func reportChecks(w io.Writer, checks []doctorCheck) error {
+	failures, warnings := 0, 0
+	for _, check := range checks {
+		var symbol string
+		switch check.Status {
+		case checkOK:
+			symbol = "✓"
+		case checkWarn:
+			symbol = "!"
+			warnings++
+		default:
+			symbol = "✗"
+			failures++
+		}
+
+		fmt.Fprintf(w, "%s %s: %s\n", symbol, check.Name, check.Message)
+		if check.Status != checkOK && check.Fix != "" {
+			fmt.Fprintf(w, "    → %s\n", check.Fix)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d failed, %d warnings\n", failures, warnings)
+	if failures > 0 {
+		return &ExitError{Code: exitCodeError}
+	}
+	return nil
+}
+
+// containsString reports whether a slice contains a string
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
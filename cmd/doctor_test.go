@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// findCheck returns the first check whose message contains substr
+func findCheck(checks []doctorCheck, substr string) (doctorCheck, bool) {
+	for _, check := range checks {
+		if strings.Contains(check.Message, substr) {
+			return check, true
+		}
+	}
+	return doctorCheck{}, false
+}
+
+func TestRunDoctor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DOCTOR_SET_VAR", "value")
+
+	originalLookPath := lookPath
+	defer func() { lookPath = originalLookPath }()
+	lookPath = func(file string) (string, error) {
+		if file == "uvx" {
+			return "/usr/local/bin/uvx", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer tokenServer.Close()
+
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+    environment:
+      TZ: ${DOCTOR_SET_VAR}
+  github:
+    command: npx -y @modelcontextprotocol/server-github
+    environment:
+      GITHUB_TOKEN: ${DOCTOR_MISSING_VAR}
+  api:
+    command: https://api.example.com/mcp
+    labels:
+      mcp.grant-type: client_credentials
+      mcp.token-endpoint: `+tokenServer.URL+`
+      mcp.client-id: id
+      mcp.client-secret: secret
+`), 0644)
+
+	checks := runDoctor(context.Background(), composePath)
+
+	expectations := []struct {
+		substr string
+		status string
+	}{
+		{"parses (3 servers)", checkOK},
+		{".env not found", checkWarn},
+		{"DOCTOR_MISSING_VAR is not set (used by github)", checkFail},
+		{"uvx found", checkOK},
+		{"npx not found on PATH (used by github)", checkFail},
+		{"no supported tools found", checkWarn},
+		{"api: token endpoint " + tokenServer.URL + " is reachable", checkOK},
+	}
+	for _, e := range expectations {
+		check, ok := findCheck(checks, e.substr)
+		if !ok {
+			t.Errorf("Expected a check containing %q, got %+v", e.substr, checks)
+			continue
+		}
+		if check.Status != e.status {
+			t.Errorf("Check %q: expected status %s, got %s", e.substr, e.status, check.Status)
+		}
+		if check.Status != checkOK && check.Fix == "" {
+			t.Errorf("Check %q: expected a fix suggestion", e.substr)
+		}
+	}
+}
+
+func TestRunDoctorMissingComposeFile(t *testing.T) {
+	checks := runDoctor(context.Background(), filepath.Join(t.TempDir(), "missing.yml"))
+	if len(checks) != 1 || checks[0].Status != checkFail {
+		t.Fatalf("Expected a single failed check, got %+v", checks)
+	}
+}
+
+func TestCheckToolDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	checks := checkToolDirs([]string{"kiro"})
+	if len(checks) != 1 || checks[0].Status != checkOK {
+		t.Errorf("Expected kiro dir to be writable, got %+v", checks)
+	}
+}
+
+func TestReportChecks(t *testing.T) {
+	var out bytes.Buffer
+	err := reportChecks(&out, []doctorCheck{
+		{Name: "Binary", Status: checkOK, Message: "uvx found"},
+		{Name: "Binary", Status: checkFail, Message: "npx not found", Fix: "Install Node.js"},
+	})
+
+	if ExitCode(err) != exitCodeError {
+		t.Errorf("Expected exit code %d, got %d", exitCodeError, ExitCode(err))
+	}
+	expected := "✓ Binary: uvx found\n✗ Binary: npx not found\n    → Install Node.js\n\n1 failed, 0 warnings\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// disableCmd represents the disable command
+var disableCmd = &cobra.Command{
+	Use:   "disable <server...>",
+	Short: "Turn off servers in a deployed tool config",
+	Long: `Turn off servers in a tool's MCP configuration without regenerating it.
+Tools with a native flag (e.g. kiro) get "disabled": true on the server's entry;
+for other tools the entry is removed so 'mcp enable' can restore it: a server of
+the compose file is converted again then, and the entry of one added by hand is
+remembered as it was.
+Disabled servers are tracked in ~/.config/mcp/state.json, so 'mcp set', 'mcp sync',
+'mcp watch', and 'mcp apply' keep them off until they are enabled again.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := toggleConfigPath()
+		if err != nil {
+			return err
+		}
+		return disableServers(os.Stdout, path, toolShortcut, args)
+	},
+}
+
+// enableCmd represents the enable command
+var enableCmd = &cobra.Command{
+	Use:   "enable <server...>",
+	Short: "Turn servers disabled with 'mcp disable' back on",
+	Long: `Turn servers back on in a tool's MCP configuration: the native "disabled" flag
+is cleared, or the entry removed by 'mcp disable' is restored, converted again
+from the compose file, or as it was when disabled for a server added by hand.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := toggleConfigPath()
+		if err != nil {
+			return err
+		}
+		return enableServers(cmd.Context(), os.Stdout, path, toolShortcut, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(disableCmd)
+	rootCmd.AddCommand(enableCmd)
+	for _, cmd := range []*cobra.Command{disableCmd, enableCmd} {
+		cmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to the MCP JSON configuration file")
+		cmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Tool shortcut (q-cli, q-ide, claude-desktop, cursor, kiro)")
+		cmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+	}
+}
+
+// toggleConfigPath resolves the tool config enable and disable change
+func toggleConfigPath() (string, error) {
+	envVars, err := loadEnvVars(composeFile)
+	if err != nil {
+		return "", newConfigError("load environment variables", composeFile, err)
+	}
+	return getOutputPath(envVars)
+}
+
+// disableServers turns servers off in the config at path, using the tool's
+// native flag if it has one and removing the entries otherwise
+func disableServers(w io.Writer, path, tool string, names []string) error {
+	config, err := readMCPConfig(path)
+	if err != nil {
+		return newConfigError("load tool config", path, err)
+	}
+	state, err := loadState()
+	if err != nil {
+		return newConfigError("load state", "", err)
+	}
+
+	key := stateKey(path)
+	disabled := state.Disabled[key]
+	if disabled == nil {
+		disabled = make(map[string]MCPServer)
+	}
+
+	// Servers of the compose file are converted again when enabled, so only
+	// the entries of servers added by hand are remembered
+	var services map[string]Service
+	if compose, err := loadComposeFile(composeFile); err == nil {
+		services = compose.Services
+	}
+	managed := managedServerFunc(path, services)
+
+	native := toolSupportsDisabled(tool)
+	var messages []string
+	for _, name := range names {
+		server, exists := config.MCPServers[name]
+		if _, tracked := disabled[name]; tracked && (!exists || server.Disabled) {
+			messages = append(messages, fmt.Sprintf("%s is already disabled in %s", name, path))
+			continue
+		}
+		if !exists {
+			return newValidationError("server '%s' not found in %s", name, path)
+		}
+
+		if native {
+			server.Disabled = true
+			config.MCPServers[name] = server
+			messages = append(messages, fmt.Sprintf("Disabled %s in %s", name, path))
+		} else {
+			delete(config.MCPServers, name)
+			messages = append(messages, fmt.Sprintf("Disabled %s in %s (removed until 'mcp enable %s')", name, path, name))
+		}
+		server.Disabled = false
+		if native || managed(name) {
+			server = MCPServer{}
+		}
+		disabled[name] = server
+	}
+
+	if state.Disabled == nil {
+		state.Disabled = make(map[string]map[string]MCPServer)
+	}
+	state.Disabled[key] = disabled
+	return saveToggledConfig(w, path, tool, config, state, messages)
+}
+
+// enableServers turns servers disabled with disableServers back on
+func enableServers(ctx context.Context, w io.Writer, path, tool string, names []string) error {
+	config, err := readMCPConfig(path)
+	if err != nil {
+		return newConfigError("load tool config", path, err)
+	}
+	state, err := loadState()
+	if err != nil {
+		return newConfigError("load state", "", err)
+	}
+
+	key := stateKey(path)
+	disabled := state.Disabled[key]
+	var messages []string
+	for _, name := range names {
+		entry, tracked := disabled[name]
+		server, exists := config.MCPServers[name]
+		switch {
+		case exists && server.Disabled:
+			server.Disabled = false
+			config.MCPServers[name] = server
+		case exists && !tracked:
+			messages = append(messages, fmt.Sprintf("%s is already enabled in %s", name, path))
+			continue
+		case !exists && tracked:
+			if entry.Command == "" && entry.URL == "" {
+				resolved, err := convertDisabledServer(ctx, name, tool)
+				if err != nil {
+					return err
+				}
+				entry = resolved.MCPServers[name]
+				for _, input := range resolved.Inputs {
+					if !slices.ContainsFunc(config.Inputs, func(other ConfigInput) bool { return other.ID == input.ID }) {
+						config.Inputs = append(config.Inputs, input)
+					}
+				}
+			}
+			if config.MCPServers == nil {
+				config.MCPServers = make(map[string]MCPServer)
+			}
+			config.MCPServers[name] = entry
+		case !exists:
+			return newValidationError("server '%s' not found in %s", name, path)
+		}
+		delete(disabled, name)
+		messages = append(messages, fmt.Sprintf("Enabled %s in %s", name, path))
+	}
+
+	if len(disabled) == 0 {
+		delete(state.Disabled, key)
+	}
+	return saveToggledConfig(w, path, tool, config, state, messages)
+}
+
+// convertDisabledServer converts a server of the compose file again for
+// enableServers, as 'mcp set' would write it to tool
+func convertDisabledServer(ctx context.Context, name, tool string) (MCPConfig, error) {
+	compose, err := loadComposeFile(composeFile)
+	if err != nil {
+		return MCPConfig{}, newConfigError("load compose file", composeFile, err)
+	}
+	service, ok := compose.Services[name]
+	if !ok {
+		return MCPConfig{}, newValidationError("server '%s' is no longer defined in %s; run 'mcp set' instead", name, composeFile)
+	}
+	envVars, err := loadEnvVars(composeFile)
+	if err != nil {
+		return MCPConfig{}, newConfigError("load environment variables", composeFile, err)
+	}
+
+	servers := map[string]Service{name: service}
+	config, err := convertToMCPConfig(ctx, servers, envVars)
+	if err != nil {
+		return MCPConfig{}, err
+	}
+	config, _ = stripUnsupportedFields(config, tool)
+	return applyToolInputs(config, tool, servers, envVars), nil
+}
+
+// saveToggledConfig writes the tool config and then the state that tracks it
+func saveToggledConfig(w io.Writer, path, tool string, config MCPConfig, state cliState, messages []string) error {
+	if err := writeToolConfig(config, path, tool); err != nil {
+		return newConfigError("write MCP config", path, err)
+	}
+	if err := saveState(state); err != nil {
+		return newConfigError("save state", "", err)
+	}
+	for _, message := range messages {
+		fmt.Fprintln(w, message)
+	}
+	return nil
+}
+
+// applyDisabledServers keeps the servers disabled in the config at path off
+// in a freshly generated config, returning a copy and the names kept off
+func applyDisabledServers(config MCPConfig, path, tool string) (MCPConfig, []string, error) {
+	state, err := loadState()
+	if err != nil {
+		return config, nil, newConfigError("load state", "", err)
+	}
+	disabled := state.Disabled[stateKey(path)]
+	if len(disabled) == 0 {
+		return config, nil, nil
+	}
+
+	result := MCPConfig{MCPServers: make(map[string]MCPServer, len(config.MCPServers))}
+	for name, server := range config.MCPServers {
+		result.MCPServers[name] = server
+	}
+
+	native := toolSupportsDisabled(tool)
+	var kept []string
+	for name := range disabled {
+		server, exists := result.MCPServers[name]
+		if !exists {
+			continue
+		}
+		if native {
+			server.Disabled = true
+			result.MCPServers[name] = server
+		} else {
+			delete(result.MCPServers, name)
+		}
+		kept = append(kept, name)
+	}
+	sort.Strings(kept)
+	return result, kept, nil
+}
+
+// printDisabledServers notes the servers a write kept disabled
+func printDisabledServers(w io.Writer, names []string) {
+	if len(names) > 0 {
+		fmt.Fprintf(w, "Kept disabled: %s (turn back on with 'mcp enable')\n", strings.Join(names, ", "))
+	}
+}
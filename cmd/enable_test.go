@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDisableEnableRemovesEntries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	github := MCPServer{Command: "docker", Args: []string{"run", "-i", "--rm", "ghcr.io/github/github-mcp-server"}}
+	writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{
+		"time":   {Command: "uvx", Args: []string{"mcp-server-time"}},
+		"github": github,
+	}}, path, "")
+
+	var out bytes.Buffer
+	if err := disableServers(&out, path, "", []string{"github"}); err != nil {
+		t.Fatalf("disableServers failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "removed until 'mcp enable github'") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+	config, _ := readMCPConfig(path)
+	if _, exists := config.MCPServers["github"]; exists {
+		t.Error("Expected github to be removed from the config")
+	}
+
+	// Regenerating the config keeps it off
+	generated := MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx"}, "github": github}}
+	applied, kept, err := applyDisabledServers(generated, path, "")
+	if err != nil {
+		t.Fatalf("applyDisabledServers failed: %v", err)
+	}
+	if _, exists := applied.MCPServers["github"]; exists || !reflect.DeepEqual(kept, []string{"github"}) {
+		t.Errorf("Expected github to be kept disabled, got %v (kept %v)", applied.MCPServers, kept)
+	}
+	if _, exists := generated.MCPServers["github"]; !exists {
+		t.Error("applyDisabledServers must not modify its input")
+	}
+
+	out.Reset()
+	disableServers(&out, path, "", []string{"github"})
+	if !strings.Contains(out.String(), "github is already disabled") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+
+	out.Reset()
+	if err := enableServers(context.Background(), &out, path, "", []string{"github"}); err != nil {
+		t.Fatalf("enableServers failed: %v", err)
+	}
+	config, _ = readMCPConfig(path)
+	if !reflect.DeepEqual(config.MCPServers["github"], github) {
+		t.Errorf("Expected github to be restored, got %+v", config.MCPServers["github"])
+	}
+	state, _ := loadState()
+	if len(state.Disabled) != 0 {
+		t.Errorf("Expected the state to be cleared, got %v", state.Disabled)
+	}
+}
+
+func TestDisableEnableNativeFlag(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx"}}}, path, "")
+
+	var out bytes.Buffer
+	if err := disableServers(&out, path, "kiro", []string{"time"}); err != nil {
+		t.Fatalf("disableServers failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"disabled": true`) {
+		t.Errorf("Expected the native disabled flag, got:\n%s", data)
+	}
+
+	applied, _, _ := applyDisabledServers(MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx"}}}, path, "kiro")
+	if !applied.MCPServers["time"].Disabled {
+		t.Error("Expected regenerated configs to keep the disabled flag")
+	}
+
+	if err := enableServers(context.Background(), &out, path, "kiro", []string{"time"}); err != nil {
+		t.Fatalf("enableServers failed: %v", err)
+	}
+	config, _ := readMCPConfig(path)
+	if config.MCPServers["time"].Disabled {
+		t.Error("Expected the disabled flag to be cleared")
+	}
+
+	out.Reset()
+	enableServers(context.Background(), &out, path, "kiro", []string{"time"})
+	if !strings.Contains(out.String(), "time is already enabled") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+}
+
+func TestDisableUnknownServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx"}}}, path, "")
+
+	err := disableServers(&bytes.Buffer{}, path, "", []string{"time", "missing"})
+	if ExitCode(err) != exitCodeValidation {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	// Nothing is written when any server is unknown
+	config, _ := readMCPConfig(path)
+	if _, exists := config.MCPServers["time"]; !exists {
+		t.Error("Expected the config to be unchanged")
+	}
+	if err := enableServers(context.Background(), &bytes.Buffer{}, path, "", []string{"missing"}); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestDisableEnableVSCodeFormat(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := setupVSCodeTool(t, map[string]MCPServer{
+		"time":   {Command: "uvx"},
+		"github": {Command: "npx"},
+	})
+
+	if err := disableServers(&bytes.Buffer{}, path, "vscode", []string{"time"}); err != nil {
+		t.Fatalf("disableServers failed: %v", err)
+	}
+	servers := readVSCodeServers(t, path)
+	if _, exists := servers["time"]; exists || len(servers) != 1 {
+		t.Errorf("Expected only github under servers, got %v", servers)
+	}
+
+	if err := enableServers(context.Background(), &bytes.Buffer{}, path, "vscode", []string{"time"}); err != nil {
+		t.Fatalf("enableServers failed: %v", err)
+	}
+	if servers := readVSCodeServers(t, path); len(servers) != 2 {
+		t.Errorf("Expected time to be restored under servers, got %v", servers)
+	}
+}
+
+func TestDisableKeepsNoSecretsInState(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+	originalComposeFile := composeFile
+	defer func() { composeFile = originalComposeFile }()
+	composeFile = filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(composeFile, []byte(`services:
+  github:
+    command: npx -y @modelcontextprotocol/server-github
+    environment:
+      GITHUB_TOKEN: ${GITHUB_TOKEN}
+`), 0644)
+
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	github := MCPServer{Command: "npx", Args: []string{"-y", "@modelcontextprotocol/server-github"}, Env: map[string]string{"GITHUB_TOKEN": "ghp_secret"}}
+	mine := MCPServer{Command: "mine", Env: map[string]string{"TOKEN": "mine_secret"}}
+	writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{"github": github, "mine": mine}}, path, "")
+
+	if err := disableServers(&bytes.Buffer{}, path, "", []string{"github", "mine"}); err != nil {
+		t.Fatalf("disableServers failed: %v", err)
+	}
+	statePath, _ := getStatePath()
+	data, _ := os.ReadFile(statePath)
+	if strings.Contains(string(data), "ghp_secret") {
+		t.Errorf("Expected a server of the compose file to be kept without its secrets, got:\n%s", data)
+	}
+	if info, err := os.Stat(statePath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the state file to be readable only by the user, got %v (%v)", info.Mode().Perm(), err)
+	}
+
+	if err := enableServers(context.Background(), &bytes.Buffer{}, path, "", []string{"github", "mine"}); err != nil {
+		t.Fatalf("enableServers failed: %v", err)
+	}
+	config, _ := readMCPConfig(path)
+	if !reflect.DeepEqual(config.MCPServers["github"], github) {
+		t.Errorf("Expected github to be converted again from the compose file, got %+v", config.MCPServers["github"])
+	}
+	if !reflect.DeepEqual(config.MCPServers["mine"], mine) {
+		t.Errorf("Expected the hand-added server to be restored as it was, got %+v", config.MCPServers["mine"])
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"os"
@@ -140,7 +141,10 @@ HOME=/Users/testuser
 
 	// Step 4: Test MCP configuration generation (like set command does)
 	defaultServers := filterServers(config, "", false)
-	mcpConfig := convertToMCPConfig(defaultServers, envVars)
+	mcpConfig, err := convertToMCPConfig(context.Background(), defaultServers, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
 
 	if len(mcpConfig.MCPServers) != 3 {
 		t.Errorf("Expected 3 MCP servers, got %d", len(mcpConfig.MCPServers))
@@ -201,7 +205,7 @@ HOME=/Users/testuser
 		}
 	}
 
-	// Step 5: Test JSON serialization (like writeMCPConfig does)
+	// Step 5: Test JSON serialization (like writeToolConfig does)
 	jsonData, err := json.MarshalIndent(mcpConfig, "", "  ")
 	if err != nil {
 		t.Fatalf("Failed to marshal MCP config: %v", err)
@@ -238,7 +242,10 @@ HOME=/Users/testuser
 
 	// Step 8: Test that all existing functionality works with container servers
 	productivityServers := filterServers(config, "productivity", false)
-	productivityConfig := convertToMCPConfig(productivityServers, envVars)
+	productivityConfig, err := convertToMCPConfig(context.Background(), productivityServers, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
 
 	weatherServer, exists := productivityConfig.MCPServers["weather-server"]
 	if !exists {
@@ -3,13 +3,224 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
 )
 
-// loadEnvVars loads environment variables from the system and .env file
+// envDocLabelPrefix is the label family documenting the variables a server
+// needs, e.g. mcp.env-doc.GITHUB_TOKEN: "GitHub PAT with repo scope"
+const envDocLabelPrefix = "mcp.env-doc."
+
+// envAllServers includes servers outside the selected profile in env reports
+var envAllServers bool
+
+// envCmd represents the env command
+var envCmd = &cobra.Command{
+	Use:   "env [profile]",
+	Short: "List the environment variables servers reference",
+	Long: `List every environment variable referenced by the servers of a profile,
+whether it is set (in the environment or the .env file next to the compose file),
+which servers use it, and its documentation from mcp.env-doc.<VAR> labels.
+Without arguments, it reports on the default servers. With the -a flag, it
+reports on all servers. Values are never shown.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		usages, err := loadEnvVarUsages(args)
+		if err != nil {
+			return err
+		}
+		displayEnvVarUsages(os.Stdout, usages)
+		return nil
+	},
+}
+
+// envCheckCmd represents the env check command
+var envCheckCmd = &cobra.Command{
+	Use:   "check [profile]",
+	Short: "Check that every referenced environment variable is set",
+	Long: `Check that every environment variable referenced by the servers of a profile
+is set. Each missing variable is printed with the servers that use it and its
+documentation from mcp.env-doc.<VAR> labels, so you know what to set before
+deploying. Exits non-zero if any variable is missing.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		usages, err := loadEnvVarUsages(args)
+		if err != nil {
+			return err
+		}
+		return checkEnvVarUsages(os.Stdout, usages)
+	},
+}
+
+// envShowSecrets reveals secret values in 'mcp env show'
+var envShowSecrets bool
+
+// envShowCmd represents the env show command
+var envShowCmd = &cobra.Command{
+	Use:   "show <server>",
+	Short: "Show a server's fully expanded command line, environment, and headers",
+	Long: `Print a server's command line (or URL), environment variables, and headers
+with every variable expanded, exactly as 'mcp set' would write them, to debug
+variable expansion without opening the generated JSON.
+Secrets are masked: values of variables and headers whose names look like
+secrets, and values in well-known credential formats. Use --show-secrets to
+reveal them. OAuth tokens are not acquired, so they show as a placeholder.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+		service, exists := config.Services[args[0]]
+		if !exists {
+			return newValidationError("server '%s' not found in %s", args[0], composeFile)
+		}
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+
+		resolved, err := buildExpectedConfig(cmd.Context(), map[string]Service{args[0]: service}, envVars)
+		if err != nil {
+			return err
+		}
+		server := resolved.MCPServers[args[0]]
+
+		var secrets []string
+		if !envShowSecrets {
+			secrets = secretValues(service, server, envVars)
+		}
+		printResolvedServer(os.Stdout, args[0], server, secrets)
+		return nil
+	},
+}
+
+// envExampleOutput is where 'mcp env-example' writes, or - for stdout
+var envExampleOutput string
+
+// envExampleCmd represents the env-example command
+var envExampleCmd = &cobra.Command{
+	Use:   "env-example",
+	Short: "Generate a .env.example documenting every referenced variable",
+	Long: `Write a .env.example next to the compose file with a placeholder for every
+environment variable referenced by any server (in commands, images, environment,
+volumes, and labels), commented with its mcp.env-doc.<VAR> documentation and
+the servers that use it. Commit it so teammates know what to put in their .env.
+Values are never copied. Use -o to write elsewhere, or -o - to print it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+		content := envExampleFile(filepath.Base(composeFile), collectEnvVarUsages(config.Services, nil))
+
+		if envExampleOutput == "-" {
+			fmt.Print(content)
+			return nil
+		}
+		path := envExampleOutput
+		if path == "" {
+			path = filepath.Join(filepath.Dir(composeFile), ".env.example")
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return newConfigError("write env example", path, err)
+		}
+		fmt.Printf("Wrote %s\n", path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(envExampleCmd)
+	envCmd.AddCommand(envCheckCmd)
+	envCmd.AddCommand(envShowCmd)
+	envCmd.PersistentFlags().BoolVarP(&envAllServers, "all", "a", false, "Include all servers")
+	envShowCmd.Flags().BoolVar(&envShowSecrets, "show-secrets", false, "Reveal secret values instead of masking them")
+	envExampleCmd.Flags().StringVarP(&envExampleOutput, "output", "o", "", "Write to this file instead of .env.example next to the compose file (- for stdout)")
+}
+
+// envExampleFile renders a .env.example with a placeholder and comments for each variable
+func envExampleFile(composeName string, usages []envVarUsage) string {
+	var b strings.Builder
+	b.WriteString("# Environment variables for " + composeName + "\n")
+	b.WriteString("# Copy to .env and fill in the values; keep .env out of version control\n")
+	for _, usage := range usages {
+		b.WriteString("\n")
+		if usage.Doc != "" {
+			b.WriteString("# " + usage.Doc + "\n")
+		}
+		fmt.Fprintf(&b, "# Used by: %s\n", strings.Join(usage.Servers, ", "))
+		fmt.Fprintf(&b, "%s=%s\n", usage.Name, envPlaceholder(usage.Name))
+	}
+	return b.String()
+}
+
+// envPlaceholder suggests a placeholder value for a variable, e.g.
+// GITHUB_TOKEN -> your-github-token
+func envPlaceholder(name string) string {
+	return "your-" + strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
+
+// envOverrides holds the variables given with --set, which take precedence
+// over the environment and the .env file
+var envOverrides = envOverrideFlag{}
+
+// envOverrideFlag is the repeatable --set KEY=VALUE flag
+type envOverrideFlag map[string]string
+
+// envVarNamePattern matches a valid variable name
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (f envOverrideFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for _, key := range sortedKeys(f) {
+		pairs = append(pairs, key+"="+f[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f envOverrideFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || !envVarNamePattern.MatchString(key) {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	f[key] = val
+	return nil
+}
+
+func (f envOverrideFlag) Type() string { return "KEY=VALUE" }
+
+// addEnvOverrideFlag adds the --set flag to a command that loads variables
+func addEnvOverrideFlag(cmd *cobra.Command) {
+	cmd.Flags().Var(envOverrides, "set", "Set a variable for this run only, overriding the environment and .env; repeat for several")
+}
+
+// loadEnvVars loads environment variables from the system and .env file,
+// with the values given with --set taking precedence
 func loadEnvVars(composePath string) (map[string]string, error) {
+	envVars, err := readEnvVars(composePath)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range envOverrides {
+		envVars[key] = value
+	}
+	return envVars, nil
+}
+
+// readEnvVars reads environment variables from the system and the .env file
+// next to the compose file, which takes precedence
+func readEnvVars(composePath string) (map[string]string, error) {
 	envVars := make(map[string]string)
 
 	// First, load all environment variables from the system
@@ -66,19 +277,552 @@ func loadEnvVars(composePath string) (map[string]string, error) {
 	return envVars, nil
 }
 
-// expandEnvVars replaces ${VAR} or $VAR in the input string with their values from the environment
+// expandEnvVars replaces ${VAR} or $VAR in the input string with their values
+// from the environment, leaving references to unset variables as they are.
+// ${VAR:-default} gives default when VAR is unset or empty, and
+// ${VAR-default} only when it is unset; default may contain references too.
+// ${VAR:?message} and ${VAR?message} are left as they are when VAR is
+// missing; checkRequiredEnvVars reports them.
 func expandEnvVars(input string, envVars map[string]string) string {
-	result := input
+	var b strings.Builder
+	for i := 0; i < len(input); {
+		expr, n := parseEnvVarExpr(input[i:])
+		if n == 0 {
+			b.WriteByte(input[i])
+			i++
+			continue
+		}
+		b.WriteString(expr.expand(input[i:i+n], envVars))
+		i += n
+	}
+	return b.String()
+}
+
+// envVarExpr is a variable reference such as $VAR, ${VAR}, ${VAR:-default},
+// or ${VAR:?message}
+type envVarExpr struct {
+	name string
+	op   string // "", ":-", "-", ":?", or "?"
+	arg  string // the default or message
+}
+
+// envVarExprOps are the operators that may follow a name within braces
+var envVarExprOps = []string{":-", "-", ":?", "?"}
+
+// parseEnvVarExpr parses the variable reference at the start of s, returning
+// it and its length, or a length of 0 if s doesn't start with one
+func parseEnvVarExpr(s string) (envVarExpr, int) {
+	if len(s) < 2 || s[0] != '$' {
+		return envVarExpr{}, 0
+	}
+	if s[1] != '{' {
+		n := envVarNameLen(s[1:])
+		if n == 0 {
+			return envVarExpr{}, 0
+		}
+		return envVarExpr{name: s[1 : 1+n]}, 1 + n
+	}
+
+	n := envVarNameLen(s[2:])
+	if n == 0 {
+		return envVarExpr{}, 0
+	}
+	expr := envVarExpr{name: s[2 : 2+n]}
+	rest := s[2+n:]
+	for _, op := range envVarExprOps {
+		if strings.HasPrefix(rest, op) {
+			expr.op, rest = op, rest[len(op):]
+			break
+		}
+	}
+
+	// Find the closing brace, skipping those of references in the default
+	depth := 0
+	for i := 0; i < len(rest); i++ {
+		switch {
+		case rest[i] == '$' && i+1 < len(rest) && rest[i+1] == '{':
+			depth++
+			i++
+		case rest[i] == '}' && depth > 0:
+			depth--
+		case rest[i] == '}':
+			// Anything else after the name, as in ${VAR.x}, isn't a reference
+			if expr.op == "" && i > 0 {
+				return envVarExpr{}, 0
+			}
+			expr.arg = rest[:i]
+			return expr, len(s) - len(rest) + i + 1
+		}
+	}
+	return envVarExpr{}, 0
+}
+
+// envVarExprs returns the variable references in s, followed by each one's
+// references in its default, as in ${CONFIG_DIR:-${HOME}/.config}
+func envVarExprs(s string) []envVarExpr {
+	var exprs []envVarExpr
+	for i := 0; i < len(s); {
+		expr, n := parseEnvVarExpr(s[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+		exprs = append(exprs, expr)
+		if strings.HasSuffix(expr.op, "-") {
+			exprs = append(exprs, envVarExprs(expr.arg)...)
+		}
+		i += n
+	}
+	return exprs
+}
+
+// envVarNameLen returns the length of the variable name at the start of s
+func envVarNameLen(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		letter := c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+		if !letter && (i == 0 || c < '0' || c > '9') {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// expand returns the value of a reference written as ref
+func (e envVarExpr) expand(ref string, envVars map[string]string) string {
+	value, ok := envVars[e.name]
+	switch {
+	case e.op == ":-" && value == "", e.op == "-" && !ok:
+		return expandEnvVars(e.arg, envVars)
+	case !ok, e.op == ":?" && value == "":
+		return ref
+	}
+	return value
+}
+
+// envVarReference matches ${VAR}, ${VAR:-default}, ${VAR:?message}, and
+// $VAR references, capturing the name and any operator and its argument
+var envVarReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?])([^}]*))?\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// envVarRef is a reference to an environment variable within a service
+type envVarRef struct {
+	name string
+	path []string // keys leading to the value, e.g. ["environment", "API_KEY"]
+
+	// hasDefault is set for ${VAR:-default}, which needn't be set
+	hasDefault bool
+	// required is the operator of ${VAR:?message} or ${VAR?message}, and
+	// message its message
+	required, message string
+}
+
+// envVarRefs returns every variable reference of a service in a stable order
+func envVarRefs(service Service) []envVarRef {
+	var refs []envVarRef
+	check := func(value string, path ...string) {
+		for _, expr := range envVarExprs(value) {
+			ref := envVarRef{name: expr.name, path: path, hasDefault: strings.HasSuffix(expr.op, "-")}
+			if strings.HasSuffix(expr.op, "?") {
+				ref.required, ref.message = expr.op, strings.TrimSpace(expr.arg)
+			}
+			refs = append(refs, ref)
+		}
+	}
+
+	check(service.Command, "command")
+	check(service.Image, "image")
+	for _, key := range sortedKeys(service.Environment) {
+		check(service.Environment[key], "environment", key)
+	}
+	for _, volume := range service.Volumes {
+		check(volume, "volumes")
+	}
+	for _, port := range service.Ports {
+		check(port, "ports")
+	}
+	for _, arg := range service.Entrypoint {
+		check(arg, "entrypoint")
+	}
+	check(service.WorkingDir, "working_dir")
+	check(service.User, "user")
+	for _, key := range sortedKeys(service.Labels) {
+		check(service.Labels[key], "labels", key)
+	}
+
+	return refs
+}
+
+// unresolvedEnvVars returns the variable references of a service that are
+// not set and have no default
+func unresolvedEnvVars(service Service, envVars map[string]string) []envVarRef {
+	var refs []envVarRef
+	for _, ref := range envVarRefs(service) {
+		if _, ok := envVars[ref.name]; !ok && !ref.hasDefault {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// checkRequiredEnvVars fails with its message for each ${VAR:?message}
+// reference of a service whose variable is unset, or empty for ":?", so a
+// config isn't written with the placeholder in it. References left for the
+// client to resolve in passthrough mode aren't checked.
+func checkRequiredEnvVars(name string, service Service, envVars map[string]string) error {
+	passthrough := GetEnvMode(service) == envModePassthrough && !IsRemoteServerWithEnvExpansion(service, envVars)
+	for _, ref := range envVarRefs(service) {
+		if ref.required == "" || (passthrough && ref.path[0] != "labels") {
+			continue
+		}
+		value, ok := envVars[ref.name]
+		if ok && (value != "" || ref.required == "?") {
+			continue
+		}
+		if ref.message != "" {
+			return newValidationError("server '%s': required variable %s is not set: %s", name, ref.name, ref.message)
+		}
+		return newValidationError("server '%s': required variable %s is not set", name, ref.name)
+	}
+	return nil
+}
+
+// checkServersRequiredEnvVars runs checkRequiredEnvVars on each server, in
+// name order so the same one is reported every time
+func checkServersRequiredEnvVars(servers map[string]Service, envVars map[string]string) error {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := checkRequiredEnvVars(name, servers[name], envVars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetEnvDocs returns the variable documentation from a service's
+// mcp.env-doc.<VAR> labels, keyed by variable name
+func GetEnvDocs(service Service) map[string]string {
+	docs := make(map[string]string)
+	for label, value := range service.Labels {
+		name := strings.TrimPrefix(label, envDocLabelPrefix)
+		if name != label && name != "" && strings.TrimSpace(value) != "" {
+			docs[name] = strings.TrimSpace(value)
+		}
+	}
+	return docs
+}
 
-	// Replace ${VAR} format
-	for key, value := range envVars {
-		result = strings.ReplaceAll(result, "${"+key+"}", value)
+// writtenEnvVarUsages returns the usages of the variables whose values 'mcp set'
+// writes, leaving out those of local servers that pass them through
+func writtenEnvVarUsages(servers map[string]Service, envVars map[string]string) []envVarUsage {
+	expanded := make(map[string]Service, len(servers))
+	for name, service := range servers {
+		if GetEnvMode(service) == envModePassthrough && !IsRemoteServerWithEnvExpansion(service, envVars) {
+			continue
+		}
+		expanded[name] = service
+	}
+	return collectEnvVarUsages(expanded, envVars)
+}
+
+// secretVarPattern matches the variable names whose values are hidden when typed
+var secretVarPattern = regexp.MustCompile(`(?i)KEY|TOKEN|SECRET`)
+
+// promptMissingEnvVars asks for the value of each missing variable, hiding
+// the answer for names that look like secrets, and returns the answers that
+// aren't empty. hide turns terminal echo off (true) and back on; nil leaves it.
+func promptMissingEnvVars(reader *bufio.Reader, w io.Writer, missing []envVarUsage, hide func(bool)) map[string]string {
+	values := make(map[string]string)
+	for _, usage := range missing {
+		question := usage.Name
+		if usage.Doc != "" {
+			question += " (" + usage.Doc + ")"
+		}
+		question += " for " + strings.Join(usage.Servers, ", ") + ": "
+
+		secret := hide != nil && secretVarPattern.MatchString(usage.Name)
+		if secret {
+			hide(true)
+		}
+		value := prompt(reader, w, question)
+		if secret {
+			hide(false)
+			// The newline typed wasn't echoed
+			fmt.Fprintln(w)
+		}
+		if value != "" {
+			values[usage.Name] = value
+		}
 	}
+	return values
+}
+
+// envVarUsage describes one environment variable referenced by the compose file
+type envVarUsage struct {
+	Name    string
+	Set     bool     // or has a default wherever it is used
+	Servers []string // sorted names of the servers referencing it
+	Doc     string   // from the first server with an mcp.env-doc label for it
+}
 
-	// Replace $VAR format
-	for key, value := range envVars {
-		result = strings.ReplaceAll(result, "$"+key, value)
+// collectEnvVarUsages returns the variables referenced by servers, sorted by name
+func collectEnvVarUsages(servers map[string]Service, envVars map[string]string) []envVarUsage {
+	var names []string
+	for name := range servers {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
+	usages := make(map[string]*envVarUsage)
+	for _, name := range names {
+		service := servers[name]
+		docs := GetEnvDocs(service)
+		for _, ref := range envVarRefs(service) {
+			usage, ok := usages[ref.name]
+			if !ok {
+				usage = &envVarUsage{Name: ref.name, Set: true}
+				usages[ref.name] = usage
+			}
+			// A variable with a default wherever it is used needn't be set
+			if _, set := envVars[ref.name]; !set && !ref.hasDefault {
+				usage.Set = false
+			}
+			if !containsString(usage.Servers, name) {
+				usage.Servers = append(usage.Servers, name)
+			}
+			if usage.Doc == "" {
+				usage.Doc = docs[ref.name]
+			}
+		}
+	}
+
+	result := make([]envVarUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
 	return result
 }
+
+// missingEnvVarUsages returns the usages whose variable is not set
+func missingEnvVarUsages(usages []envVarUsage) []envVarUsage {
+	var missing []envVarUsage
+	for _, usage := range usages {
+		if !usage.Set {
+			missing = append(missing, usage)
+		}
+	}
+	return missing
+}
+
+// loadEnvVarUsages loads the compose file and reports on the servers of the
+// profile given in args
+func loadEnvVarUsages(args []string) ([]envVarUsage, error) {
+	config, err := loadComposeFile(composeFile)
+	if err != nil {
+		return nil, newConfigError("load compose file", composeFile, err)
+	}
+
+	envVars, err := loadEnvVars(composeFile)
+	if err != nil {
+		return nil, newConfigError("load environment variables", composeFile, err)
+	}
+
+	var profile string
+	if len(args) > 0 {
+		profile = args[0]
+	}
+	return collectEnvVarUsages(filterServers(config, profile, envAllServers), envVars), nil
+}
+
+// displayEnvVarUsages prints a table of variables, their status, and their documentation
+func displayEnvVarUsages(w io.Writer, usages []envVarUsage) {
+	if len(usages) == 0 {
+		fmt.Fprintln(w, "No environment variables referenced")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VARIABLE\tSTATUS\tSERVERS\tDESCRIPTION")
+	fmt.Fprintln(tw, "--------\t------\t-------\t-----------")
+	for _, usage := range usages {
+		status := "set"
+		if !usage.Set {
+			status = "missing"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", usage.Name, status, strings.Join(usage.Servers, ", "), usage.Doc)
+	}
+	tw.Flush()
+}
+
+// checkEnvVarUsages prints the missing variables with their documentation and
+// returns a ValidationError if there are any
+func checkEnvVarUsages(w io.Writer, usages []envVarUsage) error {
+	missing := missingEnvVarUsages(usages)
+	if len(missing) == 0 {
+		fmt.Fprintf(w, "All %d referenced environment variables are set\n", len(usages))
+		return nil
+	}
+
+	printMissingEnvVars(w, missing)
+
+	noun := "variables are"
+	if len(missing) == 1 {
+		noun = "variable is"
+	}
+	return newValidationError("%d environment %s not set", len(missing), noun)
+}
+
+// printMissingEnvVars lists missing variables with the servers that use them
+// and what to set them to
+func printMissingEnvVars(w io.Writer, missing []envVarUsage) {
+	fmt.Fprintln(w, "Missing environment variables:")
+	for _, usage := range missing {
+		fmt.Fprintf(w, "  %s (used by %s)\n", usage.Name, strings.Join(usage.Servers, ", "))
+		if usage.Doc != "" {
+			fmt.Fprintf(w, "      %s\n", usage.Doc)
+		}
+	}
+}
+
+// secretValues returns the values to mask when showing a resolved server:
+// those of variables named like secrets, and the secrets in the server itself
+func secretValues(service Service, server MCPServer, envVars map[string]string) []string {
+	var values []string
+	for _, ref := range envVarRefs(service) {
+		key := ref.path[len(ref.path)-1]
+		if secretKeyPattern.MatchString(ref.name) || secretKeyPattern.MatchString(key) {
+			values = append(values, envVars[ref.name])
+		}
+	}
+	return serverSecretValues(server, values...)
+}
+
+// serverSecretValues returns the values to mask in a server entry, along with
+// extra: those of environment entries, container -e arguments, and headers
+// named like secrets, and any value in a well-known credential format
+func serverSecretValues(server MCPServer, extra ...string) []string {
+	seen := make(map[string]bool)
+	var secrets []string
+	add := func(value string) {
+		// Short values would mask unrelated text wherever they appear
+		if len(value) >= 4 && value != oauthTokenPlaceholder && !seen[value] {
+			seen[value] = true
+			secrets = append(secrets, value)
+		}
+	}
+
+	for _, value := range extra {
+		add(value)
+	}
+	for key, value := range server.Env {
+		if secretKeyPattern.MatchString(key) || secretValuePattern.MatchString(value) {
+			add(value)
+		}
+	}
+	for key, value := range server.Headers {
+		if secretKeyPattern.MatchString(key) || secretValuePattern.MatchString(value) {
+			// Keep the scheme of values such as "Bearer <token>" readable
+			if scheme, credentials, ok := strings.Cut(value, " "); ok && !strings.Contains(credentials, " ") && scheme != "" {
+				value = credentials
+			}
+			add(value)
+		}
+	}
+	for _, arg := range server.Args {
+		key, value, _ := strings.Cut(arg, "=")
+		if value != "" && secretKeyPattern.MatchString(key) {
+			add(value)
+		}
+		for _, v := range []string{arg, value} {
+			if secretValuePattern.MatchString(v) {
+				add(v)
+			}
+		}
+	}
+
+	// Mask longer secrets first so one containing another is masked whole
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	return secrets
+}
+
+// maskServer returns a copy of a server with the secrets masked in every field
+func maskServer(server MCPServer, secrets []string) MCPServer {
+	masked := server
+	masked.Command = maskSecrets(server.Command, secrets)
+	masked.URL = maskSecrets(server.URL, secrets)
+	if server.Args != nil {
+		masked.Args = make([]string, len(server.Args))
+		for i, arg := range server.Args {
+			masked.Args[i] = maskSecrets(arg, secrets)
+		}
+	}
+	maskMap := func(values map[string]string) map[string]string {
+		if values == nil {
+			return nil
+		}
+		result := make(map[string]string, len(values))
+		for key, value := range values {
+			result[key] = maskSecrets(value, secrets)
+		}
+		return result
+	}
+	masked.Env = maskMap(server.Env)
+	masked.Headers = maskMap(server.Headers)
+	return masked
+}
+
+// maskSecrets replaces every occurrence of the secrets in s
+func maskSecrets(s string, secrets []string) string {
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, maskSecret(secret))
+	}
+	return s
+}
+
+// maskSecret hides a secret, keeping the start of long ones (often a format
+// prefix such as ghp_) to tell them apart
+func maskSecret(secret string) string {
+	if len(secret) >= 16 {
+		return secret[:4] + "****"
+	}
+	return "****"
+}
+
+// printResolvedServer prints a resolved server's command line or URL,
+// environment, and headers, masking the given secrets
+func printResolvedServer(w io.Writer, name string, server MCPServer, secrets []string) {
+	fmt.Fprintf(w, "Server:  %s\n", name)
+	if server.URL != "" {
+		fmt.Fprintf(w, "URL:     %s\n", maskSecrets(server.URL, secrets))
+	} else {
+		parts := []string{shellQuote(server.Command)}
+		for _, arg := range server.Args {
+			parts = append(parts, shellQuote(arg))
+		}
+		fmt.Fprintf(w, "Command: %s\n", maskSecrets(strings.Join(parts, " "), secrets))
+	}
+
+	if server.URL == "" {
+		if len(server.Env) == 0 {
+			fmt.Fprintln(w, "Environment: (none)")
+		} else {
+			fmt.Fprintln(w, "Environment:")
+			for _, key := range sortedKeys(server.Env) {
+				fmt.Fprintf(w, "  %s=%s\n", key, maskSecrets(server.Env[key], secrets))
+			}
+		}
+	}
+
+	if len(server.Headers) > 0 {
+		fmt.Fprintln(w, "Headers:")
+		for _, key := range sortedKeys(server.Headers) {
+			fmt.Fprintf(w, "  %s: %s\n", key, maskSecrets(server.Headers[key], secrets))
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -177,6 +181,46 @@ func TestExpandEnvVars(t *testing.T) {
 			input:    "${HOME}/.config/app/${USER}/data/$API_KEY.json",
 			expected: "/home/user/.config/app/testuser/data/secret123.json",
 		},
+		{
+			name:     "longer name sharing a prefix",
+			input:    "$HOMEDIR and $HOME",
+			expected: "$HOMEDIR and /home/user",
+		},
+		{
+			name:     "default for unset variable",
+			input:    "TZ=${TZ:-UTC}",
+			expected: "TZ=UTC",
+		},
+		{
+			name:     "default for empty variable",
+			input:    "${EMPTY:-fallback}",
+			expected: "fallback",
+		},
+		{
+			name:     "default not used when set",
+			input:    "${USER:-nobody}",
+			expected: "testuser",
+		},
+		{
+			name:     "unset-only default keeps empty value",
+			input:    "[${EMPTY-fallback}] [${UNDEFINED_VAR-fallback}]",
+			expected: "[] [fallback]",
+		},
+		{
+			name:     "nested default",
+			input:    "${CONFIG_DIR:-${HOME}/.config}",
+			expected: "/home/user/.config",
+		},
+		{
+			name:     "empty default",
+			input:    "x${UNDEFINED_VAR:-}y",
+			expected: "xy",
+		},
+		{
+			name:     "not a reference",
+			input:    "${HOME.x} and $1 and ${unterminated",
+			expected: "${HOME.x} and $1 and ${unterminated",
+		},
 	}
 
 	for _, tt := range tests {
@@ -290,3 +334,273 @@ AFTER_COMMENT=after_comment_value`
 		}
 	})
 }
+
+func TestGetEnvDocs(t *testing.T) {
+	service := Service{Labels: map[string]string{
+		"mcp.env-doc.GITHUB_TOKEN": "  GitHub PAT with repo scope ",
+		"mcp.env-doc.EMPTY":        " ",
+		"mcp.env-doc.":             "no variable name",
+		"mcp.description":          "GitHub",
+	}}
+
+	docs := GetEnvDocs(service)
+	expected := map[string]string{"GITHUB_TOKEN": "GitHub PAT with repo scope"}
+	if !reflect.DeepEqual(docs, expected) {
+		t.Errorf("Expected %v, got %v", expected, docs)
+	}
+}
+
+func TestCollectEnvVarUsages(t *testing.T) {
+	servers := map[string]Service{
+		"github": {
+			Command:     "npx -y @modelcontextprotocol/server-github",
+			Environment: map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "${GITHUB_TOKEN}"},
+			Labels:      map[string]string{"mcp.env-doc.GITHUB_TOKEN": "GitHub PAT with repo scope"},
+		},
+		"gh-issues": {
+			Command:     "gh-issues-mcp --token $GITHUB_TOKEN",
+			Environment: map[string]string{"REGION": "${AWS_REGION}"},
+		},
+	}
+	envVars := map[string]string{"AWS_REGION": "us-east-1"}
+
+	usages := collectEnvVarUsages(servers, envVars)
+	expected := []envVarUsage{
+		{Name: "AWS_REGION", Set: true, Servers: []string{"gh-issues"}},
+		{Name: "GITHUB_TOKEN", Servers: []string{"gh-issues", "github"}, Doc: "GitHub PAT with repo scope"},
+	}
+	if !reflect.DeepEqual(usages, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, usages)
+	}
+
+	var out bytes.Buffer
+	err := checkEnvVarUsages(&out, usages)
+	if ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	want := "Missing environment variables:\n  GITHUB_TOKEN (used by gh-issues, github)\n      GitHub PAT with repo scope\n"
+	if out.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out.String())
+	}
+
+	out.Reset()
+	displayEnvVarUsages(&out, usages)
+	if !strings.Contains(out.String(), "GITHUB_TOKEN  missing") || strings.Contains(out.String(), "us-east-1") {
+		t.Errorf("Expected status without values, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := checkEnvVarUsages(&out, usages[:1]); err != nil {
+		t.Errorf("Expected no error when all variables are set, got %v", err)
+	}
+}
+
+func TestEnvExampleFile(t *testing.T) {
+	servers := map[string]Service{
+		"github": {
+			Image:       "ghcr.io/github/github-mcp-server:${GITHUB_MCP_VERSION}",
+			Environment: map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "${GITHUB_TOKEN}"},
+			Labels:      map[string]string{"mcp.env-doc.GITHUB_TOKEN": "GitHub PAT with repo scope"},
+		},
+		"api": {
+			Command: "https://api.example.com/mcp",
+			Labels:  map[string]string{"mcp.header.Authorization": "Bearer ${GITHUB_TOKEN}"},
+		},
+	}
+
+	got := envExampleFile("mcp-compose.yml", collectEnvVarUsages(servers, map[string]string{"GITHUB_TOKEN": "ghp_secret"}))
+	want := `# Environment variables for mcp-compose.yml
+# Copy to .env and fill in the values; keep .env out of version control
+
+# Used by: github
+GITHUB_MCP_VERSION=your-github-mcp-version
+
+# GitHub PAT with repo scope
+# Used by: api, github
+GITHUB_TOKEN=your-github-token
+`
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestPrintResolvedServerMasksSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(filepath.Join(dir, ".env"), []byte("GITHUB_TOKEN=ghp_abcdefghijklmnop1234\nAPI_KEY=k3y-v4lue\nREGION=eu\n"), 0600)
+
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		t.Fatalf("loadEnvVars failed: %v", err)
+	}
+	servers := map[string]Service{
+		"github": {
+			Image:       "ghcr.io/github/github-mcp-server",
+			Environment: map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "${GITHUB_TOKEN}", "REGION": "${REGION}"},
+		},
+		"api": {
+			Command: "https://api.example.com/mcp",
+			Labels:  map[string]string{"mcp.header.Authorization": "Bearer ${API_KEY}"},
+		},
+	}
+	resolved, err := buildExpectedConfig(context.Background(), servers, envVars)
+	if err != nil {
+		t.Fatalf("buildExpectedConfig failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	printResolvedServer(&out, "github", resolved.MCPServers["github"], secretValues(servers["github"], resolved.MCPServers["github"], envVars))
+	for _, want := range []string{
+		"Command: docker run -i --rm",
+		"-e GITHUB_PERSONAL_ACCESS_TOKEN=ghp_****",
+		"  GITHUB_PERSONAL_ACCESS_TOKEN=ghp_****\n",
+		"  REGION=eu\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "abcdefgh") {
+		t.Errorf("Expected the token to be masked:\n%s", out.String())
+	}
+
+	out.Reset()
+	printResolvedServer(&out, "api", resolved.MCPServers["api"], secretValues(servers["api"], resolved.MCPServers["api"], envVars))
+	if !strings.Contains(out.String(), "URL:     https://api.example.com/mcp\n") || !strings.Contains(out.String(), "  Authorization: Bearer ****\n") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	printResolvedServer(&out, "api", resolved.MCPServers["api"], nil)
+	if !strings.Contains(out.String(), "Authorization: Bearer k3y-v4lue") {
+		t.Errorf("Expected secrets to be shown without masking:\n%s", out.String())
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	t.Cleanup(func() { envOverrides = envOverrideFlag{} })
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(filepath.Join(dir, ".env"), []byte("API_URL=https://prod.example.com\nREGION=eu\n"), 0644)
+	t.Setenv("API_TOKEN", "from-environment")
+
+	for _, value := range []string{"API_URL=https://staging.example.com", "API_TOKEN=one-off", "EMPTY=", "QUERY=a=b"} {
+		if err := envOverrides.Set(value); err != nil {
+			t.Fatalf("Set(%q) failed: %v", value, err)
+		}
+	}
+	for _, value := range []string{"NOVALUE", "=x", "1BAD=x", "BAD NAME=x"} {
+		if err := envOverrides.Set(value); err == nil {
+			t.Errorf("Expected Set(%q) to fail", value)
+		}
+	}
+
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		t.Fatalf("loadEnvVars failed: %v", err)
+	}
+	expected := map[string]string{"API_URL": "https://staging.example.com", "API_TOKEN": "one-off", "EMPTY": "", "QUERY": "a=b", "REGION": "eu"}
+	for key, value := range expected {
+		if got, ok := envVars[key]; !ok || got != value {
+			t.Errorf("Expected %s=%q, got %q", key, value, got)
+		}
+	}
+	if got := envOverrides.String(); got != "API_TOKEN=one-off,API_URL=https://staging.example.com,EMPTY=,QUERY=a=b" {
+		t.Errorf("Unexpected flag string %q", got)
+	}
+}
+
+func TestEnvVarDefaultsNeedNotBeSet(t *testing.T) {
+	service := Service{
+		Image:       "mcp/time",
+		Environment: map[string]string{"TZ": "${TZ:-UTC}", "TOKEN": "${TOKEN}"},
+	}
+	refs := unresolvedEnvVars(service, map[string]string{})
+	if len(refs) != 1 || refs[0].name != "TOKEN" {
+		t.Errorf("Expected only TOKEN to be unresolved, got %+v", refs)
+	}
+
+	missing := missingEnvVarUsages(collectEnvVarUsages(map[string]Service{"time": service}, map[string]string{}))
+	if len(missing) != 1 || missing[0].Name != "TOKEN" {
+		t.Errorf("Expected only TOKEN to be missing, got %+v", missing)
+	}
+}
+
+func TestNestedEnvVarRefs(t *testing.T) {
+	service := Service{
+		Image:       "mcp/config",
+		Environment: map[string]string{"CONFIG": "${CONFIG_DIR:-${HOME}/.config} ${TOKEN:?${NOT_A_REF}}"},
+	}
+	var names []string
+	for _, ref := range envVarRefs(service) {
+		names = append(names, ref.name)
+	}
+	if expected := []string{"CONFIG_DIR", "HOME", "TOKEN"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	refs := unresolvedEnvVars(service, map[string]string{"TOKEN": "secret"})
+	if len(refs) != 1 || refs[0].name != "HOME" || refs[0].hasDefault {
+		t.Errorf("Expected HOME in the default to be unresolved, got %+v", refs)
+	}
+}
+
+func TestRequiredEnvVars(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	envVars := map[string]string{"API_KEY": "secret", "EMPTY": ""}
+	if got := expandEnvVars("${API_KEY:?API key required} ${MISSING:?needed}", envVars); got != "secret ${MISSING:?needed}" {
+		t.Errorf("Unexpected expansion: %q", got)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		mode    string
+		wantErr string
+	}{
+		{"set", "${API_KEY:?API key required}", "", ""},
+		{"unset with message", "${BRAVE_API_KEY:?API key required}", "", "required variable BRAVE_API_KEY is not set: API key required"},
+		{"unset without message", "${BRAVE_API_KEY:?}", "", "required variable BRAVE_API_KEY is not set"},
+		{"empty with colon", "${EMPTY:?must not be empty}", "", "required variable EMPTY is not set: must not be empty"},
+		{"empty without colon", "${EMPTY?must be set}", "", ""},
+		{"passthrough", "${BRAVE_API_KEY:?API key required}", envModePassthrough, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := Service{Image: "mcp/brave-search", Environment: map[string]string{"BRAVE_API_KEY": tt.value}}
+			if tt.mode != "" {
+				service.Labels = map[string]string{"mcp.env-mode": tt.mode}
+			}
+			_, err := convertToMCPConfig(context.Background(), map[string]Service{"brave": service}, envVars)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if ExitCode(err) != exitCodeValidation || !strings.Contains(err.Error(), "server 'brave': "+tt.wantErr) {
+				t.Errorf("Expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("status", func(t *testing.T) {
+		originalTool := toolShortcut
+		defer func() { toolShortcut = originalTool }()
+		toolShortcut = "kiro"
+
+		composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+		os.WriteFile(composePath, []byte(`services:
+  gh:
+    image: mcp/github
+    environment:
+      GITHUB_TOKEN: ${MCP_TEST_GH_TOKEN:?GitHub token required}
+`), 0644)
+		err := runStatus(&bytes.Buffer{}, composePath, "")
+		if ExitCode(err) != exitCodeValidation || !strings.Contains(err.Error(), "required variable MCP_TEST_GH_TOKEN is not set: GitHub token required") {
+			t.Errorf("Expected the required variable's message, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Exit codes returned by the CLI for each class of error
+const (
+	exitCodeOK         = 0
+	exitCodeError      = 1 // unclassified errors
+	exitCodeDifferent  = 1 // checks such as diff found differences
+	exitCodeValidation = 2 // invalid input, flags, or compose definitions
+	exitCodeConfig     = 3 // compose, CLI, or tool config files could not be read or written
+	exitCodeAuth       = 4 // OAuth or header authentication failures
+)
+
+// errorFormat controls how errors are printed ("text" or "json")
+var errorFormat string
+
+// ConfigError reports a problem reading or writing a compose, CLI, or tool config file
+type ConfigError struct {
+	Op   string // what was being attempted, e.g. "load compose file"
+	Path string // file involved, if any
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("failed to %s: %v", e.Op, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// AuthError reports a failure to authenticate against a remote MCP server
+type AuthError struct {
+	Server string
+	Err    error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("authentication failed for '%s': %v", e.Server, e.Err)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError reports invalid user input or compose definitions
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ExitError carries an explicit exit code. A nil Err exits silently,
+// which commands use when they have already reported their result.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// newConfigError wraps err as a ConfigError
+func newConfigError(op, path string, err error) error {
+	return &ConfigError{Op: op, Path: path, Err: err}
+}
+
+// newValidationError creates a ValidationError from a format string
+func newValidationError(format string, args ...interface{}) error {
+	return &ValidationError{Err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode maps an error returned by Execute to a process exit code
+func ExitCode(err error) int {
+	if err == nil {
+		return exitCodeOK
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		return exitCodeAuth
+	}
+
+	var configErr *ConfigError
+	if errors.As(err, &configErr) {
+		return exitCodeConfig
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return exitCodeValidation
+	}
+
+	return exitCodeError
+}
+
+// errorType returns a short name for the class of an error
+func errorType(err error) string {
+	var authErr *AuthError
+	var configErr *ConfigError
+	var validationErr *ValidationError
+
+	switch {
+	case errors.As(err, &authErr):
+		return "auth"
+	case errors.As(err, &configErr):
+		return "config"
+	case errors.As(err, &validationErr):
+		return "validation"
+	default:
+		return "error"
+	}
+}
+
+// printError writes an error to w in the configured error format.
+// Silent exit errors are not printed.
+func printError(w io.Writer, err error) {
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) && exitErr.Err == nil {
+		return
+	}
+
+	if errorFormat != "json" {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return
+	}
+
+	type jsonError struct {
+		Type     string `json:"type"`
+		Message  string `json:"message"`
+		ExitCode int    `json:"exitCode"`
+		Path     string `json:"path,omitempty"`
+		Server   string `json:"server,omitempty"`
+	}
+
+	out := jsonError{
+		Type:     errorType(err),
+		Message:  err.Error(),
+		ExitCode: ExitCode(err),
+	}
+
+	var configErr *ConfigError
+	if errors.As(err, &configErr) {
+		out.Path = configErr.Path
+	}
+	var authErr *AuthError
+	if errors.As(err, &authErr) {
+		out.Server = authErr.Server
+	}
+
+	data, _ := json.Marshal(map[string]jsonError{"error": out})
+	fmt.Fprintln(w, string(data))
+}
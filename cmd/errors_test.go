@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	cause := errors.New("boom")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"nil error", nil, exitCodeOK},
+		{"plain error", cause, exitCodeError},
+		{"validation error", newValidationError("bad input"), exitCodeValidation},
+		{"config error", newConfigError("load compose file", "mcp-compose.yml", cause), exitCodeConfig},
+		{"auth error", &AuthError{Server: "remote", Err: cause}, exitCodeAuth},
+		{"explicit exit error", &ExitError{Code: 7}, 7},
+		{"wrapped config error", fmt.Errorf("context: %w", newConfigError("read", "", cause)), exitCodeConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.expected {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestErrorMessagesAndUnwrap(t *testing.T) {
+	cause := errors.New("no such file")
+
+	configErr := newConfigError("load compose file", "mcp-compose.yml", cause)
+	if configErr.Error() != "failed to load compose file: no such file" {
+		t.Errorf("Unexpected config error message: %s", configErr.Error())
+	}
+	if !errors.Is(configErr, cause) {
+		t.Error("Expected config error to wrap its cause")
+	}
+
+	authErr := &AuthError{Server: "remote", Err: cause}
+	if authErr.Error() != "authentication failed for 'remote': no such file" {
+		t.Errorf("Unexpected auth error message: %s", authErr.Error())
+	}
+	if !errors.Is(authErr, cause) {
+		t.Error("Expected auth error to wrap its cause")
+	}
+
+	validationErr := newValidationError("server '%s' not found", "github")
+	if validationErr.Error() != "server 'github' not found" {
+		t.Errorf("Unexpected validation error message: %s", validationErr.Error())
+	}
+
+	if (&ExitError{Code: 2}).Error() != "exit status 2" {
+		t.Error("Expected silent exit error to describe its code")
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	originalFormat := errorFormat
+	defer func() { errorFormat = originalFormat }()
+
+	t.Run("text format", func(t *testing.T) {
+		errorFormat = "text"
+		var buf bytes.Buffer
+		printError(&buf, newValidationError("bad input"))
+		if buf.String() != "Error: bad input\n" {
+			t.Errorf("Unexpected text output: %q", buf.String())
+		}
+	})
+
+	t.Run("json format", func(t *testing.T) {
+		errorFormat = "json"
+		var buf bytes.Buffer
+		printError(&buf, newConfigError("write MCP config", "/tmp/mcp.json", errors.New("denied")))
+
+		var out map[string]map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatalf("Expected valid JSON, got %q: %v", buf.String(), err)
+		}
+		if out["error"]["type"] != "config" {
+			t.Errorf("Expected type config, got %v", out["error"]["type"])
+		}
+		if out["error"]["path"] != "/tmp/mcp.json" {
+			t.Errorf("Expected path to be reported, got %v", out["error"]["path"])
+		}
+		if out["error"]["exitCode"] != float64(exitCodeConfig) {
+			t.Errorf("Expected exit code %d, got %v", exitCodeConfig, out["error"]["exitCode"])
+		}
+	})
+
+	t.Run("silent exit error", func(t *testing.T) {
+		errorFormat = "text"
+		var buf bytes.Buffer
+		printError(&buf, &ExitError{Code: 1})
+		if strings.TrimSpace(buf.String()) != "" {
+			t.Errorf("Expected no output for silent exit, got %q", buf.String())
+		}
+	})
+}
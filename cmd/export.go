@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	exportServerDocs    string
+	exportAllServers    bool
+	exportComposeOutput string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [profile]",
+	Short: "Export the compose file in other formats",
+	Long: `Export the servers of a profile in other formats.
+With the --server-docs flag, it writes a short markdown file per server to a
+directory, with its resolved command, required environment variables, profiles,
+and a docs link, plus a README.md index, so the compose file doubles as
+onboarding documentation. Variable values are never written.
+Docs links come from the mcp.docs label, or the npm or PyPI page of npx and
+uvx servers.
+Use 'mcp export docker-compose' to write a runnable docker-compose.yml instead.
+Without arguments, it exports the default servers. With the -a flag, it
+exports all servers.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportServerDocs == "" {
+			return newValidationError("choose what to export with --server-docs <dir>, or use 'mcp export docker-compose'")
+		}
+
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+		servers := filterServers(config, profile, exportAllServers)
+
+		return writeServerDocs(cmd.Context(), os.Stdout, exportServerDocs, servers)
+	},
+}
+
+// exportComposeCmd represents the export docker-compose command
+var exportComposeCmd = &cobra.Command{
+	Use:   "docker-compose [profile]",
+	Short: "Export container servers as a runnable docker-compose.yml",
+	Long: `Write a docker-compose.yml with the image-based servers of a profile, with
+their images, environment, and volumes, so the same definitions that configure
+editors can be brought up with 'docker compose up' for hosting.
+Variables stay as ${VAR} references, which docker compose resolves from the .env
+file next to it, so no secrets are written. mcp.* labels are dropped, and
+services keep stdin open so stdio servers don't exit on start.
+Command-based and remote servers can't run in a container and are skipped.
+The file is printed unless --output is set.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+		servers := filterServers(config, profile, exportAllServers)
+
+		data, skipped, err := dockerComposeFile(servers)
+		if err != nil {
+			return err
+		}
+		printSkippedServers(os.Stderr, skipped)
+
+		if exportComposeOutput == "" {
+			os.Stdout.Write(data)
+			return nil
+		}
+		if err := os.WriteFile(exportComposeOutput, data, 0644); err != nil {
+			return newConfigError("write docker compose file", exportComposeOutput, err)
+		}
+		fmt.Printf("Wrote %s to %s\n", pluralize(len(servers)-len(skipped), "service"), exportComposeOutput)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportComposeCmd)
+	exportCmd.Flags().StringVar(&exportServerDocs, "server-docs", "", "Write a markdown file per server to this directory")
+	exportCmd.PersistentFlags().BoolVarP(&exportAllServers, "all", "a", false, "Export all servers")
+	exportCmd.MarkFlagDirname("server-docs")
+	exportComposeCmd.Flags().StringVarP(&exportComposeOutput, "output", "o", "", "Write the docker-compose.yml to this file instead of printing it")
+}
+
+// writeServerDocs writes <name>.md for each server and a README.md index to dir
+func writeServerDocs(ctx context.Context, w io.Writer, dir string, servers map[string]Service) error {
+	if len(servers) == 0 {
+		fmt.Fprintln(w, "No servers found")
+		return nil
+	}
+
+	// Variables stay unexpanded so no secrets end up in the docs, and remote
+	// servers are documented by URL so no headers or tokens are resolved
+	local := make(map[string]Service)
+	for name, service := range servers {
+		if !IsRemoteServer(service) {
+			local[name] = service
+		}
+	}
+	resolved, err := convertToMCPConfig(ctx, local, map[string]string{})
+	if err != nil {
+		return err
+	}
+	for name, service := range servers {
+		if IsRemoteServer(service) {
+			resolved.MCPServers[name] = MCPServer{Type: "http", URL: service.Command}
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return newConfigError("create docs directory", dir, err)
+	}
+
+	var names []string
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(dir, name+".md")
+		doc := serverDoc(name, servers[name], resolved.MCPServers[name])
+		if err := os.WriteFile(path, []byte(doc), 0644); err != nil {
+			return newConfigError("write server docs", path, err)
+		}
+	}
+
+	index := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(index, []byte(serverDocsIndex(names, servers)), 0644); err != nil {
+		return newConfigError("write server docs", index, err)
+	}
+
+	fmt.Fprintf(w, "Wrote docs for %s to %s\n", pluralize(len(names), "server"), dir)
+	return nil
+}
+
+// serverDoc renders the markdown documentation of one server
+func serverDoc(name string, service Service, resolved MCPServer) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", name)
+	if desc := GetDescription(service); desc != "" {
+		fmt.Fprintf(&b, "%s\n\n", desc)
+	}
+
+	fmt.Fprintf(&b, "**Profiles:** %s\n\n", strings.Join(docsProfiles(service), ", "))
+
+	b.WriteString("## Command\n\n")
+	if resolved.URL != "" {
+		fmt.Fprintf(&b, "Remote server at `%s`\n\n", resolved.URL)
+	} else {
+		fmt.Fprintf(&b, "```sh\n%s\n```\n\n", strings.Join(append([]string{resolved.Command}, resolved.Args...), " "))
+	}
+
+	b.WriteString("## Required environment\n\n")
+	usages := collectEnvVarUsages(map[string]Service{name: service}, nil)
+	if len(usages) == 0 {
+		b.WriteString("None\n\n")
+	} else {
+		b.WriteString("| Variable | Description |\n")
+		b.WriteString("| -------- | ----------- |\n")
+		for _, usage := range usages {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", usage.Name, markdownCell(usage.Doc))
+		}
+		b.WriteString("\n")
+	}
+
+	if link := serverDocsLink(service); link != "" {
+		fmt.Fprintf(&b, "## Docs\n\n%s\n", link)
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// serverDocsIndex renders a README.md table linking every server's docs
+func serverDocsIndex(names []string, servers map[string]Service) string {
+	var b strings.Builder
+	b.WriteString("# MCP Servers\n\n")
+	b.WriteString("| Server | Profiles | Description |\n")
+	b.WriteString("| ------ | -------- | ----------- |\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "| [%s](%s.md) | %s | %s |\n", name, name, strings.Join(docsProfiles(servers[name]), ", "), markdownCell(GetDescription(servers[name])))
+	}
+	return b.String()
+}
+
+// markdownCell escapes text for use in a markdown table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+// serverDocsLink returns the mcp.docs label, or the package page of npx and uvx servers
+func serverDocsLink(service Service) string {
+	if link := service.Labels["mcp.docs"]; link != "" {
+		return link
+	}
+
+	parts := strings.Fields(service.Command)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	// The package is the first argument that isn't a flag
+	var pkg string
+	for _, part := range parts[1:] {
+		if !strings.HasPrefix(part, "-") {
+			pkg = part
+			break
+		}
+	}
+	if pkg == "" || strings.Contains(pkg, "$") {
+		return ""
+	}
+
+	switch filepath.Base(parts[0]) {
+	case "npx":
+		// Drop a version suffix, keeping the @ of scoped packages
+		if i := strings.LastIndex(pkg, "@"); i > 0 {
+			pkg = pkg[:i]
+		}
+		return "https://www.npmjs.com/package/" + pkg
+	case "uvx":
+		pkg = strings.FieldsFunc(pkg, func(r rune) bool { return r == '@' || r == '=' || r == '[' })[0]
+		return "https://pypi.org/project/" + pkg + "/"
+	}
+	return ""
+}
+
+// dockerComposeService is a service of a generated docker-compose.yml
+type dockerComposeService struct {
+	Image       string            `yaml:"image"`
+	Entrypoint  []string          `yaml:"entrypoint,omitempty"`
+	WorkingDir  string            `yaml:"working_dir,omitempty"`
+	User        string            `yaml:"user,omitempty"`
+	Environment map[string]string `yaml:"environment,omitempty"`
+	Volumes     []string          `yaml:"volumes,omitempty"`
+	Ports       []string          `yaml:"ports,omitempty"`
+	StdinOpen   bool              `yaml:"stdin_open"`
+}
+
+// dockerComposeFile renders the image-based servers as a docker-compose.yml,
+// returning the sorted names of the servers that were skipped
+func dockerComposeFile(servers map[string]Service) ([]byte, []string, error) {
+	services := make(map[string]dockerComposeService)
+	var skipped []string
+	for name, service := range servers {
+		if service.Image == "" || IsRemoteServer(service) {
+			skipped = append(skipped, name)
+			continue
+		}
+		services[name] = dockerComposeService{
+			Image:       service.Image,
+			Entrypoint:  service.Entrypoint,
+			WorkingDir:  service.WorkingDir,
+			User:        service.User,
+			Environment: service.Environment,
+			Volumes:     service.Volumes,
+			Ports:       service.Ports,
+			StdinOpen:   true,
+		}
+	}
+	sort.Strings(skipped)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(map[string]any{"services": services}); err != nil {
+		return nil, nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), skipped, nil
+}
+
+// printSkippedServers notes the servers left out of an export
+func printSkippedServers(w io.Writer, names []string) {
+	if len(names) > 0 {
+		fmt.Fprintf(w, "Skipped %s that don't run from an image: %s\n", pluralize(len(names), "server"), strings.Join(names, ", "))
+	}
+}
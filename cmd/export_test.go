@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServerDocsLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  Service
+		expected string
+	}{
+		{"scoped npm package with version", Service{Command: "npx -y @modelcontextprotocol/server-github@1.0.0"}, "https://www.npmjs.com/package/@modelcontextprotocol/server-github"},
+		{"uvx package", Service{Command: "uvx mcp-server-time@2025.1.0"}, "https://pypi.org/project/mcp-server-time/"},
+		{"docs label wins", Service{Command: "uvx mcp-server-time", Labels: map[string]string{"mcp.docs": "https://example.com/time"}}, "https://example.com/time"},
+		{"image", Service{Image: "mcp/time"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if link := serverDocsLink(tt.service); link != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, link)
+			}
+		})
+	}
+}
+
+func TestWriteServerDocs(t *testing.T) {
+	t.Setenv("EXPORT_TEST_TOKEN", "secret-value")
+	dir := filepath.Join(t.TempDir(), "docs")
+	servers := map[string]Service{
+		"github": {
+			Command:     "npx -y @modelcontextprotocol/server-github",
+			Environment: map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "${EXPORT_TEST_TOKEN}"},
+			Labels: map[string]string{
+				"mcp.profile":                   "work",
+				"mcp.description":               "GitHub issues | PRs",
+				"mcp.env-doc.EXPORT_TEST_TOKEN": "GitHub PAT with repo scope",
+			},
+		},
+		"api": {
+			Command: "https://api.example.com/mcp",
+			Labels:  map[string]string{"mcp.header.Authorization": "Bearer ${EXPORT_TEST_TOKEN}"},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := writeServerDocs(context.Background(), &out, dir, servers); err != nil {
+		t.Fatalf("writeServerDocs failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Wrote docs for 2 servers") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+
+	github, err := os.ReadFile(filepath.Join(dir, "github.md"))
+	if err != nil {
+		t.Fatalf("Expected github.md: %v", err)
+	}
+	for _, want := range []string{
+		"**Profiles:** work",
+		"npx -y @modelcontextprotocol/server-github",
+		"| `EXPORT_TEST_TOKEN` | GitHub PAT with repo scope |",
+		"https://www.npmjs.com/package/@modelcontextprotocol/server-github",
+	} {
+		if !strings.Contains(string(github), want) {
+			t.Errorf("Expected github.md to contain %q, got:\n%s", want, github)
+		}
+	}
+
+	api, err := os.ReadFile(filepath.Join(dir, "api.md"))
+	if err != nil {
+		t.Fatalf("Expected api.md: %v", err)
+	}
+	if !strings.Contains(string(api), "Remote server at `https://api.example.com/mcp`") {
+		t.Errorf("Expected the remote URL, got:\n%s", api)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		t.Fatalf("Expected README.md: %v", err)
+	}
+	if !strings.Contains(string(index), `| [github](github.md) | work | GitHub issues \| PRs |`) {
+		t.Errorf("Unexpected index:\n%s", index)
+	}
+
+	for _, doc := range [][]byte{github, api, index} {
+		if strings.Contains(string(doc), "secret-value") {
+			t.Errorf("Variable values must not be written:\n%s", doc)
+		}
+	}
+}
+
+func TestDockerComposeFile(t *testing.T) {
+	servers := map[string]Service{
+		"postgres": {
+			Image:       "mcp/postgres",
+			Environment: map[string]string{"DATABASE_URL": "${DATABASE_URL}"},
+			Volumes:     []string{"./data:/data"},
+			Labels:      map[string]string{"mcp.profile": "data"},
+		},
+		"time": {Command: "uvx mcp-server-time"},
+		"api":  {Command: "https://api.example.com/mcp"},
+	}
+
+	data, skipped, err := dockerComposeFile(servers)
+	if err != nil {
+		t.Fatalf("dockerComposeFile failed: %v", err)
+	}
+
+	want := `services:
+  postgres:
+    image: mcp/postgres
+    environment:
+      DATABASE_URL: ${DATABASE_URL}
+    volumes:
+      - ./data:/data
+    stdin_open: true
+`
+	if string(data) != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, data)
+	}
+	if strings.Join(skipped, ",") != "api,time" {
+		t.Errorf("Expected api and time to be skipped, got %v", skipped)
+	}
+}
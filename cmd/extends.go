@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ServiceExtends names the service a service extends, in the same compose
+// file unless File is set
+type ServiceExtends struct {
+	Service string `yaml:"service"`
+	File    string `yaml:"file"`
+}
+
+// UnmarshalYAML accepts the short form, extends: base, as well as the mapping
+func (e *ServiceExtends) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		e.Service = value.Value
+		return nil
+	}
+	// plainExtends has ServiceExtends' fields without this method
+	type plainExtends ServiceExtends
+	return value.Decode((*plainExtends)(e))
+}
+
+// resolveMergeKeys returns a mapping with its YAML merge keys (<<: *base)
+// replaced by the entries they merge in. Keys set in the mapping itself take
+// precedence, then those of earlier merged mappings, as in YAML.
+func resolveMergeKeys(node *yaml.Node) (*yaml.Node, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return node, nil
+	}
+
+	resolved := *node
+	resolved.Content = nil
+	set := make(map[string]bool)
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag != "!!merge" {
+			set[key.Value] = true
+			resolved.Content = append(resolved.Content, key, value)
+			continue
+		}
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		if value.Kind == yaml.SequenceNode {
+			merges = append(merges, value.Content...)
+		} else {
+			merges = append(merges, value)
+		}
+	}
+
+	for _, merge := range merges {
+		merge, err := resolveMergeKeys(merge)
+		if err != nil {
+			return nil, err
+		}
+		if merge.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("line %d: << must merge a mapping or a list of mappings", merge.Line)
+		}
+		for i := 0; i+1 < len(merge.Content); i += 2 {
+			if key := merge.Content[i]; !set[key.Value] {
+				set[key.Value] = true
+				resolved.Content = append(resolved.Content, key, merge.Content[i+1])
+			}
+		}
+	}
+	return &resolved, nil
+}
+
+// extendsResolver resolves the extends of services, loading the compose
+// files they name as needed
+type extendsResolver struct {
+	files map[string]*ComposeConfig // by absolute path, extends unresolved
+}
+
+// resolveExtends replaces each service of a compose file that extends
+// another with the other's definition merged with its own
+func resolveExtends(config *ComposeConfig, path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	r := &extendsResolver{files: map[string]*ComposeConfig{path: config}}
+
+	resolved := make(map[string]Service)
+	for name, service := range config.Services {
+		if service.Extends == nil {
+			continue
+		}
+		if resolved[name], err = r.service(path, name, nil); err != nil {
+			return err
+		}
+	}
+	maps.Copy(config.Services, resolved)
+	return nil
+}
+
+// service returns a service of the compose file at path with its extends
+// resolved; chain lists the services already being resolved, to catch cycles
+func (r *extendsResolver) service(path, name string, chain []string) (Service, error) {
+	config, err := r.load(path)
+	if err != nil {
+		return Service{}, err
+	}
+	service, ok := config.Services[name]
+	if !ok {
+		return Service{}, fmt.Errorf("%s: no service '%s'", path, name)
+	}
+	if service.Extends == nil {
+		return service, nil
+	}
+
+	key := path + ":" + name
+	if slices.Contains(chain, key) {
+		return Service{}, fmt.Errorf("service '%s': extends cycle: %s", name, strings.Join(append(chain, key), " -> "))
+	}
+	basePath := path
+	if file := service.Extends.File; file != "" {
+		basePath = file
+		if !filepath.IsAbs(file) {
+			basePath = filepath.Join(filepath.Dir(path), file)
+		}
+	}
+	base, err := r.service(basePath, service.Extends.Service, append(chain, key))
+	if err != nil {
+		return Service{}, fmt.Errorf("service '%s' extends '%s': %w", name, service.Extends.Service, err)
+	}
+	// A base from another file has that file's x-mcp defaults
+	if basePath != path {
+		applyXMCPDefaults(&base, r.files[basePath].defaults)
+	}
+	return mergeServices(base, service), nil
+}
+
+// load returns the compose file at path, parsing it on first use
+func (r *extendsResolver) load(path string) (*ComposeConfig, error) {
+	if config, ok := r.files[path]; ok {
+		return config, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config ComposeConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	r.files[path] = &config
+	return &config, nil
+}
+
+// mergeServices returns base overridden by service, as Docker Compose merges
+// them: command, image, entrypoint, working_dir, user, and profiles are
+// replaced, environment and labels are merged by key, volumes by their path
+// in the container, and ports are added
+func mergeServices(base, service Service) Service {
+	merged := base
+	merged.Extends = nil
+	if service.Command != "" {
+		merged.Command = service.Command
+	}
+	if service.Image != "" {
+		merged.Image = service.Image
+	}
+	if len(service.Entrypoint) > 0 {
+		merged.Entrypoint = service.Entrypoint
+	}
+	if service.WorkingDir != "" {
+		merged.WorkingDir = service.WorkingDir
+	}
+	if service.User != "" {
+		merged.User = service.User
+	}
+	if len(service.Profiles) > 0 {
+		merged.Profiles = service.Profiles
+	}
+	merged.Environment = mergeStringMaps(base.Environment, service.Environment)
+	merged.Labels = mergeStringMaps(base.Labels, service.Labels)
+
+	merged.Volumes = nil
+	for _, volume := range base.Volumes {
+		if !slices.ContainsFunc(service.Volumes, func(v string) bool { return volumeTarget(v) == volumeTarget(volume) }) {
+			merged.Volumes = append(merged.Volumes, volume)
+		}
+	}
+	merged.Volumes = append(merged.Volumes, service.Volumes...)
+
+	merged.Ports = slices.Clone(base.Ports)
+	for _, port := range service.Ports {
+		if !slices.Contains(merged.Ports, port) {
+			merged.Ports = append(merged.Ports, port)
+		}
+	}
+	return merged
+}
+
+// mergeStringMaps returns the entries of base and override, with those of
+// override taking precedence, or nil if both are empty
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	maps.Copy(merged, base)
+	maps.Copy(merged, override)
+	return merged
+}
+
+// volumeTarget returns the path in the container of a volume such as
+// "./data:/data:ro"
+func volumeTarget(volume string) string {
+	parts := strings.Split(volume, ":")
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return parts[1]
+}
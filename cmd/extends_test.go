@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(path, []byte(`x-base: &base
+  image: mcp/base
+  labels: &base-labels
+    mcp.profile: work
+    mcp.description: Base server
+x-env: &base-env
+  TZ: UTC
+  LOG_LEVEL: info
+
+services:
+  first:
+    <<: *base
+    environment:
+      <<: *base-env
+      LOG_LEVEL: debug
+  second:
+    <<: *base
+    image: mcp/second
+    labels:
+      <<: *base-labels
+      mcp.description: Second server
+`), 0644)
+
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+
+	first := config.Services["first"]
+	if first.Image != "mcp/base" {
+		t.Errorf("Expected the merged image, got %q", first.Image)
+	}
+	if expected := map[string]string{"TZ": "UTC", "LOG_LEVEL": "debug"}; !reflect.DeepEqual(first.Environment, expected) {
+		t.Errorf("Expected %v, got %v", expected, first.Environment)
+	}
+
+	second := config.Services["second"]
+	if second.Image != "mcp/second" {
+		t.Errorf("Expected the service's own image to win, got %q", second.Image)
+	}
+	if expected := map[string]string{"mcp.profile": "work", "mcp.description": "Second server"}; !reflect.DeepEqual(second.Labels, expected) {
+		t.Errorf("Expected %v, got %v", expected, second.Labels)
+	}
+
+	problems, err := validateComposeFile(path, map[string]string{}, "")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("Expected no problems, got %+v, %v", problems, err)
+	}
+}
+
+func TestExtends(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "common.yml"), []byte(`x-mcp:
+  profile: shared
+services:
+  node:
+    command: npx -y @modelcontextprotocol/server-filesystem
+    environment:
+      NODE_ENV: production
+`), 0644)
+	path := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(path, []byte(`services:
+  base:
+    image: mcp/base
+    environment:
+      TZ: UTC
+      LOG_LEVEL: info
+    labels:
+      mcp.description: Base server
+    volumes:
+      - ./data:/data
+      - ./cache:/cache
+  derived:
+    extends: base
+    environment:
+      LOG_LEVEL: debug
+    volumes:
+      - ./other:/data:ro
+  chained:
+    extends:
+      service: derived
+    image: mcp/chained
+  files:
+    extends:
+      file: common.yml
+      service: node
+`), 0644)
+
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+
+	derived := config.Services["derived"]
+	if derived.Image != "mcp/base" || derived.Extends != nil {
+		t.Errorf("Expected derived to be resolved from base, got %+v", derived)
+	}
+	if expected := map[string]string{"TZ": "UTC", "LOG_LEVEL": "debug"}; !reflect.DeepEqual(derived.Environment, expected) {
+		t.Errorf("Expected %v, got %v", expected, derived.Environment)
+	}
+	if expected := []string{"./cache:/cache", "./other:/data:ro"}; !reflect.DeepEqual(derived.Volumes, expected) {
+		t.Errorf("Expected %v, got %v", expected, derived.Volumes)
+	}
+
+	chained := config.Services["chained"]
+	if chained.Image != "mcp/chained" || chained.Environment["LOG_LEVEL"] != "debug" || GetDescription(chained) != "Base server" {
+		t.Errorf("Expected chained to extend derived, got %+v", chained)
+	}
+
+	files := config.Services["files"]
+	if !strings.HasPrefix(files.Command, "npx") || files.Environment["NODE_ENV"] != "production" {
+		t.Errorf("Expected files to extend the service in common.yml, got %+v", files)
+	}
+	if profiles := GetProfiles(files); !reflect.DeepEqual(profiles, []string{"shared"}) {
+		t.Errorf("Expected common.yml's x-mcp defaults, got %v", profiles)
+	}
+
+	problems, err := validateComposeFile(path, map[string]string{}, "")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("Expected no problems, got %+v, %v", problems, err)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		os.WriteFile(path, []byte(`services:
+  a:
+    extends: b
+  b:
+    extends: a
+`), 0644)
+		if _, err := loadComposeFile(path); err == nil || !strings.Contains(err.Error(), "extends cycle") {
+			t.Errorf("Expected an extends cycle error, got %v", err)
+		}
+
+		os.WriteFile(path, []byte(`services:
+  a:
+    extends: missing
+`), 0644)
+		if _, err := loadComposeFile(path); err == nil || !strings.Contains(err.Error(), "no service 'missing'") {
+			t.Errorf("Expected a missing service error, got %v", err)
+		}
+		problems, _ := validateComposeFile(path, map[string]string{}, "")
+		if len(problems) != 1 || !strings.Contains(problems[0].Message, "extends unknown service 'missing'") || problems[0].Line != 3 {
+			t.Errorf("Expected an unknown service problem on line 3, got %+v", problems)
+		}
+	})
+}
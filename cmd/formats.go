@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ToolAdapter translates between MCPConfig and the config file of a tool,
+// which keeps its servers under a key of its own alongside other settings.
+// Adding a tool with a new layout or file format means adding an adapter.
+type ToolAdapter interface {
+	// ServersKey is the top-level key the tool keeps its servers under
+	ServersKey() string
+	// DecodeServer reads one of the tool's server entries
+	DecodeServer(name string, raw json.RawMessage) (MCPServer, error)
+	// EncodeServer returns a server as the tool expects it, with the fields
+	// it requires set and the fields it doesn't support stripped
+	EncodeServer(server MCPServer) any
+	// Parse reads a config file as its top-level settings
+	Parse(data []byte) (map[string]json.RawMessage, error)
+	// Render writes top-level settings as a config file
+	Render(top map[string]json.RawMessage) ([]byte, error)
+	// SupportsInputs reports whether the tool prompts for ${input:<id>}
+	// references declared in a top-level "inputs" list
+	SupportsInputs() bool
+}
+
+// jsonAdapter is the ToolAdapter of a tool with a plain JSON config file
+type jsonAdapter struct {
+	key    string
+	decode func(name string, raw json.RawMessage) (MCPServer, error)
+	encode func(server MCPServer) any
+	inputs bool
+}
+
+func (a jsonAdapter) ServersKey() string { return a.key }
+
+func (a jsonAdapter) DecodeServer(name string, raw json.RawMessage) (MCPServer, error) {
+	return a.decode(name, raw)
+}
+
+func (a jsonAdapter) EncodeServer(server MCPServer) any { return a.encode(server) }
+
+func (a jsonAdapter) SupportsInputs() bool { return a.inputs }
+
+func (a jsonAdapter) Parse(data []byte) (map[string]json.RawMessage, error) {
+	top := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, fmt.Errorf("error parsing config file (comments and trailing commas aren't supported): %w", err)
+	}
+	if top == nil {
+		top = make(map[string]json.RawMessage)
+	}
+	return top, nil
+}
+
+func (a jsonAdapter) Render(top map[string]json.RawMessage) ([]byte, error) {
+	return json.MarshalIndent(top, "", "  ")
+}
+
+// configFormats maps each config file format to the adapter for its layout
+var configFormats = map[string]ToolAdapter{
+	// Claude Desktop, Cursor, Kiro, and Amazon Q: "mcpServers", with "type" only on remote servers
+	"standard": jsonAdapter{key: "mcpServers", decode: decodeStandardServer, encode: func(s MCPServer) any { return s }},
+	// VS Code: "servers", with "type" on every server
+	"vscode": jsonAdapter{key: "servers", decode: decodeStandardServer, encode: encodeVSCodeServer, inputs: true},
+	// Zed: "context_servers" in settings.json
+	"zed": jsonAdapter{key: "context_servers", decode: decodeZedServer, encode: encodeZedServer},
+}
+
+// toolAdapter returns the adapter for a tool's config file format
+// Returns nil for a format without one.
+func toolAdapter(tool string) ToolAdapter {
+	return configFormats[getToolFormat(tool)]
+}
+
+// configFormatNames returns the names of the translatable formats, sorted
+func configFormatNames() []string {
+	names := make([]string, 0, len(configFormats))
+	for name := range configFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decodeStandardServer reads an mcpServers-style entry, normalizing the
+// transport types other tools use for remote and local servers
+func decodeStandardServer(name string, raw json.RawMessage) (MCPServer, error) {
+	var server MCPServer
+	if err := json.Unmarshal(raw, &server); err != nil {
+		return MCPServer{}, fmt.Errorf("server '%s': %w", name, err)
+	}
+	switch server.Type {
+	case "stdio":
+		server.Type = ""
+	case "sse", "streamable-http", "streamableHttp":
+		server.Type = "http"
+	}
+	if server.Type == "" && server.URL != "" {
+		server.Type = "http"
+	}
+	return server, nil
+}
+
+// encodeVSCodeServer writes a server with the "type" VS Code requires
+func encodeVSCodeServer(server MCPServer) any {
+	if server.Type == "" {
+		server.Type = "stdio"
+	}
+	server.Disabled = false
+	server.AutoApprove, server.AlwaysAllow = nil, nil
+	server.Timeout = 0
+	return server
+}
+
+// zedServer is a context server entry in Zed's settings.json
+type zedServer struct {
+	Source  string            `json:"source,omitempty"`
+	Command json.RawMessage   `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// zedCommand is the nested command object of older Zed settings
+type zedCommand struct {
+	Path string            `json:"path"`
+	Args []string          `json:"args,omitempty"`
+	Env  map[string]string `json:"env,omitempty"`
+}
+
+// decodeZedServer reads a context server, in either the current flat layout
+// or the older one that nests the command under "command"
+func decodeZedServer(name string, raw json.RawMessage) (MCPServer, error) {
+	var entry zedServer
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return MCPServer{}, fmt.Errorf("server '%s': %w", name, err)
+	}
+	if entry.URL != "" {
+		return MCPServer{Type: "http", URL: entry.URL, Headers: entry.Headers}, nil
+	}
+
+	server := MCPServer{Args: entry.Args, Env: entry.Env}
+	if err := json.Unmarshal(entry.Command, &server.Command); err != nil {
+		var nested zedCommand
+		if err := json.Unmarshal(entry.Command, &nested); err != nil {
+			return MCPServer{}, fmt.Errorf("server '%s': command must be a string or an object with a path", name)
+		}
+		server = MCPServer{Command: nested.Path, Args: nested.Args, Env: nested.Env}
+	}
+	return server, nil
+}
+
+// encodeZedServer writes a server in Zed's current flat layout
+func encodeZedServer(server MCPServer) any {
+	if server.URL != "" {
+		return zedServer{URL: server.URL, Headers: server.Headers}
+	}
+	command, _ := json.Marshal(server.Command)
+	return zedServer{Source: "custom", Command: command, Args: server.Args, Env: server.Env}
+}
+
+// readToolConfigFile reads the servers from a config file in the given format
+// Returns an empty config if the file doesn't exist
+func readToolConfigFile(path, format string) (MCPConfig, error) {
+	adapter, ok := configFormats[format]
+	if !ok {
+		return MCPConfig{}, fmt.Errorf("unsupported format '%s'", format)
+	}
+
+	top, err := readConfigFile(path, adapter)
+	if err != nil {
+		return MCPConfig{}, err
+	}
+
+	config := MCPConfig{MCPServers: make(map[string]MCPServer)}
+	raw, ok := top[adapter.ServersKey()]
+	if !ok || string(raw) == "null" {
+		return config, nil
+	}
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return MCPConfig{}, fmt.Errorf("error parsing %s in config file: %w", adapter.ServersKey(), err)
+	}
+	for name, entry := range entries {
+		server, err := adapter.DecodeServer(name, entry)
+		if err != nil {
+			return MCPConfig{}, fmt.Errorf("error parsing config file: %w", err)
+		}
+		config.MCPServers[name] = server
+	}
+	return config, nil
+}
+
+// writeToolConfigFile writes the servers to a config file in the given
+// format, keeping the file's other top-level settings
+func writeToolConfigFile(path, format string, config MCPConfig) error {
+	adapter, ok := configFormats[format]
+	if !ok {
+		return fmt.Errorf("unsupported format '%s'", format)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeConfigFile(path, adapter, config)
+}
+
+// writeConfigFile replaces the servers of a config file through its adapter,
+// keeping the file's other top-level settings
+func writeConfigFile(path string, adapter ToolAdapter, config MCPConfig) error {
+	data, err := renderConfigFile(path, adapter, config)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// renderConfigFile returns what writeConfigFile would write to a config file
+func renderConfigFile(path string, adapter ToolAdapter, config MCPConfig) ([]byte, error) {
+	top, err := readConfigFile(path, adapter)
+	if err != nil {
+		return nil, err
+	}
+	return encodeToolConfig(top, adapter, config)
+}
+
+// encodeToolConfig sets the servers in a config file's top-level keys and
+// renders the file
+func encodeToolConfig(top map[string]json.RawMessage, adapter ToolAdapter, config MCPConfig) ([]byte, error) {
+	servers := make(map[string]any, len(config.MCPServers))
+	for name, server := range config.MCPServers {
+		servers[name] = adapter.EncodeServer(server)
+	}
+	encoded, err := json.Marshal(servers)
+	if err != nil {
+		return nil, err
+	}
+	top[adapter.ServersKey()] = encoded
+
+	if len(config.Inputs) > 0 && adapter.SupportsInputs() {
+		inputs, err := mergeConfigInputs(top["inputs"], config.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing inputs in config file: %w", err)
+		}
+		if top["inputs"], err = json.Marshal(inputs); err != nil {
+			return nil, err
+		}
+	}
+	return adapter.Render(top)
+}
+
+// detectConfigFormat returns the format of a config file from the key its
+// servers are under
+func detectConfigFormat(path string) (string, error) {
+	top, err := readConfigObject(path)
+	if err != nil {
+		return "", err
+	}
+	var found []string
+	for _, name := range configFormatNames() {
+		if _, ok := top[configFormats[name].ServersKey()]; ok {
+			found = append(found, name)
+		}
+	}
+	switch len(found) {
+	case 0:
+		var keys []string
+		for _, name := range configFormatNames() {
+			keys = append(keys, configFormats[name].ServersKey())
+		}
+		return "", fmt.Errorf("no MCP servers found (expected one of %s)", strings.Join(keys, ", "))
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("servers found in more than one format (%s)", strings.Join(found, ", "))
+}
+
+// readConfigObject reads a JSON config file as its top-level keys
+// Returns an empty object if the file doesn't exist
+func readConfigObject(path string) (map[string]json.RawMessage, error) {
+	return readConfigFile(path, configFormats["standard"])
+}
+
+// readConfigFile reads a config file as its top-level settings through its adapter
+// Returns no settings if the file doesn't exist
+func readConfigFile(path string, adapter ToolAdapter) (map[string]json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return make(map[string]json.RawMessage), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	return adapter.Parse(data)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// setupVSCodeTool registers VS Code as a custom tool in the current home and
+// writes its config with the given servers, returning the config's path
+func setupVSCodeTool(t *testing.T, servers map[string]MCPServer) string {
+	t.Helper()
+	home := os.Getenv("HOME")
+	path := filepath.Join(home, "vscode", "mcp.json")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.MkdirAll(filepath.Join(home, ".config", "mcp"), 0755)
+	saveCLIConfig(CLIConfig{Tools: map[string]CustomTool{
+		"vscode": {Path: path, Format: "vscode", SupportsRemote: true},
+	}})
+	if err := writeToolConfig(MCPConfig{MCPServers: servers}, path, "vscode"); err != nil {
+		t.Fatalf("writeToolConfig failed: %v", err)
+	}
+	return path
+}
+
+// readVSCodeServers returns the servers of a VS Code config, failing the test
+// if a write left an mcpServers key next to its servers
+func readVSCodeServers(t *testing.T, path string) map[string]MCPServer {
+	t.Helper()
+	top, err := readConfigObject(path)
+	if err != nil {
+		t.Fatalf("readConfigObject failed: %v", err)
+	}
+	if _, ok := top["mcpServers"]; ok {
+		t.Errorf("Expected no mcpServers key in a VS Code config, got keys %v", slices.Sorted(maps.Keys(top)))
+	}
+	config, err := readToolConfigFile(path, "vscode")
+	if err != nil {
+		t.Fatalf("readToolConfigFile failed: %v", err)
+	}
+	return config.MCPServers
+}
+
+func TestWriteToolConfig(t *testing.T) {
+	_, vscodePath, _ := setupCopyTools(t)
+	os.MkdirAll(filepath.Dir(vscodePath), 0755)
+	os.WriteFile(vscodePath, []byte(`{"inputs": []}`), 0644)
+
+	config := MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx", Args: []string{"mcp-server-time"}, Disabled: true}}}
+	if err := writeToolConfig(config, vscodePath, "vscode"); err != nil {
+		t.Fatalf("writeToolConfig failed: %v", err)
+	}
+
+	var settings map[string]any
+	data, _ := os.ReadFile(vscodePath)
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("Invalid config written: %v\n%s", err, data)
+	}
+	want := map[string]any{"time": map[string]any{"type": "stdio", "command": "uvx", "args": []any{"mcp-server-time"}}}
+	if !reflect.DeepEqual(settings["servers"], want) || settings["inputs"] == nil || settings["mcpServers"] != nil {
+		t.Errorf("Expected the VS Code adapter's layout with other settings kept, got %s", data)
+	}
+
+	if adapter := toolAdapter("cursor"); adapter == nil || adapter.ServersKey() != "mcpServers" {
+		t.Errorf("Expected built-in tools to use the standard adapter, got %v", adapter)
+	}
+}
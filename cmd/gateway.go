@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// gatewaySeparator joins a server name and one of its tool names in the
+// gateway's namespaced tool names, e.g. github__create_issue
+const gatewaySeparator = "__"
+
+var (
+	gatewayAllServers bool
+	gatewayHTTP       string
+	gatewayTimeout    time.Duration
+)
+
+// gatewayCmd represents the gateway command
+var gatewayCmd = &cobra.Command{
+	Use:   "gateway [profile]",
+	Short: "Serve every server of a profile as one MCP server",
+	Long: `Start the servers of a profile and expose them together as a single MCP
+server, for clients that only support a few server entries.
+Tools are namespaced by server, e.g. github__create_issue, and each call is
+forwarded to the server that owns the tool. Servers that fail to start are
+reported and left out.
+By default the gateway speaks MCP over stdio, so a client can launch it like any
+local server ('mcp gateway programming'). With --http, it serves the streamable
+HTTP transport at /mcp and the SSE transport at /sse on the given address instead.
+Without arguments, it serves the default servers. With the -a flag, it serves
+all servers.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+		var names []string
+		for name := range enabledServers(filterServers(config, profile, gatewayAllServers)) {
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			return newValidationError("no servers found for the gateway")
+		}
+
+		servers, err := resolveServers(cmd.Context(), composeFile, config, names)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		metricsServer, err := startMetricsServer(metricsAddr)
+		if err != nil {
+			return err
+		}
+		if metricsServer != nil {
+			defer metricsServer.Close()
+		}
+
+		// stdout carries the protocol in stdio mode, so progress goes to stderr
+		gateway, err := startGateway(ctx, os.Stderr, servers, gatewayTimeout)
+		if err != nil {
+			return err
+		}
+		defer gateway.Close()
+
+		if gatewayHTTP == "" {
+			return serveMCPStdio(ctx, os.Stdin, os.Stdout, gateway.handle)
+		}
+		return serveMCPHTTP(ctx, os.Stderr, gatewayHTTP, gateway.handle, "")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gatewayCmd)
+	gatewayCmd.Flags().BoolVarP(&gatewayAllServers, "all", "a", false, "Serve all servers")
+	gatewayCmd.Flags().StringVar(&gatewayHTTP, "http", "", "Serve over HTTP on this address (e.g. :8080) instead of stdio")
+	gatewayCmd.Flags().DurationVar(&gatewayTimeout, "timeout", defaultMCPTimeout, "How long each server gets to start and to answer a request")
+	addMetricsFlag(gatewayCmd)
+}
+
+// mcpGateway aggregates the tools of several servers behind one endpoint
+type mcpGateway struct {
+	backends []*mcpBackend // sorted by name
+	timeout  time.Duration
+}
+
+// startGateway connects to and initializes each server, leaving out those that fail
+func startGateway(ctx context.Context, w io.Writer, servers map[string]MCPServer, timeout time.Duration) (*mcpGateway, error) {
+	var names []string
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gateway := &mcpGateway{timeout: timeout}
+	for _, name := range names {
+		backend, err := startBackend(ctx, name, servers[name], timeout)
+		if err != nil {
+			fmt.Fprintf(w, "Skipped %s: %v\n", name, err)
+			continue
+		}
+		gateway.backends = append(gateway.backends, backend)
+		fmt.Fprintf(w, "Started %s\n", name)
+	}
+
+	if len(gateway.backends) == 0 {
+		return nil, fmt.Errorf("none of the %s could be started", pluralize(len(servers), "server"))
+	}
+	return gateway, nil
+}
+
+// Close ends every server's session
+func (g *mcpGateway) Close() {
+	for _, backend := range g.backends {
+		backend.client.Close()
+	}
+}
+
+// handle answers a client's request to the gateway
+func (g *mcpGateway) handle(ctx context.Context, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		return g.initializeResult(params), nil
+	case "ping":
+		return nil, nil
+	case "tools/list":
+		tools, err := g.listTools(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		return g.callTool(ctx, params)
+	}
+	if strings.HasPrefix(method, "notifications/") {
+		return nil, nil
+	}
+	return nil, &jsonrpcError{Code: jsonrpcMethodNotFound, Message: "method not found: " + method}
+}
+
+// initializeResult describes the gateway, agreeing to the client's protocol
+// version since tool calls are forwarded unchanged
+func (g *mcpGateway) initializeResult(params json.RawMessage) map[string]any {
+	var request struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	json.Unmarshal(params, &request)
+	version := request.ProtocolVersion
+	if version == "" {
+		version = mcpProtocolVersion
+	}
+
+	names := make([]string, 0, len(g.backends))
+	for _, backend := range g.backends {
+		names = append(names, backend.name)
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{}},
+		"serverInfo":      map[string]string{"name": mcpClientName + "-gateway", "version": "dev"},
+		"instructions": fmt.Sprintf("Tools of the servers %s, named <server>%s<tool>.",
+			strings.Join(names, ", "), gatewaySeparator),
+	}
+}
+
+// listTools returns the tools of every server that has them, with their names namespaced
+func (g *mcpGateway) listTools(ctx context.Context) ([]map[string]any, error) {
+	tools := []map[string]any{}
+	for _, backend := range g.backends {
+		if _, ok := backend.info.Capabilities["tools"]; !ok {
+			continue
+		}
+
+		backend.mu.Lock()
+		listCtx, cancel := context.WithTimeout(ctx, g.timeout)
+		// Tools are kept as raw objects so fields the gateway doesn't know,
+		// like annotations and output schemas, reach the client
+		backendTools, err := listAll[map[string]any](listCtx, backend.client, "tools/list", "tools")
+		cancel()
+		backend.mu.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", backend.name, err)
+		}
+
+		for _, tool := range backendTools {
+			name, _ := tool["name"].(string)
+			tool["name"] = backend.name + gatewaySeparator + name
+			tools = append(tools, tool)
+		}
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i]["name"].(string) < tools[j]["name"].(string) })
+	return tools, nil
+}
+
+// callTool forwards a tool call to the server its namespaced name belongs to
+func (g *mcpGateway) callTool(ctx context.Context, params json.RawMessage) (any, error) {
+	var call map[string]json.RawMessage
+	var name string
+	if json.Unmarshal(params, &call) != nil || json.Unmarshal(call["name"], &name) != nil {
+		return nil, &jsonrpcError{Code: jsonrpcInvalidParams, Message: "tools/call needs a tool name"}
+	}
+
+	backend, tool := g.route(name)
+	if backend == nil {
+		return nil, &jsonrpcError{Code: jsonrpcInvalidParams, Message: "unknown tool: " + name}
+	}
+	call["name"], _ = json.Marshal(tool)
+	return backend.call(ctx, g.timeout, "tools/call", call)
+}
+
+// route finds the server a namespaced tool name belongs to, preferring the
+// longest matching server name so names containing the separator still work
+func (g *mcpGateway) route(name string) (*mcpBackend, string) {
+	var match *mcpBackend
+	for _, backend := range g.backends {
+		prefix := backend.name + gatewaySeparator
+		if strings.HasPrefix(name, prefix) && (match == nil || len(backend.name) > len(match.name)) {
+			match = backend
+		}
+	}
+	if match == nil {
+		return nil, ""
+	}
+	return match, strings.TrimPrefix(name, match.name+gatewaySeparator)
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeToolServer is a remote MCP server with one tool that echoes its name
+// and arguments
+func fakeToolServer(t *testing.T, tool string) MCPServer {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     *int64         `json:"id"`
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		result := fakeInitializeResult
+		switch req.Method {
+		case "tools/list":
+			result = fmt.Sprintf(`{"tools":[{"name":%q,"annotations":{"readOnlyHint":true}}]}`, tool)
+		case "tools/call":
+			if req.Params["name"] != tool {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32602,"message":"unknown tool %s"}}`, *req.ID, req.Params["name"])
+				return
+			}
+			result = fmt.Sprintf(`{"content":[{"type":"text","text":"%s %v"}]}`, tool, req.Params["arguments"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, *req.ID, result)
+	}))
+	t.Cleanup(server.Close)
+	return MCPServer{Type: "http", URL: server.URL}
+}
+
+func startTestGateway(t *testing.T) *mcpGateway {
+	t.Helper()
+	stopped := httptest.NewServer(http.NotFoundHandler())
+	stopped.Close()
+
+	var log bytes.Buffer
+	gateway, err := startGateway(context.Background(), &log, map[string]MCPServer{
+		"github":  fakeToolServer(t, "create_issue"),
+		"time":    fakeToolServer(t, "get_time"),
+		"offline": {Type: "http", URL: stopped.URL},
+	}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("startGateway failed: %v", err)
+	}
+	t.Cleanup(gateway.Close)
+
+	if !strings.Contains(log.String(), "Skipped offline") || !strings.Contains(log.String(), "Started time") {
+		t.Errorf("Expected offline to be skipped, got:\n%s", log.String())
+	}
+	return gateway
+}
+
+func TestGatewayHTTP(t *testing.T) {
+	gateway := startTestGateway(t)
+	server := httptest.NewServer(mcpHTTPHandler(gateway.handle))
+	defer server.Close()
+
+	var tools []map[string]any
+	var result mcpToolResult
+	var unknownErr error
+	err := withMCPSession(context.Background(), MCPServer{URL: server.URL}, 5*time.Second, func(ctx context.Context, client *mcpClient, info mcpInitializeResult) error {
+		if _, ok := info.Capabilities["tools"]; !ok {
+			t.Errorf("Expected the gateway to advertise tools, got %+v", info)
+		}
+		var err error
+		if tools, err = listAll[map[string]any](ctx, client, "tools/list", "tools"); err != nil {
+			return err
+		}
+		if result, err = client.CallTool(ctx, "time__get_time", map[string]any{"timezone": "UTC"}); err != nil {
+			return err
+		}
+		_, unknownErr = client.CallTool(ctx, "slack__post", nil)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Session with gateway failed: %v", err)
+	}
+
+	if len(tools) != 2 || tools[0]["name"] != "github__create_issue" || tools[1]["name"] != "time__get_time" {
+		t.Errorf("Expected namespaced tools, got %v", tools)
+	}
+	if _, ok := tools[0]["annotations"]; !ok {
+		t.Errorf("Expected tool annotations to be passed through, got %v", tools[0])
+	}
+	if len(result.Content) != 1 || result.Content[0].Text != "get_time map[timezone:UTC]" {
+		t.Errorf("Expected the call to reach the time server, got %+v", result)
+	}
+	if unknownErr == nil || !strings.Contains(unknownErr.Error(), "unknown tool: slack__post") {
+		t.Errorf("Expected an unknown tool error, got %v", unknownErr)
+	}
+}
+
+func TestGatewayStdio(t *testing.T) {
+	gateway := startTestGateway(t)
+
+	in := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}
+{"jsonrpc":"2.0","method":"notifications/initialized"}
+not json
+{"jsonrpc":"2.0","id":2,"method":"resources/list"}
+`)
+	var out bytes.Buffer
+	if err := serveMCPStdio(context.Background(), in, &out, gateway.handle); err != nil {
+		t.Fatalf("serveMCPStdio failed: %v", err)
+	}
+
+	responses := make(map[string]jsonrpcMessage)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var msg jsonrpcMessage
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("Expected JSON-RPC lines, got %q", line)
+		}
+		responses[string(msg.ID)] = msg
+	}
+
+	if len(responses) != 3 {
+		t.Fatalf("Expected responses to the two requests and the bad line, got:\n%s", out.String())
+	}
+	if !strings.Contains(string(responses["1"].Result), `"protocolVersion":"2025-03-26"`) {
+		t.Errorf("Expected the client's protocol version, got %s", responses["1"].Result)
+	}
+	if responses["2"].Error == nil || responses["2"].Error.Code != jsonrpcMethodNotFound {
+		t.Errorf("Expected method not found, got %+v", responses["2"])
+	}
+	if responses["null"].Error == nil || responses["null"].Error.Code != jsonrpcParseError {
+		t.Errorf("Expected a parse error, got %+v", responses["null"])
+	}
+}
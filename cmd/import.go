@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Ways to handle imported servers whose name is already in the compose file
+const (
+	conflictSkip    = "skip"
+	conflictRename  = "rename"
+	conflictReplace = "replace"
+)
+
+var (
+	importProfile    string
+	importOnConflict string
+	importDryRun     bool
+)
+
+// containerTools are the launchers whose "run" arguments can be mapped back to an image
+var containerTools = map[string]bool{"docker": true, "podman": true, "finch": true, "nerdctl": true}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import servers from a tool config into the compose file",
+	Long: `Import the MCP servers configured in a tool's JSON config into the
+mcp-compose.yml file, creating the file if needed.
+Container servers launched with "docker run" (or podman, finch, nerdctl) are
+mapped back to image, environment, and volumes where possible. Remote servers
+keep their headers as mcp.header.* labels.
+Servers whose name is already in the compose file are skipped by default; use
+--on-conflict rename to import them with the tool name as a suffix, or
+--on-conflict replace to overwrite them.
+Environment values are imported as-is; consider moving secrets to the .env file.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if toolShortcut == "" && configFile == "" {
+			return newValidationError("specify the tool config to import with -t <tool> or -c <path>")
+		}
+
+		path, err := resolveOutputPath(nil)
+		if err != nil {
+			return err
+		}
+
+		source := toolShortcut
+		if source == "" {
+			source = "imported"
+		}
+		return importServers(os.Stdout, composeFile, path, source)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Tool to import from (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	importCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to the MCP JSON configuration file to import")
+	importCmd.Flags().StringVar(&importProfile, "profile", "", "Comma-separated profiles for the mcp.profile label of imported servers")
+	importCmd.Flags().StringVar(&importOnConflict, "on-conflict", conflictSkip, "What to do when a server name already exists (skip, rename, replace)")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without changing the compose file")
+	importCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+	importCmd.RegisterFlagCompletionFunc("on-conflict", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{conflictSkip, conflictRename, conflictReplace}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// importServers converts the servers of a tool config and adds them to the
+// compose file, which is created if it doesn't exist
+// source names the tool and is used as the suffix for renamed servers
+func importServers(w io.Writer, composePath, configPath, source string) error {
+	switch importOnConflict {
+	case conflictSkip, conflictRename, conflictReplace:
+	default:
+		return newValidationError("invalid --on-conflict value '%s' (expected %s, %s, or %s)",
+			importOnConflict, conflictSkip, conflictRename, conflictReplace)
+	}
+
+	config, err := loadImportSource(configPath)
+	if err != nil {
+		return err
+	}
+
+	doc, err := loadComposeDocument(composePath)
+	if os.IsNotExist(err) {
+		doc, err = parseComposeDocument(nil)
+	}
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+
+	verb := "Imported"
+	if importDryRun {
+		verb = "Would import"
+	}
+
+	imported, err := importIntoDocument(w, doc, config, composePath, source, importProfile, importOnConflict, verb)
+	if err != nil {
+		return err
+	}
+
+	if imported == 0 {
+		fmt.Fprintf(w, "No servers imported from %s\n", configPath)
+		return nil
+	}
+	if importDryRun {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(composePath), 0755); err != nil {
+		return newConfigError("create config directory", filepath.Dir(composePath), err)
+	}
+	if err := saveComposeDocument(composePath, doc); err != nil {
+		return newConfigError("write compose file", composePath, err)
+	}
+
+	noun := "servers"
+	if imported == 1 {
+		noun = "server"
+	}
+	fmt.Fprintf(w, "Imported %d %s from %s into %s\n", imported, noun, configPath, composePath)
+	return nil
+}
+
+// loadImportSource reads the tool config to import, which unlike a deploy
+// target must already exist
+func loadImportSource(path string) (MCPConfig, error) {
+	if !fileExists(path) {
+		return MCPConfig{}, newConfigError("load tool config", path, os.ErrNotExist)
+	}
+	config, err := readMCPConfig(path)
+	if err != nil {
+		return MCPConfig{}, newConfigError("load tool config", path, err)
+	}
+	return config, nil
+}
+
+// importIntoDocument adds the servers of a tool config to a compose document,
+// reporting each one with verb, and returns how many were added
+func importIntoDocument(w io.Writer, doc *yaml.Node, config MCPConfig, composePath, source, profile, onConflict, verb string) (int, error) {
+	var names []string
+	for name := range config.MCPServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	imported := 0
+	for _, name := range names {
+		service, err := mcpServerToService(config.MCPServers[name])
+		if err != nil {
+			fmt.Fprintf(w, "Skipped %s: %v\n", name, err)
+			continue
+		}
+		if profile != "" {
+			setServiceLabel(&service, "mcp.profile", profile)
+			if err := ValidateProfileLabel(name, service); err != nil {
+				return imported, err
+			}
+		}
+
+		target := name
+		if mappingIndex(servicesNode(doc, true), name) >= 0 {
+			switch onConflict {
+			case conflictSkip:
+				fmt.Fprintf(w, "Skipped %s: already in %s\n", name, composePath)
+				continue
+			case conflictRename:
+				target = uniqueServiceName(doc, name+"-"+source)
+			case conflictReplace:
+				removeComposeService(doc, name)
+			}
+		}
+
+		addComposeService(doc, target, service)
+		imported++
+		if target != name {
+			fmt.Fprintf(w, "%s %s as %s\n", verb, name, target)
+		} else {
+			fmt.Fprintf(w, "%s %s\n", verb, name)
+		}
+	}
+	return imported, nil
+}
+
+// uniqueServiceName returns name, or name with a numeric suffix, such that it
+// isn't already a service in the compose document
+func uniqueServiceName(doc *yaml.Node, name string) string {
+	services := servicesNode(doc, true)
+	candidate := name
+	for i := 2; mappingIndex(services, candidate) >= 0; i++ {
+		candidate = fmt.Sprintf("%s-%d", name, i)
+	}
+	return candidate
+}
+
+// mcpServerToService reverses convertToMCPConfig for a single server
+// Container launches are mapped back to an image where every argument is
+// understood; otherwise the server is kept as a command.
+func mcpServerToService(server MCPServer) (Service, error) {
+	var service Service
+
+	// Remote server
+	if server.URL != "" {
+		service.Command = server.URL
+		service.Labels = make(map[string]string)
+		for key, value := range server.Headers {
+			service.Labels["mcp.header."+key] = value
+		}
+		// Remote servers need auth labels; an empty placeholder means none
+		if len(server.Headers) == 0 {
+			service.Labels["mcp.header.X-Empty"] = ""
+		}
+		return service, nil
+	}
+
+	if server.Command == "" {
+		return Service{}, fmt.Errorf("neither command nor url is set")
+	}
+
+	// The compose command is split on whitespace, so arguments containing
+	// whitespace can't be represented
+	for _, arg := range append([]string{server.Command}, server.Args...) {
+		if strings.ContainsAny(arg, " \t\n") {
+			return Service{}, fmt.Errorf("argument %q contains whitespace, which a compose command can't represent", arg)
+		}
+	}
+
+	if containerTools[filepath.Base(server.Command)] {
+		if image, ok := parseContainerRun(server.Args, server.Env); ok {
+			return image, nil
+		}
+	}
+
+	service.Command = strings.Join(append([]string{server.Command}, server.Args...), " ")
+	if len(server.Env) > 0 {
+		service.Environment = copyStringMap(server.Env)
+	}
+	return service, nil
+}
+
+// parseContainerRun maps "run -i --rm -e K=V -v SRC:DST IMAGE" arguments back
+// to an image service. env supplies values for "-e KEY" without a value.
+// Arguments after the image are kept only with --entrypoint, as the rest of
+// the entrypoint. Returns false if any argument has no compose equivalent.
+func parseContainerRun(args []string, env map[string]string) (Service, bool) {
+	if len(args) == 0 || args[0] != "run" {
+		return Service{}, false
+	}
+
+	var service Service
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		// Arguments after the image are passed to the entrypoint, which
+		// compose images can only express as part of the entrypoint
+		if service.Image != "" {
+			if len(service.Entrypoint) == 0 {
+				return Service{}, false
+			}
+			service.Entrypoint = append(service.Entrypoint, arg)
+			continue
+		}
+
+		switch arg {
+		case "-i", "--interactive", "--rm", "-t", "--tty", "-it":
+			continue
+		case "-e", "--env", "-v", "--volume", "-p", "--publish", "--entrypoint", "-w", "--workdir", "-u", "--user":
+			if i+1 >= len(args) {
+				return Service{}, false
+			}
+			i++
+			value := args[i]
+			switch arg {
+			case "-v", "--volume":
+				service.Volumes = append(service.Volumes, value)
+				continue
+			case "-p", "--publish":
+				service.Ports = append(service.Ports, value)
+				continue
+			case "--entrypoint":
+				service.Entrypoint = []string{value}
+				continue
+			case "-w", "--workdir":
+				service.WorkingDir = value
+				continue
+			case "-u", "--user":
+				service.User = value
+				continue
+			}
+
+			key, v, ok := strings.Cut(value, "=")
+			if !ok {
+				// "-e KEY" passes the variable through from the server's env
+				if v, ok = env[key]; !ok {
+					return Service{}, false
+				}
+			}
+			if service.Environment == nil {
+				service.Environment = make(map[string]string)
+			}
+			service.Environment[key] = v
+		default:
+			// The first positional argument is the image
+			if strings.HasPrefix(arg, "-") {
+				return Service{}, false
+			}
+			service.Image = arg
+		}
+	}
+
+	if service.Image == "" {
+		return Service{}, false
+	}
+	return service, true
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMCPServerToService(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   MCPServer
+		expected Service
+		wantErr  bool
+	}{
+		{
+			name:     "command with env",
+			server:   MCPServer{Command: "uvx", Args: []string{"mcp-server-time"}, Env: map[string]string{"TZ": "UTC"}},
+			expected: Service{Command: "uvx mcp-server-time", Environment: map[string]string{"TZ": "UTC"}},
+		},
+		{
+			name: "docker run mapped back to image",
+			server: MCPServer{
+				Command: "docker",
+				Args:    []string{"run", "-i", "--rm", "-e", "TOKEN=abc", "-e", "REGION", "-v", "/data:/data", "mcp/github"},
+				Env:     map[string]string{"REGION": "us-east-1"},
+			},
+			expected: Service{
+				Image:       "mcp/github",
+				Environment: map[string]string{"TOKEN": "abc", "REGION": "us-east-1"},
+				Volumes:     []string{"/data:/data"},
+			},
+		},
+		{
+			name:     "docker run with unknown flags stays a command",
+			server:   MCPServer{Command: "docker", Args: []string{"run", "-i", "--network", "host", "mcp/fetch"}},
+			expected: Service{Command: "docker run -i --network host mcp/fetch"},
+		},
+		{
+			name:     "docker run with container args stays a command",
+			server:   MCPServer{Command: "docker", Args: []string{"run", "-i", "mcp/filesystem", "/projects"}},
+			expected: Service{Command: "docker run -i mcp/filesystem /projects"},
+		},
+		{
+			name:   "remote server with headers",
+			server: MCPServer{Type: "http", URL: "https://api.example.com/mcp", Headers: map[string]string{"X-API-Key": "secret"}},
+			expected: Service{
+				Command: "https://api.example.com/mcp",
+				Labels:  map[string]string{"mcp.header.X-API-Key": "secret"},
+			},
+		},
+		{
+			name:     "remote server without headers",
+			server:   MCPServer{Type: "http", URL: "https://api.example.com/mcp"},
+			expected: Service{Command: "https://api.example.com/mcp", Labels: map[string]string{"mcp.header.X-Empty": ""}},
+		},
+		{
+			name:    "argument with whitespace",
+			server:  MCPServer{Command: "node", Args: []string{"/Users/me/My Servers/index.js"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, err := mcpServerToService(tt.server)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", service)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(service, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, service)
+			}
+		})
+	}
+}
+
+func TestImportServers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	originalProfile, originalConflict, originalDryRun := importProfile, importOnConflict, importDryRun
+	defer func() {
+		importProfile, importOnConflict, importDryRun = originalProfile, originalConflict, originalDryRun
+	}()
+	importProfile, importDryRun = "", false
+
+	configPath := filepath.Join(dir, "claude_desktop_config.json")
+	writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{
+		"time":  {Command: "uvx", Args: []string{"mcp-server-time"}},
+		"fetch": {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+	}}, configPath, "")
+
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time --local-timezone UTC
+`), 0644)
+
+	t.Run("skip existing", func(t *testing.T) {
+		importOnConflict = conflictSkip
+		importDryRun = true
+		defer func() { importDryRun = false }()
+
+		var out bytes.Buffer
+		if err := importServers(&out, composePath, configPath, "claude-desktop"); err != nil {
+			t.Fatalf("importServers failed: %v", err)
+		}
+		if out.String() != "Would import fetch\nSkipped time: already in "+composePath+"\n" {
+			t.Errorf("Unexpected output:\n%s", out.String())
+		}
+	})
+
+	t.Run("rename existing", func(t *testing.T) {
+		importOnConflict = conflictRename
+
+		var out bytes.Buffer
+		if err := importServers(&out, composePath, configPath, "claude-desktop"); err != nil {
+			t.Fatalf("importServers failed: %v", err)
+		}
+		if !strings.Contains(out.String(), "Imported time as time-claude-desktop") {
+			t.Errorf("Expected renamed server, got:\n%s", out.String())
+		}
+
+		config, err := loadComposeFile(composePath)
+		if err != nil {
+			t.Fatalf("Failed to load compose file: %v", err)
+		}
+		if config.Services["time"].Command != "uvx mcp-server-time --local-timezone UTC" {
+			t.Errorf("Existing server was changed: %+v", config.Services["time"])
+		}
+		if config.Services["time-claude-desktop"].Command != "uvx mcp-server-time" {
+			t.Errorf("Expected imported server, got %+v", config.Services)
+		}
+		if config.Services["fetch"].Command != "uvx mcp-server-fetch" {
+			t.Errorf("Expected fetch server, got %+v", config.Services)
+		}
+	})
+
+	t.Run("creates compose file", func(t *testing.T) {
+		importOnConflict = conflictSkip
+		importProfile = "imported"
+		defer func() { importProfile = "" }()
+
+		newCompose := filepath.Join(dir, "new", "mcp-compose.yml")
+		var out bytes.Buffer
+		if err := importServers(&out, newCompose, configPath, "claude-desktop"); err != nil {
+			t.Fatalf("importServers failed: %v", err)
+		}
+
+		config, err := loadComposeFile(newCompose)
+		if err != nil {
+			t.Fatalf("Failed to load compose file: %v", err)
+		}
+		if len(config.Services) != 2 || config.Services["time"].Labels["mcp.profile"] != "imported" {
+			t.Errorf("Expected two servers with the imported profile, got %+v", config.Services)
+		}
+	})
+
+	t.Run("missing tool config", func(t *testing.T) {
+		err := importServers(&bytes.Buffer{}, composePath, filepath.Join(dir, "missing.json"), "cursor")
+		if ExitCode(err) != exitCodeConfig {
+			t.Errorf("Expected config error, got %v", err)
+		}
+	})
+
+	t.Run("invalid conflict mode", func(t *testing.T) {
+		importOnConflict = "merge"
+		err := importServers(&bytes.Buffer{}, composePath, configPath, "cursor")
+		if ExitCode(err) != exitCodeValidation {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ComposeInclude is an entry of a compose file's top-level include list:
+// a path or glob, or a mapping whose path is one or a list of them
+type ComposeInclude struct {
+	Paths []string
+}
+
+// UnmarshalYAML accepts the short form, - servers.d/*.yml, as well as the
+// long form, - path: [a.yml, b.yml]
+func (i *ComposeInclude) UnmarshalYAML(value *yaml.Node) error {
+	node := value
+	if node.Kind == yaml.MappingNode {
+		if node = mappingValue(value, "path"); node == nil {
+			return fmt.Errorf("line %d: include entry has no path", value.Line)
+		}
+	}
+	if node.Kind == yaml.SequenceNode {
+		return node.Decode(&i.Paths)
+	}
+	var path string
+	if err := node.Decode(&path); err != nil {
+		return err
+	}
+	i.Paths = []string{path}
+	return nil
+}
+
+// resolveIncludes adds the services of the files a compose file includes.
+// A service defined in the including file is merged over an included one of
+// the same name, while two included files can't define the same service.
+// including lists the files already being parsed, to catch cycles.
+func resolveIncludes(config *ComposeConfig, path string, including []string) error {
+	if len(config.Include) == 0 {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(including, abs) {
+		return fmt.Errorf("include cycle: %s", strings.Join(append(including, abs), " -> "))
+	}
+	including = append(including, abs)
+
+	paths, err := includePaths(config.Include, filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	definedIn := make(map[string]string)
+	for _, includePath := range paths {
+		included, err := parseComposeFile(includePath, including)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", includePath, err)
+		}
+		if config.Services == nil && len(included.Services) > 0 {
+			config.Services = make(map[string]Service, len(included.Services))
+		}
+		for name, service := range included.Services {
+			if other, ok := definedIn[name]; ok {
+				return fmt.Errorf("service '%s' is defined in both %s and %s", name, other, includePath)
+			}
+			definedIn[name] = includePath
+
+			applyXMCPDefaults(&service, included.defaults)
+			if own, ok := config.Services[name]; ok {
+				service = mergeServices(service, own)
+			}
+			config.Services[name] = service
+		}
+	}
+	config.Include = nil
+	return nil
+}
+
+// includePaths returns the files an include list names, relative to dir.
+// Globs are expanded in sorted order and may match nothing, so a directory
+// of server files can be empty.
+func includePaths(includes []ComposeInclude, dir string) ([]string, error) {
+	var paths []string
+	for _, include := range includes {
+		for _, pattern := range include.Paths {
+			if pattern == "~" || strings.HasPrefix(pattern, "~/") || strings.HasPrefix(pattern, "~\\") {
+				homeDir, err := getHomeDir()
+				if err != nil {
+					return nil, err
+				}
+				pattern = filepath.Join(homeDir, pattern[1:])
+			} else if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(dir, pattern)
+			}
+
+			if !strings.ContainsAny(pattern, "*?[") {
+				paths = append(paths, pattern)
+				continue
+			}
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("include %s: %w", pattern, err)
+			}
+			paths = append(paths, matches...)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInclude(t *testing.T) {
+	dir := t.TempDir()
+	serversDir := filepath.Join(dir, "servers.d")
+	os.MkdirAll(serversDir, 0755)
+	os.WriteFile(filepath.Join(serversDir, "time.yml"), []byte(`services:
+  time:
+    command: uvx mcp-server-time
+`), 0644)
+	os.WriteFile(filepath.Join(serversDir, "search.yml"), []byte(`x-mcp:
+  profile: research
+services:
+  search:
+    image: mcp/brave-search
+    environment:
+      LOG_LEVEL: info
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "fetch.yml"), []byte(`services:
+  fetch:
+    command: uvx mcp-server-fetch
+`), 0644)
+
+	path := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(path, []byte(`include:
+  - servers.d/*.yml
+  - path: [fetch.yml]
+  - empty.d/*.yml
+services:
+  search:
+    environment:
+      LOG_LEVEL: debug
+`), 0644)
+
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+	if len(config.Services) != 3 {
+		t.Fatalf("Expected 3 services, got %v", config.Services)
+	}
+	search := config.Services["search"]
+	if search.Image != "mcp/brave-search" || search.Environment["LOG_LEVEL"] != "debug" {
+		t.Errorf("Expected the including file's search to be merged over the included one, got %+v", search)
+	}
+	if profiles := GetProfiles(search); !reflect.DeepEqual(profiles, []string{"research"}) {
+		t.Errorf("Expected the included file's x-mcp defaults, got %v", profiles)
+	}
+	if config.Services["fetch"].Command != "uvx mcp-server-fetch" {
+		t.Errorf("Expected fetch from the long form, got %+v", config.Services["fetch"])
+	}
+
+	problems, err := validateComposeFile(path, map[string]string{}, "")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("Expected no problems, got %+v, %v", problems, err)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		os.WriteFile(filepath.Join(dir, "time-again.yml"), []byte(`services:
+  time:
+    command: uvx mcp-server-time
+`), 0644)
+		os.WriteFile(path, []byte(`include:
+  - servers.d/time.yml
+  - time-again.yml
+`), 0644)
+		if _, err := loadComposeFile(path); err == nil || !strings.Contains(err.Error(), "service 'time' is defined in both") {
+			t.Errorf("Expected a duplicate service error, got %v", err)
+		}
+
+		os.WriteFile(path, []byte(`include:
+  - mcp-compose.yml
+`), 0644)
+		if _, err := loadComposeFile(path); err == nil || !strings.Contains(err.Error(), "include cycle") {
+			t.Errorf("Expected an include cycle error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// initDefaultTemplate is the starter server, which needs no credentials
+const initDefaultTemplate = "time"
+
+var (
+	initGlobal   bool
+	initEnvFile  bool
+	initFrom     bool
+	initTemplate string
+	initProfile  string
+	initForce    bool
+	initYes      bool
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a starter compose file",
+	Long: `Create a starter mcp-compose.yml in the current directory, or in ~/.config/mcp/
+with the --global flag.
+When run in a terminal, it prompts for a first server from the built-in
+templates and its profile; use --template and --profile (or -y to accept the
+defaults) to skip the prompts. Use --template none for an empty file.
+With the --from flag, the compose file is seeded from the servers already
+configured in a tool (-t) or MCP JSON file (-c) instead.
+With the --env flag, it also creates a .env file next to the compose file with a
+placeholder for every variable the servers reference.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := initComposePath(cmd.Flags().Changed("file"))
+		if err != nil {
+			return err
+		}
+
+		var from MCPConfig
+		if initFrom {
+			if toolShortcut == "" && configFile == "" {
+				return newValidationError("--from requires the tool config to seed from with -t <tool> or -c <path>")
+			}
+			fromPath, err := resolveOutputPath(nil)
+			if err != nil {
+				return err
+			}
+			if from, err = loadImportSource(fromPath); err != nil {
+				return err
+			}
+		}
+
+		interactive := !initYes && !initFrom && isTerminal(os.Stdin)
+		return runInit(os.Stdin, os.Stdout, path, from, interactive)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().BoolVar(&initGlobal, "global", false, "Create the compose file in ~/.config/mcp/ instead of the current directory")
+	initCmd.Flags().BoolVar(&initEnvFile, "env", false, "Also create a .env file with placeholders for referenced variables")
+	initCmd.Flags().BoolVar(&initFrom, "from", false, "Seed the compose file from an existing tool config (-t or -c)")
+	initCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Tool to seed from with --from (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	initCmd.Flags().StringVarP(&configFile, "config", "c", "", "MCP JSON configuration file to seed from with --from")
+	initCmd.Flags().StringVar(&initTemplate, "template", "", "Template for the first server, or none (default "+initDefaultTemplate+")")
+	initCmd.Flags().StringVar(&initProfile, "profile", "", "Profile for the first server, or for every server with --from")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite an existing compose file")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "Don't prompt; accept the defaults")
+	initCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+	initCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
+}
+
+// initComposePath returns where init creates the compose file: the -f path
+// when given, the global config directory with --global, or the current directory
+func initComposePath(explicit bool) (string, error) {
+	if explicit {
+		return composeFile, nil
+	}
+	name := getComposeFileNames()[0]
+	if !initGlobal {
+		return name, nil
+	}
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", newConfigError("determine config directory", "", err)
+	}
+	return filepath.Join(configDir, name), nil
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// setTerminalEcho turns the echo of typed input on or off with stty, so
+// secrets can be typed unseen; without stty (e.g. on Windows) input stays visible
+func setTerminalEcho(f *os.File, on bool) {
+	arg := "-echo"
+	if on {
+		arg = "echo"
+	}
+	stty := exec.Command("stty", arg)
+	stty.Stdin = f
+	stty.Run()
+}
+
+// runInit creates the compose file at path, seeded from the servers of from
+// if it has any, or else from a template chosen by flag or prompt
+func runInit(in io.Reader, w io.Writer, path string, from MCPConfig, interactive bool) error {
+	if fileExists(path) && !initForce {
+		return newValidationError("%s already exists (use --force to overwrite it)", path)
+	}
+
+	doc, err := parseComposeDocument(nil)
+	if err != nil {
+		return err
+	}
+	servicesNode(doc, true)
+
+	if len(from.MCPServers) > 0 {
+		if _, err := importIntoDocument(w, doc, from, path, "", initProfile, conflictSkip, "Imported"); err != nil {
+			return err
+		}
+	} else if err := addInitServer(in, w, doc, interactive); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return newConfigError("create config directory", filepath.Dir(path), err)
+	}
+	if err := saveComposeDocument(path, doc); err != nil {
+		return newConfigError("write compose file", path, err)
+	}
+	fmt.Fprintf(w, "Created %s\n", path)
+
+	if initEnvFile {
+		if err := writeInitEnvFile(w, path); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(w, "Add more servers with 'mcp add --template <name>', then deploy them with 'mcp set -t <tool>'")
+	return nil
+}
+
+// addInitServer adds the first server from --template or the prompt
+func addInitServer(in io.Reader, w io.Writer, doc *yaml.Node, interactive bool) error {
+	template, profile := initTemplate, initProfile
+
+	var reader *bufio.Reader
+	if interactive {
+		reader = bufio.NewReader(in)
+		for template == "" {
+			answer := prompt(reader, w, fmt.Sprintf("First server template (%s, or none) [%s]: ",
+				strings.Join(getTemplateNames(), ", "), initDefaultTemplate))
+			if answer == "" {
+				answer = initDefaultTemplate
+			}
+			if _, ok := getTemplate(answer); !ok && answer != "none" {
+				fmt.Fprintf(w, "Unknown template: %s\n", answer)
+				continue
+			}
+			template = answer
+		}
+	}
+
+	if template == "" {
+		template = initDefaultTemplate
+	}
+	if template == "none" {
+		return nil
+	}
+
+	service, ok := getTemplate(template)
+	if !ok {
+		return newValidationError("unknown template: %s (available: %s)", template, strings.Join(getTemplateNames(), ", "))
+	}
+
+	// The template's own profiles are the default answer
+	if interactive && profile == "" {
+		current := strings.Join(GetProfiles(service), ",")
+		if current == "" {
+			current = "default"
+		}
+		profile = prompt(reader, w, fmt.Sprintf("Profile [%s]: ", current))
+	}
+	if profile != "" {
+		setServiceLabel(&service, "mcp.profile", profile)
+	}
+	if err := ValidateProfileLabel(template, service); err != nil {
+		return err
+	}
+
+	addComposeService(doc, template, service)
+	return nil
+}
+
+// prompt writes a question and returns the trimmed answer, or "" at end of input
+func prompt(reader *bufio.Reader, w io.Writer, question string) string {
+	fmt.Fprint(w, question)
+	answer, err := reader.ReadString('\n')
+	if err != nil && answer == "" {
+		// Treat end of input as accepting the default
+		fmt.Fprintln(w)
+		return ""
+	}
+	return strings.TrimSpace(answer)
+}
+
+// writeInitEnvFile creates a .env file next to the compose file with a
+// commented-out placeholder for each variable the servers reference
+// An existing .env file is left untouched.
+func writeInitEnvFile(w io.Writer, composePath string) error {
+	envPath := filepath.Join(filepath.Dir(composePath), ".env")
+	if fileExists(envPath) {
+		fmt.Fprintf(w, "Kept existing %s\n", envPath)
+		return nil
+	}
+
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Environment variables for " + filepath.Base(composePath) + "\n")
+	b.WriteString("# Uncomment and fill in the values; keep this file out of version control\n")
+	for _, usage := range collectEnvVarUsages(config.Services, nil) {
+		b.WriteString("\n")
+		if usage.Doc != "" {
+			b.WriteString("# " + usage.Doc + "\n")
+		}
+		fmt.Fprintf(&b, "# %s=\n", usage.Name)
+	}
+
+	// The file holds secrets, so keep it private
+	if err := os.WriteFile(envPath, []byte(b.String()), 0600); err != nil {
+		return newConfigError("write env file", envPath, err)
+	}
+	fmt.Fprintf(w, "Created %s\n", envPath)
+	return nil
+}
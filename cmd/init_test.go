@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// resetInitFlags restores the init flags after a test
+func resetInitFlags(t *testing.T) {
+	originalTemplate, originalProfile, originalEnv, originalForce := initTemplate, initProfile, initEnvFile, initForce
+	t.Cleanup(func() {
+		initTemplate, initProfile, initEnvFile, initForce = originalTemplate, originalProfile, originalEnv, originalForce
+	})
+	initTemplate, initProfile, initEnvFile, initForce = "", "", false, false
+}
+
+func TestRunInitPrompts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	resetInitFlags(t)
+	initEnvFile = true
+
+	path := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	in := strings.NewReader("nope\ngithub\nprogramming\n")
+
+	var out bytes.Buffer
+	if err := runInit(in, &out, path, MCPConfig{}, true); err != nil {
+		t.Fatalf("runInit failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Unknown template: nope") {
+		t.Errorf("Expected unknown template to be reported, got:\n%s", out.String())
+	}
+
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load compose file: %v", err)
+	}
+	github, ok := config.Services["github"]
+	if !ok || len(config.Services) != 1 {
+		t.Fatalf("Expected only the github server, got %+v", config.Services)
+	}
+	if github.Labels["mcp.profile"] != "programming" {
+		t.Errorf("Expected programming profile, got %q", github.Labels["mcp.profile"])
+	}
+
+	env, err := os.ReadFile(filepath.Join(filepath.Dir(path), ".env"))
+	if err != nil {
+		t.Fatalf("Expected .env to be created: %v", err)
+	}
+	for _, ref := range envVarRefs(github) {
+		if !strings.Contains(string(env), "# "+ref.name+"=\n") {
+			t.Errorf("Expected placeholder for %s in .env, got:\n%s", ref.name, env)
+		}
+	}
+}
+
+func TestRunInitDefaults(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	resetInitFlags(t)
+	path := filepath.Join(t.TempDir(), "mcp-compose.yml")
+
+	// End of input accepts the defaults
+	var out bytes.Buffer
+	if err := runInit(strings.NewReader(""), &out, path, MCPConfig{}, true); err != nil {
+		t.Fatalf("runInit failed: %v", err)
+	}
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load compose file: %v", err)
+	}
+	if _, ok := config.Services[initDefaultTemplate]; !ok || len(config.Services) != 1 {
+		t.Errorf("Expected the %s server, got %+v", initDefaultTemplate, config.Services)
+	}
+
+	t.Run("existing file", func(t *testing.T) {
+		err := runInit(strings.NewReader(""), &bytes.Buffer{}, path, MCPConfig{}, false)
+		if ExitCode(err) != exitCodeValidation {
+			t.Errorf("Expected validation error for existing file, got %v", err)
+		}
+	})
+
+	t.Run("force with no server", func(t *testing.T) {
+		initForce, initTemplate = true, "none"
+		if err := runInit(strings.NewReader(""), &bytes.Buffer{}, path, MCPConfig{}, false); err != nil {
+			t.Fatalf("runInit failed: %v", err)
+		}
+		config, err := loadComposeFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load compose file: %v", err)
+		}
+		if len(config.Services) != 0 {
+			t.Errorf("Expected no servers, got %+v", config.Services)
+		}
+	})
+}
+
+func TestRunInitFrom(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	resetInitFlags(t)
+	initProfile = "work"
+
+	path := filepath.Join(t.TempDir(), "nested", "mcp-compose.yml")
+	from := MCPConfig{MCPServers: map[string]MCPServer{
+		"fetch": {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+	}}
+
+	var out bytes.Buffer
+	if err := runInit(strings.NewReader(""), &out, path, from, false); err != nil {
+		t.Fatalf("runInit failed: %v", err)
+	}
+
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load compose file: %v", err)
+	}
+	fetch := config.Services["fetch"]
+	if len(config.Services) != 1 || fetch.Command != "uvx mcp-server-fetch" || fetch.Labels["mcp.profile"] != "work" {
+		t.Errorf("Expected the imported fetch server, got %+v", config.Services)
+	}
+}
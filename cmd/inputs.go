@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// inputLabelPrefix is the label family marking the variables a client should
+// prompt for instead of having their values written to its config, e.g.
+// mcp.input.API_KEY: "Example API key"
+const inputLabelPrefix = "mcp.input."
+
+// ConfigInput is an entry of the "inputs" list of a VS Code mcp.json, which
+// VS Code prompts for the first time a server referencing ${input:<id>} starts
+type ConfigInput struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	Password    bool   `json:"password,omitempty"`
+}
+
+// GetInputs returns the variables named by a service's mcp.input.<VAR>
+// labels, mapped to their prompt description: the label value, else the
+// variable's mcp.env-doc label, else the variable name
+func GetInputs(service Service) map[string]string {
+	docs := GetEnvDocs(service)
+	inputs := make(map[string]string)
+	for label, value := range service.Labels {
+		name := strings.TrimPrefix(label, inputLabelPrefix)
+		if name == label || name == "" {
+			continue
+		}
+		switch {
+		case strings.TrimSpace(value) != "":
+			inputs[name] = strings.TrimSpace(value)
+		case docs[name] != "":
+			inputs[name] = docs[name]
+		default:
+			inputs[name] = name
+		}
+	}
+	return inputs
+}
+
+// inputReference matches a ${input:<id>} reference in a VS Code config
+var inputReference = regexp.MustCompile(`\$\{input:([^}]+)\}`)
+
+// serverInputIDs returns the sorted ids of the inputs a server entry
+// references, which only a tool that prompts for inputs can resolve
+func serverInputIDs(server MCPServer) []string {
+	values := []string{server.Command, server.URL}
+	values = append(values, server.Args...)
+	for _, value := range server.Env {
+		values = append(values, value)
+	}
+	for _, value := range server.Headers {
+		values = append(values, value)
+	}
+
+	var ids []string
+	for _, value := range values {
+		for _, match := range inputReference.FindAllStringSubmatch(value, -1) {
+			if !containsString(ids, match[1]) {
+				ids = append(ids, match[1])
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// readConfigInputs returns the inputs listed in a config file
+func readConfigInputs(path string, adapter ToolAdapter) ([]ConfigInput, error) {
+	top, err := readConfigFile(path, adapter)
+	if err != nil {
+		return nil, err
+	}
+	var inputs []ConfigInput
+	if raw, ok := top["inputs"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &inputs); err != nil {
+			return nil, err
+		}
+	}
+	return inputs, nil
+}
+
+// inputID returns the input id of a variable, e.g. api-key for API_KEY
+func inputID(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
+
+// applyConfigInputs replaces the values of the variables servers mark with
+// mcp.input.<VAR> labels by ${input:<id>} references in the environment of
+// their MCP JSON entries, and lists the inputs for the client to prompt for
+// Other references are written as convertToMCPConfig writes them.
+func applyConfigInputs(config MCPConfig, servers map[string]Service, envVars map[string]string) MCPConfig {
+	result := MCPConfig{MCPServers: make(map[string]MCPServer, len(config.MCPServers))}
+	used := make(map[string]ConfigInput)
+	for name, server := range config.MCPServers {
+		service, ok := servers[name]
+		inputs := GetInputs(service)
+		if !ok || len(inputs) == 0 || server.URL != "" {
+			result.MCPServers[name] = server
+			continue
+		}
+
+		expand := serviceEnvExpander(service, envVars)
+		env := make(map[string]string, len(server.Env))
+		for key, value := range server.Env {
+			env[key] = value
+		}
+		args := append([]string(nil), server.Args...)
+		for key, value := range service.Environment {
+			replaced := false
+			value = envVarReference.ReplaceAllStringFunc(value, func(ref string) string {
+				match := envVarReference.FindStringSubmatch(ref)
+				variable := match[1] + match[4]
+				description, ok := inputs[variable]
+				if !ok {
+					return expand(ref)
+				}
+				replaced = true
+				used[variable] = ConfigInput{Type: "promptString", ID: inputID(variable), Description: description, Password: true}
+				return "${input:" + inputID(variable) + "}"
+			})
+			if !replaced {
+				continue
+			}
+			env[key] = value
+			// Container servers also pass the variable with -e
+			for i := 1; i < len(args); i++ {
+				if args[i-1] == "-e" && strings.HasPrefix(args[i], key+"=") {
+					args[i] = key + "=" + value
+				}
+			}
+		}
+		server.Env = env
+		server.Args = args
+		result.MCPServers[name] = server
+	}
+
+	for _, input := range used {
+		result.Inputs = append(result.Inputs, input)
+	}
+	sort.Slice(result.Inputs, func(i, j int) bool { return result.Inputs[i].ID < result.Inputs[j].ID })
+	return result
+}
+
+// mergeConfigInputs returns the inputs of a config file with those of
+// inputs added, replacing any with the same id
+func mergeConfigInputs(existing json.RawMessage, inputs []ConfigInput) ([]any, error) {
+	var current []json.RawMessage
+	if len(existing) > 0 && string(existing) != "null" {
+		if err := json.Unmarshal(existing, &current); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		ids[input.ID] = true
+	}
+	var merged []any
+	for _, raw := range current {
+		var entry struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(raw, &entry) == nil && ids[entry.ID] {
+			continue
+		}
+		merged = append(merged, raw)
+	}
+	for _, input := range inputs {
+		merged = append(merged, input)
+	}
+	return merged, nil
+}
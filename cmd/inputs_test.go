@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestApplyToolInputs(t *testing.T) {
+	_, vscodePath, _ := setupCopyTools(t)
+	os.MkdirAll(filepath.Dir(vscodePath), 0755)
+	os.WriteFile(vscodePath, []byte(`{"inputs": [{"type": "promptString", "id": "other"}, {"type": "promptString", "id": "api-key", "description": "old"}]}`), 0644)
+
+	servers := map[string]Service{
+		"example": {
+			Image:       "example/mcp",
+			Environment: map[string]string{"API_KEY": "${API_KEY}", "REGION": "${REGION}"},
+			Labels:      map[string]string{"mcp.input.API_KEY": "Example API key"},
+		},
+	}
+	envVars := map[string]string{"API_KEY": "secret", "REGION": "eu"}
+	config, err := convertToMCPConfig(context.Background(), servers, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+
+	if got := applyToolInputs(config, "cursor", servers, envVars); got.MCPServers["example"].Env["API_KEY"] != "secret" || got.Inputs != nil {
+		t.Errorf("Expected tools without inputs to get the value, got %+v", got)
+	}
+
+	config = applyToolInputs(config, "vscode", servers, envVars)
+	server := config.MCPServers["example"]
+	if server.Env["API_KEY"] != "${input:api-key}" || server.Env["REGION"] != "eu" {
+		t.Errorf("Expected only API_KEY to become an input, got %v", server.Env)
+	}
+	if !slices.Contains(server.Args, "API_KEY=${input:api-key}") || slices.Contains(server.Args, "API_KEY=secret") {
+		t.Errorf("Expected the -e argument to use the input, got %v", server.Args)
+	}
+	want := []ConfigInput{{Type: "promptString", ID: "api-key", Description: "Example API key", Password: true}}
+	if !reflect.DeepEqual(config.Inputs, want) {
+		t.Errorf("Expected inputs %+v, got %+v", want, config.Inputs)
+	}
+
+	if err := writeToolConfig(config, vscodePath, "vscode"); err != nil {
+		t.Fatalf("writeToolConfig failed: %v", err)
+	}
+	data, _ := os.ReadFile(vscodePath)
+	var written struct {
+		Inputs []ConfigInput `json:"inputs"`
+	}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("Invalid config written: %v\n%s", err, data)
+	}
+	want = append([]ConfigInput{{Type: "promptString", ID: "other"}}, want...)
+	if !reflect.DeepEqual(written.Inputs, want) {
+		t.Errorf("Expected existing inputs kept and api-key replaced, got %s", data)
+	}
+
+	// The written file reads back through the VS Code layout
+	read, err := readMCPConfig(vscodePath)
+	if err != nil || read.MCPServers["example"].Env["API_KEY"] != "${input:api-key}" {
+		t.Errorf("Expected readMCPConfig to read the VS Code config, got %+v (%v)", read, err)
+	}
+}
+
+func TestVSCodeInputsSurviveOtherWriters(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := setupVSCodeTool(t, nil)
+	config := MCPConfig{
+		MCPServers: map[string]MCPServer{
+			"example": {Command: "npx", Env: map[string]string{"API_KEY": "${input:api-key}"}},
+			"time":    {Command: "uvx"},
+		},
+		Inputs: []ConfigInput{{Type: "promptString", ID: "api-key", Password: true}},
+	}
+	if err := writeToolConfig(config, path, "vscode"); err != nil {
+		t.Fatalf("writeToolConfig failed: %v", err)
+	}
+
+	if err := disableServers(&bytes.Buffer{}, path, "vscode", []string{"time"}); err != nil {
+		t.Fatalf("disableServers failed: %v", err)
+	}
+	servers := readVSCodeServers(t, path)
+	if servers["example"].Env["API_KEY"] != "${input:api-key}" {
+		t.Errorf("Expected the input reference to be kept, got %v", servers["example"].Env)
+	}
+	data, _ := os.ReadFile(path)
+	var written struct {
+		Inputs []ConfigInput `json:"inputs"`
+	}
+	json.Unmarshal(data, &written)
+	if !reflect.DeepEqual(written.Inputs, config.Inputs) {
+		t.Errorf("Expected the inputs to be kept, got %s", data)
+	}
+}
+
+// setupVSCodeInputs registers VS Code as a custom tool in a temporary home
+// and writes a compose file with a server whose token VS Code prompts for,
+// returning the compose file's path and the VS Code config's path
+func setupVSCodeInputs(t *testing.T) (string, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+	originalScope := configScope
+	t.Cleanup(func() { configScope = originalScope })
+	configScope = scopeUser
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  github:
+    command: npx -y @modelcontextprotocol/server-github
+    environment:
+      GITHUB_TOKEN: ${GITHUB_TOKEN}
+    labels:
+      mcp.input.GITHUB_TOKEN: GitHub token
+`), 0644)
+	return composePath, setupVSCodeTool(t, nil)
+}
+
+// setVSCodeInputs writes the servers of a compose file to the VS Code config
+// at path the way 'mcp set -t vscode' does
+func setVSCodeInputs(t *testing.T, composePath, path string) {
+	t.Helper()
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+	envVars, _ := loadEnvVars(composePath)
+	mcpConfig, err := convertToMCPConfig(context.Background(), config.Services, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+	prepared, err := prepareToolConfig(mcpConfig, "vscode", path, config.Services, envVars, false)
+	if err != nil {
+		t.Fatalf("prepareToolConfig failed: %v", err)
+	}
+	if err := writeToolConfig(prepared.Config, path, "vscode"); err != nil {
+		t.Fatalf("writeToolConfig failed: %v", err)
+	}
+}
+
+// checkVSCodeInputs fails the test unless the VS Code config at path
+// references the token as an input rather than holding its value
+func checkVSCodeInputs(t *testing.T, path string) {
+	t.Helper()
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte("ghp_secret")) {
+		t.Errorf("Expected the token to be left for VS Code to prompt for, got %s", data)
+	}
+	if env := readVSCodeServers(t, path)["github"].Env; env["GITHUB_TOKEN"] != "${input:github-token}" {
+		t.Errorf("Expected the token to reference its input, got %v", env)
+	}
+	var written struct {
+		Inputs []ConfigInput `json:"inputs"`
+	}
+	json.Unmarshal(data, &written)
+	want := []ConfigInput{{Type: "promptString", ID: "github-token", Description: "GitHub token", Password: true}}
+	if !reflect.DeepEqual(written.Inputs, want) {
+		t.Errorf("Expected inputs %+v, got %s", want, data)
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	inspectJSON    bool
+	inspectTimeout time.Duration
+)
+
+// inspectCmd represents the inspect command
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <server>",
+	Short: "Report everything a server exposes",
+	Long: `Connect to a server from the compose file and report its name, version,
+protocol version, capabilities, and instructions, with every tool, resource,
+resource template, and prompt it exposes, for quick checks without the MCP
+Inspector. Only what the server advertises in its capabilities is listed.
+Use --json for a structured report.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		servers, err := resolveServers(cmd.Context(), composeFile, config, args)
+		if err != nil {
+			return err
+		}
+
+		report, err := inspectServer(cmd.Context(), args[0], servers[args[0]], timeoutFlag(cmd, inspectTimeout))
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		if inspectJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		printInspectReport(os.Stdout, report)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+	inspectCmd.Flags().BoolVar(&inspectJSON, "json", false, "Print the report as JSON")
+	inspectCmd.Flags().DurationVar(&inspectTimeout, "timeout", defaultMCPTimeout, "How long the server gets to respond")
+}
+
+// inspectReport is everything a server exposes
+type inspectReport struct {
+	Server            string                `json:"server"`
+	Name              string                `json:"name"`
+	Version           string                `json:"version"`
+	ProtocolVersion   string                `json:"protocolVersion"`
+	Capabilities      []string              `json:"capabilities"`
+	Instructions      string                `json:"instructions,omitempty"`
+	Tools             []mcpTool             `json:"tools"`
+	Resources         []mcpResourceInfo     `json:"resources"`
+	ResourceTemplates []mcpResourceTemplate `json:"resourceTemplates"`
+	Prompts           []mcpPrompt           `json:"prompts"`
+}
+
+// inspectServer initializes a session with a server and lists everything
+// its capabilities advertise
+func inspectServer(ctx context.Context, name string, server MCPServer, timeout time.Duration) (inspectReport, error) {
+	report := inspectReport{
+		Server:            name,
+		Tools:             []mcpTool{},
+		Resources:         []mcpResourceInfo{},
+		ResourceTemplates: []mcpResourceTemplate{},
+		Prompts:           []mcpPrompt{},
+	}
+
+	err := withMCPSession(ctx, server, timeout, func(ctx context.Context, client *mcpClient, info mcpInitializeResult) error {
+		report.Name = info.ServerInfo.Name
+		report.Version = info.ServerInfo.Version
+		report.ProtocolVersion = info.ProtocolVersion
+		report.Capabilities = info.capabilityNames()
+		report.Instructions = info.Instructions
+
+		var err error
+		if _, ok := info.Capabilities["tools"]; ok {
+			if report.Tools, err = client.ListTools(ctx); err != nil {
+				return err
+			}
+		}
+		if _, ok := info.Capabilities["resources"]; ok {
+			if report.Resources, err = client.ListResources(ctx); err != nil {
+				return err
+			}
+			// Templates are optional even for servers with resources
+			templates, err := client.ListResourceTemplates(ctx)
+			if err != nil && !isMethodNotFound(err) {
+				return err
+			}
+			if err == nil {
+				report.ResourceTemplates = templates
+			}
+		}
+		if _, ok := info.Capabilities["prompts"]; ok {
+			if report.Prompts, err = client.ListPrompts(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return report, err
+}
+
+// printInspectReport prints a report as a server summary followed by one
+// section per kind of capability
+func printInspectReport(w io.Writer, report inspectReport) {
+	info := report.Name
+	if report.Version != "" {
+		info += " " + report.Version
+	}
+	capabilities := "none"
+	if len(report.Capabilities) > 0 {
+		capabilities = strings.Join(report.Capabilities, ", ")
+	}
+
+	fmt.Fprintf(w, "Server:       %s\n", info)
+	fmt.Fprintf(w, "Protocol:     %s\n", report.ProtocolVersion)
+	fmt.Fprintf(w, "Capabilities: %s\n", capabilities)
+	if report.Instructions != "" {
+		fmt.Fprintf(w, "Instructions: %s\n", TruncateDescription(strings.Join(strings.Fields(report.Instructions), " "), 100))
+	}
+
+	fmt.Fprintf(w, "\nTools (%d):\n", len(report.Tools))
+	for _, tool := range report.Tools {
+		printInspectItem(w, fmt.Sprintf("%s(%s)", tool.Name, schemaArguments(tool.InputSchema)), tool.Description)
+	}
+
+	fmt.Fprintf(w, "\nResources (%d):\n", len(report.Resources))
+	for _, resource := range report.Resources {
+		printInspectItem(w, resourceLabel(resource.URI, resource.Name, resource.MimeType), resource.Description)
+	}
+
+	fmt.Fprintf(w, "\nResource templates (%d):\n", len(report.ResourceTemplates))
+	for _, template := range report.ResourceTemplates {
+		printInspectItem(w, resourceLabel(template.URITemplate, template.Name, template.MimeType), template.Description)
+	}
+
+	fmt.Fprintf(w, "\nPrompts (%d):\n", len(report.Prompts))
+	for _, prompt := range report.Prompts {
+		var arguments []string
+		for _, argument := range prompt.Arguments {
+			if argument.Required {
+				arguments = append(arguments, argument.Name+"*")
+			} else {
+				arguments = append(arguments, argument.Name)
+			}
+		}
+		printInspectItem(w, fmt.Sprintf("%s(%s)", prompt.Name, strings.Join(arguments, ", ")), prompt.Description)
+	}
+}
+
+// printInspectItem prints an item with the first line of its description indented below it
+func printInspectItem(w io.Writer, label, description string) {
+	fmt.Fprintf(w, "  %s\n", label)
+	description = strings.TrimSpace(description)
+	if i := strings.IndexByte(description, '\n'); i >= 0 {
+		description = strings.TrimSpace(description[:i])
+	}
+	if description != "" {
+		fmt.Fprintf(w, "      %s\n", TruncateDescription(description, 100))
+	}
+}
+
+// resourceLabel formats a resource URI with its name and MIME type
+func resourceLabel(uri, name, mimeType string) string {
+	label := uri
+	if name != "" && name != uri {
+		label += " (" + name + ")"
+	}
+	if mimeType != "" {
+		label += " " + mimeType
+	}
+	return label
+}
+
+// schemaArguments is schemaSummary without the "-" placeholder for no arguments
+func schemaArguments(schema map[string]any) string {
+	if summary := schemaSummary(schema); summary != "-" {
+		return summary
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInspectServer(t *testing.T) {
+	results := map[string]string{
+		"initialize":     `{"protocolVersion":"2025-06-18","capabilities":{"tools":{},"resources":{},"prompts":{}},"serverInfo":{"name":"notes","version":"0.3.0"},"instructions":"Use search before create."}`,
+		"tools/list":     `{"tools":[{"name":"search","description":"Search notes","inputSchema":{"type":"object","properties":{"query":{"type":"string"}},"required":["query"]}}]}`,
+		"resources/list": `{"resources":[{"uri":"notes://inbox","name":"Inbox","mimeType":"text/markdown"}]}`,
+		"prompts/list":   `{"prompts":[{"name":"summarize","description":"Summarize a note","arguments":[{"name":"id","required":true},{"name":"style"}]}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		result, ok := results[req.Method]
+		if !ok {
+			// Templates aren't supported by this server
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32601,"message":"Method not found"}}`, *req.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, *req.ID, result)
+	}))
+	defer server.Close()
+
+	report, err := inspectServer(context.Background(), "notes", MCPServer{URL: server.URL}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("inspectServer failed: %v", err)
+	}
+	if len(report.Tools) != 1 || len(report.Resources) != 1 || len(report.ResourceTemplates) != 0 || len(report.Prompts) != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	var out bytes.Buffer
+	printInspectReport(&out, report)
+	expected := `Server:       notes 0.3.0
+Protocol:     2025-06-18
+Capabilities: prompts, resources, tools
+Instructions: Use search before create.
+
+Tools (1):
+  search(query*: string)
+      Search notes
+
+Resources (1):
+  notes://inbox (Inbox) text/markdown
+
+Resource templates (0):
+
+Prompts (1):
+  summarize(id*, style)
+      Summarize a note
+`
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
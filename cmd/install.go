@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	installName    string
+	installProfile string
+	installYes     bool
+)
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:   "install <registry-name>",
+	Short: "Add a server from the MCP registry to the compose file",
+	Long: `Add a server from the MCP server registry to the mcp-compose.yml file, creating
+the file if needed. Find registry names with 'mcp search'.
+The service is generated from the server's package (npx for npm, uvx for pypi,
+or an image for docker) or its hosted endpoint. Required environment variables
+are referenced as ${VARS} and documented with mcp.env-doc labels.
+When run in a terminal, it prompts for each required variable that isn't set
+yet and saves the answers to the .env file next to the compose file; use -y to
+skip the prompts. The service name defaults to the last part of the registry
+name; use --name to choose another.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newRegistryClient()
+		if err != nil {
+			return err
+		}
+		server, err := client.Get(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		interactive := !installYes && isTerminal(os.Stdin)
+		return installServer(os.Stdin, os.Stdout, composeFile, server, interactive)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(installCmd)
+	installCmd.Flags().StringVar(&installName, "name", "", "Service name in the compose file (default: derived from the registry name)")
+	installCmd.Flags().StringVar(&installProfile, "profile", "", "Comma-separated profiles for the mcp.profile label")
+	installCmd.Flags().BoolVarP(&installYes, "yes", "y", false, "Don't prompt for environment variables")
+}
+
+// installServer adds a registry server to the compose file, prompting for
+// its unset required variables and saving them to .env when interactive
+func installServer(in io.Reader, w io.Writer, composePath string, server registryServer, interactive bool) error {
+	service, err := server.toService()
+	if err != nil {
+		return err
+	}
+
+	name := installName
+	if name == "" {
+		name = server.defaultServiceName()
+	}
+	if installProfile != "" {
+		setServiceLabel(&service, "mcp.profile", installProfile)
+	}
+	if err := ValidateProfileLabel(name, service); err != nil {
+		return err
+	}
+
+	// Check before prompting so answers aren't saved for a server that can't be added
+	if config, err := loadComposeFile(composePath); err == nil {
+		if _, exists := config.Services[name]; exists {
+			return newValidationError("server '%s' already exists in %s (use --name to add it under another name)", name, composePath)
+		}
+	}
+
+	if interactive {
+		if err := promptForEnvVars(in, w, composePath, service); err != nil {
+			return err
+		}
+	}
+
+	return addServer(w, composePath, name, service)
+}
+
+// promptForEnvVars asks for each unset variable a service references and
+// appends the answers to the .env file next to the compose file
+// Empty answers are skipped.
+func promptForEnvVars(in io.Reader, w io.Writer, composePath string, service Service) error {
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return newConfigError("load environment variables", composePath, err)
+	}
+
+	reader := bufio.NewReader(in)
+	values := make(map[string]string)
+	var names []string
+	docs := GetEnvDocs(service)
+	for _, ref := range unresolvedEnvVars(service, envVars) {
+		if _, asked := values[ref.name]; asked {
+			continue
+		}
+		question := ref.name
+		if doc := docs[ref.name]; doc != "" {
+			question += " (" + doc + ")"
+		}
+		values[ref.name] = prompt(reader, w, question+": ")
+		names = append(names, ref.name)
+	}
+
+	envPath := filepath.Join(filepath.Dir(composePath), ".env")
+	var saved, lines []string
+	for _, name := range names {
+		if values[name] != "" {
+			saved = append(saved, name)
+			lines = append(lines, name+"="+quoteEnvValue(values[name]))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	if err := appendEnvFile(envPath, lines); err != nil {
+		return newConfigError("write env file", envPath, err)
+	}
+	fmt.Fprintf(w, "Saved %s to %s\n", strings.Join(saved, ", "), envPath)
+	return nil
+}
+
+// quoteEnvValue double-quotes a .env value that contains whitespace or a #
+func quoteEnvValue(value string) string {
+	if strings.ContainsAny(value, " \t#") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// appendEnvFile appends VAR=value lines to a .env file, creating it with
+// private permissions if needed
+func appendEnvFile(path string, lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Start on a new line if the file doesn't end with one
+	prefix := ""
+	if data, err := os.ReadFile(path); err == nil && len(data) > 0 && data[len(data)-1] != '\n' {
+		prefix = "\n"
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(prefix + strings.Join(lines, "\n") + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegistryServerToService(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   registryServer
+		expected Service
+		wantErr  bool
+	}{
+		{
+			name: "npm with required and optional variables",
+			server: registryServer{
+				Name:        "io.github.example/server-notes",
+				Description: "Notes",
+				Packages: []registryPackage{{
+					RegistryType: "npm",
+					Identifier:   "@example/notes",
+					Version:      "1.2.0",
+					EnvironmentVariables: []registryVariable{
+						{Name: "NOTES_TOKEN", Description: "Notes API token", IsRequired: true},
+						{Name: "NOTES_REGION", Default: "us"},
+						{Name: "NOTES_DEBUG"},
+					},
+				}},
+			},
+			expected: Service{
+				Command:     "npx -y @example/notes@1.2.0",
+				Environment: map[string]string{"NOTES_TOKEN": "${NOTES_TOKEN}", "NOTES_REGION": "us"},
+				Labels: map[string]string{
+					"mcp.env-doc.NOTES_TOKEN": "Notes API token",
+					"mcp.description":         "Notes",
+				},
+			},
+		},
+		{
+			name:     "pypi",
+			server:   registryServer{Packages: []registryPackage{{RegistryType: "pypi", Identifier: "mcp-server-time"}}},
+			expected: Service{Command: "uvx mcp-server-time"},
+		},
+		{
+			name:     "oci image gets the version as tag",
+			server:   registryServer{Packages: []registryPackage{{RegistryType: "oci", Identifier: "ghcr.io/example/notes", Version: "1.0.0"}}},
+			expected: Service{Image: "ghcr.io/example/notes:1.0.0"},
+		},
+		{
+			name: "remote with headers",
+			server: registryServer{Remotes: []registryRemote{{
+				Type:    "streamable-http",
+				URL:     "https://notes.example.com/mcp",
+				Headers: []registryVariable{{Name: "X-API-Key", Description: "Notes API key", IsRequired: true}},
+			}}},
+			expected: Service{
+				Command: "https://notes.example.com/mcp",
+				Labels: map[string]string{
+					"mcp.header.X-API-Key":  "${X_API_KEY}",
+					"mcp.env-doc.X_API_KEY": "Notes API key",
+				},
+			},
+		},
+		{
+			name:    "unsupported package type",
+			server:  registryServer{Name: "x", Packages: []registryPackage{{RegistryType: "nuget", Identifier: "Example.Notes"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, err := tt.server.toService()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", service)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(service, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, service)
+			}
+		})
+	}
+}
+
+func TestDefaultServiceName(t *testing.T) {
+	for registryName, expected := range map[string]string{
+		"io.github.modelcontextprotocol/server-filesystem": "filesystem",
+		"io.github.example/notes-mcp-server":               "notes",
+		"io.github.example/mcp-github":                     "github",
+		"weather":                                          "weather",
+	} {
+		if name := (registryServer{Name: registryName}).defaultServiceName(); name != expected {
+			t.Errorf("%s: expected %s, got %s", registryName, expected, name)
+		}
+	}
+}
+
+func TestInstallServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalName, originalProfile := installName, installProfile
+	defer func() { installName, installProfile = originalName, originalProfile }()
+	installName, installProfile = "", "work"
+
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	server := registryServer{
+		Name: "io.github.example/server-notes",
+		Packages: []registryPackage{{
+			RegistryType: "npm",
+			Identifier:   "@example/notes",
+			EnvironmentVariables: []registryVariable{
+				{Name: "INSTALL_TEST_TOKEN", Description: "Notes API token", IsRequired: true},
+				{Name: "INSTALL_TEST_WORKSPACE", IsRequired: true},
+			},
+		}},
+	}
+
+	var out bytes.Buffer
+	in := strings.NewReader("secret value\n\n")
+	if err := installServer(in, &out, composePath, server, true); err != nil {
+		t.Fatalf("installServer failed: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "INSTALL_TEST_TOKEN (Notes API token): ") {
+		t.Errorf("Expected a documented prompt, got:\n%s", out.String())
+	}
+	env, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil {
+		t.Fatalf("Expected .env to be written: %v", err)
+	}
+	if string(env) != "INSTALL_TEST_TOKEN=\"secret value\"\n" {
+		t.Errorf("Unexpected .env contents: %q", env)
+	}
+	if !strings.Contains(out.String(), "Set INSTALL_TEST_WORKSPACE in your environment") {
+		t.Errorf("Expected a hint for the skipped variable, got:\n%s", out.String())
+	}
+
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		t.Fatalf("Failed to load compose file: %v", err)
+	}
+	notes := config.Services["notes"]
+	if notes.Command != "npx -y @example/notes" || notes.Labels["mcp.profile"] != "work" {
+		t.Errorf("Unexpected service: %+v", notes)
+	}
+
+	t.Run("existing server", func(t *testing.T) {
+		err := installServer(strings.NewReader(""), &bytes.Buffer{}, composePath, server, false)
+		if ExitCode(err) != exitCodeValidation {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+}
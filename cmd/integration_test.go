@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -274,7 +275,10 @@ API_KEY=secret-key-123
 	}
 
 	// Test 4: Verify MCP configuration generation for local servers
-	mcpConfig := convertToMCPConfig(defaultServers, envVars)
+	mcpConfig, err := convertToMCPConfig(context.Background(), defaultServers, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
 
 	if len(mcpConfig.MCPServers) != 2 {
 		t.Errorf("Expected 2 MCP servers, got %d", len(mcpConfig.MCPServers))
@@ -382,7 +386,10 @@ API_KEY=container-secret-123
 
 	// Test 2: Verify MCP configuration generation for container servers
 	servers := filterServers(config, "", false)
-	mcpConfig := convertToMCPConfig(servers, envVars)
+	mcpConfig, err := convertToMCPConfig(context.Background(), servers, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
 
 	containerServer, exists := mcpConfig.MCPServers["container-with-env"]
 	if !exists {
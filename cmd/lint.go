@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var lintFix bool
+
+// Severities of lint rules: 'mcp ci' fails on errors and lists warnings
+const (
+	lintSeverityWarning = "warning"
+	lintSeverityError   = "error"
+)
+
+// lintRule is a style rule checked by 'mcp lint'
+type lintRule struct {
+	name        string
+	description string
+	fixable     bool
+	severity    string
+}
+
+// lintRules lists the rules 'mcp lint' checks, in display order
+var lintRules = []lintRule{
+	{name: "missing-description", description: "servers should have an mcp.description label", severity: lintSeverityWarning},
+	{name: "single-use-profile", description: "profiles should be shared by more than one server", severity: lintSeverityWarning},
+	{name: "unused-env-var", description: "variables in .env should be referenced by a server", severity: lintSeverityWarning},
+	{name: "hardcoded-secret", description: "secrets should be referenced as ${VARS} instead of written inline", fixable: true, severity: lintSeverityError},
+	{name: "latest-image", description: "images should be pinned to a tag other than latest", severity: lintSeverityWarning},
+}
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the compose file for style problems",
+	Long: `Check the compose file against style rules that 'mcp validate' doesn't
+enforce, because the file still works without them:
+` + lintRuleList() + `
+Each warning is printed with its line number and rule, and the command exits 1
+if any are found; 'mcp ci' only fails on rules marked as errors. With --fix,
+fixable warnings are fixed first: hard-coded secrets are moved to .env and
+referenced as ${VARS}.
+Turn rules off with 'mcp config set lint-disable <rule,...>'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+		warnings, err := lintComposeFile(composeFile, envVars, lintDisabledRules())
+		if err != nil {
+			return err
+		}
+
+		if lintFix {
+			warnings, err = fixLintWarnings(os.Stdout, composeFile, envVars, warnings)
+			if err != nil {
+				return err
+			}
+		}
+		return reportLintWarnings(os.Stdout, composeFile, warnings)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "Fix the warnings that can be fixed automatically")
+}
+
+// lintRuleList describes each rule on its own line for the help text
+func lintRuleList() string {
+	var b strings.Builder
+	for _, rule := range lintRules {
+		fmt.Fprintf(&b, "  %-20s %s", rule.name, rule.description)
+		switch {
+		case rule.fixable && rule.severity == lintSeverityError:
+			b.WriteString(" (error, fixable)")
+		case rule.fixable:
+			b.WriteString(" (fixable)")
+		case rule.severity == lintSeverityError:
+			b.WriteString(" (error)")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// lintRuleNames returns the names of the lint rules
+func lintRuleNames() []string {
+	names := make([]string, 0, len(lintRules))
+	for _, rule := range lintRules {
+		names = append(names, rule.name)
+	}
+	return names
+}
+
+// lintRuleSeverity returns the severity of a rule, a warning if it is unknown
+func lintRuleSeverity(name string) string {
+	for _, rule := range lintRules {
+		if rule.name == name {
+			return rule.severity
+		}
+	}
+	return lintSeverityWarning
+}
+
+// lintDisabledRules returns the rules turned off in the CLI config
+func lintDisabledRules() map[string]bool {
+	disabled := make(map[string]bool)
+	config, err := loadCLIConfig()
+	if err != nil {
+		return disabled
+	}
+	for _, name := range strings.Split(config.LintDisable, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			disabled[name] = true
+		}
+	}
+	return disabled
+}
+
+// lintWarning is a rule violation at a line of the compose or .env file
+type lintWarning struct {
+	Path    string
+	Line    int
+	Rule    string
+	Message string
+	secret  *hardcodedSecret // set for hardcoded-secret warnings, to fix them
+}
+
+// lintComposeFile checks a compose file and the .env file next to it against
+// every rule that isn't disabled, returning the warnings in file and line order
+func lintComposeFile(composePath string, envVars map[string]string, disabled map[string]bool) ([]lintWarning, error) {
+	doc, err := loadComposeDocument(composePath)
+	if err != nil {
+		return nil, newConfigError("load compose file", composePath, err)
+	}
+	services := servicesNode(doc, false)
+	if services == nil || services.Kind != yaml.MappingNode {
+		return nil, newValidationError("%s has no services; run 'mcp validate' for details", composePath)
+	}
+
+	var warnings []lintWarning
+	add := func(path string, line int, rule, format string, args ...any) {
+		if !disabled[rule] {
+			warnings = append(warnings, lintWarning{Path: path, Line: line, Rule: rule, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	decoded := make(map[string]Service)
+	keyNodes := make(map[string]*yaml.Node)
+	valueNodes := make(map[string]*yaml.Node)
+	profileUsers := make(map[string][]string)
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		keyNode, valueNode := services.Content[i], services.Content[i+1]
+		name := keyNode.Value
+		var service Service
+		if err := valueNode.Decode(&service); err != nil {
+			return nil, newValidationError("%s:%d: service '%s': %v; run 'mcp validate' for details", composePath, keyNode.Line, name, err)
+		}
+		decoded[name], keyNodes[name], valueNodes[name] = service, keyNode, valueNode
+
+		if GetDescription(service) == "" {
+			add(composePath, keyNode.Line, "missing-description", "service '%s' has no mcp.description label", name)
+		}
+		for _, profile := range GetProfiles(service) {
+			if profile != "default" {
+				profileUsers[profile] = append(profileUsers[profile], name)
+			}
+		}
+		if image := service.Image; image != "" && !strings.ContainsAny(image, "@$") {
+			if pkg, _ := findPackage(name, service); pkg.Current == "" || pkg.Current == "latest" {
+				add(composePath, nodeLine(valueNode, keyNode.Line, "image"), "latest-image",
+					"service '%s': image %s is not pinned; use a version tag so every deploy runs the same image", name, image)
+			}
+		}
+	}
+
+	for profile, users := range profileUsers {
+		if len(users) == 1 {
+			name := users[0]
+			add(composePath, nodeLine(valueNodes[name], keyNodes[name].Line, "labels", "mcp.profile"), "single-use-profile",
+				"service '%s': profile '%s' isn't used by any other server", name, profile)
+		}
+	}
+
+	for _, secret := range scanHardcodedSecrets(decoded) {
+		if !disabled["hardcoded-secret"] {
+			warnings = append(warnings, lintWarning{
+				Path:    composePath,
+				Line:    nodeLine(valueNodes[secret.Server], keyNodes[secret.Server].Line, secret.Path...),
+				Rule:    "hardcoded-secret",
+				Message: secret.String(),
+				secret:  &secret,
+			})
+		}
+	}
+
+	envPath := filepath.Join(filepath.Dir(composePath), ".env")
+	if !disabled["unused-env-var"] {
+		defined, err := envFileVariables(envPath)
+		if err != nil {
+			return nil, newConfigError("load env file", envPath, err)
+		}
+		used := make(map[string]bool)
+		for _, service := range decoded {
+			for _, ref := range envVarRefs(service) {
+				used[ref.name] = true
+			}
+		}
+		for name, line := range defined {
+			if !used[name] {
+				add(envPath, line, "unused-env-var", "variable '%s' isn't referenced by any server", name)
+			}
+		}
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Path != warnings[j].Path {
+			return warnings[i].Path == composePath
+		}
+		return warnings[i].Line < warnings[j].Line
+	})
+	return warnings, nil
+}
+
+// envFileVariables returns the line each variable of a .env file is defined on
+// Returns an empty map if the file doesn't exist.
+func envFileVariables(path string) (map[string]int, error) {
+	lines := make(map[string]int)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return lines, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, _, ok := strings.Cut(line, "="); ok {
+			if _, seen := lines[strings.TrimSpace(key)]; !seen {
+				lines[strings.TrimSpace(key)] = n
+			}
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// fixLintWarnings fixes the fixable warnings, returning the ones that remain
+// Hard-coded secrets are appended to .env and replaced with a reference,
+// unless .env or the environment already sets the variable to another value.
+func fixLintWarnings(w io.Writer, composePath string, envVars map[string]string, warnings []lintWarning) ([]lintWarning, error) {
+	doc, err := loadComposeDocument(composePath)
+	if err != nil {
+		return nil, newConfigError("load compose file", composePath, err)
+	}
+	services := servicesNode(doc, false)
+
+	var remaining []lintWarning
+	var envLines, messages []string
+	added := make(map[string]string)
+	for _, warning := range warnings {
+		secret := warning.secret
+		if secret == nil {
+			remaining = append(remaining, warning)
+			continue
+		}
+
+		value, set := envVars[secret.Variable]
+		if pending, ok := added[secret.Variable]; ok {
+			value, set = pending, true
+		}
+		if set && value != secret.Value {
+			remaining = append(remaining, warning)
+			continue
+		}
+		node := mappingValue(services, secret.Server)
+		for _, key := range secret.Path {
+			node = mappingValue(node, key)
+		}
+		if node == nil {
+			remaining = append(remaining, warning)
+			continue
+		}
+
+		node.Value = "${" + secret.Variable + "}"
+		node.Style = 0
+		if !set {
+			added[secret.Variable] = secret.Value
+			envLines = append(envLines, secret.Variable+"="+quoteEnvValue(secret.Value))
+		}
+		messages = append(messages, fmt.Sprintf("Replaced %s of %s with ${%s}", secret.Path[1], secret.Server, secret.Variable))
+	}
+	if len(messages) == 0 {
+		return remaining, nil
+	}
+
+	// Write .env first, so the compose file never references a missing variable
+	envPath := filepath.Join(filepath.Dir(composePath), ".env")
+	if len(envLines) > 0 {
+		if err := appendEnvFile(envPath, envLines); err != nil {
+			return nil, newConfigError("write env file", envPath, err)
+		}
+	}
+	if err := saveComposeDocument(composePath, doc); err != nil {
+		return nil, newConfigError("write compose file", composePath, err)
+	}
+	for _, message := range messages {
+		fmt.Fprintln(w, message)
+	}
+	if len(envLines) > 0 {
+		fmt.Fprintf(w, "Saved %s to %s\n", pluralize(len(envLines), "variable"), envPath)
+	}
+	return remaining, nil
+}
+
+// reportLintWarnings prints each warning as "path:line: message (rule)" and
+// returns a silent ExitError if there were any
+func reportLintWarnings(w io.Writer, composePath string, warnings []lintWarning) error {
+	if len(warnings) == 0 {
+		fmt.Fprintf(w, "No lint warnings in %s\n", composePath)
+		return nil
+	}
+
+	fixable := 0
+	for _, warning := range warnings {
+		fmt.Fprintf(w, "%s:%d: %s (%s)\n", warning.Path, warning.Line, warning.Message, warning.Rule)
+		if warning.secret != nil {
+			fixable++
+		}
+	}
+	fmt.Fprintf(w, "\n%s", pluralize(len(warnings), "warning"))
+	if fixable > 0 && !lintFix {
+		fmt.Fprintf(w, " (%d fixable with --fix)", fixable)
+	}
+	fmt.Fprintln(w)
+	return &ExitError{Code: exitCodeDifferent}
+}
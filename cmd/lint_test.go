@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintComposeFile(t *testing.T) {
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	compose := `services:
+  github:
+    command: npx -y @modelcontextprotocol/server-github
+    environment:
+      GITHUB_TOKEN: ghp_abcdef123456
+    labels:
+      mcp.description: GitHub tools
+      mcp.profile: programming
+  time:
+    image: mcp/time
+    labels:
+      mcp.description: Time tools
+      mcp.profile: programming, research
+  fetch:
+    image: mcp/fetch:v1.2
+    environment:
+      API_KEY: ${FETCH_API_KEY}
+`
+	if err := os.WriteFile(composePath, []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+	envPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envPath, []byte("# secrets\nFETCH_API_KEY=abc\nOLD_TOKEN=xyz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	envVars := map[string]string{"FETCH_API_KEY": "abc", "OLD_TOKEN": "xyz"}
+
+	warnings, err := lintComposeFile(composePath, envVars, nil)
+	if err != nil {
+		t.Fatalf("lintComposeFile failed: %v", err)
+	}
+	var got []string
+	for _, warning := range warnings {
+		got = append(got, fmt.Sprintf("%s:%d %s", filepath.Base(warning.Path), warning.Line, warning.Rule))
+	}
+	want := []string{
+		"mcp-compose.yml:5 hardcoded-secret",
+		"mcp-compose.yml:10 latest-image",
+		"mcp-compose.yml:13 single-use-profile",
+		"mcp-compose.yml:14 missing-description",
+		".env:3 unused-env-var",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	// Disabled rules aren't reported
+	warnings, _ = lintComposeFile(composePath, envVars, map[string]bool{"missing-description": true, "unused-env-var": true})
+	if len(warnings) != 3 {
+		t.Errorf("Expected 3 warnings with two rules disabled, got %v", warnings)
+	}
+
+	// --fix moves the secret to .env
+	var out bytes.Buffer
+	remaining, err := fixLintWarnings(&out, composePath, envVars, warnings)
+	if err != nil {
+		t.Fatalf("fixLintWarnings failed: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("Expected 2 warnings to remain, got %v", remaining)
+	}
+	data, _ := os.ReadFile(composePath)
+	if !strings.Contains(string(data), "GITHUB_TOKEN: ${GITHUB_TOKEN}") {
+		t.Errorf("Expected the secret to be replaced, got:\n%s", data)
+	}
+	env, _ := os.ReadFile(envPath)
+	if !strings.HasSuffix(string(env), "OLD_TOKEN=xyz\nGITHUB_TOKEN=ghp_abcdef123456\n") {
+		t.Errorf("Expected the secret to be saved to .env, got:\n%s", env)
+	}
+
+	if err := reportLintWarnings(&out, composePath, remaining); ExitCode(err) != exitCodeDifferent {
+		t.Errorf("Expected exit code %d, got %v", exitCodeDifferent, err)
+	}
+}
@@ -1,10 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 	"text/tabwriter"
@@ -20,6 +20,7 @@ var (
 	allTools        bool
 	commandFormat   bool
 	showDescription bool
+	showToolPaths   bool
 )
 
 // listCmd represents the list command
@@ -35,18 +36,23 @@ With the -l flag, it shows detailed information including command and environmen
 With the -s flag, it shows deployment status across configured tools.
 With the -c flag, it shows the executable command with environment variables expanded and inline.
 With the -d flag, it shows server descriptions from the mcp.description label.
+With the --tool-paths flag, it shows each tool's config path, whether it exists, and its server count.
 Descriptions are truncated to 60 characters by default; use -c with -d to show full descriptions.
 The -d flag cannot be combined with -s, -t, or --all-tools flags.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := validateDescriptionFlag(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return &ValidationError{Err: err}
+		}
+
+		// The tool inventory doesn't depend on the compose file
+		if showToolPaths {
+			displayToolPaths()
+			return nil
 		}
 
 		config, err := loadComposeFile(composeFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading compose file: %v\n", err)
-			os.Exit(1)
+			return newConfigError("load compose file", composeFile, err)
 		}
 
 		var profile string
@@ -59,10 +65,10 @@ The -d flag cannot be combined with -s, -t, or --all-tools flags.`,
 
 		// Display the servers
 		if showStatus {
-			displayServersWithStatus(servers)
-		} else {
-			displayServers(servers)
+			return displayServersWithStatus(servers)
 		}
+		displayServers(servers)
+		return nil
 	},
 }
 
@@ -71,10 +77,12 @@ func init() {
 	listCmd.Flags().BoolVarP(&allServers, "all", "a", false, "List all servers")
 	listCmd.Flags().BoolVarP(&longFormat, "long", "l", false, "Show detailed information including command and environment variables")
 	listCmd.Flags().BoolVarP(&showStatus, "status", "s", false, "Show deployment status across configured tools")
-	listCmd.Flags().StringVarP(&toolFilter, "tool", "t", "", "Show status for specific tool only (q-cli, claude-desktop, cursor, kiro)")
+	listCmd.Flags().StringVarP(&toolFilter, "tool", "t", "", "Show status for specific tool only (q-cli, q-ide, claude-desktop, cursor, kiro)")
 	listCmd.Flags().BoolVar(&allTools, "all-tools", false, "Show status across all supported tools")
 	listCmd.Flags().BoolVarP(&commandFormat, "command", "c", false, "Show executable command with environment variables expanded inline. WARNING: may expose sensitive data such as API keys and secrets")
 	listCmd.Flags().BoolVarP(&showDescription, "description", "d", false, "Show server descriptions")
+	listCmd.Flags().BoolVar(&showToolPaths, "tool-paths", false, "Show each tool's config path, whether it exists, and how many servers it contains")
+	listCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
 }
 
 // validateDescriptionFlag checks for incompatible flag combinations with -d/--description
@@ -103,7 +111,7 @@ func displayServers(servers map[string]Service) {
 		}
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	w := tabwriter.NewWriter(&rowStyler{w: os.Stdout, color: useColor(os.Stdout)}, 0, 0, 2, ' ', 0)
 
 	// Display headers based on format
 	if commandFormat {
@@ -147,24 +155,8 @@ func displayServers(servers map[string]Service) {
 		// Categorize servers
 		for name := range config.Services {
 			if _, exists := servers[name]; exists {
-				service := servers[name]
-				isDefault := false
-
 				// Check if this is a default server (no profile or has "default" in profile)
-				profileStr, hasProfile := service.Labels["mcp.profile"]
-				if !hasProfile {
-					isDefault = true
-				} else {
-					profiles := strings.Split(profileStr, ",")
-					for _, p := range profiles {
-						if strings.TrimSpace(p) == "default" {
-							isDefault = true
-							break
-						}
-					}
-				}
-
-				if isDefault {
+				if IsDefaultServer(servers[name]) {
 					defaultServers = append(defaultServers, name)
 				} else {
 					otherServers = append(otherServers, name)
@@ -188,6 +180,20 @@ func displayServers(servers map[string]Service) {
 	}
 
 	w.Flush()
+
+	// The long format points out variables that would fail to resolve on deploy
+	if longFormat && !commandFormat {
+		if envVars == nil {
+			envVars, err = loadEnvVars(composeFile)
+			if err != nil {
+				return
+			}
+		}
+		if missing := missingEnvVarUsages(collectEnvVarUsages(enabledServers(servers), envVars)); len(missing) > 0 {
+			fmt.Println()
+			printMissingEnvVars(os.Stdout, missing)
+		}
+	}
 }
 
 // shellQuote quotes a string for safe use in shell commands
@@ -203,16 +209,81 @@ func shellQuote(s string) string {
 	return "\"" + escaped + "\""
 }
 
+// containerRunOptions returns the --entrypoint, -w, and -u flags and the
+// mcp.docker-args of an image-based service as listed, each value formatted
+// with format
+func containerRunOptions(service Service, format func(string) string) string {
+	var options string
+	if len(service.Entrypoint) > 0 {
+		options += " --entrypoint " + format(service.Entrypoint[0])
+	}
+	if service.WorkingDir != "" {
+		options += " -w " + format(service.WorkingDir)
+	}
+	if service.User != "" {
+		options += " -u " + format(service.User)
+	}
+	// Invalid arguments are reported by validate, and listed as written
+	if extra, err := GetDockerArgs(service); err == nil {
+		for _, arg := range extra {
+			options += " " + format(arg)
+		}
+	} else {
+		options += " " + service.Labels[dockerArgsLabel]
+	}
+	return options
+}
+
+// disabledRowMark starts the name of a disabled server so rowStyler can find
+// its row. It is an empty escaped segment, which a tabwriter passes through
+// without counting it, so the columns stay aligned.
+const disabledRowMark = "\xff\xff" // two tabwriter.Escape bytes
+
+// listedName returns a server's name as listed, marked as disabled for a
+// server turned off with an mcp.disabled label
+func listedName(name string, service Service) string {
+	if IsServiceDisabled(service) {
+		return disabledRowMark + name + " (disabled)"
+	}
+	return name
+}
+
+// useColor reports whether output to f may be styled: f is a terminal and
+// NO_COLOR isn't set
+func useColor(f *os.File) bool {
+	return os.Getenv("NO_COLOR") == "" && isTerminal(f)
+}
+
+// rowStyler writes the lines of a server table, greying out the rows of
+// disabled servers if color is set and dropping their marks
+type rowStyler struct {
+	w     io.Writer
+	color bool
+	line  []byte // the start of a line not yet written
+}
+
+func (s *rowStyler) Write(p []byte) (int, error) {
+	s.line = append(s.line, p...)
+	for {
+		i := bytes.IndexByte(s.line, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := bytes.ReplaceAll(s.line[:i], []byte(disabledRowMark), nil)
+		if s.color && len(line) < i {
+			line = append(append([]byte("\x1b[2m"), line...), "\x1b[0m"...)
+		}
+		if _, err := s.w.Write(append(line, '\n')); err != nil {
+			return 0, err
+		}
+		s.line = s.line[i+1:]
+	}
+}
+
 // Helper function to print a single server row
 func printServerRow(w *tabwriter.Writer, name string, service Service, envVars map[string]string) {
 	// Get profiles
-	var profiles []string
-	if profilesStr, ok := service.Labels["mcp.profile"]; ok {
-		profiles = strings.Split(profilesStr, ",")
-		for i, p := range profiles {
-			profiles[i] = strings.TrimSpace(p)
-		}
-	}
+	profiles := GetProfiles(service)
 	if len(profiles) == 0 {
 		profiles = append(profiles, "default")
 	}
@@ -246,23 +317,12 @@ func printServerRow(w *tabwriter.Writer, name string, service Service, envVars m
 			}
 
 			// Get the container tool from config, default to "docker"
-			containerTool := "docker"
-			configDir := getConfigDir()
-			configPath := filepath.Join(configDir, "config.json")
-
-			if _, err := os.Stat(configPath); err == nil {
-				data, err := os.ReadFile(configPath)
-				if err == nil {
-					var config CLIConfig
-					if err := json.Unmarshal(data, &config); err == nil && config.ContainerTool != "" {
-						containerTool = config.ContainerTool
-					}
-				}
-			}
+			containerTool := getContainerTool()
 
 			if service.Image != "" {
 				// For image-based servers, show the container run command format
 				commandStr = fmt.Sprintf("%s run -i --rm", containerTool)
+				commandStr += containerRunOptions(service, func(s string) string { return shellQuote(expandEnvVars(s, envVars)) })
 
 				// Add environment variables as -e flags
 				var keys []string
@@ -282,8 +342,16 @@ func printServerRow(w *tabwriter.Writer, name string, service Service, envVars m
 					commandStr += fmt.Sprintf(" -v %s", shellQuote(expandedVolume))
 				}
 
-				// Add the image name
+				// Add published ports as -p flags
+				for _, port := range service.Ports {
+					commandStr += fmt.Sprintf(" -p %s", shellQuote(expandEnvVars(port, envVars)))
+				}
+
+				// Add the image name and the rest of the entrypoint
 				commandStr += fmt.Sprintf(" %s", service.Image)
+				for _, arg := range entrypointArgs(service) {
+					commandStr += " " + shellQuote(expandEnvVars(arg, envVars))
+				}
 			} else {
 				// For command-based servers, prepend env vars and expand command
 				expandedCommand := expandEnvVars(service.Command, envVars)
@@ -294,9 +362,9 @@ func printServerRow(w *tabwriter.Writer, name string, service Service, envVars m
 		if showDescription {
 			// Command format shows full description without truncation
 			desc := GetDescription(service)
-			fmt.Fprintf(w, "%s\t%s\t%s\n", name, commandStr, desc)
+			fmt.Fprintf(w, "%s\t%s\t%s\n", listedName(name, service), commandStr, desc)
 		} else {
-			fmt.Fprintf(w, "%s\t%s\n", name, commandStr)
+			fmt.Fprintf(w, "%s\t%s\n", listedName(name, service), commandStr)
 		}
 	} else if longFormat {
 		var commandStr string
@@ -307,23 +375,12 @@ func printServerRow(w *tabwriter.Writer, name string, service Service, envVars m
 			commandStr = service.Command
 		} else {
 			// Get the container tool from config, default to "docker"
-			containerTool := "docker"
-			configDir := getConfigDir()
-			configPath := filepath.Join(configDir, "config.json")
-
-			if _, err := os.Stat(configPath); err == nil {
-				data, err := os.ReadFile(configPath)
-				if err == nil {
-					var config CLIConfig
-					if err := json.Unmarshal(data, &config); err == nil && config.ContainerTool != "" {
-						containerTool = config.ContainerTool
-					}
-				}
-			}
+			containerTool := getContainerTool()
 
 			if service.Image != "" {
 				// For image-based servers, show the container run command format
 				commandStr = fmt.Sprintf("%s run -i --rm", containerTool)
+				commandStr += containerRunOptions(service, func(s string) string { return s })
 
 				// Add environment variables to the command
 				for key := range service.Environment {
@@ -335,8 +392,16 @@ func printServerRow(w *tabwriter.Writer, name string, service Service, envVars m
 					commandStr += fmt.Sprintf(" -v %s", volume)
 				}
 
-				// Add the image name
+				// Add published ports to the command
+				for _, port := range service.Ports {
+					commandStr += fmt.Sprintf(" -p %s", port)
+				}
+
+				// Add the image name and the rest of the entrypoint
 				commandStr += fmt.Sprintf(" %s", service.Image)
+				for _, arg := range entrypointArgs(service) {
+					commandStr += " " + arg
+				}
 			} else {
 				// For command-based servers, show the command
 				commandStr = service.Command
@@ -357,9 +422,9 @@ func printServerRow(w *tabwriter.Writer, name string, service Service, envVars m
 			// Long format shows truncated description
 			desc := GetDescription(service)
 			truncatedDesc := TruncateDescription(desc, MaxDescriptionLength)
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, profilesStr, commandStr, envVarsStr, truncatedDesc)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", listedName(name, service), profilesStr, commandStr, envVarsStr, truncatedDesc)
 		} else {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, profilesStr, commandStr, envVarsStr)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", listedName(name, service), profilesStr, commandStr, envVarsStr)
 		}
 	} else {
 		// Simple format with just name and profiles
@@ -367,37 +432,68 @@ func printServerRow(w *tabwriter.Writer, name string, service Service, envVars m
 			// Simple format shows truncated description
 			desc := GetDescription(service)
 			truncatedDesc := TruncateDescription(desc, MaxDescriptionLength)
-			fmt.Fprintf(w, "%s\t%s\t%s\n", name, profilesStr, truncatedDesc)
+			fmt.Fprintf(w, "%s\t%s\t%s\n", listedName(name, service), profilesStr, truncatedDesc)
 		} else {
-			fmt.Fprintf(w, "%s\t%s\n", name, profilesStr)
+			fmt.Fprintf(w, "%s\t%s\n", listedName(name, service), profilesStr)
+		}
+	}
+}
+
+// displayToolPaths prints the config location, existence, and server count of each tool
+func displayToolPaths() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOOL\tPATH\tEXISTS\tSERVERS")
+	fmt.Fprintln(w, "----\t----\t------\t-------")
+
+	for _, status := range getToolStatuses(getStatusTargets(getAllTools())) {
+		exists := "✗"
+		if status.Exists {
+			exists = "✓"
+		}
+
+		servers := fmt.Sprintf("%d", status.ServerCount)
+		if status.Error != "" {
+			servers = "?"
+		} else if !status.Exists {
+			servers = "-"
 		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status.ToolName, status.ConfigPath, exists, servers)
 	}
+
+	w.Flush()
 }
 
 // displayServersWithStatus displays servers with their deployment status across tools
-func displayServersWithStatus(servers map[string]Service) {
+func displayServersWithStatus(servers map[string]Service) error {
 	if len(servers) == 0 {
 		fmt.Println("No servers found")
-		return
+		return nil
 	}
 
 	// Determine which tools to check
 	var tools []string
 	if toolFilter != "" {
 		// Check if tool shortcut exists
-		if getPlatformToolPath(toolFilter) == "" {
-			fmt.Fprintf(os.Stderr, "Error: unknown tool shortcut: %s\n", toolFilter)
-			os.Exit(1)
+		path, err := getPlatformToolPath(toolFilter)
+		if err != nil {
+			return newConfigError("resolve config path for "+toolFilter, "", err)
+		}
+		if path == "" {
+			return newValidationError("unknown tool shortcut: %s", toolFilter)
 		}
 		tools = []string{toolFilter}
 	} else if allTools {
 		// Get all tool shortcuts
-		tools = supportedTools
+		tools = getAllTools()
 	} else {
 		// Default: show all tools
-		tools = supportedTools
+		tools = getAllTools()
 	}
 
+	// Check project-scoped configs alongside user-level ones
+	tools = getStatusTargets(tools)
+
 	// Load environment variables for comparison
 	envVars, err := loadEnvVars(composeFile)
 	if err != nil {
@@ -408,7 +504,7 @@ func displayServersWithStatus(servers map[string]Service) {
 	// Load tool configs
 	toolConfigs := getToolConfigs(tools)
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	w := tabwriter.NewWriter(&rowStyler{w: os.Stdout, color: useColor(os.Stdout)}, 0, 0, 2, ' ', 0)
 
 	// Print headers
 	if longFormat {
@@ -452,24 +548,8 @@ func displayServersWithStatus(servers map[string]Service) {
 		// Categorize servers
 		for name := range config.Services {
 			if _, exists := servers[name]; exists {
-				service := servers[name]
-				isDefault := false
-
 				// Check if this is a default server (no profile or has "default" in profile)
-				profileStr, hasProfile := service.Labels["mcp.profile"]
-				if !hasProfile {
-					isDefault = true
-				} else {
-					profiles := strings.Split(profileStr, ",")
-					for _, p := range profiles {
-						if strings.TrimSpace(p) == "default" {
-							isDefault = true
-							break
-						}
-					}
-				}
-
-				if isDefault {
+				if IsDefaultServer(servers[name]) {
 					defaultServers = append(defaultServers, name)
 				} else {
 					otherServers = append(otherServers, name)
@@ -493,18 +573,13 @@ func displayServersWithStatus(servers map[string]Service) {
 	}
 
 	w.Flush()
+	return nil
 }
 
 // printServerRowWithStatus prints a server row with status information
 func printServerRowWithStatus(w *tabwriter.Writer, name string, service Service, tools []string, toolConfigs map[string]ToolConfig, envVars map[string]string) {
 	// Get profiles
-	var profiles []string
-	if profilesStr, ok := service.Labels["mcp.profile"]; ok {
-		profiles = strings.Split(profilesStr, ",")
-		for i, p := range profiles {
-			profiles[i] = strings.TrimSpace(p)
-		}
-	}
+	profiles := GetProfiles(service)
 	if len(profiles) == 0 {
 		profiles = append(profiles, "default")
 	}
@@ -530,6 +605,8 @@ func printServerRowWithStatus(w *tabwriter.Writer, name string, service Service,
 			indicator = "✗"
 		case "different":
 			indicator = "~"
+		case "disabled":
+			indicator = "-"
 		case "unknown":
 			indicator = "?"
 		default:
@@ -545,6 +622,8 @@ func printServerRowWithStatus(w *tabwriter.Writer, name string, service Service,
 				indicator = "✗ not configured"
 			case "different":
 				indicator = "~ different"
+			case "disabled":
+				indicator = "- disabled"
 			case "unknown":
 				indicator = "? unknown"
 			default:
@@ -556,17 +635,7 @@ func printServerRowWithStatus(w *tabwriter.Writer, name string, service Service,
 	}
 
 	if longFormat {
-		// Determine server type
-		var serverType string
-		if IsRemoteServer(service) {
-			serverType = "remote"
-		} else if service.Image != "" {
-			serverType = "container"
-		} else {
-			serverType = "local"
-		}
-
-		row := fmt.Sprintf("%s\t%s\t%s", name, profilesStr, serverType)
+		row := fmt.Sprintf("%s\t%s\t%s", listedName(name, service), profilesStr, serverType(service))
 		for _, indicator := range statusIndicators {
 			row += "\t" + indicator
 		}
@@ -579,10 +648,21 @@ func printServerRowWithStatus(w *tabwriter.Writer, name string, service Service,
 		}
 	} else {
 		// Simple format
-		row := fmt.Sprintf("%s\t%s", name, profilesStr)
+		row := fmt.Sprintf("%s\t%s", listedName(name, service), profilesStr)
 		for _, indicator := range statusIndicators {
 			row += "\t" + indicator
 		}
 		fmt.Fprintln(w, row)
 	}
 }
+
+// serverType classifies a service as remote, container, or local
+func serverType(service Service) string {
+	if IsRemoteServer(service) {
+		return "remote"
+	}
+	if service.Image != "" {
+		return "container"
+	}
+	return "local"
+}
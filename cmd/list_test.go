@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"text/tabwriter"
 )
 
 func TestValidateDescriptionFlag(t *testing.T) {
@@ -169,3 +175,56 @@ func TestShellQuote(t *testing.T) {
 		})
 	}
 }
+
+func TestRowStylerDimsDisabledRows(t *testing.T) {
+	disabled := Service{Labels: map[string]string{"mcp.disabled": "true"}}
+	table := func(color bool) string {
+		var out bytes.Buffer
+		w := tabwriter.NewWriter(&rowStyler{w: &out, color: color}, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tPROFILES")
+		fmt.Fprintf(w, "%s\tdefault\n", listedName("time", Service{}))
+		fmt.Fprintf(w, "%s\tdefault\n", listedName("fetch", disabled))
+		w.Flush()
+		return out.String()
+	}
+
+	want := "NAME              PROFILES\ntime              default\nfetch (disabled)  default\n"
+	if got := table(false); got != want {
+		t.Errorf("Expected plain text:\n%q\ngot:\n%q", want, got)
+	}
+
+	want = "NAME              PROFILES\ntime              default\n\x1b[2mfetch (disabled)  default\x1b[0m\n"
+	if got := table(true); got != want {
+		t.Errorf("Expected only the disabled row to be styled:\n%q\ngot:\n%q", want, got)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /dev/null as a character device")
+	}
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	t.Setenv("NO_COLOR", "")
+	if !useColor(devNull) {
+		t.Error("Expected color on a character device")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if useColor(devNull) {
+		t.Error("Expected no color with NO_COLOR set")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if useColor(file) {
+		t.Error("Expected no color when not writing to a terminal")
+	}
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// lockFileHeader is written at the top of every lockfile
+const lockFileHeader = "# Generated by 'mcp lock'; commit it with the compose file. Do not edit.\n"
+
+// lockCmd represents the lock command
+var lockCmd = &cobra.Command{
+	Use:   "lock [server...]",
+	Short: "Record the exact versions of servers in a lockfile",
+	Long: `Resolve the exact version of each server and record it in a lockfile next to
+the compose file (mcp-compose.lock for mcp-compose.yml): the npm or PyPI version
+of npx and uvx packages, the digest of Docker Hub images, and the protocol
+version of remote servers. Unpinned packages and tags resolve to their latest
+release.
+Once the lockfile exists, 'mcp set' deploys the locked versions, and locks
+servers that were added or changed since. Commit it so teammates deploy
+identical versions; 'mcp set --frozen' fails instead of changing it.
+Without arguments, every server is locked again, picking up new releases, and
+servers no longer in the compose file are dropped.`,
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+
+		path := getLockPath(composeFile)
+		lock, _, err := loadLockFile(path)
+		if err != nil {
+			return newConfigError("load lockfile", path, err)
+		}
+
+		names := args
+		if len(names) == 0 {
+			lock.Servers = make(map[string]lockEntry)
+			for name := range config.Services {
+				names = append(names, name)
+			}
+		}
+		for _, name := range names {
+			if _, ok := config.Services[name]; !ok {
+				return newValidationError("server '%s' not found in %s", name, composeFile)
+			}
+		}
+
+		if err := lockServers(cmd.Context(), os.Stdout, lock, config.Services, names, envVars); err != nil {
+			return err
+		}
+		if err := saveLockFile(path, lock); err != nil {
+			return newConfigError("write lockfile", path, err)
+		}
+		fmt.Printf("Wrote %s\n", path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lockCmd)
+}
+
+// lockFile records the resolved version of each server
+type lockFile struct {
+	Servers map[string]lockEntry `yaml:"servers"`
+}
+
+// lockEntry is the resolved version of one server
+type lockEntry struct {
+	// Source is the command or image the entry was resolved from, so changes
+	// to the compose file are noticed
+	Source          string `yaml:"source"`
+	Kind            string `yaml:"kind"` // npm, pypi, docker, or remote
+	Package         string `yaml:"package,omitempty"`
+	Version         string `yaml:"version,omitempty"`
+	Digest          string `yaml:"digest,omitempty"`
+	ProtocolVersion string `yaml:"protocolVersion,omitempty"`
+}
+
+// getLockPath returns the lockfile of a compose file: the same name with a .lock extension
+func getLockPath(composePath string) string {
+	return strings.TrimSuffix(composePath, filepath.Ext(composePath)) + ".lock"
+}
+
+// loadLockFile reads a lockfile, reporting whether it exists
+// A missing lockfile yields an empty one.
+func loadLockFile(path string) (*lockFile, bool, error) {
+	lock := &lockFile{Servers: make(map[string]lockEntry)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return lock, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, true, err
+	}
+	if lock.Servers == nil {
+		lock.Servers = make(map[string]lockEntry)
+	}
+	return lock, true, nil
+}
+
+// saveLockFile writes a lockfile with its servers sorted by name
+func saveLockFile(path string, lock *lockFile) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(lockFileHeader), data...), 0644)
+}
+
+// serviceSource returns what a lock entry of the service is resolved from
+func serviceSource(service Service) string {
+	if service.Image != "" {
+		return service.Image
+	}
+	return service.Command
+}
+
+// lockable reports whether a server's version can be locked: it runs an npx
+// or uvx package or an image, or is remote
+func lockable(name string, service Service) bool {
+	_, ok := findPackage(name, service)
+	return ok || IsRemoteServer(service)
+}
+
+// lockServers resolves the named servers and records them in lock, printing
+// what each was locked to; servers that can't be locked are skipped
+func lockServers(ctx context.Context, w io.Writer, lock *lockFile, services map[string]Service, names []string, envVars map[string]string) error {
+	cache, err := newHTTPCache()
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		service := services[name]
+		if !lockable(name, service) {
+			delete(lock.Servers, name)
+			continue
+		}
+		entry, err := resolveLockEntry(ctx, cache, name, service, envVars)
+		if err != nil {
+			return fmt.Errorf("lock %s: %w", name, err)
+		}
+		lock.Servers[name] = entry
+		fmt.Fprintf(w, "Locked %s to %s\n", name, entry)
+	}
+	return nil
+}
+
+// String describes what an entry locks, e.g. mcp-server-fetch==0.6.2
+func (e lockEntry) String() string {
+	switch e.Kind {
+	case "npm":
+		return e.Package + "@" + e.Version
+	case "pypi":
+		return e.Package + "==" + e.Version
+	case "docker":
+		return e.Package + "@" + e.Digest
+	}
+	return "protocol " + e.ProtocolVersion
+}
+
+// resolveLockEntry resolves the exact version a server runs: the registry
+// version of its package, the digest of its image, or the protocol version
+// a remote server negotiates
+func resolveLockEntry(ctx context.Context, cache *httpCache, name string, service Service, envVars map[string]string) (lockEntry, error) {
+	entry := lockEntry{Source: serviceSource(service)}
+	if IsRemoteServer(service) {
+		resolved, err := convertToMCPConfig(ctx, map[string]Service{name: service}, envVars)
+		if err != nil {
+			return lockEntry{}, err
+		}
+		info, err := handshake(ctx, resolved.MCPServers[name], defaultMCPTimeout)
+		if err != nil {
+			return lockEntry{}, err
+		}
+		entry.Kind, entry.ProtocolVersion = "remote", info.ProtocolVersion
+		return entry, nil
+	}
+
+	pkg, _ := findPackage(name, service)
+	entry.Kind, entry.Package = pkg.Kind, pkg.Name
+	switch pkg.Kind {
+	case "npm":
+		version := pkg.Current
+		if version == "" {
+			version = "latest"
+		}
+		var result struct {
+			Version string `json:"version"`
+		}
+		err := getJSON(ctx, cache, npmRegistryURL+"/"+strings.Replace(pkg.Name, "/", "%2F", 1)+"/"+version, &result)
+		entry.Version = result.Version
+		return entry, err
+	case "pypi":
+		path := "/pypi/" + pkg.Name + "/json"
+		if pkg.Current != "" {
+			path = "/pypi/" + pkg.Name + "/" + pkg.Current + "/json"
+		}
+		var result struct {
+			Info struct {
+				Version string `json:"version"`
+			} `json:"info"`
+		}
+		err := getJSON(ctx, cache, pypiURL+path, &result)
+		entry.Version = result.Info.Version
+		return entry, err
+	}
+
+	tag := pkg.Current
+	if tag == "" {
+		tag = "latest"
+	}
+	repo, err := dockerHubRepo(pkg.Name)
+	if err != nil {
+		return lockEntry{}, err
+	}
+	var result struct {
+		Digest string `json:"digest"`
+	}
+	if err := getJSON(ctx, cache, dockerHubURL+"/v2/repositories/"+repo+"/tags/"+tag, &result); err != nil {
+		return lockEntry{}, err
+	}
+	if result.Digest == "" {
+		return lockEntry{}, fmt.Errorf("no digest found for %s:%s", pkg.Name, tag)
+	}
+	entry.Version, entry.Digest = tag, result.Digest
+	return entry, nil
+}
+
+// applyLockFile pins servers to the versions in the compose file's lockfile,
+// if it has one, first locking servers that were added or changed since
+// With frozen, the lockfile must exist and be up to date, and is never written.
+func applyLockFile(ctx context.Context, w io.Writer, composePath string, servers map[string]Service, envVars map[string]string, frozen bool) (map[string]Service, error) {
+	path := getLockPath(composePath)
+	lock, exists, err := loadLockFile(path)
+	if err != nil {
+		return nil, newConfigError("load lockfile", path, err)
+	}
+	if !exists {
+		if frozen {
+			return nil, newValidationError("--frozen needs a lockfile, but %s doesn't exist; run 'mcp lock' first", path)
+		}
+		return servers, nil
+	}
+
+	var stale []string
+	for name, service := range servers {
+		if entry, ok := lock.Servers[name]; lockable(name, service) && (!ok || entry.Source != serviceSource(service)) {
+			stale = append(stale, name)
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		if frozen {
+			return nil, newValidationError("%s is out of date for %s; run 'mcp lock'", path, strings.Join(stale, ", "))
+		}
+		if err := lockServers(ctx, w, lock, servers, stale, envVars); err != nil {
+			return nil, err
+		}
+		if err := saveLockFile(path, lock); err != nil {
+			return nil, newConfigError("write lockfile", path, err)
+		}
+		fmt.Fprintf(w, "Updated %s\n", path)
+	}
+
+	pinned := make(map[string]Service, len(servers))
+	for name, service := range servers {
+		if entry, ok := lock.Servers[name]; ok {
+			service = lockService(name, service, entry)
+		}
+		pinned[name] = service
+	}
+	return pinned, nil
+}
+
+// lockService returns a copy of a service that runs the locked version
+func lockService(name string, service Service, entry lockEntry) Service {
+	pkg, ok := findPackage(name, service)
+	if !ok {
+		return service
+	}
+	switch entry.Kind {
+	case "npm", "pypi":
+		parts := strings.Fields(service.Command)
+		for i, part := range parts {
+			if part == pkg.pin {
+				parts[i] = pkg.Name + pkg.separator + entry.Version
+				break
+			}
+		}
+		service.Command = strings.Join(parts, " ")
+	case "docker":
+		service.Image += "@" + entry.Digest
+	}
+	return service
+}
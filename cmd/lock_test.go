@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLockFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	releases := map[string]string{
+		"/mcp-time/latest":                    `{"version": "2.0.1"}`,
+		"/pypi/mcp-server-fetch/0.6/json":     `{"info": {"version": "0.6"}}`,
+		"/v2/repositories/mcp/github/tags/v1": `{"digest": "sha256:aaa"}`,
+	}
+	fakePackageRegistries(t, func(w http.ResponseWriter, r *http.Request) {
+		body, ok := releases[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	})
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	servers := map[string]Service{
+		"time":   {Command: "npx -y mcp-time"},
+		"fetch":  {Command: "uvx mcp-server-fetch==0.6"},
+		"github": {Image: "mcp/github:v1"},
+		"local":  {Command: "node server.js"},
+	}
+
+	// Without a lockfile, servers are left alone unless --frozen
+	var out bytes.Buffer
+	if _, err := applyLockFile(context.Background(), &out, composePath, servers, nil, true); err == nil || ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error with --frozen and no lockfile, got %v", err)
+	}
+	unchanged, err := applyLockFile(context.Background(), &out, composePath, servers, nil, false)
+	if err != nil || unchanged["time"].Command != "npx -y mcp-time" {
+		t.Fatalf("Expected servers to be unchanged without a lockfile, got %v, %v", unchanged, err)
+	}
+
+	lockPath := getLockPath(composePath)
+	if filepath.Base(lockPath) != "mcp-compose.lock" {
+		t.Errorf("Expected mcp-compose.lock, got %s", lockPath)
+	}
+	lock, _, _ := loadLockFile(lockPath)
+	if err := lockServers(context.Background(), &out, lock, servers, []string{"fetch", "github", "local", "time"}, nil); err != nil {
+		t.Fatalf("lockServers failed: %v", err)
+	}
+	if err := saveLockFile(lockPath, lock); err != nil {
+		t.Fatalf("saveLockFile failed: %v", err)
+	}
+
+	pinned, err := applyLockFile(context.Background(), &out, composePath, servers, nil, true)
+	if err != nil {
+		t.Fatalf("applyLockFile failed: %v", err)
+	}
+	for name, want := range map[string]string{
+		"time":   "npx -y mcp-time@2.0.1",
+		"fetch":  "uvx mcp-server-fetch==0.6",
+		"github": "mcp/github:v1@sha256:aaa",
+		"local":  "node server.js",
+	} {
+		if got := serviceSource(pinned[name]); got != want {
+			t.Errorf("Expected %s to be pinned to %q, got %q", name, want, got)
+		}
+	}
+
+	// A changed server is out of date: an error with --frozen, relocked otherwise
+	servers["time"] = Service{Command: "npx -y mcp-time --utc"}
+	if _, err := applyLockFile(context.Background(), &out, composePath, servers, nil, true); err == nil || !strings.Contains(err.Error(), "out of date for time") {
+		t.Errorf("Expected the lockfile to be out of date for time, got %v", err)
+	}
+	pinned, err = applyLockFile(context.Background(), &out, composePath, servers, nil, false)
+	if err != nil || pinned["time"].Command != "npx -y mcp-time@2.0.1 --utc" {
+		t.Errorf("Expected time to be relocked, got %v, %v", pinned["time"], err)
+	}
+	data, _ := os.ReadFile(lockPath)
+	if !strings.Contains(string(data), "source: npx -y mcp-time --utc") {
+		t.Errorf("Expected the lockfile to record the new source, got:\n%s", data)
+	}
+}
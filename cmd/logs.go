@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsFollow  bool
+	logsTimeout time.Duration
+)
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs <server>",
+	Short: "Show a server's diagnostic output",
+	Long: `Launch a server from the compose file and print its stdout and stderr with
+timestamps, to see why it fails inside an editor.
+The server is sent an MCP initialize request so it gets through startup. Without
+--follow, it is stopped once it answers (or exits, or --timeout passes); with
+--follow, it keeps running until interrupted.
+For image-based servers, the logs of a running container of the image are shown
+instead, if there is one.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		service, exists := config.Services[name]
+		if !exists {
+			return newValidationError("server '%s' not found in %s", name, composeFile)
+		}
+
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+		if IsRemoteServerWithEnvExpansion(service, envVars) {
+			return newValidationError("server '%s' is a remote server; its logs are only available from its host", name)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		if service.Image != "" {
+			containerTool := getContainerTool()
+			id, err := findRunningContainer(ctx, containerTool, expandEnvVars(service.Image, envVars))
+			if err != nil {
+				return err
+			}
+			if id != "" {
+				return containerLogs(ctx, os.Stdout, os.Stderr, containerTool, id, logsFollow)
+			}
+		}
+
+		server, err := resolveRunServer(cmd.Context(), os.Stderr, composeFile, name)
+		if err != nil {
+			return err
+		}
+		return streamServerLogs(ctx, os.Stdout, server, logsFollow, logsTimeout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().BoolVar(&logsFollow, "follow", false, "Keep the server running and stream its output until interrupted")
+	logsCmd.Flags().DurationVar(&logsTimeout, "timeout", defaultMCPTimeout, "How long to wait for the server to start without --follow")
+}
+
+// findRunningContainer returns the ID of a running container of image, or ""
+func findRunningContainer(ctx context.Context, containerTool, image string) (string, error) {
+	path, err := lookPath(containerTool)
+	if err != nil {
+		return "", fmt.Errorf("%s not found in PATH: %w", containerTool, err)
+	}
+
+	out, err := exec.CommandContext(ctx, path, "ps", "-q", "--filter", "ancestor="+image).Output()
+	if err != nil {
+		return "", fmt.Errorf("list %s containers: %w", containerTool, err)
+	}
+	if ids := strings.Fields(string(out)); len(ids) > 0 {
+		return ids[0], nil
+	}
+	return "", nil
+}
+
+// containerLogs streams a container's logs with the container tool's own timestamps
+func containerLogs(ctx context.Context, stdout, stderr io.Writer, containerTool, id string, follow bool) error {
+	path, err := lookPath(containerTool)
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH: %w", containerTool, err)
+	}
+
+	args := []string{"logs", "--timestamps"}
+	if follow {
+		args = append(args, "--follow")
+	}
+	cmd := exec.CommandContext(ctx, path, append(args, id)...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("%s logs %s: %w", containerTool, id, err)
+	}
+	return nil
+}
+
+// streamServerLogs launches a server, initializes it, and prints its output
+// with timestamps until it answers (or until ctx ends, when following)
+func streamServerLogs(ctx context.Context, w io.Writer, server MCPServer, follow bool, timeout time.Duration) error {
+	cmd, err := serverCommand(ctx, server)
+	if err != nil {
+		return err
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	ready := make(chan struct{})
+	var readyOnce sync.Once
+	logs := &logWriter{w: w, now: time.Now}
+	stdout := logs.stream("stdout", func(line []byte) {
+		var msg jsonrpcMessage
+		if json.Unmarshal(line, &msg) == nil && msg.isResponseTo(1) {
+			readyOnce.Do(func() { close(ready) })
+		}
+	})
+	stderr := logs.stream("stderr", nil)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = mcpCloseTimeout
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", server.Command, err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	id := int64(1)
+	initialize, _ := json.Marshal(jsonrpcRequest{JSONRPC: "2.0", ID: &id, Method: "initialize", Params: map[string]any{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": mcpClientName, "version": "dev"},
+	}})
+	stdin.Write(append(initialize, '\n'))
+
+	var timedOut <-chan time.Time
+	if !follow {
+		timedOut = time.After(timeout)
+	}
+
+	stopped := false
+	for !stopped {
+		select {
+		case exitErr := <-exited:
+			stdout.Flush()
+			stderr.Flush()
+			if exitErr != nil {
+				fmt.Fprintf(w, "Server exited: %v\n", exitErr)
+				return &ExitError{Code: exitCodeError}
+			}
+			fmt.Fprintln(w, "Server exited")
+			return nil
+		case <-ready:
+			initialized, _ := json.Marshal(jsonrpcRequest{JSONRPC: "2.0", Method: "notifications/initialized"})
+			stdin.Write(append(initialized, '\n'))
+			ready = nil
+			stopped = !follow
+		case <-timedOut:
+			fmt.Fprintf(w, "No response to initialize within %s\n", timeout)
+			stopped = true
+		case <-ctx.Done():
+			stopped = true
+		}
+	}
+
+	// Closing stdin asks the server to exit
+	stdin.Close()
+	select {
+	case <-exited:
+	case <-time.After(mcpCloseTimeout):
+		cmd.Process.Kill()
+		<-exited
+	}
+	stdout.Flush()
+	stderr.Flush()
+	return nil
+}
+
+// logWriter prefixes each line written to its streams with a timestamp and
+// the stream name, keeping lines from different streams whole
+type logWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// stream returns a writer for one stream; onLine, if set, sees each complete line
+func (l *logWriter) stream(name string, onLine func(line []byte)) *logStream {
+	return &logStream{logs: l, name: name, onLine: onLine}
+}
+
+// logStream buffers a stream's partial lines until they are complete
+type logStream struct {
+	logs    *logWriter
+	name    string
+	onLine  func(line []byte)
+	partial []byte
+}
+
+func (s *logStream) Write(p []byte) (int, error) {
+	s.partial = append(s.partial, p...)
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		s.writeLine(s.partial[:i])
+		s.partial = s.partial[i+1:]
+	}
+}
+
+// Flush writes a final line that didn't end with a newline
+func (s *logStream) Flush() {
+	if len(s.partial) > 0 {
+		s.writeLine(s.partial)
+		s.partial = nil
+	}
+}
+
+func (s *logStream) writeLine(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if s.onLine != nil {
+		s.onLine(line)
+	}
+
+	s.logs.mu.Lock()
+	defer s.logs.mu.Unlock()
+	fmt.Fprintf(s.logs.w, "%s %s | %s\n", s.logs.now().Format(time.RFC3339), s.name, line)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogStream(t *testing.T) {
+	var out bytes.Buffer
+	logs := &logWriter{w: &out, now: func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }}
+
+	var seen []string
+	stdout := logs.stream("stdout", func(line []byte) { seen = append(seen, string(line)) })
+	stderr := logs.stream("stderr", nil)
+
+	stdout.Write([]byte("hel"))
+	stderr.Write([]byte("warning\r\n"))
+	stdout.Write([]byte("lo\nwor"))
+	stdout.Flush()
+
+	expected := `2025-01-02T03:04:05Z stderr | warning
+2025-01-02T03:04:05Z stdout | hello
+2025-01-02T03:04:05Z stdout | wor
+`
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+	if strings.Join(seen, ",") != "hello,wor" {
+		t.Errorf("Unexpected lines seen: %v", seen)
+	}
+}
+
+func TestStreamServerLogs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	t.Run("stops once initialized", func(t *testing.T) {
+		server := MCPServer{Command: "sh", Args: []string{"-c", `echo "loading config" >&2
+read line
+echo '{"jsonrpc":"2.0","id":1,"result":{}}'
+cat > /dev/null
+echo "shutting down" >&2`}}
+
+		var out bytes.Buffer
+		if err := streamServerLogs(context.Background(), &out, server, false, 5*time.Second); err != nil {
+			t.Fatalf("streamServerLogs failed: %v", err)
+		}
+		for _, want := range []string{"stderr | loading config", `stdout | {"jsonrpc":"2.0","id":1,"result":{}}`, "stderr | shutting down"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", want, out.String())
+			}
+		}
+	})
+
+	t.Run("crash", func(t *testing.T) {
+		server := MCPServer{Command: "sh", Args: []string{"-c", `echo "GITHUB_TOKEN is required" >&2; exit 2`}}
+
+		var out bytes.Buffer
+		err := streamServerLogs(context.Background(), &out, server, false, 5*time.Second)
+		if ExitCode(err) != exitCodeError {
+			t.Errorf("Expected exit code %d, got %v", exitCodeError, err)
+		}
+		if !strings.Contains(out.String(), "stderr | GITHUB_TOKEN is required\nServer exited: exit status 2\n") {
+			t.Errorf("Unexpected output:\n%s", out.String())
+		}
+	})
+}
+
+func TestFindRunningContainer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "docker")
+	os.WriteFile(script, []byte("#!/bin/sh\nif [ \"$4\" = \"ancestor=mcp/time\" ]; then echo abc123; echo def456; fi\n"), 0755)
+	t.Setenv("PATH", dir)
+
+	if id, err := findRunningContainer(context.Background(), "docker", "mcp/time"); err != nil || id != "abc123" {
+		t.Errorf("Expected abc123, got %q, %v", id, err)
+	}
+	if id, err := findRunningContainer(context.Background(), "docker", "mcp/fetch"); err != nil || id != "" {
+		t.Errorf("Expected no container, got %q, %v", id, err)
+	}
+}
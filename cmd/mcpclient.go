@@ -0,0 +1,696 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// mcpProtocolVersion is the MCP protocol revision the client requests
+	mcpProtocolVersion = "2025-06-18"
+
+	// mcpClientName identifies the CLI to servers during initialization
+	mcpClientName = "mcp-cli"
+
+	// mcpStderrLimit bounds how much of a stdio server's stderr is kept for error messages
+	mcpStderrLimit = 4096
+
+	// mcpCloseTimeout is how long a stdio server gets to exit after its stdin is closed
+	mcpCloseTimeout = 2 * time.Second
+
+	// JSON-RPC error codes for malformed messages, unknown methods, bad
+	// parameters, and failures while handling a request
+	jsonrpcParseError     = -32700
+	jsonrpcMethodNotFound = -32601
+	jsonrpcInvalidParams  = -32602
+	jsonrpcInternalError  = -32603
+
+	// defaultMCPTimeout bounds how long a session with a server may take
+	defaultMCPTimeout = 30 * time.Second
+)
+
+// jsonrpcRequest is a JSON-RPC 2.0 request, or a notification when ID is nil
+type jsonrpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      *int64 `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// jsonrpcMessage is any JSON-RPC 2.0 message received from a server or
+// client: a response to one of our requests, or a request or notification
+type jsonrpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
+}
+
+// isResponseTo reports whether the message is the response to request id
+func (m jsonrpcMessage) isResponseTo(id int64) bool {
+	return m.Method == "" && string(m.ID) == strconv.FormatInt(id, 10)
+}
+
+// jsonrpcError is the error of a failed JSON-RPC request
+type jsonrpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *jsonrpcError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// mcpTransport carries JSON-RPC messages to an MCP server
+type mcpTransport interface {
+	// Request sends a request and waits for the response with the same ID
+	Request(ctx context.Context, req jsonrpcRequest) (jsonrpcMessage, error)
+	// Notify sends a notification, which has no response
+	Notify(ctx context.Context, req jsonrpcRequest) error
+	// Close ends the session and releases the connection or process
+	Close() error
+}
+
+// mcpClient is a minimal MCP client over a stdio or streamable HTTP transport
+type mcpClient struct {
+	transport mcpTransport
+	nextID    atomic.Int64
+}
+
+// mcpInitializeResult is a server's response to the initialize request
+type mcpInitializeResult struct {
+	ProtocolVersion string                     `json:"protocolVersion"`
+	Capabilities    map[string]json.RawMessage `json:"capabilities"`
+	ServerInfo      struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"serverInfo"`
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// capabilityNames returns the server's advertised capabilities, sorted
+func (r mcpInitializeResult) capabilityNames() []string {
+	names := make([]string, 0, len(r.Capabilities))
+	for name := range r.Capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// connectMCPServer starts a local server or opens a session with a remote one
+func connectMCPServer(server MCPServer) (*mcpClient, error) {
+	if server.URL != "" {
+		return &mcpClient{transport: newHTTPTransport(server)}, nil
+	}
+
+	transport, err := newStdioTransport(server)
+	if err != nil {
+		return nil, err
+	}
+	return &mcpClient{transport: transport}, nil
+}
+
+// Initialize performs the MCP handshake and returns what the server advertised
+func (c *mcpClient) Initialize(ctx context.Context) (mcpInitializeResult, error) {
+	params := map[string]any{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": mcpClientName, "version": "dev"},
+	}
+
+	var result mcpInitializeResult
+	if err := c.call(ctx, "initialize", params, &result); err != nil {
+		return mcpInitializeResult{}, fmt.Errorf("initialize: %w", err)
+	}
+
+	if t, ok := c.transport.(*httpTransport); ok {
+		t.protocolVersion = result.ProtocolVersion
+	}
+
+	if err := c.transport.Notify(ctx, jsonrpcRequest{JSONRPC: "2.0", Method: "notifications/initialized"}); err != nil {
+		return mcpInitializeResult{}, fmt.Errorf("initialized notification: %w", err)
+	}
+	return result, nil
+}
+
+// mcpTool is a tool advertised by a server's tools/list
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema,omitempty"`
+}
+
+// ListTools returns every tool the server exposes
+func (c *mcpClient) ListTools(ctx context.Context) ([]mcpTool, error) {
+	return listAll[mcpTool](ctx, c, "tools/list", "tools")
+}
+
+// mcpResourceInfo is a resource advertised by a server's resources/list
+type mcpResourceInfo struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// mcpResourceTemplate is a parameterized resource advertised by resources/templates/list
+type mcpResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// mcpPrompt is a prompt template advertised by prompts/list
+type mcpPrompt struct {
+	Name        string              `json:"name"`
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Arguments   []mcpPromptArgument `json:"arguments,omitempty"`
+}
+
+// mcpPromptArgument is an argument a prompt template accepts
+type mcpPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// ListResources returns every resource the server exposes
+func (c *mcpClient) ListResources(ctx context.Context) ([]mcpResourceInfo, error) {
+	return listAll[mcpResourceInfo](ctx, c, "resources/list", "resources")
+}
+
+// ListResourceTemplates returns every resource template the server exposes
+func (c *mcpClient) ListResourceTemplates(ctx context.Context) ([]mcpResourceTemplate, error) {
+	return listAll[mcpResourceTemplate](ctx, c, "resources/templates/list", "resourceTemplates")
+}
+
+// ListPrompts returns every prompt the server exposes
+func (c *mcpClient) ListPrompts(ctx context.Context) ([]mcpPrompt, error) {
+	return listAll[mcpPrompt](ctx, c, "prompts/list", "prompts")
+}
+
+// listAll calls a paginated list method, following cursors, and returns the
+// items of the given result field from every page
+func listAll[T any](ctx context.Context, c *mcpClient, method, field string) ([]T, error) {
+	items := []T{}
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		var page map[string]json.RawMessage
+		if err := c.call(ctx, method, params, &page); err != nil {
+			return nil, fmt.Errorf("%s: %w", method, err)
+		}
+
+		var batch []T
+		if raw, ok := page[field]; ok {
+			if err := json.Unmarshal(raw, &batch); err != nil {
+				return nil, fmt.Errorf("parse %s result: %w", method, err)
+			}
+		}
+		items = append(items, batch...)
+
+		var next string
+		if raw, ok := page["nextCursor"]; ok {
+			json.Unmarshal(raw, &next)
+		}
+		if next == "" || next == cursor {
+			return items, nil
+		}
+		cursor = next
+	}
+}
+
+// isMethodNotFound reports whether err is a server saying it doesn't implement a method
+func isMethodNotFound(err error) bool {
+	var rpcErr *jsonrpcError
+	return errors.As(err, &rpcErr) && rpcErr.Code == jsonrpcMethodNotFound
+}
+
+// mcpContent is a content block of a tool result: text, an image or audio
+// clip, an embedded resource, or a link to one
+type mcpContent struct {
+	Type     string       `json:"type"`
+	Text     string       `json:"text,omitempty"`
+	Data     string       `json:"data,omitempty"`
+	MimeType string       `json:"mimeType,omitempty"`
+	URI      string       `json:"uri,omitempty"`
+	Resource *mcpResource `json:"resource,omitempty"`
+}
+
+// mcpResource is the contents of an embedded resource
+type mcpResource struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// mcpToolResult is the result of tools/call
+type mcpToolResult struct {
+	Content           []mcpContent `json:"content"`
+	StructuredContent any          `json:"structuredContent,omitempty"`
+	IsError           bool         `json:"isError,omitempty"`
+}
+
+// CallTool invokes a tool with the given arguments
+// A tool that fails reports it with IsError rather than an error.
+func (c *mcpClient) CallTool(ctx context.Context, name string, arguments map[string]any) (mcpToolResult, error) {
+	params := map[string]any{"name": name, "arguments": arguments}
+
+	var result mcpToolResult
+	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+		return mcpToolResult{}, fmt.Errorf("tools/call %s: %w", name, err)
+	}
+	return result, nil
+}
+
+// withMCPSession connects to a server, initializes a session, runs fn, and
+// closes the session, all within timeout
+// A zero timeout uses the server's mcp.timeout label, or defaultMCPTimeout,
+// and its mcp.init-timeout label bounds the initialization.
+func withMCPSession(ctx context.Context, server MCPServer, timeout time.Duration, fn func(ctx context.Context, client *mcpClient, info mcpInitializeResult) error) error {
+	if timeout <= 0 {
+		timeout = server.RequestTimeout
+	}
+	if timeout <= 0 {
+		timeout = defaultMCPTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := connectMCPServer(server)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	initCtx := ctx
+	if server.InitTimeout > 0 {
+		var cancelInit context.CancelFunc
+		initCtx, cancelInit = context.WithTimeout(ctx, server.InitTimeout)
+		defer cancelInit()
+	}
+	info, err := client.Initialize(initCtx)
+	if err != nil {
+		if errors.Is(initCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return fmt.Errorf("initialize: no response within %s (mcp.init-timeout): %w", server.InitTimeout, err)
+		}
+		return err
+	}
+	return fn(ctx, client, info)
+}
+
+// Close ends the session
+func (c *mcpClient) Close() error {
+	return c.transport.Close()
+}
+
+// call sends a request and decodes its result into result, if not nil
+func (c *mcpClient) call(ctx context.Context, method string, params, result any) error {
+	id := c.nextID.Add(1)
+	resp, err := c.transport.Request(ctx, jsonrpcRequest{JSONRPC: "2.0", ID: &id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("parse %s result: %w", method, err)
+	}
+	return nil
+}
+
+// stdioTransport talks newline-delimited JSON-RPC to a server process
+type stdioTransport struct {
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	stderr   *tailBuffer
+	messages chan jsonrpcMessage
+	done     chan struct{} // closed when the server has exited
+	exitErr  error         // how the server exited, set before done is closed
+	writeMu  sync.Mutex
+}
+
+// newStdioTransport starts a local server with its stdio attached to the transport
+func newStdioTransport(server MCPServer) (*stdioTransport, error) {
+	// The transport outlives the call that opens it; Close stops the server
+	cmd, err := serverCommand(context.Background(), server)
+	if err != nil {
+		return nil, err
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := &tailBuffer{limit: mcpStderrLimit}
+	cmd.Stderr = stderr
+	// Child processes of the server (e.g. node under npx) may keep its
+	// output open after it exits; don't wait on them forever
+	cmd.WaitDelay = mcpCloseTimeout
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", server.Command, err)
+	}
+
+	t := &stdioTransport{
+		cmd:      cmd,
+		stdin:    stdin,
+		stderr:   stderr,
+		messages: make(chan jsonrpcMessage, 16),
+		done:     make(chan struct{}),
+	}
+	go t.read(stdout)
+	return t, nil
+}
+
+// read decodes messages from the server's stdout until it ends, answering
+// the server's own requests so it doesn't wait on us, then reaps the server
+func (t *stdioTransport) read(stdout io.Reader) {
+	defer func() {
+		t.exitErr = t.cmd.Wait()
+		close(t.done)
+	}()
+
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var msg jsonrpcMessage
+			// Some servers log to stdout; lines that aren't JSON-RPC are ignored
+			if json.Unmarshal(line, &msg) == nil && msg.JSONRPC == "2.0" {
+				switch {
+				case msg.Method != "" && len(msg.ID) > 0:
+					t.write(serverRequestReply(msg))
+				case msg.Method == "":
+					// Drop responses nobody is waiting for rather than block
+					select {
+					case t.messages <- msg:
+					default:
+					}
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// write sends one message as a line on the server's stdin
+func (t *stdioTransport) write(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.stdin.Write(append(data, '\n'))
+	return err
+}
+
+func (t *stdioTransport) Request(ctx context.Context, req jsonrpcRequest) (jsonrpcMessage, error) {
+	if err := t.write(req); err != nil {
+		return jsonrpcMessage{}, t.writeError(ctx, err)
+	}
+
+	for {
+		select {
+		case msg := <-t.messages:
+			if msg.isResponseTo(*req.ID) {
+				return msg, nil
+			}
+		case <-t.done:
+			// The response may have arrived just before the server exited
+			for {
+				select {
+				case msg := <-t.messages:
+					if msg.isResponseTo(*req.ID) {
+						return msg, nil
+					}
+				default:
+					return jsonrpcMessage{}, t.exitError()
+				}
+			}
+		case <-ctx.Done():
+			return jsonrpcMessage{}, fmt.Errorf("no response to %s: %w", req.Method, ctx.Err())
+		}
+	}
+}
+
+func (t *stdioTransport) Notify(ctx context.Context, req jsonrpcRequest) error {
+	if err := t.write(req); err != nil {
+		return t.writeError(ctx, err)
+	}
+	return nil
+}
+
+// Close closes the server's stdin, which asks it to exit, and kills it if it doesn't
+func (t *stdioTransport) Close() error {
+	t.stdin.Close()
+
+	select {
+	case <-t.done:
+	case <-time.After(mcpCloseTimeout):
+		t.cmd.Process.Kill()
+		<-t.done
+	}
+	return nil
+}
+
+// writeError explains a failed write, which usually means the server exited
+func (t *stdioTransport) writeError(ctx context.Context, err error) error {
+	select {
+	case <-t.done:
+		return t.exitError()
+	case <-ctx.Done():
+		return err
+	}
+}
+
+// exitError describes how the server exited, with the end of its stderr,
+// which usually explains why
+func (t *stdioTransport) exitError() error {
+	err := errors.New("server exited")
+	if t.exitErr != nil {
+		err = fmt.Errorf("server exited (%v)", t.exitErr)
+	}
+	if tail := t.stderr.lastLine(); tail != "" {
+		return fmt.Errorf("%w: %s", err, tail)
+	}
+	return err
+}
+
+// serverRequestReply answers a request the server sent to the client:
+// pings succeed and everything else is reported as unsupported
+func serverRequestReply(msg jsonrpcMessage) any {
+	reply := map[string]any{"jsonrpc": "2.0", "id": msg.ID}
+	if msg.Method == "ping" {
+		reply["result"] = map[string]any{}
+	} else {
+		reply["error"] = jsonrpcError{Code: jsonrpcMethodNotFound, Message: "method not supported by " + mcpClientName}
+	}
+	return reply
+}
+
+// tailBuffer keeps the last limit bytes written to it
+type tailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	data  []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if len(b.data) > b.limit {
+		b.data = b.data[len(b.data)-b.limit:]
+	}
+	return len(p), nil
+}
+
+// lastLine returns the last non-empty line written
+func (b *tailBuffer) lastLine() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines := strings.Split(strings.TrimSpace(string(b.data)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// httpTransport talks to a remote server over the streamable HTTP transport:
+// each message is POSTed, and responses come back as JSON or an event stream
+type httpTransport struct {
+	url             string
+	headers         map[string]string
+	client          *http.Client
+	sessionID       string
+	protocolVersion string
+}
+
+// newHTTPTransport creates a transport for a remote server, sending its headers with every request
+func newHTTPTransport(server MCPServer) *httpTransport {
+	return &httpTransport{url: server.URL, headers: server.Headers, client: &http.Client{}}
+}
+
+func (t *httpTransport) Request(ctx context.Context, req jsonrpcRequest) (jsonrpcMessage, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return jsonrpcMessage{}, err
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		return readEventStream(resp.Body, *req.ID)
+	}
+
+	var msg jsonrpcMessage
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return jsonrpcMessage{}, fmt.Errorf("parse response to %s: %w", req.Method, err)
+	}
+	return msg, nil
+}
+
+func (t *httpTransport) Notify(ctx context.Context, req jsonrpcRequest) error {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Close ends the session, if the server started one
+func (t *httpTransport) Close() error {
+	if t.sessionID == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mcpCloseTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(req)
+	if resp, err := t.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// post sends one message and returns the successful response, remembering
+// the session ID the server assigns
+func (t *httpTransport) post(ctx context.Context, msg jsonrpcRequest) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setHeaders(req)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return nil, fmt.Errorf("%s rejected the credentials (HTTP %d)", t.url, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("%s returned HTTP %d: %s", t.url, resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.sessionID = id
+	}
+	return resp, nil
+}
+
+// setHeaders adds the server's configured headers and the session headers
+func (t *httpTransport) setHeaders(req *http.Request) {
+	for _, key := range sortedKeys(t.headers) {
+		req.Header.Set(key, t.headers[key])
+	}
+	if t.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set("MCP-Protocol-Version", t.protocolVersion)
+	}
+}
+
+// readEventStream reads server-sent events until the response to request id
+func readEventStream(r io.Reader, id int64) (jsonrpcMessage, error) {
+	reader := bufio.NewReader(r)
+	var data strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "" && data.Len() > 0:
+			// A blank line ends an event
+			var msg jsonrpcMessage
+			if json.Unmarshal([]byte(data.String()), &msg) == nil && msg.isResponseTo(id) {
+				return msg, nil
+			}
+			data.Reset()
+		}
+
+		if err != nil {
+			// The stream may end without a blank line after the last event
+			var msg jsonrpcMessage
+			if data.Len() > 0 && json.Unmarshal([]byte(data.String()), &msg) == nil && msg.isResponseTo(id) {
+				return msg, nil
+			}
+			return jsonrpcMessage{}, errors.New("event stream ended without a response")
+		}
+	}
+}
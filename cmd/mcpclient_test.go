@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeInitializeResult is what the fake servers answer the handshake with
+const fakeInitializeResult = `{"protocolVersion":"2025-06-18","capabilities":{"tools":{},"logging":{}},"serverInfo":{"name":"fake","version":"1.0.0"}}`
+
+// fakeStdioServer is a shell MCP server that logs to stdout, pings the client,
+// answers initialize, and then waits for stdin to close
+func fakeStdioServer(t *testing.T) MCPServer {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	script := `read line
+echo "starting up"
+echo '{"jsonrpc":"2.0","id":"ping-1","method":"ping"}'
+read pong
+echo '{"jsonrpc":"2.0","method":"notifications/message","params":{}}'
+echo '{"jsonrpc":"2.0","id":1,"result":` + fakeInitializeResult + `}'
+cat > /dev/null`
+	return MCPServer{Command: "sh", Args: []string{"-c", script}}
+}
+
+func TestStdioHandshake(t *testing.T) {
+	result, err := handshake(context.Background(), fakeStdioServer(t), 5*time.Second)
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	if result.ServerInfo.Name != "fake" || result.ProtocolVersion != "2025-06-18" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if names := result.capabilityNames(); !reflect.DeepEqual(names, []string{"logging", "tools"}) {
+		t.Errorf("Unexpected capabilities: %v", names)
+	}
+
+	t.Run("server exits", func(t *testing.T) {
+		server := MCPServer{Command: "sh", Args: []string{"-c", "echo 'missing API_KEY' >&2; exit 1"}}
+		_, err := handshake(context.Background(), server, 5*time.Second)
+		if err == nil || !strings.Contains(err.Error(), "missing API_KEY") {
+			t.Errorf("Expected the server's stderr in the error, got %v", err)
+		}
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		server := MCPServer{Command: "sh", Args: []string{"-c", "cat > /dev/null"}}
+		_, err := handshake(context.Background(), server, 100*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+			t.Errorf("Expected a timeout, got %v", err)
+		}
+	})
+
+	t.Run("timeout labels", func(t *testing.T) {
+		server := MCPServer{Command: "sh", Args: []string{"-c", "cat > /dev/null"}, InitTimeout: 100 * time.Millisecond}
+		_, err := handshake(context.Background(), server, 5*time.Second)
+		if err == nil || !strings.Contains(err.Error(), "no response within 100ms (mcp.init-timeout)") {
+			t.Errorf("Expected the init timeout, got %v", err)
+		}
+
+		// Without a --timeout, the server's mcp.timeout bounds the session
+		server = MCPServer{Command: "sh", Args: []string{"-c", "cat > /dev/null"}, RequestTimeout: 100 * time.Millisecond}
+		start := time.Now()
+		if _, err := handshake(context.Background(), server, 0); err == nil || time.Since(start) > 3*time.Second {
+			t.Errorf("Expected mcp.timeout to end the session, got %v after %s", err, time.Since(start))
+		}
+	})
+}
+
+func TestHTTPHandshake(t *testing.T) {
+	for _, stream := range []bool{false, true} {
+		t.Run(fmt.Sprintf("stream=%v", stream), func(t *testing.T) {
+			var methods []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				if r.Method == http.MethodDelete {
+					methods = append(methods, "DELETE "+r.Header.Get("Mcp-Session-Id"))
+					return
+				}
+
+				var req jsonrpcRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				methods = append(methods, req.Method)
+
+				if req.ID == nil {
+					if r.Header.Get("Mcp-Session-Id") != "session-1" || r.Header.Get("MCP-Protocol-Version") != "2025-06-18" {
+						t.Errorf("Expected session headers, got %v", r.Header)
+					}
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+
+				w.Header().Set("Mcp-Session-Id", "session-1")
+				response := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":%s}`, *req.ID, fakeInitializeResult)
+				if stream {
+					w.Header().Set("Content-Type", "text/event-stream")
+					fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\ndata: %s\n\n", response)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, response)
+			}))
+			defer server.Close()
+
+			result, err := handshake(context.Background(), MCPServer{Type: "http", URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}}, 5*time.Second)
+			if err != nil {
+				t.Fatalf("handshake failed: %v", err)
+			}
+			if result.ServerInfo.Version != "1.0.0" {
+				t.Errorf("Unexpected result: %+v", result)
+			}
+			if !reflect.DeepEqual(methods, []string{"initialize", "notifications/initialized", "DELETE session-1"}) {
+				t.Errorf("Unexpected requests: %v", methods)
+			}
+
+			_, err = handshake(context.Background(), MCPServer{Type: "http", URL: server.URL}, 5*time.Second)
+			if err == nil || !strings.Contains(err.Error(), "rejected the credentials") {
+				t.Errorf("Expected an auth error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestListAndCallTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     *int64         `json:"id"`
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		result := fakeInitializeResult
+		if req.Method == "tools/call" {
+			result = fmt.Sprintf(`{"content":[{"type":"text","text":"called %s with %v"}]}`, req.Params["name"], req.Params["arguments"])
+		}
+		if req.Method == "tools/list" {
+			if req.Params["cursor"] == "page-2" {
+				result = `{"tools":[{"name":"get_time"}]}`
+			} else {
+				result = `{"tools":[{"name":"convert_time","inputSchema":{"type":"object"}}],"nextCursor":"page-2"}`
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, *req.ID, result)
+	}))
+	defer server.Close()
+
+	var tools []mcpTool
+	var result mcpToolResult
+	err := withMCPSession(context.Background(), MCPServer{URL: server.URL}, 5*time.Second, func(ctx context.Context, client *mcpClient, info mcpInitializeResult) error {
+		var err error
+		if tools, err = client.ListTools(ctx); err != nil {
+			return err
+		}
+		result, err = client.CallTool(ctx, "get_time", map[string]any{"timezone": "UTC"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name != "convert_time" || tools[1].Name != "get_time" {
+		t.Errorf("Expected both pages of tools, got %+v", tools)
+	}
+	if len(result.Content) != 1 || result.Content[0].Text != "called get_time with map[timezone:UTC]" {
+		t.Errorf("Unexpected tool result: %+v", result)
+	}
+}
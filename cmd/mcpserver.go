@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// mcpServerMaxMessage bounds the size of one message a client may send
+const mcpServerMaxMessage = 16 << 20
+
+// mcpHandler answers a client's request or notification with its result
+// A *jsonrpcError is sent back as is; other errors become internal errors.
+type mcpHandler func(ctx context.Context, method string, params json.RawMessage) (any, error)
+
+// jsonrpcResponse is a JSON-RPC 2.0 response sent to a client
+type jsonrpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
+}
+
+// mcpBackend is a running server that requests are forwarded to
+// Clients aren't safe for concurrent requests, so calls hold mu.
+type mcpBackend struct {
+	name   string
+	client *mcpClient
+	info   mcpInitializeResult
+	mu     sync.Mutex
+}
+
+// startBackend connects to a server and initializes a session with it
+func startBackend(ctx context.Context, name string, server MCPServer, timeout time.Duration) (*mcpBackend, error) {
+	client, err := connectMCPServer(server)
+	if err != nil {
+		metrics.Set(metricServerUp, 0, map[string]string{"server": name})
+		return nil, err
+	}
+
+	initCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	info, err := client.Initialize(initCtx)
+	if err != nil {
+		client.Close()
+		metrics.Set(metricServerUp, 0, map[string]string{"server": name})
+		return nil, err
+	}
+	metrics.Set(metricServerUp, 1, map[string]string{"server": name})
+	return &mcpBackend{name: name, client: client, info: info}, nil
+}
+
+// call forwards a request to the server, returning its raw result
+func (b *mcpBackend) call(ctx context.Context, timeout time.Duration, method string, params any) (json.RawMessage, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var result json.RawMessage
+	err := b.client.call(ctx, method, params, &result)
+	return result, err
+}
+
+// handleMCPMessage passes one message from a client to handler and returns
+// the response to send, or nil for notifications and stray responses
+func handleMCPMessage(ctx context.Context, handler mcpHandler, msg jsonrpcMessage) *jsonrpcResponse {
+	if msg.Method == "" {
+		return nil
+	}
+	metrics.Inc(metricRequestsTotal, map[string]string{"method": msg.Method})
+
+	result, err := handler(ctx, msg.Method, msg.Params)
+	if len(msg.ID) == 0 {
+		return nil
+	}
+
+	resp := &jsonrpcResponse{JSONRPC: "2.0", ID: msg.ID}
+	if err != nil {
+		countError("serve")
+		var rpcErr *jsonrpcError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &jsonrpcError{Code: jsonrpcInternalError, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+		return resp
+	}
+	if result == nil {
+		result = map[string]any{}
+	}
+	resp.Result = result
+	return resp
+}
+
+// parseErrorResponse answers a message that isn't valid JSON-RPC
+func parseErrorResponse(err error) *jsonrpcResponse {
+	return &jsonrpcResponse{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage("null"),
+		Error:   &jsonrpcError{Code: jsonrpcParseError, Message: "parse error: " + err.Error()},
+	}
+}
+
+// serveMCPStdio reads newline-delimited JSON-RPC messages from in and writes
+// the responses to out until in ends or ctx is done
+// Requests are handled concurrently, so a slow tool call doesn't hold up pings.
+func serveMCPStdio(ctx context.Context, in io.Reader, out io.Writer, handler mcpHandler) error {
+	var writeMu sync.Mutex
+	write := func(resp *jsonrpcResponse) {
+		data, _ := json.Marshal(resp)
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		out.Write(append(data, '\n'))
+	}
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 64*1024), mcpServerMaxMessage)
+		for scanner.Scan() {
+			lines <- append([]byte(nil), scanner.Bytes()...)
+		}
+		readErr <- scanner.Err()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case line := <-lines:
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			var msg jsonrpcMessage
+			if err := json.Unmarshal(line, &msg); err != nil {
+				write(parseErrorResponse(err))
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp := handleMCPMessage(ctx, handler, msg); resp != nil {
+					write(resp)
+				}
+			}()
+		case err := <-readErr:
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// mcpHTTPHandler serves handler over the streamable HTTP transport: each
+// message is POSTed and answered with a JSON response
+func mcpHTTPHandler(handler mcpHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// There is no stream of server-initiated messages to GET, and no
+		// session to DELETE
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var msg jsonrpcMessage
+		if err := json.NewDecoder(io.LimitReader(req.Body, mcpServerMaxMessage)).Decode(&msg); err != nil {
+			writeJSONResponse(w, http.StatusBadRequest, parseErrorResponse(err))
+			return
+		}
+
+		resp := handleMCPMessage(req.Context(), handler, msg)
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, resp)
+	})
+}
+
+// serveMCPHTTP serves handler on addr until ctx is done: over streamable HTTP
+// at /mcp, and over the older HTTP+SSE transport at /sse for clients that
+// only support it. If token is set, requests must carry it as a bearer token.
+func serveMCPHTTP(ctx context.Context, w io.Writer, addr string, handler mcpHandler, token string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	sse := newMCPSSEServer(handler, "/messages")
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcpHTTPHandler(handler))
+	mux.HandleFunc("/sse", sse.stream)
+	mux.HandleFunc("/messages", sse.message)
+	// Request contexts end with ctx, so open event streams don't hold up shutdown
+	server := &http.Server{
+		Handler:     requireBearerToken(token, mux),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	fmt.Fprintf(w, "Serving MCP on http://%s/mcp (streamable HTTP) and http://%s/sse (SSE)\n", listener.Addr(), listener.Addr())
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), mcpCloseTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// requireBearerToken rejects requests that don't carry token as a bearer
+// token, unless token is empty
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// mcpSSEServer serves a handler over the HTTP+SSE transport: a client opens
+// an event stream, is told where to POST its messages, and receives the
+// responses as events on the stream
+type mcpSSEServer struct {
+	handler      mcpHandler
+	messagesPath string
+	mu           sync.Mutex
+	sessions     map[string]*mcpSSESession
+}
+
+// mcpSSESession is the event stream of one connected client
+type mcpSSESession struct {
+	responses chan *jsonrpcResponse
+	done      chan struct{} // closed when the client disconnects
+}
+
+// newMCPSSEServer creates an SSE server whose clients POST to messagesPath
+func newMCPSSEServer(handler mcpHandler, messagesPath string) *mcpSSEServer {
+	return &mcpSSEServer{handler: handler, messagesPath: messagesPath, sessions: make(map[string]*mcpSSESession)}
+}
+
+// stream opens a session and sends its responses as events until the client disconnects
+func (s *mcpSSEServer) stream(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	idBytes := make([]byte, 16)
+	rand.Read(idBytes)
+	id := hex.EncodeToString(idBytes)
+	session := &mcpSSESession{responses: make(chan *jsonrpcResponse, 16), done: make(chan struct{})}
+	s.mu.Lock()
+	s.sessions[id] = session
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.sessions, id)
+		s.mu.Unlock()
+		close(session.done)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprintf(w, "event: endpoint\ndata: %s?sessionId=%s\n\n", s.messagesPath, id)
+	flusher.Flush()
+
+	for {
+		select {
+		case resp := <-session.responses:
+			data, _ := json.Marshal(resp)
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+// message accepts a message for a session; its response is sent on the session's stream
+func (s *mcpSSEServer) message(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.Lock()
+	session, ok := s.sessions[req.URL.Query().Get("sessionId")]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	var msg jsonrpcMessage
+	if err := json.NewDecoder(io.LimitReader(req.Body, mcpServerMaxMessage)).Decode(&msg); err != nil {
+		http.Error(w, "parse error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+
+	// The request ends now, but handling it shouldn't
+	ctx := context.WithoutCancel(req.Context())
+	go func() {
+		resp := handleMCPMessage(ctx, s.handler, msg)
+		if resp == nil {
+			return
+		}
+		select {
+		case session.responses <- resp:
+		case <-session.done:
+		}
+	}()
+}
+
+// writeJSONResponse writes a JSON-RPC response as the body of an HTTP response
+func writeJSONResponse(w http.ResponseWriter, status int, resp *jsonrpcResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
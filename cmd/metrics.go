@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// Metric names exposed by long-running modes
+const (
+	metricRequestsTotal      = "mcp_requests_total"
+	metricTokenRefreshes     = "mcp_token_refreshes_total"
+	metricErrorsTotal        = "mcp_errors_total"
+	metricServerUp           = "mcp_server_up"
+	metricSyncsTotal         = "mcp_syncs_total"
+	metricsContentTypeHeader = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// metricHelp documents the well-known metrics in the exposition output
+var metricHelp = map[string]string{
+	metricRequestsTotal:  "Total number of MCP requests handled.",
+	metricTokenRefreshes: "Total number of OAuth access tokens acquired.",
+	metricErrorsTotal:    "Total number of errors encountered, by operation.",
+	metricServerUp:       "Whether an MCP server is healthy (1) or not (0).",
+	metricSyncsTotal:     "Total number of configuration syncs performed.",
+}
+
+// countError counts an error of an operation (oauth, serve, sync, or watch),
+// so every mcp_errors_total sample has the same op label
+func countError(op string) {
+	metrics.Inc(metricErrorsTotal, map[string]string{"op": op})
+}
+
+// metricsAddr is the listen address for the metrics endpoint of long-running modes
+var metricsAddr string
+
+// metricFamily groups all samples of one metric name
+type metricFamily struct {
+	kind    string             // "counter" or "gauge"
+	samples map[string]float64 // keyed by rendered label set
+}
+
+// metricsRegistry stores counters and gauges in memory and renders them
+// in the Prometheus text exposition format
+type metricsRegistry struct {
+	mu       sync.Mutex
+	families map[string]*metricFamily
+}
+
+// metrics is the process-wide registry used by long-running modes
+var metrics = newMetricsRegistry()
+
+// newMetricsRegistry creates an empty metrics registry
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{families: make(map[string]*metricFamily)}
+}
+
+// family returns the family for a metric name, creating it if needed
+func (r *metricsRegistry) family(name, kind string) *metricFamily {
+	family, ok := r.families[name]
+	if !ok {
+		family = &metricFamily{kind: kind, samples: make(map[string]float64)}
+		r.families[name] = family
+	}
+	return family
+}
+
+// Inc increments a counter by one
+func (r *metricsRegistry) Inc(name string, labels map[string]string) {
+	r.Add(name, 1, labels)
+}
+
+// Add increments a counter by the given value
+func (r *metricsRegistry) Add(name string, value float64, labels map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, "counter").samples[formatMetricLabels(labels)] += value
+}
+
+// Set sets a gauge to the given value
+func (r *metricsRegistry) Set(name string, value float64, labels map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.family(name, "gauge").samples[formatMetricLabels(labels)] = value
+}
+
+// Value returns the current value of a metric sample, or 0 if it has not been recorded
+func (r *metricsRegistry) Value(name string, labels map[string]string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	family, ok := r.families[name]
+	if !ok {
+		return 0
+	}
+	return family.samples[formatMetricLabels(labels)]
+}
+
+// WriteTo renders all metrics in the Prometheus text exposition format
+func (r *metricsRegistry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var names []string
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		family := r.families[name]
+		if help, ok := metricHelp[name]; ok {
+			fmt.Fprintf(&b, "# HELP %s %s\n", name, help)
+		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, family.kind)
+
+		var keys []string
+		for key := range family.samples {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%s%s %v\n", name, key, family.samples[key])
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP exposes the registry as a Prometheus scrape target
+func (r *metricsRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", metricsContentTypeHeader)
+	r.WriteTo(w)
+}
+
+// formatMetricLabels renders a label set as {k="v",...} with keys sorted
+func formatMetricLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var keys []string
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[k])
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, k, value))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// addMetricsFlag registers the --metrics-addr flag on a long-running command
+func addMetricsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Expose Prometheus metrics on this address (e.g. :9090)")
+}
+
+// startMetricsServer serves /metrics on addr in the background.
+// Returns a nil server when addr is empty.
+func startMetricsServer(addr string) (*http.Server, error) {
+	if addr == "" {
+		return nil, nil
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("error starting metrics endpoint: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+
+	fmt.Fprintf(os.Stderr, "serving metrics on http://%s/metrics\n", listener.Addr())
+	return server, nil
+}
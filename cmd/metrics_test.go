@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsRegistry(t *testing.T) {
+	r := newMetricsRegistry()
+
+	r.Inc(metricRequestsTotal, map[string]string{"server": "time"})
+	r.Inc(metricRequestsTotal, map[string]string{"server": "time"})
+	r.Add(metricRequestsTotal, 3, map[string]string{"server": "fetch"})
+	r.Set(metricServerUp, 1, map[string]string{"server": "time"})
+	r.Set(metricServerUp, 0, map[string]string{"server": "time"})
+
+	if got := r.Value(metricRequestsTotal, map[string]string{"server": "time"}); got != 2 {
+		t.Errorf("Expected time requests to be 2, got %v", got)
+	}
+	if got := r.Value(metricRequestsTotal, map[string]string{"server": "fetch"}); got != 3 {
+		t.Errorf("Expected fetch requests to be 3, got %v", got)
+	}
+	if got := r.Value(metricServerUp, map[string]string{"server": "time"}); got != 0 {
+		t.Errorf("Expected gauge to be overwritten to 0, got %v", got)
+	}
+	if got := r.Value("missing", nil); got != 0 {
+		t.Errorf("Expected missing metric to be 0, got %v", got)
+	}
+
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	output := b.String()
+
+	expected := []string{
+		"# HELP mcp_requests_total Total number of MCP requests handled.",
+		"# TYPE mcp_requests_total counter",
+		`mcp_requests_total{server="fetch"} 3`,
+		`mcp_requests_total{server="time"} 2`,
+		"# TYPE mcp_server_up gauge",
+		`mcp_server_up{server="time"} 0`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestFormatMetricLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected string
+	}{
+		{"no labels", nil, ""},
+		{"sorted keys", map[string]string{"tool": "kiro", "server": "time"}, `{server="time",tool="kiro"}`},
+		{"escaped value", map[string]string{"err": `say "hi"`}, `{err="say \"hi\""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatMetricLabels(tt.labels); got != tt.expected {
+				t.Errorf("formatMetricLabels() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	r := newMetricsRegistry()
+	r.Inc(metricErrorsTotal, nil)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Expected text/plain content type, got %s", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "mcp_errors_total 1") {
+		t.Errorf("Expected errors counter in body, got:\n%s", rec.Body.String())
+	}
+}
+
+func TestStartMetricsServerDisabled(t *testing.T) {
+	server, err := startMetricsServer("")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if server != nil {
+		t.Error("Expected nil server when address is empty")
+	}
+}
+
+func TestCountErrorLabelsByOp(t *testing.T) {
+	countError("serve")
+	countError("oauth")
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if strings.HasPrefix(line, metricErrorsTotal+"{") && !strings.HasPrefix(line, metricErrorsTotal+`{op="`) {
+			t.Errorf("Expected every error sample to be labeled by op alone, got %s", line)
+		}
+	}
+	for _, want := range []string{`mcp_errors_total{op="serve"}`, `mcp_errors_total{op="oauth"}`} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("Expected %s in body, got:\n%s", want, rec.Body.String())
+		}
+	}
+}
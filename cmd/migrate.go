@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFrom       string
+	migrateFromFormat string
+	migrateTo         string
+	migrateOut        string
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate --from <file> --to <format|tool> [--out <file>]",
+	Short: "Convert an MCP config file to another tool's format",
+	Long: `Convert any MCP config file between tool layouts, without a compose file:
+the standard mcpServers layout (Claude Desktop, Cursor, Kiro, Amazon Q),
+VS Code's "servers" (format vscode), and Zed's "context_servers" (format zed).
+The format of the --from file is detected from the key its servers are under,
+unless set with --from-format. --to takes a format or a tool shortcut; for a
+tool, remote servers are skipped if it doesn't support them.
+The converted config is printed unless --out is set. An existing --out file
+has its servers replaced and its other settings kept.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if migrateFrom == "" || migrateTo == "" {
+			return newValidationError("specify the file to convert with --from and the target format with --to")
+		}
+		return migrateConfig(os.Stdout, os.Stderr, migrateFrom, migrateFromFormat, migrateTo, migrateOut)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "", "MCP config file to convert")
+	migrateCmd.Flags().StringVar(&migrateFromFormat, "from-format", "", "Format of the --from file (default: detected)")
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "", "Format or tool shortcut to convert to ("+strings.Join(configFormatNames(), ", ")+")")
+	migrateCmd.Flags().StringVar(&migrateOut, "out", "", "Write the converted config to this file instead of printing it")
+	migrateCmd.RegisterFlagCompletionFunc("from-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return configFormatNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	migrateCmd.RegisterFlagCompletionFunc("to", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		tools, _ := completeToolNames(cmd, args, toComplete)
+		return append(configFormatNames(), tools...), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// migrateFormat resolves --to to a format, and to the tool it names, if any
+func migrateFormat(to string) (string, string, error) {
+	if _, ok := configFormats[to]; ok {
+		return to, "", nil
+	}
+	if path, err := getPlatformToolPath(to); err != nil || path == "" {
+		return "", "", newValidationError("unknown format or tool: %s (formats: %s)", to, strings.Join(configFormatNames(), ", "))
+	}
+	format := getToolFormat(to)
+	if _, ok := configFormats[format]; !ok {
+		return "", "", newValidationError("tool '%s' uses unsupported format '%s' (supported: %s)",
+			to, format, strings.Join(configFormatNames(), ", "))
+	}
+	return format, to, nil
+}
+
+// migrateConfig converts the servers of the config file at fromPath to the
+// format named by to, printing the result or writing it to outPath
+// Notes go to stderr so the printed config can be redirected to a file.
+func migrateConfig(w, stderr io.Writer, fromPath, fromFormat, to, outPath string) error {
+	toFormat, tool, err := migrateFormat(to)
+	if err != nil {
+		return err
+	}
+
+	if !fileExists(fromPath) {
+		return newConfigError("load MCP config", fromPath, fmt.Errorf("%s does not exist", fromPath))
+	}
+	if fromFormat == "" {
+		if fromFormat, err = detectConfigFormat(fromPath); err != nil {
+			return newConfigError("load MCP config", fromPath, fmt.Errorf("%w; set the format with --from-format", err))
+		}
+	} else if _, ok := configFormats[fromFormat]; !ok {
+		return newValidationError("unsupported format: %s (supported: %s)", fromFormat, strings.Join(configFormatNames(), ", "))
+	}
+	source, err := readToolConfigFile(fromPath, fromFormat)
+	if err != nil {
+		return newConfigError("load MCP config", fromPath, err)
+	}
+
+	converted := MCPConfig{MCPServers: make(map[string]MCPServer)}
+	var skipped []string
+	for name, server := range source.MCPServers {
+		if tool != "" && server.URL != "" && !toolSupportsRemote(tool) {
+			skipped = append(skipped, name)
+			continue
+		}
+		converted.MCPServers[name] = server
+	}
+	sort.Strings(skipped)
+
+	if outPath == "" {
+		data, err := encodeToolConfig(map[string]json.RawMessage{}, configFormats[toFormat], converted)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	} else {
+		if err := checkConfigWritable(outPath); err != nil {
+			return newConfigError("write MCP config", outPath, fmt.Errorf("%s is not writable (%w); %s", outPath, err, notWritableHint))
+		}
+		if err := writeToolConfigFile(outPath, toFormat, converted); err != nil {
+			return newConfigError("write MCP config", outPath, err)
+		}
+		fmt.Fprintf(w, "Converted %s from %s (%s) to %s (%s)\n",
+			pluralize(len(converted.MCPServers), "server"), fromPath, fromFormat, outPath, toFormat)
+	}
+
+	for _, name := range skipped {
+		fmt.Fprintf(stderr, "Skipped %s: %s does not support remote servers\n", name, tool)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const migrateTestConfig = `{
+  "globalShortcut": "Ctrl+Space",
+  "mcpServers": {
+    "time": {"command": "uvx", "args": ["mcp-server-time"]},
+    "api": {"type": "http", "url": "https://api.example.com/mcp"}
+  }
+}`
+
+func TestMigrateConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	fromPath := filepath.Join(dir, "claude_desktop_config.json")
+	os.WriteFile(fromPath, []byte(migrateTestConfig), 0644)
+
+	// An existing destination keeps its other settings
+	outPath := filepath.Join(dir, ".vscode", "mcp.json")
+	os.MkdirAll(filepath.Dir(outPath), 0755)
+	os.WriteFile(outPath, []byte(`{"inputs": [{"id": "token"}], "servers": {"old": {"command": "npx"}}}`), 0644)
+
+	var out, stderr bytes.Buffer
+	if err := migrateConfig(&out, &stderr, fromPath, "", "vscode", outPath); err != nil {
+		t.Fatalf("migrateConfig failed: %v", err)
+	}
+
+	var written struct {
+		Inputs  []map[string]string  `json:"inputs"`
+		Servers map[string]MCPServer `json:"servers"`
+	}
+	data, _ := os.ReadFile(outPath)
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("Failed to parse %s: %v", outPath, err)
+	}
+	if len(written.Inputs) != 1 {
+		t.Errorf("Expected inputs to be kept, got:\n%s", data)
+	}
+	if _, ok := written.Servers["old"]; ok || len(written.Servers) != 2 {
+		t.Errorf("Expected servers to be replaced, got:\n%s", data)
+	}
+	if written.Servers["time"].Type != "stdio" || written.Servers["api"].URL == "" {
+		t.Errorf("Expected servers in VS Code's layout, got:\n%s", data)
+	}
+	if !strings.Contains(out.String(), "Converted 2 servers") {
+		t.Errorf("Expected a summary, got: %s", out.String())
+	}
+}
+
+func TestMigrateConfigToTool(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fromPath := filepath.Join(t.TempDir(), "claude_desktop_config.json")
+	os.WriteFile(fromPath, []byte(migrateTestConfig), 0644)
+
+	// Printed configs only contain the servers, and remote servers are
+	// skipped for tools that don't support them
+	var out, stderr bytes.Buffer
+	if err := migrateConfig(&out, &stderr, fromPath, "", "claude-desktop", ""); err != nil {
+		t.Fatalf("migrateConfig failed: %v", err)
+	}
+
+	var printed map[string]map[string]MCPServer
+	if err := json.Unmarshal(out.Bytes(), &printed); err != nil {
+		t.Fatalf("Expected only JSON on stdout, got: %s", out.String())
+	}
+	if len(printed) != 1 || len(printed["mcpServers"]) != 1 {
+		t.Errorf("Expected only the time server under mcpServers, got: %s", out.String())
+	}
+	if !strings.Contains(stderr.String(), "Skipped api") {
+		t.Errorf("Expected api to be reported as skipped, got: %s", stderr.String())
+	}
+}
+
+func TestMigrateConfigErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	noServers := filepath.Join(dir, "settings.json")
+	os.WriteFile(noServers, []byte(`{"theme": "dark"}`), 0644)
+	standard := filepath.Join(dir, "mcp.json")
+	os.WriteFile(standard, []byte(migrateTestConfig), 0644)
+
+	tests := []struct {
+		name       string
+		from       string
+		fromFormat string
+		to         string
+		code       int
+	}{
+		{"unknown target", standard, "", "emacs", exitCodeValidation},
+		{"unknown source format", standard, "toml", "vscode", exitCodeValidation},
+		{"missing file", filepath.Join(dir, "missing.json"), "", "vscode", exitCodeConfig},
+		{"undetectable format", noServers, "", "vscode", exitCodeConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, stderr bytes.Buffer
+			err := migrateConfig(&out, &stderr, tt.from, tt.fromFormat, tt.to, "")
+			if ExitCode(err) != tt.code {
+				t.Errorf("Expected exit code %d, got %v", tt.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	monitorAllServers bool
+	monitorInterval   time.Duration
+	monitorTimeout    time.Duration
+	monitorOnce       bool
+	monitorDesktop    bool
+	monitorWebhook    string
+)
+
+// monitorCmd represents the monitor command
+var monitorCmd = &cobra.Command{
+	Use:   "monitor [profile]",
+	Short: "Periodically check that servers answer the MCP handshake",
+	Long: `Run the MCP handshake of 'mcp test' against each server of a profile every
+--interval, printing each round's results and recording them in
+~/.config/mcp/state/health.jsonl.
+When a server starts failing, or recovers, a desktop notification is shown with
+--notify, and a JSON payload is POSTed to --webhook if set. The last recorded
+status is remembered across restarts, so a restart doesn't notify again.
+The compose file is re-read every round, so edits apply without a restart.
+Use --once to run a single round, e.g. from cron. Stop with Ctrl-C.
+Without arguments, it monitors the default servers. With the -a flag, it
+monitors all servers.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+
+		historyPath, err := getHealthHistoryPath()
+		if err != nil {
+			return newConfigError("resolve health history path", "", err)
+		}
+		previous, err := lastHealthStatus(historyPath)
+		if err != nil {
+			return newConfigError("load health history", historyPath, err)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		metricsServer, err := startMetricsServer(metricsAddr)
+		if err != nil {
+			return err
+		}
+		if metricsServer != nil {
+			defer metricsServer.Close()
+		}
+
+		notifier := &healthNotifier{desktop: monitorDesktop, webhook: monitorWebhook, client: &http.Client{Timeout: 10 * time.Second}}
+		ticker := time.NewTicker(monitorInterval)
+		defer ticker.Stop()
+		for {
+			if err := monitorRound(ctx, os.Stdout, composeFile, profile, historyPath, previous, notifier); err != nil {
+				if monitorOnce {
+					return err
+				}
+				fmt.Printf("%s Error: %v\n", time.Now().Format(time.TimeOnly), err)
+			}
+			if monitorOnce {
+				return nil
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(monitorCmd)
+	monitorCmd.Flags().BoolVarP(&monitorAllServers, "all", "a", false, "Monitor all servers")
+	monitorCmd.Flags().DurationVar(&monitorInterval, "interval", 5*time.Minute, "How often to check the servers")
+	monitorCmd.Flags().DurationVar(&monitorTimeout, "timeout", defaultMCPTimeout, "How long each server gets to respond")
+	monitorCmd.Flags().BoolVar(&monitorOnce, "once", false, "Check the servers once and exit")
+	monitorCmd.Flags().BoolVar(&monitorDesktop, "notify", false, "Show a desktop notification when a server starts failing or recovers")
+	monitorCmd.Flags().StringVar(&monitorWebhook, "webhook", "", "POST a JSON payload to this URL when a server starts failing or recovers")
+	addMetricsFlag(monitorCmd)
+}
+
+// healthCheck is the recorded outcome of one server's handshake
+type healthCheck struct {
+	Time      time.Time `json:"time"`
+	Server    string    `json:"server"`
+	OK        bool      `json:"ok"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMS int64     `json:"latencyMs"`
+}
+
+// getHealthHistoryPath returns the path to the file monitor records checks in
+func getHealthHistoryPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "state", "health.jsonl"), nil
+}
+
+// lastHealthStatus returns whether each server passed its last recorded check
+// Returns an empty map if there is no history yet.
+func lastHealthStatus(path string) (map[string]bool, error) {
+	status := make(map[string]bool)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var check healthCheck
+		// A line cut short by a crash shouldn't lose the rest of the history
+		if json.Unmarshal(scanner.Bytes(), &check) == nil && check.Server != "" {
+			status[check.Server] = check.OK
+		}
+	}
+	return status, scanner.Err()
+}
+
+// appendHealthChecks adds checks to the history file, creating it if needed
+func appendHealthChecks(path string, checks []healthCheck) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for _, check := range checks {
+		data, err := json.Marshal(check)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// monitorRound checks every server of the profile once, records the results,
+// and notifies about servers whose status changed since previous, which it updates
+func monitorRound(ctx context.Context, w io.Writer, composePath, profile, historyPath string, previous map[string]bool, notifier *healthNotifier) error {
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+
+	var names []string
+	for name := range enabledServers(filterServers(config, profile, monitorAllServers)) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	servers, err := resolveServers(ctx, composePath, config, names)
+	if err != nil {
+		return err
+	}
+
+	checks := checkServersHealth(ctx, names, servers, monitorTimeout)
+	if err := appendHealthChecks(historyPath, checks); err != nil {
+		return newConfigError("record health checks", historyPath, err)
+	}
+	reportHealthChecks(ctx, w, checks, previous, notifier)
+	return nil
+}
+
+// checkServersHealth runs the handshake against each server in turn
+func checkServersHealth(ctx context.Context, names []string, servers map[string]MCPServer, timeout time.Duration) []healthCheck {
+	checks := make([]healthCheck, 0, len(names))
+	for _, name := range names {
+		start := time.Now()
+		_, err := handshake(ctx, servers[name], timeout)
+		check := healthCheck{Time: start.UTC(), Server: name, OK: err == nil, LatencyMS: time.Since(start).Milliseconds()}
+		if err != nil {
+			check.Error = err.Error()
+			metrics.Set(metricServerUp, 0, map[string]string{"server": name})
+		} else {
+			metrics.Set(metricServerUp, 1, map[string]string{"server": name})
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// reportHealthChecks prints a round's results and notifies about servers
+// that started failing or recovered, updating previous
+// A server never checked before only notifies if it fails.
+func reportHealthChecks(ctx context.Context, w io.Writer, checks []healthCheck, previous map[string]bool, notifier *healthNotifier) {
+	failures := 0
+	for _, check := range checks {
+		if !check.OK {
+			failures++
+		}
+	}
+	now := time.Now().Format(time.TimeOnly)
+	fmt.Fprintf(w, "%s %d passed, %d failed\n", now, len(checks)-failures, failures)
+
+	for _, check := range checks {
+		wasOK, known := previous[check.Server]
+		previous[check.Server] = check.OK
+
+		switch {
+		case !check.OK:
+			fmt.Fprintf(w, "  ✗ %s: %s\n", check.Server, check.Error)
+			if known && !wasOK {
+				continue
+			}
+		case known && !wasOK:
+			fmt.Fprintf(w, "  ✓ %s recovered\n", check.Server)
+		default:
+			continue
+		}
+		if err := notifier.notify(ctx, check); err != nil {
+			fmt.Fprintf(w, "  Failed to notify about %s: %v\n", check.Server, err)
+		}
+	}
+}
+
+// healthNotifier sends notifications about status changes
+type healthNotifier struct {
+	desktop bool
+	webhook string
+	client  *http.Client
+}
+
+// healthEvent is the JSON payload POSTed to the webhook
+type healthEvent struct {
+	Server string    `json:"server"`
+	Status string    `json:"status"` // "failing" or "recovered"
+	Error  string    `json:"error,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// notify sends a check's status change through every configured channel
+func (n *healthNotifier) notify(ctx context.Context, check healthCheck) error {
+	event := healthEvent{Server: check.Server, Status: "recovered", Time: check.Time}
+	message := check.Server + " recovered"
+	if !check.OK {
+		event.Status = "failing"
+		event.Error = check.Error
+		message = check.Server + " is failing: " + check.Error
+	}
+
+	var errs []string
+	if n.desktop {
+		if err := desktopNotify(ctx, "MCP server "+event.Status, message); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if n.webhook != "" {
+		if err := n.postWebhook(event); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// postWebhook POSTs an event to the webhook URL
+func (n *healthNotifier) postWebhook(event healthEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(n.webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// desktopNotify shows a desktop notification with the platform's notifier
+func desktopNotify(ctx context.Context, title, message string) error {
+	argv := desktopNotifyCommand(runtime.GOOS, title, message)
+	if argv == nil {
+		return fmt.Errorf("desktop notifications aren't supported on %s", runtime.GOOS)
+	}
+	path, err := lookPath(argv[0])
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH: %w", argv[0], err)
+	}
+	return exec.CommandContext(ctx, path, argv[1:]...).Run()
+}
+
+// desktopNotifyCommand returns the command that shows a notification on goos,
+// or nil if there is none
+func desktopNotifyCommand(goos, title, message string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"osascript", "-e", fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"notify-send", title, message}
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
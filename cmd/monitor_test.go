@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckServersHealth(t *testing.T) {
+	stopped := httptest.NewServer(http.NotFoundHandler())
+	stopped.Close()
+	servers := map[string]MCPServer{
+		"time":    fakeToolServer(t, "get_time"),
+		"offline": {Type: "http", URL: stopped.URL},
+	}
+
+	checks := checkServersHealth(context.Background(), []string{"offline", "time"}, servers, 5*time.Second)
+	if len(checks) != 2 || checks[0].OK || checks[0].Error == "" || !checks[1].OK {
+		t.Errorf("Expected offline to fail and time to pass, got %+v", checks)
+	}
+
+	// The last check of each server is remembered across runs
+	path := filepath.Join(t.TempDir(), "state", "health.jsonl")
+	if err := appendHealthChecks(path, checks); err != nil {
+		t.Fatalf("appendHealthChecks failed: %v", err)
+	}
+	if err := appendHealthChecks(path, []healthCheck{{Server: "offline", OK: true}}); err != nil {
+		t.Fatalf("appendHealthChecks failed: %v", err)
+	}
+	status, err := lastHealthStatus(path)
+	if err != nil {
+		t.Fatalf("lastHealthStatus failed: %v", err)
+	}
+	if want := map[string]bool{"offline": true, "time": true}; !reflect.DeepEqual(status, want) {
+		t.Errorf("Expected %v, got %v", want, status)
+	}
+}
+
+func TestReportHealthChecks(t *testing.T) {
+	var events []healthEvent
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event healthEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		events = append(events, event)
+	}))
+	defer webhook.Close()
+	notifier := &healthNotifier{webhook: webhook.URL, client: webhook.Client()}
+
+	previous := map[string]bool{"github": true, "slack": false, "time": false}
+	checks := []healthCheck{
+		{Server: "github", OK: false, Error: "server exited"}, // starts failing
+		{Server: "slack", OK: false, Error: "timeout"},        // still failing
+		{Server: "time", OK: true},                            // recovers
+		{Server: "fetch", OK: true},                           // new and healthy
+		{Server: "new", OK: false, Error: "not found"},        // new and failing
+	}
+
+	var out bytes.Buffer
+	reportHealthChecks(context.Background(), &out, checks, previous, notifier)
+
+	var got []string
+	for _, event := range events {
+		got = append(got, event.Server+" "+event.Status)
+	}
+	if want := []string{"github failing", "time recovered", "new failing"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected notifications %v, got %v", want, got)
+	}
+	if !previous["time"] || previous["github"] {
+		t.Errorf("Expected previous statuses to be updated, got %v", previous)
+	}
+	if !strings.Contains(out.String(), "2 passed, 3 failed") || !strings.Contains(out.String(), "✓ time recovered") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+}
+
+func TestDesktopNotifyCommand(t *testing.T) {
+	if argv := desktopNotifyCommand("linux", "Title", "Body"); !reflect.DeepEqual(argv, []string{"notify-send", "Title", "Body"}) {
+		t.Errorf("Unexpected linux command: %v", argv)
+	}
+	argv := desktopNotifyCommand("darwin", "Title", `say "hi"`)
+	if want := `display notification "say \"hi\"" with title "Title"`; len(argv) != 3 || argv[2] != want {
+		t.Errorf("Expected %q, got %v", want, argv)
+	}
+	if argv := desktopNotifyCommand("windows", "Title", "Body"); argv != nil {
+		t.Errorf("Expected no command on windows, got %v", argv)
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// openCmd represents the open command
+var openCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the compose file or a tool's config in an editor",
+	Long: `Open the compose file, or with -t (or -c) a tool's MCP config, in $VISUAL or
+$EDITOR, or else the system's default application for the file, resolving the
+tool's platform path automatically.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := composeFile
+		if toolShortcut != "" || configFile != "" {
+			envVars, err := loadEnvVars(composeFile)
+			if err != nil {
+				return newConfigError("load environment variables", composeFile, err)
+			}
+			if path, err = resolveOutputPath(envVars); err != nil {
+				return err
+			}
+		}
+		if !fileExists(path) {
+			return newConfigError("open", path, fmt.Errorf("%s does not exist", path))
+		}
+
+		argv := editorCommand(path, os.Getenv, runtime.GOOS)
+		fmt.Fprintf(os.Stderr, "Opening %s\n", path)
+		return runEditor(argv)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+	openCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to an MCP JSON configuration file to open")
+	openCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Open this tool's config instead of the compose file")
+	openCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// editorCommand returns the command line that opens path: $VISUAL or $EDITOR
+// (which may include arguments, e.g. "code -w"), or the system opener
+func editorCommand(path string, getenv func(string) string, goos string) []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(getenv(name)); len(fields) > 0 {
+			return append(fields, path)
+		}
+	}
+
+	switch goos {
+	case "darwin":
+		return []string{"open", path}
+	case "windows":
+		// The empty argument is the window title start expects before the path
+		return []string{"cmd", "/c", "start", "", path}
+	default:
+		return []string{"xdg-open", path}
+	}
+}
+
+// runEditor runs an editor attached to the terminal and waits for it to exit
+func runEditor(argv []string) error {
+	path, err := lookPath(argv[0])
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH (set $EDITOR to choose an editor): %w", argv[0], err)
+	}
+
+	cmd := exec.Command(path, argv[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", argv[0], err)
+	}
+	return nil
+}
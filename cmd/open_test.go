@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	env := func(values map[string]string) func(string) string {
+		return func(name string) string { return values[name] }
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		goos string
+		want []string
+	}{
+		{"VISUAL wins", map[string]string{"VISUAL": "code -w", "EDITOR": "vim"}, "linux", []string{"code", "-w", "mcp.json"}},
+		{"EDITOR", map[string]string{"EDITOR": "vim"}, "darwin", []string{"vim", "mcp.json"}},
+		{"blank EDITOR", map[string]string{"EDITOR": "  "}, "linux", []string{"xdg-open", "mcp.json"}},
+		{"macOS default", nil, "darwin", []string{"open", "mcp.json"}},
+		{"Windows default", nil, "windows", []string{"cmd", "/c", "start", "", "mcp.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := editorCommand("mcp.json", env(tt.env), tt.goos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRunEditorMissing(t *testing.T) {
+	original := lookPath
+	defer func() { lookPath = original }()
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+
+	if err := runEditor([]string{"nano", "mcp.json"}); err == nil {
+		t.Error("Expected an error for a missing editor")
+	}
+}
@@ -5,33 +5,409 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
 )
 
 // supportedTools lists all supported tool shortcuts
-var supportedTools = []string{"q-cli", "claude-desktop", "cursor", "kiro"}
+var supportedTools = []string{"q-cli", "q-ide", "claude-desktop", "cursor", "kiro"}
 
-// getPlatformToolPath returns the platform-appropriate path for a tool
-// Hard fails on error, consistent with getConfigDir() in config.go
-func getPlatformToolPath(tool string) string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting user home directory: %v\n", err)
-		os.Exit(1)
+// Config scopes a tool's MCP configuration can be written to
+const (
+	scopeUser    = "user"
+	scopeProject = "project"
+)
+
+// configScope is the scope selected with the global --scope flag
+var configScope string
+
+// projectToolPaths maps tools that support per-project configs to their path
+// relative to the project root (the current directory)
+var projectToolPaths = map[string][]string{
+	"q-cli":  {".amazonq", "mcp.json"},
+	"q-ide":  {".amazonq", "mcp.json"},
+	"cursor": {".cursor", "mcp.json"},
+	"kiro":   {".kiro", "settings", "mcp.json"},
+}
+
+// homeOverrideEnvVar names the environment variable that overrides the home directory
+// used for the CLI config, default compose file, and tool config paths
+const homeOverrideEnvVar = "MCP_CLI_HOME"
+
+// homeOverride is set by the hidden --home flag and takes precedence over the environment
+var homeOverride string
+
+// getHomeOverride returns the overridden home directory, or "" if none is set
+func getHomeOverride() string {
+	if homeOverride != "" {
+		return homeOverride
+	}
+	return os.Getenv(homeOverrideEnvVar)
+}
+
+// getHomeDir returns the user's home directory, honoring --home and MCP_CLI_HOME
+// The error explains which environment variable to set when it can't be determined
+func getHomeDir() (string, error) {
+	if override := getHomeOverride(); override != "" {
+		return filepath.Abs(override)
 	}
 
-	switch tool {
-	case "q-cli":
-		return filepath.Join(homeDir, ".aws", "amazonq", "mcp.json")
-	case "claude-desktop":
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		envVar := "HOME"
 		if runtime.GOOS == "windows" {
-			return filepath.Join(homeDir, "AppData", "Roaming", "Claude", "claude_desktop_config.json")
+			envVar = "USERPROFILE"
 		}
-		return filepath.Join(homeDir, "Library", "Application Support", "Claude", "claude_desktop_config.json")
-	case "cursor":
-		return filepath.Join(homeDir, ".cursor", "mcp.json")
-	case "kiro":
-		return filepath.Join(homeDir, ".kiro", "settings", "mcp.json")
-	default:
+		return "", fmt.Errorf("cannot determine user home directory (is $%s set?): %w", envVar, err)
+	}
+	return homeDir, nil
+}
+
+// toolPathSpec describes where a tool keeps its user-level config on one OS
+type toolPathSpec struct {
+	base  string   // directory the path is relative to: "home", "appdata", or "config"
+	parts []string // path elements below the base directory
+}
+
+// toolPaths maps each built-in tool to its config location per GOOS
+// The "default" entry applies to any OS without a specific entry
+var toolPaths = map[string]map[string]toolPathSpec{
+	"q-cli": {
+		"default": {"home", []string{".aws", "amazonq", "mcp.json"}},
+	},
+	"claude-desktop": {
+		"darwin":  {"home", []string{"Library", "Application Support", "Claude", "claude_desktop_config.json"}},
+		"windows": {"appdata", []string{"Claude", "claude_desktop_config.json"}},
+		"default": {"config", []string{"Claude", "claude_desktop_config.json"}},
+	},
+	"cursor": {
+		"default": {"home", []string{".cursor", "mcp.json"}},
+	},
+	"kiro": {
+		"default": {"home", []string{".kiro", "settings", "mcp.json"}},
+	},
+}
+
+// platformDirs holds the base directories tool paths are resolved against
+type platformDirs struct {
+	home    string // user home (%USERPROFILE% on Windows)
+	appData string // %APPDATA% on Windows
+	config  string // $XDG_CONFIG_HOME or ~/.config on Linux and other Unix systems
+}
+
+// resolveToolPath returns the config path of a built-in tool for the given OS
+// Returns an empty path for tools without a path table entry
+func resolveToolPath(tool, goos string, dirs platformDirs) string {
+	specs, ok := toolPaths[tool]
+	if !ok {
 		return ""
 	}
+
+	spec, ok := specs[goos]
+	if !ok {
+		spec = specs["default"]
+	}
+
+	var base string
+	switch spec.base {
+	case "appdata":
+		base = dirs.appData
+	case "config":
+		base = dirs.config
+	default:
+		base = dirs.home
+	}
+
+	return filepath.Join(append([]string{base}, spec.parts...)...)
+}
+
+// getPlatformDirs resolves the base directories for the current OS
+func getPlatformDirs() (platformDirs, error) {
+	homeDir, err := getHomeDir()
+	if err != nil {
+		return platformDirs{}, err
+	}
+
+	dirs := platformDirs{home: homeDir}
+
+	// An overridden home is a sandbox, so don't escape it via the environment
+	if getHomeOverride() == "" {
+		dirs.appData = os.Getenv("APPDATA")
+		dirs.config = os.Getenv("XDG_CONFIG_HOME")
+	}
+	if dirs.appData == "" {
+		dirs.appData = filepath.Join(homeDir, "AppData", "Roaming")
+	}
+	if dirs.config == "" {
+		dirs.config = filepath.Join(homeDir, ".config")
+	}
+	return dirs, nil
+}
+
+// getPlatformToolPath returns the platform-appropriate path for a tool
+// Returns an empty path for unknown tools, and an error if the path can't be resolved
+func getPlatformToolPath(tool string) (string, error) {
+	if !isBuiltinTool(tool) {
+		return getCustomToolPath(tool)
+	}
+
+	// The Amazon Q IDE plugin reads a workspace-level config relative to the project
+	if tool == "q-ide" {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("cannot determine working directory: %w", err)
+		}
+		return filepath.Join(workDir, ".amazonq", "mcp.json"), nil
+	}
+
+	dirs, err := getPlatformDirs()
+	if err != nil {
+		return "", err
+	}
+
+	return resolveToolPath(tool, runtime.GOOS, dirs), nil
+}
+
+// supportedFormats lists the config file formats a custom tool may declare
+var supportedFormats = []string{"standard", "vscode"}
+
+// getToolFormat returns the config file format of a tool shortcut
+func getToolFormat(tool string) string {
+	if custom, ok := getCustomTools()[tool]; ok && custom.Format != "" {
+		return custom.Format
+	}
+	return "standard"
+}
+
+// isSupportedFormat reports whether a config file format is supported
+func isSupportedFormat(format string) bool {
+	for _, f := range supportedFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// getScopedToolPath returns the path of a tool's config in the given scope
+// Returns an empty path for unknown tools, and an error if the tool has no config in that scope
+func getScopedToolPath(tool, scope string) (string, error) {
+	if scope != scopeProject {
+		return getPlatformToolPath(tool)
+	}
+
+	var relPath string
+	if parts, ok := projectToolPaths[tool]; ok {
+		relPath = filepath.Join(parts...)
+	} else if custom, ok := getCustomTools()[tool]; ok {
+		relPath = custom.ProjectPath
+	} else if !isBuiltinTool(tool) {
+		return "", nil
+	}
+
+	if relPath == "" {
+		return "", fmt.Errorf("tool '%s' does not support project-scoped configuration", tool)
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine working directory: %w", err)
+	}
+	return filepath.Join(workDir, relPath), nil
+}
+
+// supportsProjectScope reports whether a tool has a project-scoped config location
+func supportsProjectScope(tool string) bool {
+	if _, ok := projectToolPaths[tool]; ok {
+		return true
+	}
+	custom, ok := getCustomTools()[tool]
+	return ok && custom.ProjectPath != ""
+}
+
+// projectTarget returns the status key for a tool's project-scoped config
+func projectTarget(tool string) string {
+	return tool + ":" + scopeProject
+}
+
+// splitTarget splits a status key such as "cursor:project" into tool and scope
+func splitTarget(target string) (string, string) {
+	if tool, scope, ok := strings.Cut(target, ":"); ok {
+		return tool, scope
+	}
+	return target, scopeUser
+}
+
+// getStatusTargets returns the configs status should check for the given tools:
+// each tool's user config, plus its project config when it exists or --scope project is set
+func getStatusTargets(tools []string) []string {
+	var targets []string
+	for _, tool := range tools {
+		targets = append(targets, tool)
+
+		// q-ide is already workspace-scoped
+		if tool == "q-ide" || !supportsProjectScope(tool) {
+			continue
+		}
+		if configScope == scopeProject {
+			targets = append(targets, projectTarget(tool))
+			continue
+		}
+		if path, err := getScopedToolPath(tool, scopeProject); err == nil && fileExists(path) {
+			targets = append(targets, projectTarget(tool))
+		}
+	}
+	return targets
+}
+
+// getCustomTools returns the user-defined tool shortcuts from the CLI config
+// Built-in shortcuts take precedence over custom tools with the same name
+func getCustomTools() map[string]CustomTool {
+	config, err := loadCLIConfig()
+	if err != nil {
+		return nil
+	}
+
+	result := make(map[string]CustomTool)
+	for name, tool := range config.Tools {
+		if isBuiltinTool(name) || tool.Path == "" {
+			continue
+		}
+		result[name] = tool
+	}
+	return result
+}
+
+// getCustomToolPath returns the expanded config path of a custom tool, or "" if it isn't defined
+func getCustomToolPath(tool string) (string, error) {
+	custom, ok := getCustomTools()[tool]
+	if !ok {
+		return "", nil
+	}
+
+	path := custom.Path
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~\\") {
+		homeDir, err := getHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(homeDir, path[1:])
+	}
+	return path, nil
+}
+
+// isBuiltinTool reports whether a tool shortcut is one of the built-in shortcuts
+func isBuiltinTool(tool string) bool {
+	for _, t := range supportedTools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// getAllTools returns the built-in tool shortcuts followed by custom tools sorted by name
+func getAllTools() []string {
+	tools := append([]string{}, supportedTools...)
+
+	var custom []string
+	for name := range getCustomTools() {
+		custom = append(custom, name)
+	}
+	sort.Strings(custom)
+
+	return append(tools, custom...)
+}
+
+// Features of a server entry that not every tool understands
+const (
+	capabilityRemote      = "remote"      // servers with a url instead of a command
+	capabilityHeaders     = "headers"     // HTTP headers sent to remote servers
+	capabilityEnv         = "env"         // environment variables of local servers
+	capabilityDisabled    = "disabled"    // a "disabled" flag that turns a server off
+	capabilityAutoApprove = "autoApprove" // tools run without asking first
+	capabilityTimeout     = "timeout"     // how long each request may take
+)
+
+// builtinToolCapabilities lists the server entry features each built-in tool understands
+var builtinToolCapabilities = map[string][]string{
+	"q-cli":          {capabilityRemote, capabilityHeaders, capabilityEnv, capabilityTimeout},
+	"q-ide":          {capabilityRemote, capabilityHeaders, capabilityEnv, capabilityTimeout},
+	"claude-desktop": {capabilityEnv},
+	"cursor":         {capabilityRemote, capabilityHeaders, capabilityEnv},
+	"kiro":           {capabilityRemote, capabilityHeaders, capabilityEnv, capabilityDisabled, capabilityAutoApprove},
+}
+
+// toolSupports reports whether a tool understands a server entry feature
+// Custom tools declare theirs in the CLI config; headers come with remote
+// servers, and every tool passes env to local servers.
+func toolSupports(tool, capability string) bool {
+	if capabilities, ok := builtinToolCapabilities[tool]; ok {
+		return slices.Contains(capabilities, capability)
+	}
+	custom, ok := getCustomTools()[tool]
+	if !ok {
+		return false
+	}
+	switch capability {
+	case capabilityRemote, capabilityHeaders:
+		return custom.SupportsRemote
+	case capabilityEnv:
+		return true
+	case capabilityDisabled:
+		return custom.SupportsDisabled
+	case capabilityAutoApprove:
+		return custom.SupportsAutoApprove
+	case capabilityTimeout:
+		return custom.TimeoutUnit != ""
+	}
+	return false
+}
+
+// Names tools give the list of tools a server may run without asking
+const (
+	autoApproveFieldAutoApprove = "autoApprove"
+	autoApproveFieldAlwaysAllow = "alwaysAllow"
+)
+
+// toolAutoApproveField returns the name of a tool's auto-approve field:
+// autoApprove, unless a custom tool declares alwaysAllow
+func toolAutoApproveField(tool string) string {
+	if custom, ok := getCustomTools()[tool]; ok && custom.AutoApproveField == autoApproveFieldAlwaysAllow {
+		return autoApproveFieldAlwaysAllow
+	}
+	return autoApproveFieldAutoApprove
+}
+
+// toolTimeoutUnit returns the unit of a tool's timeout field: milliseconds
+// for the built-in tools that have one, or a custom tool's timeoutUnit
+// Returns 0 for a tool without one.
+func toolTimeoutUnit(tool string) time.Duration {
+	if !toolSupports(tool, capabilityTimeout) {
+		return 0
+	}
+	if custom, ok := getCustomTools()[tool]; ok && custom.TimeoutUnit == "s" {
+		return time.Second
+	}
+	return time.Millisecond
+}
+
+// toolSupportsRemote reports whether a tool can be configured with remote MCP servers
+func toolSupportsRemote(tool string) bool {
+	return toolSupports(tool, capabilityRemote)
+}
+
+// toolSupportsDisabled reports whether a tool can turn a server off with a
+// "disabled" flag instead of having its entry removed
+func toolSupportsDisabled(tool string) bool {
+	return toolSupports(tool, capabilityDisabled)
+}
+
+// completeToolNames provides shell completion for tool shortcut flags
+func completeToolNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return getAllTools(), cobra.ShellCompDirectiveNoFileComp
 }
@@ -28,10 +28,14 @@ func TestGetPlatformToolPath(t *testing.T) {
 			name: "claude-desktop",
 			tool: "claude-desktop",
 			expectedPath: func() string {
-				if runtime.GOOS == "windows" {
+				switch runtime.GOOS {
+				case "windows":
 					return filepath.Join(homeDir, "AppData", "Roaming", "Claude", "claude_desktop_config.json")
+				case "darwin":
+					return filepath.Join(homeDir, "Library", "Application Support", "Claude", "claude_desktop_config.json")
+				default:
+					return filepath.Join(homeDir, ".config", "Claude", "claude_desktop_config.json")
 				}
-				return filepath.Join(homeDir, "Library", "Application Support", "Claude", "claude_desktop_config.json")
 			}(),
 		},
 		{
@@ -58,7 +62,10 @@ func TestGetPlatformToolPath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getPlatformToolPath(tt.tool)
+			result, err := getPlatformToolPath(tt.tool)
+			if err != nil {
+				t.Fatalf("getPlatformToolPath(%q) returned error: %v", tt.tool, err)
+			}
 			if result != tt.expectedPath {
 				t.Errorf("getPlatformToolPath(%q) = %q, want %q", tt.tool, result, tt.expectedPath)
 			}
@@ -77,8 +84,80 @@ func TestGetPlatformToolPath(t *testing.T) {
 	}
 }
 
+func TestResolveToolPathMatrix(t *testing.T) {
+	dirs := platformDirs{
+		home:    "/home/user",
+		appData: "/home/user/AppData/Roaming",
+		config:  "/home/user/.config",
+	}
+
+	tests := []struct {
+		tool     string
+		goos     string
+		expected string
+	}{
+		{"q-cli", "darwin", filepath.Join(dirs.home, ".aws", "amazonq", "mcp.json")},
+		{"q-cli", "linux", filepath.Join(dirs.home, ".aws", "amazonq", "mcp.json")},
+		{"q-cli", "windows", filepath.Join(dirs.home, ".aws", "amazonq", "mcp.json")},
+		{"claude-desktop", "darwin", filepath.Join(dirs.home, "Library", "Application Support", "Claude", "claude_desktop_config.json")},
+		{"claude-desktop", "linux", filepath.Join(dirs.config, "Claude", "claude_desktop_config.json")},
+		{"claude-desktop", "freebsd", filepath.Join(dirs.config, "Claude", "claude_desktop_config.json")},
+		{"claude-desktop", "windows", filepath.Join(dirs.appData, "Claude", "claude_desktop_config.json")},
+		{"cursor", "darwin", filepath.Join(dirs.home, ".cursor", "mcp.json")},
+		{"cursor", "linux", filepath.Join(dirs.home, ".cursor", "mcp.json")},
+		{"cursor", "windows", filepath.Join(dirs.home, ".cursor", "mcp.json")},
+		{"kiro", "darwin", filepath.Join(dirs.home, ".kiro", "settings", "mcp.json")},
+		{"kiro", "linux", filepath.Join(dirs.home, ".kiro", "settings", "mcp.json")},
+		{"kiro", "windows", filepath.Join(dirs.home, ".kiro", "settings", "mcp.json")},
+		{"unknown", "linux", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool+"/"+tt.goos, func(t *testing.T) {
+			if got := resolveToolPath(tt.tool, tt.goos, dirs); got != tt.expected {
+				t.Errorf("resolveToolPath(%q, %q) = %q, want %q", tt.tool, tt.goos, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetPlatformDirs(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("APPDATA", "")
+		t.Setenv("XDG_CONFIG_HOME", "")
+
+		dirs, err := getPlatformDirs()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if dirs.appData != filepath.Join(homeDir, "AppData", "Roaming") {
+			t.Errorf("Unexpected default appData: %s", dirs.appData)
+		}
+		if dirs.config != filepath.Join(homeDir, ".config") {
+			t.Errorf("Unexpected default config dir: %s", dirs.config)
+		}
+	})
+
+	t.Run("environment overrides", func(t *testing.T) {
+		t.Setenv("APPDATA", "/custom/appdata")
+		t.Setenv("XDG_CONFIG_HOME", "/custom/config")
+
+		dirs, err := getPlatformDirs()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if dirs.appData != "/custom/appdata" || dirs.config != "/custom/config" {
+			t.Errorf("Expected environment overrides, got %+v", dirs)
+		}
+	})
+}
+
 func TestSupportedTools(t *testing.T) {
-	expectedTools := []string{"q-cli", "claude-desktop", "cursor", "kiro"}
+	expectedTools := []string{"q-cli", "q-ide", "claude-desktop", "cursor", "kiro"}
 
 	if len(supportedTools) != len(expectedTools) {
 		t.Errorf("Expected %d supported tools, got %d", len(expectedTools), len(supportedTools))
@@ -99,9 +178,215 @@ func TestSupportedTools(t *testing.T) {
 
 	// Verify all supported tools have valid paths
 	for _, tool := range supportedTools {
-		path := getPlatformToolPath(tool)
+		path, err := getPlatformToolPath(tool)
+		if err != nil {
+			t.Errorf("Tool %q returned error: %v", tool, err)
+		}
 		if path == "" {
 			t.Errorf("Tool %q should have a valid path", tool)
 		}
 	}
 }
+
+func TestGetPlatformToolPathWorkspace(t *testing.T) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+
+	// q-ide is workspace-scoped, so it resolves relative to the current directory
+	expected := filepath.Join(workDir, ".amazonq", "mcp.json")
+	if result, _ := getPlatformToolPath("q-ide"); result != expected {
+		t.Errorf("getPlatformToolPath(\"q-ide\") = %q, want %q", result, expected)
+	}
+}
+
+func TestCustomTools(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	configDir := filepath.Join(homeDir, ".config", "mcp")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	configJSON := `{
+  "tools": {
+    "mytool": {"path": "~/.mytool/mcp.json", "format": "standard", "supportsRemote": true},
+    "abs-tool": {"path": "/opt/tool/mcp.json"},
+    "cursor": {"path": "/should/not/override.json"}
+  }
+}`
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(configJSON), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	t.Run("custom tool path expands home", func(t *testing.T) {
+		expected := filepath.Join(homeDir, ".mytool", "mcp.json")
+		if got, _ := getPlatformToolPath("mytool"); got != expected {
+			t.Errorf("getPlatformToolPath(mytool) = %q, want %q", got, expected)
+		}
+		if got, _ := getPlatformToolPath("abs-tool"); got != "/opt/tool/mcp.json" {
+			t.Errorf("getPlatformToolPath(abs-tool) = %q, want /opt/tool/mcp.json", got)
+		}
+	})
+
+	t.Run("built-in tools take precedence", func(t *testing.T) {
+		expected := filepath.Join(homeDir, ".cursor", "mcp.json")
+		if got, _ := getPlatformToolPath("cursor"); got != expected {
+			t.Errorf("getPlatformToolPath(cursor) = %q, want %q", got, expected)
+		}
+	})
+
+	t.Run("all tools include custom tools sorted after built-ins", func(t *testing.T) {
+		tools := getAllTools()
+		expected := append(append([]string{}, supportedTools...), "abs-tool", "mytool")
+		if !compareStringSlices(tools, expected) {
+			t.Errorf("getAllTools() = %v, want %v", tools, expected)
+		}
+	})
+
+	t.Run("remote support", func(t *testing.T) {
+		if !toolSupportsRemote("mytool") {
+			t.Error("Expected mytool to support remote servers")
+		}
+		if toolSupportsRemote("abs-tool") {
+			t.Error("Expected abs-tool not to support remote servers")
+		}
+		if err := ValidateToolSupport("mytool", map[string]Service{"r": {Command: "https://example.com/mcp"}}); err != nil {
+			t.Errorf("Expected mytool to accept remote servers, got %v", err)
+		}
+	})
+
+	t.Run("format", func(t *testing.T) {
+		if got := getToolFormat("mytool"); got != "standard" {
+			t.Errorf("Expected standard format, got %s", got)
+		}
+		if got := getToolFormat("abs-tool"); got != "standard" {
+			t.Errorf("Expected default standard format, got %s", got)
+		}
+	})
+}
+
+func TestGetPlatformToolPathWithoutHome(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("home directory lookup uses different variables on this platform")
+	}
+	t.Setenv("HOME", "")
+
+	path, err := getPlatformToolPath("cursor")
+	if err == nil {
+		t.Fatalf("Expected error when home directory is unavailable, got path %q", path)
+	}
+	if !strings.Contains(err.Error(), "$HOME") {
+		t.Errorf("Expected error to mention $HOME, got %v", err)
+	}
+
+	if _, err := getConfigDir(); err == nil {
+		t.Error("Expected getConfigDir to return error when home directory is unavailable")
+	}
+
+	// Unknown tools still resolve to an empty path rather than an error
+	if path, err := getPlatformToolPath("unknown"); path != "" || err != nil {
+		t.Errorf("Expected empty path and no error for unknown tool, got %q, %v", path, err)
+	}
+}
+
+func TestGetScopedToolPath(t *testing.T) {
+	projectDir := t.TempDir()
+	t.Chdir(projectDir)
+	workDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		tool      string
+		expected  string
+		expectErr bool
+	}{
+		{"cursor", "cursor", filepath.Join(workDir, ".cursor", "mcp.json"), false},
+		{"kiro", "kiro", filepath.Join(workDir, ".kiro", "settings", "mcp.json"), false},
+		{"q-cli", "q-cli", filepath.Join(workDir, ".amazonq", "mcp.json"), false},
+		{"claude-desktop has no project scope", "claude-desktop", "", true},
+		{"unknown tool", "unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := getScopedToolPath(tt.tool, scopeProject)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error for %s, got path %q", tt.tool, path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if path != tt.expected {
+				t.Errorf("getScopedToolPath(%q, project) = %q, want %q", tt.tool, path, tt.expected)
+			}
+		})
+	}
+
+	t.Run("user scope matches platform path", func(t *testing.T) {
+		userPath, _ := getScopedToolPath("cursor", scopeUser)
+		platformPath, _ := getPlatformToolPath("cursor")
+		if userPath != platformPath {
+			t.Errorf("Expected user scope path %q, got %q", platformPath, userPath)
+		}
+	})
+}
+
+func TestGetStatusTargets(t *testing.T) {
+	projectDir := t.TempDir()
+	t.Chdir(projectDir)
+
+	originalScope := configScope
+	defer func() { configScope = originalScope }()
+	configScope = scopeUser
+
+	tools := []string{"q-ide", "claude-desktop", "cursor", "kiro"}
+
+	t.Run("project configs are skipped when missing", func(t *testing.T) {
+		targets := getStatusTargets(tools)
+		if !compareStringSlices(targets, tools) {
+			t.Errorf("Expected %v, got %v", tools, targets)
+		}
+	})
+
+	t.Run("existing project configs are included", func(t *testing.T) {
+		if err := os.MkdirAll(".cursor", 0755); err != nil {
+			t.Fatalf("Failed to create project dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(".cursor", "mcp.json"), []byte(`{"mcpServers":{}}`), 0644); err != nil {
+			t.Fatalf("Failed to write project config: %v", err)
+		}
+
+		targets := getStatusTargets(tools)
+		expected := []string{"q-ide", "claude-desktop", "cursor", "cursor:project", "kiro"}
+		if !compareStringSlices(targets, expected) {
+			t.Errorf("Expected %v, got %v", expected, targets)
+		}
+	})
+
+	t.Run("project scope includes all supported project configs", func(t *testing.T) {
+		configScope = scopeProject
+		targets := getStatusTargets(tools)
+		expected := []string{"q-ide", "claude-desktop", "cursor", "cursor:project", "kiro", "kiro:project"}
+		if !compareStringSlices(targets, expected) {
+			t.Errorf("Expected %v, got %v", expected, targets)
+		}
+	})
+}
+
+func TestSplitTarget(t *testing.T) {
+	if tool, scope := splitTarget("cursor"); tool != "cursor" || scope != scopeUser {
+		t.Errorf("Expected cursor/user, got %s/%s", tool, scope)
+	}
+	if tool, scope := splitTarget(projectTarget("kiro")); tool != "kiro" || scope != scopeProject {
+		t.Errorf("Expected kiro/project, got %s/%s", tool, scope)
+	}
+}
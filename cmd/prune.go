@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneDryRun bool
+	pruneYes    bool
+)
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune [profile]",
+	Short: "Remove servers from a tool config that aren't in the compose file",
+	Long: `Remove the servers in a tool's MCP configuration that are no longer in the
+compose file, or aren't in the selected profile (default servers if none is
+given), such as servers left behind after a rename. Servers added to the file
+by hand, rather than written by mcp, are left alone.
+It asks for confirmation before changing the config; use -y to skip the prompt
+(required when not running in a terminal), or --dry-run to only list them.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+		return pruneServers(os.Stdin, os.Stdout, composeFile, profile, !pruneYes && isTerminal(os.Stdin))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+	pruneCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to the MCP JSON configuration file")
+	pruneCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Tool shortcut (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show what would be removed without changing the config")
+	pruneCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "Don't prompt for confirmation")
+	pruneCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// pruneServers removes the servers in the tool config that the profile
+// doesn't define, after confirming when interactive
+func pruneServers(in io.Reader, w io.Writer, composePath, profile string, interactive bool) error {
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return newConfigError("load environment variables", composePath, err)
+	}
+
+	var path string
+	if pruneDryRun {
+		path, err = resolveOutputPath(envVars)
+	} else {
+		path, err = getOutputPath(envVars)
+	}
+	if err != nil {
+		return err
+	}
+
+	deployed, err := readMCPConfig(path)
+	if err != nil {
+		return newConfigError("load tool config", path, err)
+	}
+
+	servers := enabledServers(filterServers(config, profile, false))
+	unmanaged := unmanagedServers(deployed, path)
+	var orphans []string
+	for name := range deployed.MCPServers {
+		if _, exists := servers[name]; !exists && !containsString(unmanaged, name) {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+
+	if len(unmanaged) > 0 {
+		fmt.Fprintf(w, "Leaving unmanaged servers alone: %s\n", strings.Join(unmanaged, ", "))
+	}
+	if len(orphans) == 0 {
+		fmt.Fprintf(w, "Nothing to prune in %s\n", path)
+		return nil
+	}
+
+	if pruneDryRun {
+		for _, name := range orphans {
+			fmt.Fprintf(w, "Would remove %s from %s\n", name, path)
+		}
+		return nil
+	}
+
+	if !pruneYes {
+		if !interactive {
+			return newValidationError("prune would remove %s from %s; use -y to confirm or --dry-run to preview",
+				strings.Join(orphans, ", "), path)
+		}
+		answer := prompt(bufio.NewReader(in), w, fmt.Sprintf("Remove %s from %s (%s)? [y/N] ",
+			pluralize(len(orphans), "server"), path, strings.Join(orphans, ", ")))
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			fmt.Fprintln(w, "Nothing removed")
+			return nil
+		}
+	}
+
+	for _, name := range orphans {
+		delete(deployed.MCPServers, name)
+	}
+	if err := writeToolConfig(deployed, path, toolShortcut); err != nil {
+		return newConfigError("write MCP config", path, err)
+	}
+	if _, known := managedServerNames(path); known {
+		if err := recordManagedServers(path, deployed, unmanaged); err != nil {
+			return newConfigError("save state", "", err)
+		}
+	}
+	for _, name := range orphans {
+		fmt.Fprintf(w, "Removed %s from %s\n", name, path)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPruneServers(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalConfig, originalTool := configFile, toolShortcut
+	originalDryRun, originalYes := pruneDryRun, pruneYes
+	defer func() {
+		configFile, toolShortcut = originalConfig, originalTool
+		pruneDryRun, pruneYes = originalDryRun, originalYes
+	}()
+
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+  fetch:
+    command: uvx mcp-server-fetch
+    labels:
+      mcp.profile: programming
+`), 0644)
+	configFile = filepath.Join(dir, "mcp.json")
+	toolShortcut = ""
+	reset := func() {
+		writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{
+			"time": {Command: "uvx"}, "fetch": {Command: "uvx"}, "old-name": {Command: "uvx"},
+		}}, configFile, "")
+	}
+
+	t.Run("dry run lists orphans", func(t *testing.T) {
+		reset()
+		pruneDryRun, pruneYes = true, false
+		var out bytes.Buffer
+		if err := pruneServers(nil, &out, composePath, "", false); err != nil {
+			t.Fatalf("pruneServers failed: %v", err)
+		}
+		want := "Would remove fetch from " + configFile + "\nWould remove old-name from " + configFile + "\n"
+		if out.String() != want {
+			t.Errorf("Expected:\n%s\ngot:\n%s", want, out.String())
+		}
+		if config, _ := readMCPConfig(configFile); len(config.MCPServers) != 3 {
+			t.Error("Expected a dry run to leave the config alone")
+		}
+	})
+
+	t.Run("requires confirmation when not interactive", func(t *testing.T) {
+		reset()
+		pruneDryRun, pruneYes = false, false
+		err := pruneServers(nil, &bytes.Buffer{}, composePath, "programming", false)
+		if ExitCode(err) != exitCodeValidation || !strings.Contains(err.Error(), "old-name") {
+			t.Errorf("Expected a validation error naming old-name, got %v", err)
+		}
+	})
+
+	t.Run("declined prompt", func(t *testing.T) {
+		reset()
+		pruneDryRun, pruneYes = false, false
+		var out bytes.Buffer
+		pruneServers(strings.NewReader("n\n"), &out, composePath, "", true)
+		if !strings.Contains(out.String(), "Remove 2 servers") || !strings.Contains(out.String(), "Nothing removed") {
+			t.Errorf("Unexpected output:\n%s", out.String())
+		}
+		if config, _ := readMCPConfig(configFile); len(config.MCPServers) != 3 {
+			t.Error("Expected the config to be unchanged")
+		}
+	})
+
+	t.Run("confirmed prompt", func(t *testing.T) {
+		reset()
+		pruneDryRun, pruneYes = false, false
+		var out bytes.Buffer
+		if err := pruneServers(strings.NewReader("y\n"), &out, composePath, "programming", true); err != nil {
+			t.Fatalf("pruneServers failed: %v", err)
+		}
+		config, _ := readMCPConfig(configFile)
+		if _, exists := config.MCPServers["old-name"]; exists || len(config.MCPServers) != 2 {
+			t.Errorf("Expected only old-name to be pruned, got %v", config.MCPServers)
+		}
+	})
+
+	t.Run("nothing to prune", func(t *testing.T) {
+		pruneDryRun, pruneYes = false, true
+		var out bytes.Buffer
+		pruneServers(nil, &out, composePath, "programming", false)
+		if !strings.HasPrefix(out.String(), "Nothing to prune") {
+			t.Errorf("Unexpected output:\n%s", out.String())
+		}
+	})
+
+	t.Run("leaves hand-added servers alone", func(t *testing.T) {
+		reset()
+		recordManagedServers(configFile, MCPConfig{MCPServers: map[string]MCPServer{"time": {}, "fetch": {}}}, nil)
+		pruneDryRun, pruneYes = false, true
+		var out bytes.Buffer
+		if err := pruneServers(nil, &out, composePath, "", false); err != nil {
+			t.Fatalf("pruneServers failed: %v", err)
+		}
+		if !strings.Contains(out.String(), "Leaving unmanaged servers alone: old-name") {
+			t.Errorf("Unexpected output:\n%s", out.String())
+		}
+		config, _ := readMCPConfig(configFile)
+		if _, exists := config.MCPServers["old-name"]; !exists || len(config.MCPServers) != 2 {
+			t.Errorf("Expected only fetch to be pruned, got %v", config.MCPServers)
+		}
+		if managed, _ := managedServerNames(configFile); managed["fetch"] || !managed["time"] {
+			t.Errorf("Expected the record to drop fetch, got %v", managed)
+		}
+	})
+}
+
+func TestPruneServersVSCodeFormat(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalConfig, originalTool := configFile, toolShortcut
+	originalDryRun, originalYes := pruneDryRun, pruneYes
+	defer func() {
+		configFile, toolShortcut = originalConfig, originalTool
+		pruneDryRun, pruneYes = originalDryRun, originalYes
+	}()
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(composePath, []byte("services:\n  time:\n    command: uvx mcp-server-time\n"), 0644)
+	path := setupVSCodeTool(t, map[string]MCPServer{
+		"time": {Command: "uvx"}, "old-name": {Command: "uvx"},
+	})
+	configFile, toolShortcut = "", "vscode"
+	pruneDryRun, pruneYes = false, true
+
+	if err := pruneServers(nil, &bytes.Buffer{}, composePath, "", false); err != nil {
+		t.Fatalf("pruneServers failed: %v", err)
+	}
+	servers := readVSCodeServers(t, path)
+	if _, exists := servers["old-name"]; exists || len(servers) != 1 {
+		t.Errorf("Expected only time under servers, got %v", servers)
+	}
+}
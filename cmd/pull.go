@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+)
+
+// pullServerImages pulls the image of each container server with the
+// container tool, streaming its progress to w, so a tool's first launch of
+// a server isn't held up by the download
+// An image shared by several servers is pulled once.
+func pullServerImages(ctx context.Context, w io.Writer, servers map[string]Service, envVars map[string]string) error {
+	var names []string
+	for name, service := range servers {
+		if service.Image != "" && !IsRemoteServerWithEnvExpansion(service, envVars) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	containerTool := getContainerTool()
+	path, err := lookPath(containerTool)
+	if err != nil {
+		return fmt.Errorf("--pull needs %s, which wasn't found in PATH: %w", containerTool, err)
+	}
+
+	pulled := make(map[string]bool)
+	for _, name := range names {
+		image := expandEnvVars(servers[name].Image, envVars)
+		if pulled[image] {
+			continue
+		}
+		pulled[image] = true
+
+		fmt.Fprintf(w, "Pulling %s for %s\n", image, name)
+		cmd := exec.CommandContext(ctx, path, "pull", image)
+		cmd.Stdout = w
+		cmd.Stderr = w
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("pull %s for server '%s': %w (check that the image exists and that you're logged in to its registry)", image, name, err)
+		}
+	}
+	fmt.Fprintf(w, "Pulled %s\n", pluralize(len(pulled), "image"))
+	return nil
+}
+
+// pullByDefault reports whether 'mcp set' pulls images without --pull, per
+// the pull setting of the CLI config
+func pullByDefault() bool {
+	config, err := loadCLIConfig()
+	return err == nil && config.Pull
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPullServerImages(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	log := filepath.Join(dir, "pulls.log")
+	docker := filepath.Join(dir, "docker")
+	os.WriteFile(docker, []byte(`#!/bin/sh
+echo "$@" >> `+log+`
+case "$2" in
+  *missing*) echo "manifest unknown" >&2; exit 1 ;;
+esac
+echo "Status: Downloaded newer image for $2"
+`), 0755)
+
+	original := lookPath
+	defer func() { lookPath = original }()
+	lookPath = func(file string) (string, error) { return docker, nil }
+
+	servers := map[string]Service{
+		"brave":  {Image: "mcp/brave-search:${BRAVE_TAG}"},
+		"brave2": {Image: "mcp/brave-search:${BRAVE_TAG}"},
+		"time":   {Command: "uvx mcp-server-time"},
+	}
+	var out bytes.Buffer
+	if err := pullServerImages(context.Background(), &out, servers, map[string]string{"BRAVE_TAG": "1.0"}); err != nil {
+		t.Fatalf("pullServerImages failed: %v", err)
+	}
+	data, _ := os.ReadFile(log)
+	if string(data) != "pull mcp/brave-search:1.0\n" {
+		t.Errorf("Expected the shared image to be pulled once, got %q", data)
+	}
+	for _, want := range []string{"Pulling mcp/brave-search:1.0 for brave\n", "Downloaded newer image", "Pulled 1 image\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	err := pullServerImages(context.Background(), &out, map[string]Service{"broken": {Image: "example/missing"}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "pull example/missing for server 'broken'") {
+		t.Errorf("Expected a clear error for a missing image, got %v", err)
+	}
+}
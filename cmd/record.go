@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	recordOutput  string
+	replayServer  string
+	replayTimeout time.Duration
+)
+
+// recordCmd represents the record command
+var recordCmd = &cobra.Command{
+	Use:   "record <server>",
+	Short: "Run a server while recording its MCP session to a file",
+	Long: `Run a local server like 'mcp run', with stdio attached, while logging every
+JSON-RPC message between the client and the server to --out as JSON lines.
+Point a tool at 'mcp record <server> --out session.jsonl' instead of the server
+to capture a session that reproduces a bug, then share the file with the
+server's authors or re-send it with 'mcp replay'.
+Lines that aren't JSON (e.g. a server logging to stdout) are recorded as text.
+WARNING: sessions may include sensitive values such as API keys passed in tool calls.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server, err := resolveRunServer(cmd.Context(), os.Stderr, composeFile, args[0])
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Create(recordOutput)
+		if err != nil {
+			return newConfigError("create session file", recordOutput, err)
+		}
+		defer file.Close()
+
+		session := &sessionLog{w: file}
+		if err := session.writeHeader(args[0]); err != nil {
+			return newConfigError("write session file", recordOutput, err)
+		}
+		fromClient := session.frameWriter("client")
+		fromServer := session.frameWriter("server")
+		defer fromClient.Flush()
+		defer fromServer.Flush()
+		return execServer(cmd.Context(), server, io.TeeReader(os.Stdin, fromClient), io.MultiWriter(os.Stdout, fromServer), os.Stderr)
+	},
+}
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay <session.jsonl>",
+	Short: "Re-send the client messages of a recorded session to a server",
+	Long: `Start the server a session was recorded with (see 'mcp record') and send it
+the client's messages in order, waiting for the response to each request and
+comparing it with the recorded one. Use --server to replay against another
+server from the compose file, e.g. a fixed build.
+Requests the server sends during the replay are answered like 'mcp test' does.
+Exits 1 if any response differs from the recording.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		header, frames, err := loadSession(args[0])
+		if err != nil {
+			return newConfigError("load session file", args[0], err)
+		}
+		name := replayServer
+		if name == "" {
+			name = header.Server
+		}
+		if name == "" {
+			return newValidationError("%s doesn't name its server; use --server", args[0])
+		}
+
+		server, err := resolveRunServer(cmd.Context(), os.Stderr, composeFile, name)
+		if err != nil {
+			return err
+		}
+		return replaySession(cmd.Context(), os.Stdout, server, frames, replayTimeout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recordCmd)
+	rootCmd.AddCommand(replayCmd)
+	recordCmd.Flags().StringVar(&recordOutput, "out", "session.jsonl", "File to record the session to")
+	replayCmd.Flags().StringVar(&replayServer, "server", "", "Replay against this server instead of the recorded one")
+	replayCmd.Flags().DurationVar(&replayTimeout, "timeout", defaultMCPTimeout, "How long to wait for each response")
+	replayCmd.RegisterFlagCompletionFunc("server", completeServerNames)
+}
+
+// sessionHeader is the first line of a session file
+type sessionHeader struct {
+	Server string    `json:"server"`
+	Time   time.Time `json:"time"`
+}
+
+// sessionFrame is one line sent between the client and the server
+type sessionFrame struct {
+	Time    time.Time       `json:"time"`
+	From    string          `json:"from"` // client or server
+	Message json.RawMessage `json:"message,omitempty"`
+	Text    string          `json:"text,omitempty"` // a line that isn't JSON
+}
+
+// sessionLog writes a session file; frames from both directions are
+// written whole, in the order they were sent
+type sessionLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// writeHeader writes the line naming the recorded server
+func (l *sessionLog) writeHeader(server string) error {
+	return l.write(sessionHeader{Server: server, Time: time.Now().UTC()})
+}
+
+// write appends one JSON line
+func (l *sessionLog) write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.w.Write(append(data, '\n'))
+	return err
+}
+
+// frameWriter returns a writer that logs each line written to it as a frame
+func (l *sessionLog) frameWriter(from string) *sessionFrameWriter {
+	return &sessionFrameWriter{log: l, from: from}
+}
+
+// sessionFrameWriter splits a stream into lines and logs each one
+type sessionFrameWriter struct {
+	log     *sessionLog
+	from    string
+	pending []byte
+}
+
+func (w *sessionFrameWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.logLine(w.pending[:i])
+		w.pending = w.pending[i+1:]
+	}
+}
+
+// Flush logs a final line that didn't end with a newline
+func (w *sessionFrameWriter) Flush() {
+	if len(w.pending) > 0 {
+		w.logLine(w.pending)
+		w.pending = nil
+	}
+}
+
+// logLine logs a line as a message if it is JSON, and as text otherwise
+// Recording is best effort: a failed write mustn't break the session.
+func (w *sessionFrameWriter) logLine(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	frame := sessionFrame{Time: time.Now().UTC(), From: w.from}
+	if json.Valid(line) {
+		frame.Message = append(json.RawMessage(nil), line...)
+	} else {
+		frame.Text = string(line)
+	}
+	w.log.write(frame)
+}
+
+// loadSession reads a session file written by 'mcp record'
+func loadSession(path string) (sessionHeader, []sessionFrame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return sessionHeader{}, nil, err
+	}
+	defer file.Close()
+
+	var header sessionHeader
+	var frames []sessionFrame
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), mcpServerMaxMessage)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var frame sessionFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return sessionHeader{}, nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if frame.From == "" {
+			json.Unmarshal(scanner.Bytes(), &header)
+			continue
+		}
+		frames = append(frames, frame)
+	}
+	return header, frames, scanner.Err()
+}
+
+// replaySession starts a server, sends it the client messages of frames in
+// order, and prints how each response compares with the recorded one
+// Returns a silent ExitError with exitCodeDifferent if any differ.
+func replaySession(ctx context.Context, w io.Writer, server MCPServer, frames []sessionFrame, timeout time.Duration) error {
+	// The recorded responses, by request ID
+	recorded := make(map[string]jsonrpcMessage)
+	for _, frame := range frames {
+		var msg jsonrpcMessage
+		if frame.From == "server" && json.Unmarshal(frame.Message, &msg) == nil && msg.Method == "" && len(msg.ID) > 0 {
+			recorded[compactJSON(msg.ID)] = msg
+		}
+	}
+
+	transport, err := newStdioTransport(server)
+	if err != nil {
+		return err
+	}
+	defer transport.Close()
+
+	differences := 0
+	for _, frame := range frames {
+		var msg jsonrpcMessage
+		// Responses to the server's own requests are answered afresh
+		if frame.From != "client" || json.Unmarshal(frame.Message, &msg) != nil || msg.Method == "" {
+			continue
+		}
+		if len(msg.ID) == 0 {
+			if err := transport.write(frame.Message); err != nil {
+				return transport.writeError(ctx, err)
+			}
+			fmt.Fprintf(w, "→ %s\n", msg.Method)
+			continue
+		}
+
+		response, err := replayRequest(ctx, transport, frame.Message, msg.ID, timeout)
+		if err != nil {
+			return fmt.Errorf("%s: %w", msg.Method, err)
+		}
+		want, ok := recorded[compactJSON(msg.ID)]
+		switch {
+		case !ok:
+			fmt.Fprintf(w, "→ %s (id %s): no recorded response to compare\n", msg.Method, compactJSON(msg.ID))
+		case sameResponse(want, response):
+			fmt.Fprintf(w, "→ %s (id %s): same response as recorded\n", msg.Method, compactJSON(msg.ID))
+		default:
+			differences++
+			fmt.Fprintf(w, "→ %s (id %s): response differs\n", msg.Method, compactJSON(msg.ID))
+			fmt.Fprintf(w, "    recorded: %s\n", responseJSON(want))
+			fmt.Fprintf(w, "    replayed: %s\n", responseJSON(response))
+		}
+	}
+
+	if differences > 0 {
+		fmt.Fprintf(w, "\n%s differed from the recording\n", pluralize(differences, "response"))
+		return &ExitError{Code: exitCodeDifferent}
+	}
+	return nil
+}
+
+// replayRequest sends a recorded request as is and waits for the response
+// with its ID, which may be a number or a string
+func replayRequest(ctx context.Context, transport *stdioTransport, raw json.RawMessage, id json.RawMessage, timeout time.Duration) (jsonrpcMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := transport.write(raw); err != nil {
+		return jsonrpcMessage{}, transport.writeError(ctx, err)
+	}
+
+	want := compactJSON(id)
+	for {
+		select {
+		case msg := <-transport.messages:
+			if compactJSON(msg.ID) == want {
+				return msg, nil
+			}
+		case <-transport.done:
+			// The response may have arrived just before the server exited
+			for {
+				select {
+				case msg := <-transport.messages:
+					if compactJSON(msg.ID) == want {
+						return msg, nil
+					}
+				default:
+					return jsonrpcMessage{}, transport.exitError()
+				}
+			}
+		case <-ctx.Done():
+			return jsonrpcMessage{}, fmt.Errorf("no response: %w", ctx.Err())
+		}
+	}
+}
+
+// sameResponse compares the results and errors of two responses, ignoring
+// key order and formatting
+func sameResponse(a, b jsonrpcMessage) bool {
+	var resultA, resultB any
+	json.Unmarshal(a.Result, &resultA)
+	json.Unmarshal(b.Result, &resultB)
+	return reflect.DeepEqual(resultA, resultB) && reflect.DeepEqual(a.Error, b.Error)
+}
+
+// responseJSON renders the result or error of a response on one line
+func responseJSON(msg jsonrpcMessage) string {
+	if msg.Error != nil {
+		data, _ := json.Marshal(msg.Error)
+		return string(data)
+	}
+	return compactJSON(msg.Result)
+}
+
+// compactJSON returns JSON without insignificant whitespace
+func compactJSON(data json.RawMessage) string {
+	var buf bytes.Buffer
+	if json.Compact(&buf, data) != nil {
+		return string(data)
+	}
+	return buf.String()
+}
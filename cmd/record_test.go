@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordSession(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &sessionLog{w: file}
+	if err := session.writeHeader("echo"); err != nil {
+		t.Fatal(err)
+	}
+	fromClient := session.frameWriter("client")
+	fromServer := session.frameWriter("server")
+
+	server := MCPServer{Command: "sh", Args: []string{"-c", `read line; echo "starting up"; echo "$line"`}}
+	request := `{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"
+	var out bytes.Buffer
+	if err := execServer(context.Background(), server, io.TeeReader(strings.NewReader(request), fromClient), io.MultiWriter(&out, fromServer), &bytes.Buffer{}); err != nil {
+		t.Fatalf("execServer failed: %v", err)
+	}
+	fromClient.Flush()
+	fromServer.Flush()
+	file.Close()
+
+	if out.String() != "starting up\n"+request {
+		t.Errorf("Expected the server's output to be passed through, got %q", out.String())
+	}
+
+	header, frames, err := loadSession(path)
+	if err != nil {
+		t.Fatalf("loadSession failed: %v", err)
+	}
+	if header.Server != "echo" {
+		t.Errorf("Expected the header to name the server, got %+v", header)
+	}
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %+v", frames)
+	}
+	if frames[0].From != "client" || string(frames[0].Message) != strings.TrimSpace(request) {
+		t.Errorf("Unexpected client frame: %+v", frames[0])
+	}
+	if frames[1].From != "server" || frames[1].Text != "starting up" || frames[1].Message != nil {
+		t.Errorf("Expected a non-JSON line to be recorded as text, got %+v", frames[1])
+	}
+	if frames[2].From != "server" || string(frames[2].Message) != strings.TrimSpace(request) {
+		t.Errorf("Unexpected server frame: %+v", frames[2])
+	}
+}
+
+func TestReplaySession(t *testing.T) {
+	server := fakeStdioServer(t)
+	frame := func(from, message string) sessionFrame {
+		return sessionFrame{From: from, Message: json.RawMessage(message)}
+	}
+	session := func(result string) []sessionFrame {
+		return []sessionFrame{
+			frame("client", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`),
+			frame("server", `{"jsonrpc":"2.0","id":"ping-1","method":"ping"}`),
+			frame("client", `{"jsonrpc":"2.0","id":"ping-1","result":{}}`),
+			frame("server", `{"jsonrpc":"2.0","id":1,"result":`+result+`}`),
+			frame("client", `{"jsonrpc":"2.0","method":"notifications/initialized"}`),
+		}
+	}
+
+	t.Run("same responses", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := replaySession(context.Background(), &out, server, session(fakeInitializeResult), 5*time.Second); err != nil {
+			t.Fatalf("replaySession failed: %v\n%s", err, out.String())
+		}
+		want := "→ initialize (id 1): same response as recorded\n→ notifications/initialized\n"
+		if out.String() != want {
+			t.Errorf("Expected:\n%s\nGot:\n%s", want, out.String())
+		}
+	})
+
+	t.Run("different response", func(t *testing.T) {
+		var out bytes.Buffer
+		recorded := strings.Replace(fakeInitializeResult, `"1.0.0"`, `"0.9.0"`, 1)
+		err := replaySession(context.Background(), &out, server, session(recorded), 5*time.Second)
+		if ExitCode(err) != exitCodeDifferent {
+			t.Errorf("Expected exit code %d, got %v", exitCodeDifferent, err)
+		}
+		for _, want := range []string{"initialize (id 1): response differs", `"version":"0.9.0"`, `"version":"1.0.0"`, "1 response differed"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("Expected %q in output:\n%s", want, out.String())
+			}
+		}
+	})
+}
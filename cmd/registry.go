@@ -0,0 +1,319 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// defaultRegistryURL is the official MCP server registry
+	defaultRegistryURL = "https://registry.modelcontextprotocol.io"
+
+	// registryPageSize is how many servers are requested per page
+	registryPageSize = 50
+)
+
+// registryClient queries an MCP server registry through the HTTP cache
+type registryClient struct {
+	baseURL string
+	cache   *httpCache
+}
+
+// registryServer is a server published to the registry
+type registryServer struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Version     string            `json:"version"`
+	Repository  registryRepo      `json:"repository"`
+	WebsiteURL  string            `json:"websiteUrl"`
+	Packages    []registryPackage `json:"packages"`
+	Remotes     []registryRemote  `json:"remotes"`
+}
+
+// registryRepo is the source repository of a registry server
+type registryRepo struct {
+	URL string `json:"url"`
+}
+
+// registryPackage is one way to install a registry server locally
+type registryPackage struct {
+	RegistryType         string             `json:"registryType"`
+	Identifier           string             `json:"identifier"`
+	Version              string             `json:"version"`
+	RuntimeHint          string             `json:"runtimeHint"`
+	EnvironmentVariables []registryVariable `json:"environmentVariables"`
+}
+
+// registryRemote is a hosted endpoint of a registry server
+type registryRemote struct {
+	Type    string             `json:"type"`
+	URL     string             `json:"url"`
+	Headers []registryVariable `json:"headers"`
+}
+
+// registryVariable is an environment variable or header a server needs
+type registryVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IsRequired  bool   `json:"isRequired"`
+	IsSecret    bool   `json:"isSecret"`
+	Default     string `json:"default"`
+}
+
+// registryListResponse is a page of the registry's server list
+// Servers are either bare or wrapped in a "server" object depending on the
+// registry version.
+type registryListResponse struct {
+	Servers  []json.RawMessage `json:"servers"`
+	Metadata struct {
+		NextCursor string `json:"nextCursor"`
+	} `json:"metadata"`
+}
+
+// newRegistryClient creates a client for the configured registry
+func newRegistryClient() (*registryClient, error) {
+	cache, err := newHTTPCache()
+	if err != nil {
+		return nil, err
+	}
+	return &registryClient{baseURL: getRegistryURL(), cache: cache}, nil
+}
+
+// getRegistryURL returns the configured registry URL, defaulting to the official registry
+func getRegistryURL() string {
+	config, err := loadCLIConfig()
+	if err != nil || config.RegistryURL == "" {
+		return defaultRegistryURL
+	}
+	return strings.TrimRight(config.RegistryURL, "/")
+}
+
+// Search returns up to limit servers whose name matches query, following
+// pagination cursors as needed
+func (c *registryClient) Search(ctx context.Context, query string, limit int) ([]registryServer, error) {
+	var servers []registryServer
+	cursor := ""
+	for {
+		params := url.Values{}
+		params.Set("search", query)
+		params.Set("version", "latest")
+		params.Set("limit", fmt.Sprint(registryPageSize))
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		page, next, err := c.list(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, page...)
+
+		if next == "" || next == cursor || len(servers) >= limit {
+			break
+		}
+		cursor = next
+	}
+
+	if len(servers) > limit {
+		servers = servers[:limit]
+	}
+	return servers, nil
+}
+
+// list fetches one page of servers and returns the cursor of the next page
+func (c *registryClient) list(ctx context.Context, params url.Values) ([]registryServer, string, error) {
+	endpoint := c.baseURL + "/v0/servers?" + params.Encode()
+	body, err := c.cache.Get(ctx, endpoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("query registry: %w", err)
+	}
+
+	var resp registryListResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, "", fmt.Errorf("parse registry response from %s: %w", endpoint, err)
+	}
+
+	servers := make([]registryServer, 0, len(resp.Servers))
+	for _, raw := range resp.Servers {
+		server, err := decodeRegistryServer(raw)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse registry response from %s: %w", endpoint, err)
+		}
+		servers = append(servers, server)
+	}
+	return servers, resp.Metadata.NextCursor, nil
+}
+
+// decodeRegistryServer decodes a server list entry, unwrapping the
+// {"server": {...}, "_meta": {...}} form used by newer registry versions
+func decodeRegistryServer(raw json.RawMessage) (registryServer, error) {
+	var wrapped struct {
+		Server *registryServer `json:"server"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err != nil {
+		return registryServer{}, err
+	}
+	if wrapped.Server != nil {
+		return *wrapped.Server, nil
+	}
+
+	var server registryServer
+	err := json.Unmarshal(raw, &server)
+	return server, err
+}
+
+// packageType returns how a registry server is distributed: npm, pypi,
+// docker, another package registry, or remote when it is only hosted
+func (s registryServer) packageType() string {
+	if len(s.Packages) == 0 {
+		if len(s.Remotes) > 0 {
+			return "remote"
+		}
+		return ""
+	}
+	switch t := strings.ToLower(s.Packages[0].RegistryType); t {
+	case "oci", "docker":
+		return "docker"
+	default:
+		return t
+	}
+}
+
+// requiredEnvVars returns the names of the required environment variables of
+// the server's first package
+func (s registryServer) requiredEnvVars() []string {
+	if len(s.Packages) == 0 {
+		return nil
+	}
+	var names []string
+	for _, v := range s.Packages[0].EnvironmentVariables {
+		if v.IsRequired {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
+
+// Get returns the latest version of the server with exactly the given name
+// The search matches names containing it, so pagination cursors are followed
+// until the exact match turns up.
+func (c *registryClient) Get(ctx context.Context, name string) (registryServer, error) {
+	cursor := ""
+	for {
+		params := url.Values{}
+		params.Set("search", name)
+		params.Set("version", "latest")
+		params.Set("limit", fmt.Sprint(registryPageSize))
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		servers, next, err := c.list(ctx, params)
+		if err != nil {
+			return registryServer{}, err
+		}
+		for _, server := range servers {
+			if server.Name == name {
+				return server, nil
+			}
+		}
+
+		if next == "" || next == cursor {
+			break
+		}
+		cursor = next
+	}
+	return registryServer{}, newValidationError("server '%s' not found in %s (try 'mcp search')", name, c.baseURL)
+}
+
+// defaultServiceName derives a compose service name from a registry name,
+// e.g. io.github.modelcontextprotocol/server-filesystem -> filesystem
+func (s registryServer) defaultServiceName() string {
+	name := s.Name[strings.LastIndex(s.Name, "/")+1:]
+	for _, affix := range []string{"mcp-server-", "server-", "mcp-"} {
+		name = strings.TrimPrefix(name, affix)
+	}
+	for _, affix := range []string{"-mcp-server", "-server", "-mcp"} {
+		name = strings.TrimSuffix(name, affix)
+	}
+	return name
+}
+
+// toService generates a compose service for the server from its first
+// package, or its first remote when it is only hosted
+// Required variables are referenced as ${VAR} and documented with
+// mcp.env-doc labels; optional variables are only included when they have a default.
+func (s registryServer) toService() (Service, error) {
+	var service Service
+
+	switch {
+	case len(s.Packages) > 0:
+		pkg := s.Packages[0]
+		switch s.packageType() {
+		case "npm":
+			service.Command = "npx -y " + versioned(pkg.Identifier, "@", pkg.Version)
+		case "pypi":
+			service.Command = "uvx " + versioned(pkg.Identifier, "@", pkg.Version)
+		case "docker":
+			image := pkg.Identifier
+			if !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+				image = versioned(image, ":", pkg.Version)
+			}
+			service.Image = image
+		default:
+			return Service{}, newValidationError("server '%s' is distributed as %s, which mcp can't run (supported: npm, pypi, docker, remote)", s.Name, pkg.RegistryType)
+		}
+
+		for _, v := range pkg.EnvironmentVariables {
+			switch {
+			case v.IsRequired:
+				setServiceEnv(&service, v.Name, "${"+v.Name+"}")
+				if v.Description != "" {
+					setServiceLabel(&service, envDocLabelPrefix+v.Name, v.Description)
+				}
+			case v.Default != "":
+				setServiceEnv(&service, v.Name, v.Default)
+			}
+		}
+	case len(s.Remotes) > 0:
+		remote := s.Remotes[0]
+		service.Command = remote.URL
+		for _, h := range remote.Headers {
+			name := envVarName(h.Name)
+			setServiceLabel(&service, "mcp.header."+h.Name, "${"+name+"}")
+			if h.Description != "" {
+				setServiceLabel(&service, envDocLabelPrefix+name, h.Description)
+			}
+		}
+		// Remote servers need auth labels; an empty placeholder means none
+		if len(remote.Headers) == 0 {
+			setServiceLabel(&service, "mcp.header.X-Empty", "")
+		}
+	default:
+		return Service{}, newValidationError("server '%s' has no packages or remotes", s.Name)
+	}
+
+	if s.Description != "" {
+		setServiceLabel(&service, "mcp.description", s.Description)
+	}
+	return service, nil
+}
+
+// versioned appends a version to a package identifier when there is one
+func versioned(identifier, separator, version string) string {
+	if version == "" || version == "latest" {
+		return identifier
+	}
+	return identifier + separator + version
+}
+
+// setServiceEnv sets an environment variable on a service, creating the map if needed
+func setServiceEnv(service *Service, key, value string) {
+	if service.Environment == nil {
+		service.Environment = make(map[string]string)
+	}
+	service.Environment[key] = value
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestRegistry serves two pages of results for searches containing
+// filesystem: the first in the wrapped format of newer registries, the second
+// with bare servers
+func newTestRegistry(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/servers" || !strings.Contains(r.URL.Query().Get("search"), "filesystem") {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{
+  "servers": [
+    {
+      "server": {
+        "name": "io.github.modelcontextprotocol/server-filesystem",
+        "description": "Secure file operations with configurable access controls",
+        "packages": [{
+          "registryType": "npm",
+          "identifier": "@modelcontextprotocol/server-filesystem",
+          "environmentVariables": [
+            {"name": "ALLOWED_DIRS", "isRequired": true},
+            {"name": "LOG_LEVEL"}
+          ]
+        }]
+      },
+      "_meta": {}
+    }
+  ],
+  "metadata": {"nextCursor": "page2"}
+}`))
+		case "page2":
+			w.Write([]byte(`{
+  "servers": [
+    {
+      "name": "io.example/filesystem-docker",
+      "description": "Filesystem access in a container",
+      "packages": [{"registryType": "oci", "identifier": "example/filesystem"}]
+    },
+    {
+      "name": "io.example/filesystem-cloud",
+      "description": "Hosted filesystem",
+      "remotes": [{"type": "streamable-http", "url": "https://fs.example.com/mcp"}]
+    }
+  ],
+  "metadata": {}
+}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRegistrySearch(t *testing.T) {
+	registry := newTestRegistry(t)
+	t.Setenv("HOME", t.TempDir())
+
+	cache, err := newHTTPCache()
+	if err != nil {
+		t.Fatalf("newHTTPCache failed: %v", err)
+	}
+	cache.minInterval = 0
+	client := &registryClient{baseURL: registry.URL, cache: cache}
+
+	servers, err := client.Search(context.Background(), "filesystem", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(servers) != 3 {
+		t.Fatalf("Expected 3 servers across both pages, got %d", len(servers))
+	}
+
+	expected := []struct {
+		name, packageType string
+		env               []string
+	}{
+		{"io.github.modelcontextprotocol/server-filesystem", "npm", []string{"ALLOWED_DIRS"}},
+		{"io.example/filesystem-docker", "docker", nil},
+		{"io.example/filesystem-cloud", "remote", nil},
+	}
+	for i, e := range expected {
+		if servers[i].Name != e.name || servers[i].packageType() != e.packageType {
+			t.Errorf("Server %d: expected %s (%s), got %s (%s)", i, e.name, e.packageType, servers[i].Name, servers[i].packageType())
+		}
+		if strings.Join(servers[i].requiredEnvVars(), ",") != strings.Join(e.env, ",") {
+			t.Errorf("Server %d: expected required env %v, got %v", i, e.env, servers[i].requiredEnvVars())
+		}
+	}
+
+	t.Run("limit stops pagination", func(t *testing.T) {
+		servers, err := client.Search(context.Background(), "filesystem", 1)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(servers) != 1 {
+			t.Errorf("Expected 1 server, got %d", len(servers))
+		}
+	})
+
+	t.Run("display", func(t *testing.T) {
+		var out bytes.Buffer
+		displayRegistryServers(&out, servers)
+		for _, want := range []string{"NAME", "io.example/filesystem-docker", "docker", "ALLOWED_DIRS"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", want, out.String())
+			}
+		}
+	})
+}
+
+func TestRegistryGet(t *testing.T) {
+	registry := newTestRegistry(t)
+	t.Setenv("HOME", t.TempDir())
+
+	cache, err := newHTTPCache()
+	if err != nil {
+		t.Fatalf("newHTTPCache failed: %v", err)
+	}
+	cache.minInterval = 0
+	client := &registryClient{baseURL: registry.URL, cache: cache}
+
+	// The server is on the second page of results
+	server, err := client.Get(context.Background(), "io.example/filesystem-cloud")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if server.Name != "io.example/filesystem-cloud" || server.packageType() != "remote" {
+		t.Errorf("Expected io.example/filesystem-cloud (remote), got %s (%s)", server.Name, server.packageType())
+	}
+
+	_, err = client.Get(context.Background(), "io.example/filesystem")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a not found error for a name no page has, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -122,11 +123,15 @@ func ExtractHeaders(service Service, envVars map[string]string) (map[string]stri
 	return headers, nil
 }
 
-// remoteSupportedTools defines which tools support remote MCP servers
-var remoteSupportedTools = map[string]bool{
-	"cursor": true,
-	"kiro":   true,
-	"q-cli":  true,
+// getRemoteSupportedTools returns all built-in and custom tools that support remote servers
+func getRemoteSupportedTools() []string {
+	var tools []string
+	for _, tool := range getAllTools() {
+		if toolSupportsRemote(tool) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
 }
 
 // ValidateToolSupport validates that the specified tool supports remote servers if any are present
@@ -140,13 +145,9 @@ func ValidateToolSupport(toolShortcut string, servers map[string]Service) error
 	}
 
 	if hasRemoteServers && toolShortcut != "" {
-		if !remoteSupportedTools[toolShortcut] {
-			supportedTools := make([]string, 0, len(remoteSupportedTools))
-			for tool := range remoteSupportedTools {
-				supportedTools = append(supportedTools, tool)
-			}
+		if !toolSupportsRemote(toolShortcut) {
 			return fmt.Errorf("tool '%s' does not support remote MCP servers. Supported tools: %s",
-				toolShortcut, strings.Join(supportedTools, ", "))
+				toolShortcut, strings.Join(getRemoteSupportedTools(), ", "))
 		}
 	}
 
@@ -157,20 +158,17 @@ func ValidateToolSupport(toolShortcut string, servers map[string]Service) error
 func ValidateToolSupportWithEnvExpansion(toolShortcut string, servers map[string]Service, envVars map[string]string) error {
 	hasRemoteServers := false
 	for _, service := range servers {
-		if IsRemoteServerWithEnvExpansion(service, envVars) {
+		// Containers serving HTTP are written as remote servers too
+		if IsRemoteServerWithEnvExpansion(service, envVars) || GetServiceURL(service, envVars) != "" {
 			hasRemoteServers = true
 			break
 		}
 	}
 
 	if hasRemoteServers && toolShortcut != "" {
-		if !remoteSupportedTools[toolShortcut] {
-			supportedTools := make([]string, 0, len(remoteSupportedTools))
-			for tool := range remoteSupportedTools {
-				supportedTools = append(supportedTools, tool)
-			}
+		if !toolSupportsRemote(toolShortcut) {
 			return fmt.Errorf("tool '%s' does not support remote MCP servers. Supported tools: %s",
-				toolShortcut, strings.Join(supportedTools, ", "))
+				toolShortcut, strings.Join(getRemoteSupportedTools(), ", "))
 		}
 	}
 
@@ -207,7 +205,17 @@ func ExtractOAuthConfig(service Service, envVars map[string]string) (OAuthConfig
 }
 
 // acquireAccessToken performs OAuth 2.0 client credentials flow to acquire an access token
-func acquireAccessToken(config OAuthConfig) (string, error) {
+func acquireAccessToken(ctx context.Context, config OAuthConfig) (string, error) {
+	oauthResp, err := requestAccessToken(ctx, config)
+	if err != nil {
+		return "", err
+	}
+	return oauthResp.AccessToken, nil
+}
+
+// requestAccessToken performs OAuth 2.0 client credentials flow and returns
+// the whole token response, including its lifetime and scopes
+func requestAccessToken(ctx context.Context, config OAuthConfig) (OAuthResponse, error) {
 	// Prepare form data for client credentials grant
 	data := url.Values{}
 	data.Set("grant_type", config.GrantType)
@@ -220,9 +228,9 @@ func acquireAccessToken(config OAuthConfig) (string, error) {
 	}
 
 	// Create POST request with application/x-www-form-urlencoded content type
-	req, err := http.NewRequest("POST", config.TokenURL, bytes.NewBufferString(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", config.TokenURL, bytes.NewBufferString(data.Encode()))
 	if err != nil {
-		return "", fmt.Errorf("failed to create OAuth request: %w", err)
+		return OAuthResponse{}, fmt.Errorf("failed to create OAuth request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -231,37 +239,42 @@ func acquireAccessToken(config OAuthConfig) (string, error) {
 	// Perform the request
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("network error: %w", err)
+		countError("oauth")
+		if ctx.Err() != nil {
+			return OAuthResponse{}, fmt.Errorf("token request canceled: %w", ctx.Err())
+		}
+		return OAuthResponse{}, fmt.Errorf("network error: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Handle HTTP error responses
 	if resp.StatusCode == 401 {
-		return "", fmt.Errorf("authentication failed (401 Unauthorized)")
+		return OAuthResponse{}, fmt.Errorf("authentication failed (401 Unauthorized)")
 	}
 	if resp.StatusCode == 403 {
-		return "", fmt.Errorf("authentication failed (403 Forbidden)")
+		return OAuthResponse{}, fmt.Errorf("authentication failed (403 Forbidden)")
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("OAuth request failed with status %d", resp.StatusCode)
+		return OAuthResponse{}, fmt.Errorf("OAuth request failed with status %d", resp.StatusCode)
 	}
 
 	// Parse JSON response
 	var oauthResp OAuthResponse
 	if err := json.NewDecoder(resp.Body).Decode(&oauthResp); err != nil {
-		return "", fmt.Errorf("failed to parse OAuth response: %w", err)
+		return OAuthResponse{}, fmt.Errorf("failed to parse OAuth response: %w", err)
 	}
 
 	// Validate that we received an access token
 	if oauthResp.AccessToken == "" {
-		return "", fmt.Errorf("OAuth response missing access_token field")
+		return OAuthResponse{}, fmt.Errorf("OAuth response missing access_token field")
 	}
 
-	return oauthResp.AccessToken, nil
+	metrics.Inc(metricTokenRefreshes, nil)
+	return oauthResp, nil
 }
 
 // AcquireAccessTokenWithFeedback acquires an OAuth access token with user feedback
-func AcquireAccessTokenWithFeedback(serverName string, config OAuthConfig) (string, error) {
+func AcquireAccessTokenWithFeedback(ctx context.Context, serverName string, config OAuthConfig) (string, error) {
 	fmt.Fprintf(os.Stderr, "acquiring access token for '%s'...\n", serverName)
-	return acquireAccessToken(config)
+	return acquireAccessToken(ctx, config)
 }
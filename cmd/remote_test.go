@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestIsRemoteServer(t *testing.T) {
@@ -574,3 +578,86 @@ func TestExtractOAuthConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestAccessTokenCountsAcquiredTokens(t *testing.T) {
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"access_token":"abc123"}`))
+	}))
+	defer server.Close()
+	config := OAuthConfig{GrantType: "client_credentials", TokenURL: server.URL, ClientID: "id"}
+
+	before := metrics.Value(metricTokenRefreshes, nil)
+	if _, err := requestAccessToken(context.Background(), config); err == nil {
+		t.Fatal("Expected an error for a failed token request")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := requestAccessToken(ctx, config); err == nil {
+		t.Fatal("Expected an error for a canceled token request")
+	}
+	if got := metrics.Value(metricTokenRefreshes, nil); got != before {
+		t.Errorf("Expected failed requests not to be counted, got %v more", got-before)
+	}
+
+	status = http.StatusOK
+	if _, err := requestAccessToken(context.Background(), config); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := metrics.Value(metricTokenRefreshes, nil); got != before+1 {
+		t.Errorf("Expected the acquired token to be counted, got %v more", got-before)
+	}
+}
+
+func TestAcquireAccessTokenContext(t *testing.T) {
+	t.Run("successful token request", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"abc123","token_type":"Bearer","expires_in":3600}`))
+		}))
+		defer server.Close()
+
+		token, err := acquireAccessToken(context.Background(), OAuthConfig{
+			GrantType: "client_credentials",
+			TokenURL:  server.URL,
+			ClientID:  "id",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if token != "abc123" {
+			t.Errorf("Expected token abc123, got %s", token)
+		}
+	})
+
+	t.Run("canceled context aborts promptly", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := acquireAccessToken(ctx, OAuthConfig{
+			GrantType: "client_credentials",
+			TokenURL:  server.URL,
+		})
+		if err == nil {
+			t.Fatal("Expected error for canceled context")
+		}
+		if !strings.Contains(err.Error(), "canceled") {
+			t.Errorf("Expected cancellation error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("Expected request to abort promptly, took %v", elapsed)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	removeDeployed bool
+	removeDryRun   bool
+)
+
+// rmCmd represents the rm command
+var rmCmd = &cobra.Command{
+	Use:     "rm <server>...",
+	Aliases: []string{"remove"},
+	Short:   "Remove MCP servers from the compose file",
+	Long: `Remove one or more MCP servers from the mcp-compose.yml file.
+Comments and formatting of the remaining services are preserved.
+With the --deployed flag, the servers are also removed from deployed tool configs.
+Use -t to limit --deployed to a single tool; otherwise every tool config containing the server is updated.
+Tool configs without any of the servers are left untouched, and one that can't be read is skipped.
+With the --dry-run flag, it shows what would be removed without changing any files.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if toolShortcut != "" && !removeDeployed {
+			return newValidationError("the -t flag requires --deployed")
+		}
+		return removeServers(os.Stdout, composeFile, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rmCmd)
+	rmCmd.Flags().BoolVar(&removeDeployed, "deployed", false, "Also remove the servers from deployed tool configs")
+	rmCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Only remove from this tool's config (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	rmCmd.Flags().BoolVar(&removeDryRun, "dry-run", false, "Show what would be removed without changing any files")
+	rmCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// removeServers deletes the named servers from the compose file and,
+// when --deployed is set, from the targeted tool configs
+func removeServers(w io.Writer, composePath string, names []string) error {
+	doc, err := loadComposeDocument(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+
+	var targets []string
+	if removeDeployed {
+		targets, err = deployedTargets()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Find which tool configs contain each server before changing anything
+	deployed := make(map[string]MCPConfig)
+	deployedPaths := make(map[string]string)
+	var readable []string
+	for _, target := range targets {
+		config, path, err := loadToolConfig(target)
+		if err != nil {
+			// A tool chosen with -t must be read; others are skipped
+			if toolShortcut != "" {
+				return newConfigError("load tool config", path, err)
+			}
+			fmt.Fprintf(w, "Skipped %s: %v\n", target, err)
+			continue
+		}
+		deployed[target] = config
+		deployedPaths[target] = path
+		readable = append(readable, target)
+	}
+	targets = readable
+
+	composeChanged := false
+	changed := make(map[string]bool)
+	for _, name := range names {
+		found := false
+
+		if removeComposeService(doc, name) {
+			found = true
+			composeChanged = true
+			printRemoval(w, name, composePath)
+		}
+
+		for _, target := range targets {
+			if _, ok := deployed[target].MCPServers[name]; ok {
+				found = true
+				delete(deployed[target].MCPServers, name)
+				changed[target] = true
+				printRemoval(w, name, deployedPaths[target])
+			}
+		}
+
+		if !found {
+			return newValidationError("server '%s' not found in %s", name, composePath)
+		}
+	}
+
+	if removeDryRun {
+		return nil
+	}
+
+	if composeChanged {
+		if err := saveComposeDocument(composePath, doc); err != nil {
+			return newConfigError("write compose file", composePath, err)
+		}
+	}
+
+	// Only rewrite the tool configs a server was removed from
+	for _, target := range targets {
+		if !changed[target] {
+			continue
+		}
+		path := deployedPaths[target]
+		tool, _ := splitTarget(target)
+		if err := writeToolConfig(deployed[target], path, tool); err != nil {
+			return newConfigError("write MCP config", path, err)
+		}
+	}
+
+	return nil
+}
+
+// deployedTargets returns the tool config targets that --deployed should update:
+// the -t tool in the selected scope, or every tool config
+func deployedTargets() ([]string, error) {
+	if toolShortcut == "" {
+		return getStatusTargets(getAllTools()), nil
+	}
+
+	path, err := getScopedToolPath(toolShortcut, configScope)
+	if err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+	if path == "" {
+		return nil, newValidationError("unknown tool shortcut: %s", toolShortcut)
+	}
+
+	if configScope == scopeProject {
+		return []string{projectTarget(toolShortcut)}, nil
+	}
+	return []string{toolShortcut}, nil
+}
+
+// printRemoval reports a removed (or, in dry-run mode, removable) server
+func printRemoval(w io.Writer, name, path string) {
+	if removeDryRun {
+		fmt.Fprintf(w, "Would remove %s from %s\n", name, path)
+		return
+	}
+	fmt.Fprintf(w, "Removed %s from %s\n", name, path)
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const removeTestCompose = `services:
+  filesystem:
+    image: mcp/filesystem
+
+  github:
+    image: mcp/github
+`
+
+// setupRemoveTest writes a compose file and a kiro config containing both servers
+func setupRemoveTest(t *testing.T) (string, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	originalDeployed, originalDryRun, originalTool := removeDeployed, removeDryRun, toolShortcut
+	t.Cleanup(func() {
+		removeDeployed, removeDryRun, toolShortcut = originalDeployed, originalDryRun, originalTool
+	})
+	removeDeployed, removeDryRun, toolShortcut = false, false, ""
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	if err := os.WriteFile(composePath, []byte(removeTestCompose), 0644); err != nil {
+		t.Fatalf("Failed to write compose file: %v", err)
+	}
+
+	kiroPath, err := getPlatformToolPath("kiro")
+	if err != nil {
+		t.Fatalf("Failed to resolve kiro path: %v", err)
+	}
+	os.MkdirAll(filepath.Dir(kiroPath), 0755)
+	config := MCPConfig{MCPServers: map[string]MCPServer{
+		"filesystem": {Command: "docker"},
+		"github":     {Command: "docker"},
+	}}
+	if err := writeToolConfig(config, kiroPath, ""); err != nil {
+		t.Fatalf("Failed to write kiro config: %v", err)
+	}
+
+	return composePath, kiroPath
+}
+
+func TestRemoveServers(t *testing.T) {
+	composePath, kiroPath := setupRemoveTest(t)
+
+	var out bytes.Buffer
+	if err := removeServers(&out, composePath, []string{"github"}); err != nil {
+		t.Fatalf("removeServers failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(composePath)
+	if strings.Contains(string(data), "github") {
+		t.Errorf("Expected github to be removed from compose file, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "filesystem") {
+		t.Errorf("Expected filesystem to remain in compose file, got:\n%s", data)
+	}
+
+	// Without --deployed the tool config is untouched
+	config, _, _ := loadToolConfig("kiro")
+	if _, ok := config.MCPServers["github"]; !ok {
+		t.Errorf("Expected github to remain in %s", kiroPath)
+	}
+}
+
+func TestRemoveServersDeployed(t *testing.T) {
+	composePath, _ := setupRemoveTest(t)
+	removeDeployed = true
+	toolShortcut = "kiro"
+
+	var out bytes.Buffer
+	if err := removeServers(&out, composePath, []string{"github"}); err != nil {
+		t.Fatalf("removeServers failed: %v", err)
+	}
+
+	config, _, _ := loadToolConfig("kiro")
+	if _, ok := config.MCPServers["github"]; ok {
+		t.Error("Expected github to be removed from kiro config")
+	}
+	if _, ok := config.MCPServers["filesystem"]; !ok {
+		t.Error("Expected filesystem to remain in kiro config")
+	}
+}
+
+func TestRemoveServersDeployedVSCodeFormat(t *testing.T) {
+	composePath, _ := setupRemoveTest(t)
+	path := setupVSCodeTool(t, map[string]MCPServer{
+		"filesystem": {Command: "docker"},
+		"github":     {Command: "docker"},
+	})
+	removeDeployed = true
+	toolShortcut = "vscode"
+
+	if err := removeServers(&bytes.Buffer{}, composePath, []string{"github"}); err != nil {
+		t.Fatalf("removeServers failed: %v", err)
+	}
+	servers := readVSCodeServers(t, path)
+	if _, ok := servers["github"]; ok || len(servers) != 1 {
+		t.Errorf("Expected only filesystem under servers, got %v", servers)
+	}
+}
+
+func TestRemoveServersDryRun(t *testing.T) {
+	composePath, kiroPath := setupRemoveTest(t)
+	removeDeployed = true
+	removeDryRun = true
+
+	var out bytes.Buffer
+	if err := removeServers(&out, composePath, []string{"github"}); err != nil {
+		t.Fatalf("removeServers failed: %v", err)
+	}
+
+	output := out.String()
+	for _, path := range []string{composePath, kiroPath} {
+		if !strings.Contains(output, "Would remove github from "+path) {
+			t.Errorf("Expected dry-run output for %s, got:\n%s", path, output)
+		}
+	}
+
+	data, _ := os.ReadFile(composePath)
+	if string(data) != removeTestCompose {
+		t.Errorf("Dry run should not modify the compose file, got:\n%s", data)
+	}
+	config, _, _ := loadToolConfig("kiro")
+	if _, ok := config.MCPServers["github"]; !ok {
+		t.Error("Dry run should not modify the kiro config")
+	}
+}
+
+func TestRemoveServersNotFound(t *testing.T) {
+	composePath, _ := setupRemoveTest(t)
+
+	err := removeServers(&bytes.Buffer{}, composePath, []string{"missing"})
+	if err == nil {
+		t.Fatal("Expected error for unknown server")
+	}
+	if ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected validation exit code, got %d", ExitCode(err))
+	}
+}
+
+func TestRemoveServersDeployedLeavesOtherConfigs(t *testing.T) {
+	composePath, kiroPath := setupRemoveTest(t)
+	removeDeployed = true
+
+	// Cursor's config doesn't have the server, and Q CLI's can't be read
+	cursorPath, _ := getPlatformToolPath("cursor")
+	os.MkdirAll(filepath.Dir(cursorPath), 0755)
+	cursorData := []byte("{\"mcpServers\":{\"time\":{\"command\":\"uvx\"}}}")
+	os.WriteFile(cursorPath, cursorData, 0644)
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(cursorPath, past, past)
+	qPath, _ := getPlatformToolPath("q-cli")
+	os.MkdirAll(filepath.Dir(qPath), 0755)
+	os.WriteFile(qPath, []byte("{not json"), 0644)
+
+	var out bytes.Buffer
+	if err := removeServers(&out, composePath, []string{"github"}); err != nil {
+		t.Fatalf("removeServers failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Skipped q-cli") {
+		t.Errorf("Expected the unreadable config to be reported, got:\n%s", out.String())
+	}
+
+	data, _ := os.ReadFile(cursorPath)
+	info, _ := os.Stat(cursorPath)
+	if !bytes.Equal(data, cursorData) || !info.ModTime().Equal(past) {
+		t.Errorf("Expected the config without the server to be untouched, got %s (modified %v)", data, info.ModTime())
+	}
+	config, _ := readMCPConfig(kiroPath)
+	if _, ok := config.MCPServers["github"]; ok {
+		t.Error("Expected github to be removed from kiro config")
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var renameDeployed bool
+
+// renameCmd represents the rename command
+var renameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename a server in the compose file",
+	Long: `Rename a server in the mcp-compose.yml file, preserving its definition, comments,
+and position.
+With the --deployed flag, it is also renamed in every tool config that contains
+it (or only the -t tool's), so renames don't leave stale entries behind.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeServerNames(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if toolShortcut != "" && !renameDeployed {
+			return newValidationError("the -t flag requires --deployed")
+		}
+		return renameServer(os.Stdout, composeFile, args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+	renameCmd.Flags().BoolVar(&renameDeployed, "deployed", false, "Also rename the server in deployed tool configs")
+	renameCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Only rename in this tool's config (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	renameCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// renameServer renames a server in the compose file and, with --deployed,
+// in the tool configs containing it, checking every file before changing any
+func renameServer(w io.Writer, composePath, oldName, newName string) error {
+	if newName == "" || strings.TrimSpace(newName) != newName {
+		return newValidationError("invalid server name '%s'", newName)
+	}
+	if newName == oldName {
+		return newValidationError("server '%s' already has that name", oldName)
+	}
+
+	doc, err := loadComposeDocument(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+	services := servicesNode(doc, false)
+	i := mappingIndex(services, oldName)
+	if i < 0 {
+		return newValidationError("server '%s' not found in %s", oldName, composePath)
+	}
+	// Names differing only in case collide in tools, except for the server itself
+	for _, name := range composeServiceNames(doc) {
+		if name != oldName && strings.EqualFold(strings.TrimSpace(name), newName) {
+			return newValidationError("server '%s' already exists in %s", name, composePath)
+		}
+	}
+
+	var targets []string
+	if renameDeployed {
+		if targets, err = deployedTargets(); err != nil {
+			return err
+		}
+	}
+
+	deployed := make(map[string]MCPConfig)
+	deployedPaths := make(map[string]string)
+	for _, target := range targets {
+		config, path, err := loadToolConfig(target)
+		if err != nil {
+			return newConfigError("load tool config", path, err)
+		}
+		if _, ok := config.MCPServers[oldName]; !ok {
+			continue
+		}
+		if _, ok := config.MCPServers[newName]; ok {
+			return newValidationError("server '%s' already exists in %s", newName, path)
+		}
+		deployed[target] = config
+		deployedPaths[target] = path
+	}
+
+	services.Content[i].Value = newName
+	if err := saveComposeDocument(composePath, doc); err != nil {
+		return newConfigError("write compose file", composePath, err)
+	}
+	fmt.Fprintf(w, "Renamed %s to %s in %s\n", oldName, newName, composePath)
+
+	for _, target := range targets {
+		config, ok := deployed[target]
+		if !ok {
+			continue
+		}
+		path := deployedPaths[target]
+		config.MCPServers[newName] = config.MCPServers[oldName]
+		delete(config.MCPServers, oldName)
+		tool, _ := splitTarget(target)
+		if err := writeToolConfig(config, path, tool); err != nil {
+			return newConfigError("write MCP config", path, err)
+		}
+		fmt.Fprintf(w, "Renamed %s to %s in %s\n", oldName, newName, path)
+	}
+
+	return renameDisabledServer(oldName, newName)
+}
+
+// renameDisabledServer carries a server's disabled state over to its new name
+func renameDisabledServer(oldName, newName string) error {
+	state, err := loadState()
+	if err != nil {
+		return newConfigError("load state", "", err)
+	}
+
+	changed := false
+	for _, disabled := range state.Disabled {
+		if entry, ok := disabled[oldName]; ok {
+			disabled[newName] = entry
+			delete(disabled, oldName)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := saveState(state); err != nil {
+		return newConfigError("save state", "", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const renameTestCompose = `services:
+  # Local files
+  filesystem:
+    image: mcp/filesystem
+
+  github:
+    image: mcp/github
+`
+
+// setupRenameTest writes a compose file and a kiro config containing both servers
+func setupRenameTest(t *testing.T) (string, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	originalDeployed, originalTool := renameDeployed, toolShortcut
+	t.Cleanup(func() {
+		renameDeployed, toolShortcut = originalDeployed, originalTool
+	})
+	renameDeployed, toolShortcut = false, ""
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	if err := os.WriteFile(composePath, []byte(renameTestCompose), 0644); err != nil {
+		t.Fatalf("Failed to write compose file: %v", err)
+	}
+
+	kiroPath, err := getPlatformToolPath("kiro")
+	if err != nil {
+		t.Fatalf("Failed to resolve kiro path: %v", err)
+	}
+	os.MkdirAll(filepath.Dir(kiroPath), 0755)
+	config := MCPConfig{MCPServers: map[string]MCPServer{
+		"filesystem": {Command: "docker"},
+		"github":     {Command: "docker", Args: []string{"run", "mcp/github"}},
+	}}
+	if err := writeToolConfig(config, kiroPath, ""); err != nil {
+		t.Fatalf("Failed to write kiro config: %v", err)
+	}
+
+	return composePath, kiroPath
+}
+
+func TestRenameServer(t *testing.T) {
+	composePath, kiroPath := setupRenameTest(t)
+
+	var out bytes.Buffer
+	if err := renameServer(&out, composePath, "filesystem", "files"); err != nil {
+		t.Fatalf("renameServer failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(composePath)
+	want := strings.Replace(renameTestCompose, "  filesystem:", "  files:", 1)
+	if string(data) != want {
+		t.Errorf("Expected comments and order to be preserved, got:\n%s\nwant:\n%s", data, want)
+	}
+
+	// Without --deployed the tool config is untouched
+	config, _, _ := loadToolConfig("kiro")
+	if _, ok := config.MCPServers["filesystem"]; !ok {
+		t.Errorf("Expected filesystem to remain in %s", kiroPath)
+	}
+}
+
+func TestRenameServerDeployed(t *testing.T) {
+	composePath, kiroPath := setupRenameTest(t)
+	renameDeployed = true
+	toolShortcut = "kiro"
+
+	var out bytes.Buffer
+	if err := renameServer(&out, composePath, "github", "gh"); err != nil {
+		t.Fatalf("renameServer failed: %v", err)
+	}
+
+	config, _, _ := loadToolConfig("kiro")
+	if _, ok := config.MCPServers["github"]; ok {
+		t.Errorf("Expected github to be renamed in %s", kiroPath)
+	}
+	if server, ok := config.MCPServers["gh"]; !ok || len(server.Args) != 2 {
+		t.Errorf("Expected gh to keep github's definition, got %+v", config.MCPServers)
+	}
+	if !strings.Contains(out.String(), kiroPath) {
+		t.Errorf("Expected output to mention %s, got:\n%s", kiroPath, out.String())
+	}
+}
+
+func TestRenameServerDeployedVSCodeFormat(t *testing.T) {
+	composePath, _ := setupRenameTest(t)
+	path := setupVSCodeTool(t, map[string]MCPServer{"github": {Command: "npx"}})
+	renameDeployed = true
+	toolShortcut = "vscode"
+
+	if err := renameServer(&bytes.Buffer{}, composePath, "github", "gh"); err != nil {
+		t.Fatalf("renameServer failed: %v", err)
+	}
+	servers := readVSCodeServers(t, path)
+	if _, ok := servers["gh"]; !ok || len(servers) != 1 {
+		t.Errorf("Expected github to be renamed to gh under servers, got %v", servers)
+	}
+}
+
+func TestRenameServerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldName  string
+		newName  string
+		deployed bool
+		setup    func(t *testing.T, kiroPath string)
+	}{
+		{name: "unknown server", oldName: "slack", newName: "chat"},
+		{name: "same name", oldName: "github", newName: "github"},
+		{name: "empty name", oldName: "github", newName: ""},
+		{name: "existing server", oldName: "github", newName: "Filesystem"},
+		{
+			name:     "existing deployed server",
+			oldName:  "github",
+			newName:  "gh",
+			deployed: true,
+			setup: func(t *testing.T, kiroPath string) {
+				config, _ := readMCPConfig(kiroPath)
+				config.MCPServers["gh"] = MCPServer{Command: "npx"}
+				writeToolConfig(config, kiroPath, "")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			composePath, kiroPath := setupRenameTest(t)
+			renameDeployed = tt.deployed
+			if tt.setup != nil {
+				tt.setup(t, kiroPath)
+			}
+
+			var out bytes.Buffer
+			err := renameServer(&out, composePath, tt.oldName, tt.newName)
+			if ExitCode(err) != exitCodeValidation {
+				t.Fatalf("Expected a validation error, got %v", err)
+			}
+
+			// Nothing is changed when the rename is rejected
+			data, _ := os.ReadFile(composePath)
+			if string(data) != renameTestCompose {
+				t.Errorf("Expected compose file to be unchanged, got:\n%s", data)
+			}
+		})
+	}
+}
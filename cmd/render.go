@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var renderOutDir string
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render [profile]",
+	Short: "Write generated tool configs into a directory tree",
+	Long: `Generate the MCP configuration of every supported tool and write it into the
+--out-dir directory, at the same path relative to that directory as the real
+config has relative to your home directory (or the current directory with --scope project).
+This lets dotfile managers such as chezmoi or stow own placement while mcp-cli owns content.
+Use -t to render a single tool.
+If no profile is specified, it uses default servers.
+Tools that don't support the profile's remote servers, or whose config lives
+outside the home directory, are skipped.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if renderOutDir == "" {
+			return newValidationError("--out-dir is required")
+		}
+
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+		servers := enabledServers(filterServers(config, profile, false))
+
+		// Validate remote servers have required auth configuration (OAuth or headers)
+		for name, service := range servers {
+			if IsRemoteServerWithEnvExpansion(service, envVars) {
+				if err := ValidateRemoteServerAuth(name, service); err != nil {
+					return &ValidationError{Err: err}
+				}
+			}
+		}
+
+		tools := getAllTools()
+		if toolShortcut != "" {
+			tools = []string{toolShortcut}
+		}
+
+		mcpConfig, err := convertToMCPConfig(cmd.Context(), servers, envVars)
+		if err != nil {
+			return err
+		}
+
+		return renderTools(os.Stdout, renderOutDir, tools, servers, envVars, mcpConfig)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().StringVarP(&renderOutDir, "out-dir", "o", "", "Directory to write the generated configs into")
+	renderCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Only render this tool (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	renderCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// renderTools writes mcpConfig for each tool below outDir, mirroring the
+// tool's config location relative to the home (or project) directory
+func renderTools(w io.Writer, outDir string, tools []string, servers map[string]Service, envVars map[string]string, mcpConfig MCPConfig) error {
+	baseDir, err := renderBaseDir()
+	if err != nil {
+		return newConfigError("locate base directory", "", err)
+	}
+
+	for _, tool := range tools {
+		path, err := getScopedToolPath(tool, configScope)
+		if err != nil {
+			// Tools without a config in this scope are skipped when rendering all
+			if toolShortcut == "" {
+				continue
+			}
+			return &ValidationError{Err: err}
+		}
+		if path == "" {
+			return newValidationError("unknown tool shortcut: %s", tool)
+		}
+
+		// q-ide is always workspace-level, so it has no home-relative location
+		if tool == "q-ide" && configScope != scopeProject {
+			fmt.Fprintf(w, "Skipped %s: config is workspace-level (use --scope project)\n", tool)
+			continue
+		}
+
+		if err := ValidateToolSupportWithEnvExpansion(tool, serversForTool(servers, tool), envVars); err != nil {
+			fmt.Fprintf(w, "Skipped %s: %v\n", tool, err)
+			continue
+		}
+		if format := getToolFormat(tool); !isSupportedFormat(format) {
+			fmt.Fprintf(w, "Skipped %s: unsupported format '%s'\n", tool, format)
+			continue
+		}
+
+		relPath, err := filepath.Rel(baseDir, path)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			fmt.Fprintf(w, "Skipped %s: %s is outside %s\n", tool, path, baseDir)
+			continue
+		}
+
+		outPath := filepath.Join(outDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+			return newConfigError("create output directory", filepath.Dir(outPath), err)
+		}
+		toolConfig, _ := stripUnsupportedFields(configForTool(mcpConfig, servers, tool), tool)
+		toolConfig = applyToolInputs(toolConfig, tool, servers, envVars)
+		if err := writeToolConfig(toolConfig, outPath, tool); err != nil {
+			return newConfigError("write MCP config", outPath, err)
+		}
+
+		fmt.Fprintf(w, "Wrote %s (%s)\n", outPath, tool)
+	}
+
+	return nil
+}
+
+// renderBaseDir returns the directory rendered paths are relative to:
+// the home directory, or the current directory for project-scoped configs
+func renderBaseDir() (string, error) {
+	if configScope == scopeProject {
+		return os.Getwd()
+	}
+	return getHomeDir()
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderTools(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	outDir := t.TempDir()
+
+	originalTool, originalScope := toolShortcut, configScope
+	defer func() { toolShortcut, configScope = originalTool, originalScope }()
+	toolShortcut, configScope = "", scopeUser
+
+	servers := map[string]Service{"time": {Command: "uvx mcp-server-time"}}
+	mcpConfig := MCPConfig{MCPServers: map[string]MCPServer{
+		"time": {Command: "uvx", Args: []string{"mcp-server-time"}},
+	}}
+
+	var out bytes.Buffer
+	if err := renderTools(&out, outDir, []string{"q-ide", "cursor", "kiro"}, servers, map[string]string{}, mcpConfig); err != nil {
+		t.Fatalf("renderTools failed: %v", err)
+	}
+
+	for _, rel := range []string{".cursor/mcp.json", ".kiro/settings/mcp.json"} {
+		path := filepath.Join(outDir, filepath.FromSlash(rel))
+		config, err := readMCPConfig(path)
+		if err != nil || !reflect.DeepEqual(config, mcpConfig) {
+			t.Errorf("Expected rendered config at %s, got %+v (err %v)", path, config, err)
+		}
+		if !fileExists(path) {
+			t.Errorf("Expected %s to exist", path)
+		}
+	}
+
+	if !strings.Contains(out.String(), "Skipped q-ide") {
+		t.Errorf("Expected q-ide to be skipped, got:\n%s", out.String())
+	}
+
+	// Nothing is written to the real locations
+	if fileExists(filepath.Join(home, ".cursor", "mcp.json")) {
+		t.Error("render should not write to the real tool config")
+	}
+}
+
+func TestRenderToolsVSCodeInputs(t *testing.T) {
+	composePath, path := setupVSCodeInputs(t)
+	outDir := t.TempDir()
+	originalTool := toolShortcut
+	defer func() { toolShortcut = originalTool }()
+	toolShortcut = "vscode"
+
+	config, _ := loadComposeFile(composePath)
+	envVars, _ := loadEnvVars(composePath)
+	mcpConfig, err := convertToMCPConfig(context.Background(), config.Services, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+
+	if err := renderTools(&bytes.Buffer{}, outDir, []string{"vscode"}, config.Services, envVars, mcpConfig); err != nil {
+		t.Fatalf("renderTools failed: %v", err)
+	}
+	checkVSCodeInputs(t, filepath.Join(outDir, "vscode", filepath.Base(path)))
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// clientRestart is what it takes for a tool to load a new config
+type clientRestart struct {
+	// restart restarts the tool on some platforms; nil if it can't be automated
+	restart func(ctx context.Context, w io.Writer, goos string) (bool, error)
+	// instructions tell the user what to do otherwise
+	instructions string
+}
+
+// clientRestarts maps each built-in tool to how it loads a new config
+var clientRestarts = map[string]clientRestart{
+	"claude-desktop": {
+		restart:      restartClaudeDesktop,
+		instructions: "Quit Claude Desktop completely (not just its window) and open it again",
+	},
+	"cursor": {
+		instructions: "Run 'Developer: Reload Window' from the command palette, or restart Cursor",
+	},
+	"kiro": {
+		instructions: "Kiro reloads its MCP config automatically; check the MCP Servers panel",
+	},
+	"q-cli": {
+		instructions: "Exit any running 'q chat' session and start a new one",
+	},
+	"q-ide": {
+		instructions: "Reload the IDE window, or restart the IDE",
+	},
+}
+
+// clientRestartFormats maps the config formats of custom tools to how they
+// load a new config, for tools that aren't built in
+var clientRestartFormats = map[string]clientRestart{
+	"vscode": {
+		instructions: "VS Code reloads mcp.json automatically; start the servers from the MCP view or with 'MCP: List Servers'",
+	},
+	"zed": {
+		instructions: "Zed reloads settings.json automatically",
+	},
+}
+
+// runClientCommand runs a command used to restart a client, returning its
+// output; tests replace it
+var runClientCommand = func(ctx context.Context, name string, args ...string) (string, error) {
+	path, err := lookPath(name)
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.CommandContext(ctx, path, args...).Output()
+	return string(out), err
+}
+
+// restartPollInterval is how often restartClaudeDesktop checks that the app
+// has quit, up to restartTimeout
+var (
+	restartPollInterval = 250 * time.Millisecond
+	restartTimeout      = 10 * time.Second
+)
+
+// restartClient does what a tool needs to load its new config: restarting it
+// where that can be automated, or printing what to do otherwise
+func restartClient(ctx context.Context, w io.Writer, tool, goos string) error {
+	restart, ok := clientRestarts[tool]
+	if !ok {
+		restart, ok = clientRestartFormats[getToolFormat(tool)]
+	}
+	if !ok {
+		fmt.Fprintf(w, "%s: restart %s to load the new config\n", tool, tool)
+		return nil
+	}
+
+	if restart.restart != nil {
+		done, err := restart.restart(ctx, w, goos)
+		if err != nil {
+			return fmt.Errorf("restart %s: %w (%s)", tool, err, strings.ToLower(restart.instructions[:1])+restart.instructions[1:])
+		}
+		if done {
+			return nil
+		}
+	}
+	fmt.Fprintf(w, "%s: %s\n", tool, restart.instructions)
+	return nil
+}
+
+// restartClaudeDesktop quits and reopens Claude Desktop on macOS if it is
+// running, reporting false on other platforms
+func restartClaudeDesktop(ctx context.Context, w io.Writer, goos string) (bool, error) {
+	if goos != "darwin" {
+		return false, nil
+	}
+	if !claudeDesktopRunning(ctx) {
+		fmt.Fprintln(w, "claude-desktop: not running; it will load the new config when opened")
+		return true, nil
+	}
+
+	if _, err := runClientCommand(ctx, "osascript", "-e", `quit app "Claude"`); err != nil {
+		return false, err
+	}
+	for deadline := time.Now().Add(restartTimeout); claudeDesktopRunning(ctx); {
+		if time.Now().After(deadline) {
+			return false, fmt.Errorf("Claude Desktop didn't quit within %s", restartTimeout)
+		}
+		time.Sleep(restartPollInterval)
+	}
+	if _, err := runClientCommand(ctx, "open", "-a", "Claude"); err != nil {
+		return false, err
+	}
+	fmt.Fprintln(w, "claude-desktop: restarted")
+	return true, nil
+}
+
+// claudeDesktopRunning reports whether the Claude Desktop app is running
+func claudeDesktopRunning(ctx context.Context) bool {
+	out, err := runClientCommand(ctx, "pgrep", "-x", "Claude")
+	return err == nil && strings.TrimSpace(out) != ""
+}
+
+// restartChangedClients restarts, or prints how to restart, each tool whose
+// config was written
+func restartChangedClients(ctx context.Context, w io.Writer, targets []toolTarget) error {
+	for _, target := range targets {
+		if !target.Changed {
+			continue
+		}
+		if err := restartClient(ctx, w, target.Tool, runtime.GOOS); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRestartClient(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	origRun, origInterval := runClientCommand, restartPollInterval
+	t.Cleanup(func() { runClientCommand, restartPollInterval = origRun, origInterval })
+	restartPollInterval = 0
+
+	running := true
+	var ran []string
+	runClientCommand = func(ctx context.Context, name string, args ...string) (string, error) {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		switch name {
+		case "pgrep":
+			if running {
+				return "123\n", nil
+			}
+			return "", errors.New("exit status 1")
+		case "osascript":
+			running = false
+		}
+		return "", nil
+	}
+
+	var out bytes.Buffer
+	if err := restartClient(context.Background(), &out, "claude-desktop", "darwin"); err != nil {
+		t.Fatalf("restartClient failed: %v", err)
+	}
+	if want := []string{"pgrep -x Claude", `osascript -e quit app "Claude"`, "pgrep -x Claude", "open -a Claude"}; strings.Join(ran, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected %q, got %q", want, ran)
+	}
+	if out.String() != "claude-desktop: restarted\n" {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+
+	// A Claude Desktop that isn't running is left closed
+	ran = nil
+	out.Reset()
+	restartClient(context.Background(), &out, "claude-desktop", "darwin")
+	if len(ran) != 1 || !strings.Contains(out.String(), "not running") {
+		t.Errorf("Expected only a check, got %q: %q", ran, out.String())
+	}
+
+	// Elsewhere, the steps are printed
+	ran = nil
+	out.Reset()
+	for _, tool := range []string{"claude-desktop", "cursor", "my-editor"} {
+		if err := restartClient(context.Background(), &out, tool, "linux"); err != nil {
+			t.Fatalf("restartClient failed: %v", err)
+		}
+	}
+	if len(ran) != 0 {
+		t.Errorf("Expected no commands to run, got %q", ran)
+	}
+	for _, want := range []string{
+		"claude-desktop: Quit Claude Desktop completely",
+		"cursor: Run 'Developer: Reload Window'",
+		"my-editor: restart my-editor to load the new config",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+}
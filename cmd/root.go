@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"fmt"
+	"context"
 	"os"
 	"path/filepath"
 
@@ -10,6 +10,11 @@ import (
 
 var (
 	composeFile string
+
+	// composeFiles are the paths given with -f; the first is composeFile and
+	// the rest are overrides layered on it, see loadComposeFile
+	composeFiles         []string
+	composeOverrideFiles []string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -18,32 +23,87 @@ var rootCmd = &cobra.Command{
 	Short: "MCP CLI is a tool for managing MCP server configuration files",
 	Long: `MCP CLI is a tool for managing MCP server configuration files.
 It helps with managing different MCP server configurations based on profiles.`,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Arguments have been validated at this point, so errors returned
+		// from here on are not usage mistakes and shouldn't print usage
+		cmd.SilenceUsage = true
+
+		if errorFormat != "text" && errorFormat != "json" {
+			return newValidationError("unsupported error format: %s (expected text or json)", errorFormat)
+		}
+		if configScope != scopeUser && configScope != scopeProject {
+			return newValidationError("unsupported scope: %s (expected user or project)", configScope)
+		}
+
+		// The default compose file was resolved before --home was parsed
+		if cmd.Flags().Changed("home") && !cmd.Flags().Changed("file") {
+			composeFiles = []string{getDefaultComposeFile()}
+		}
+		composeFile, composeOverrideFiles = composeFiles[0], composeFiles[1:]
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
-func Execute() error {
-	return rootCmd.Execute()
+// The context is passed to every command and cancels in-flight network calls.
+// Errors are reported to stderr here; use ExitCode to map them to an exit status.
+func Execute(ctx context.Context) error {
+	err := rootCmd.ExecuteContext(ctx)
+	if err != nil {
+		printError(os.Stderr, err)
+	}
+	return err
 }
 
 func init() {
 	defaultComposeFile := getDefaultComposeFile()
-	rootCmd.PersistentFlags().StringVarP(&composeFile, "file", "f", defaultComposeFile, "Path to the mcp-compose.yml file")
+	composeFile = defaultComposeFile
+	rootCmd.PersistentFlags().StringArrayVarP(&composeFiles, "file", "f", []string{defaultComposeFile}, "Path to the mcp-compose.yml file; repeat to layer override files on it")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "text", "Error output format (text, json)")
+	rootCmd.PersistentFlags().StringVar(&configScope, "scope", scopeUser, "Tool config scope (user, project)")
+	rootCmd.PersistentFlags().StringVar(&homeOverride, "home", "", "Override the home directory used for config and tool paths (also "+homeOverrideEnvVar+")")
+	rootCmd.PersistentFlags().MarkHidden("home")
+}
+
+// composeFileNames are the compose file names looked for during default
+// discovery, in order of preference
+var composeFileNames = []string{"mcp-compose.yml", "mcp-compose.yaml", "compose.mcp.yml"}
+
+// getComposeFileNames returns the compose file names to look for, starting
+// with the compose-file name from the CLI config when one is set
+func getComposeFileNames() []string {
+	config, err := loadCLIConfig()
+	if err != nil || config.ComposeFile == "" {
+		return composeFileNames
+	}
+	return append([]string{config.ComposeFile}, composeFileNames...)
 }
 
 // getDefaultComposeFile returns the default compose file path, checking local directory first
 func getDefaultComposeFile() string {
-	// First check for local mcp-compose.yml in current directory
-	localComposeFile := "mcp-compose.yml"
-	if _, err := os.Stat(localComposeFile); err == nil {
-		return localComposeFile
+	names := getComposeFileNames()
+
+	// First check for a local compose file in the current directory
+	for _, name := range names {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
 	}
 
-	// Fall back to the global config directory
-	homeDir, err := os.UserHomeDir()
+	// Fall back to the global config directory, or the local file name if
+	// the home directory is unavailable so loading reports a clear error
+	configDir, err := getConfigDir()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting user home directory: %v\n", err)
-		os.Exit(1)
+		return names[0]
+	}
+
+	for _, name := range names {
+		path := filepath.Join(configDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
 	}
 
-	return filepath.Join(homeDir, ".config", "mcp", "mcp-compose.yml")
+	return filepath.Join(configDir, names[0])
 }
@@ -3,9 +3,29 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
+// TestMain runs the suite against a sandboxed home directory so tests never
+// read or overwrite the real ~/.kiro, ~/.cursor, ~/.config/mcp, etc.
+func TestMain(m *testing.M) {
+	sandbox, err := os.MkdirTemp("", "mcp-home")
+	if err != nil {
+		panic(err)
+	}
+
+	os.Setenv("HOME", sandbox)
+	os.Setenv("USERPROFILE", sandbox)
+	os.Unsetenv("APPDATA")
+	os.Unsetenv("XDG_CONFIG_HOME")
+	os.Unsetenv(homeOverrideEnvVar)
+
+	code := m.Run()
+	os.RemoveAll(sandbox)
+	os.Exit(code)
+}
+
 func TestExecute(t *testing.T) {
 	// Test that Execute function works without panicking
 	// We can't easily test the actual execution without complex setup
@@ -65,3 +85,111 @@ func TestGetDefaultComposeFile(t *testing.T) {
 		}
 	})
 }
+
+func TestGetDefaultComposeFileAlternateNames(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(t.TempDir())
+
+	configDir := filepath.Join(home, ".config", "mcp")
+	os.MkdirAll(configDir, 0755)
+
+	t.Run("yaml extension", func(t *testing.T) {
+		os.WriteFile("mcp-compose.yaml", []byte("services: {}"), 0644)
+		defer os.Remove("mcp-compose.yaml")
+
+		if result := getDefaultComposeFile(); result != "mcp-compose.yaml" {
+			t.Errorf("Expected mcp-compose.yaml, got %s", result)
+		}
+	})
+
+	t.Run("mcp-compose.yml preferred", func(t *testing.T) {
+		os.WriteFile("compose.mcp.yml", []byte("services: {}"), 0644)
+		os.WriteFile("mcp-compose.yml", []byte("services: {}"), 0644)
+		defer os.Remove("compose.mcp.yml")
+		defer os.Remove("mcp-compose.yml")
+
+		if result := getDefaultComposeFile(); result != "mcp-compose.yml" {
+			t.Errorf("Expected mcp-compose.yml, got %s", result)
+		}
+	})
+
+	t.Run("global alternate name", func(t *testing.T) {
+		globalFile := filepath.Join(configDir, "compose.mcp.yml")
+		os.WriteFile(globalFile, []byte("services: {}"), 0644)
+		defer os.Remove(globalFile)
+
+		if result := getDefaultComposeFile(); result != globalFile {
+			t.Errorf("Expected %s, got %s", globalFile, result)
+		}
+	})
+
+	t.Run("configured name", func(t *testing.T) {
+		if err := saveCLIConfig(CLIConfig{ComposeFile: "team-mcp.yml"}); err != nil {
+			t.Fatalf("Failed to save CLI config: %v", err)
+		}
+		defer os.Remove(filepath.Join(configDir, "config.json"))
+
+		// With no files present the configured name is the global default
+		expected := filepath.Join(configDir, "team-mcp.yml")
+		if result := getDefaultComposeFile(); result != expected {
+			t.Errorf("Expected %s, got %s", expected, result)
+		}
+
+		os.WriteFile("mcp-compose.yml", []byte("services: {}"), 0644)
+		os.WriteFile("team-mcp.yml", []byte("services: {}"), 0644)
+		defer os.Remove("mcp-compose.yml")
+		defer os.Remove("team-mcp.yml")
+
+		if result := getDefaultComposeFile(); result != "team-mcp.yml" {
+			t.Errorf("Expected team-mcp.yml, got %s", result)
+		}
+	})
+}
+
+func TestHomeOverride(t *testing.T) {
+	sandbox := t.TempDir()
+
+	originalOverride := homeOverride
+	defer func() { homeOverride = originalOverride }()
+
+	t.Run("environment variable", func(t *testing.T) {
+		homeOverride = ""
+		t.Setenv(homeOverrideEnvVar, sandbox)
+		t.Setenv("XDG_CONFIG_HOME", "/outside/sandbox")
+
+		home, err := getHomeDir()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if home != sandbox {
+			t.Errorf("Expected home %s, got %s", sandbox, home)
+		}
+
+		configDir, _ := getConfigDir()
+		if configDir != filepath.Join(sandbox, ".config", "mcp") {
+			t.Errorf("Expected config dir inside sandbox, got %s", configDir)
+		}
+
+		// Environment-derived base directories must not escape the sandbox
+		path, _ := getPlatformToolPath("claude-desktop")
+		if !strings.HasPrefix(path, sandbox) {
+			t.Errorf("Expected claude-desktop path inside sandbox, got %s", path)
+		}
+	})
+
+	t.Run("flag takes precedence over environment", func(t *testing.T) {
+		flagHome := t.TempDir()
+		homeOverride = flagHome
+		t.Setenv(homeOverrideEnvVar, sandbox)
+
+		path, err := getPlatformToolPath("kiro")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := filepath.Join(flagHome, ".kiro", "settings", "mcp.json")
+		if path != expected {
+			t.Errorf("Expected %s, got %s", expected, path)
+		}
+	})
+}
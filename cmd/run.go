@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run <server>",
+	Short: "Run a server from the compose file with stdio attached",
+	Long: `Run a local server from the mcp-compose.yml file in the foreground, with stdin,
+stdout, and stderr attached, so it can be exercised by hand or spawned by tools
+as 'mcp run <server>'.
+The server is resolved exactly as 'mcp set' would write it: environment
+variables are expanded from the environment and the .env file, and image-based
+services are started with the configured container tool.
+Interrupt and termination signals are forwarded to the server, and mcp exits
+with the server's exit code. Remote servers can't be run, but image-based
+servers with an mcp.url label are, with their ports published.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server, err := resolveRunServer(cmd.Context(), os.Stderr, composeFile, args[0])
+		if err != nil {
+			return err
+		}
+		return execServer(cmd.Context(), server, os.Stdin, os.Stdout, os.Stderr)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+}
+
+// resolveRunServer resolves a compose service to the command that runs it,
+// warning on w about variables it references that aren't set
+func resolveRunServer(ctx context.Context, w io.Writer, composePath, name string) (MCPServer, error) {
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return MCPServer{}, newConfigError("load compose file", composePath, err)
+	}
+
+	service, exists := config.Services[name]
+	if !exists {
+		return MCPServer{}, newValidationError("server '%s' not found in %s", name, composePath)
+	}
+
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return MCPServer{}, newConfigError("load environment variables", composePath, err)
+	}
+
+	if IsRemoteServerWithEnvExpansion(service, envVars) {
+		return MCPServer{}, newValidationError("server '%s' is a remote server at %s; there is nothing to run locally", name, service.Command)
+	}
+
+	for _, usage := range missingEnvVarUsages(collectEnvVarUsages(map[string]Service{name: service}, envVars)) {
+		fmt.Fprintf(w, "Warning: %s is not set", usage.Name)
+		if usage.Doc != "" {
+			fmt.Fprintf(w, " (%s)", usage.Doc)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// A container serving HTTP is written as its URL, so run it as a local
+	// server instead, with its ports published for clients to connect to
+	if url := GetServiceURL(service, envVars); url != "" {
+		fmt.Fprintf(w, "Serving %s at %s\n", name, url)
+		service = copyService(service)
+		delete(service.Labels, "mcp.url")
+	}
+
+	resolved, err := convertToMCPConfig(ctx, map[string]Service{name: service}, envVars)
+	if err != nil {
+		return MCPServer{}, err
+	}
+	server := resolved.MCPServers[name]
+	if server.Command == "" {
+		return MCPServer{}, newValidationError("server '%s' has no command or image", name)
+	}
+	return server, nil
+}
+
+// serverCommand builds the command that starts a resolved local server, with
+// the server's environment added to the current one
+func serverCommand(ctx context.Context, server MCPServer) (*exec.Cmd, error) {
+	path, err := lookPath(server.Command)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found in PATH: %w", server.Command, err)
+	}
+
+	cmd := exec.CommandContext(ctx, path, server.Args...)
+	cmd.Env = os.Environ()
+	for _, key := range sortedKeys(server.Env) {
+		cmd.Env = append(cmd.Env, key+"="+server.Env[key])
+	}
+	return cmd, nil
+}
+
+// execServer runs a resolved server in the foreground and returns an
+// ExitError carrying its exit code when it fails
+func execServer(ctx context.Context, server MCPServer, stdin io.Reader, stdout, stderr io.Writer) error {
+	cmd, err := serverCommand(ctx, server)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// When stdin isn't a file (e.g. 'mcp record'), copying it would block
+	// Wait until the client closes it, even after the server has exited
+	cmd.WaitDelay = mcpCloseTimeout
+	// When ctx ends, ask the server to stop as Ctrl-C would, and only kill it
+	// if it hasn't exited after WaitDelay
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", server.Command, err)
+	}
+
+	// Forward signals so the server can shut down cleanly instead of
+	// being orphaned when mcp is stopped
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range signals {
+			cmd.Process.Signal(sig)
+		}
+	}()
+
+	err = cmd.Wait()
+	signal.Stop(signals)
+	close(signals)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			// Killed by a signal
+			code = exitCodeError
+		}
+		return &ExitError{Code: code}
+	}
+	if errors.Is(err, exec.ErrWaitDelay) {
+		return nil
+	}
+	return err
+}
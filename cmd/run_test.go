@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveRunServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("RUN_TEST_TOKEN", "secret")
+
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  github:
+    image: ghcr.io/github/github-mcp-server
+    environment:
+      GITHUB_PERSONAL_ACCESS_TOKEN: ${RUN_TEST_TOKEN}
+  notes:
+    command: npx -y notes-mcp --workspace ${RUN_TEST_WORKSPACE}
+    labels:
+      mcp.env-doc.RUN_TEST_WORKSPACE: Notes workspace ID
+  api:
+    command: https://api.example.com/mcp
+    labels:
+      mcp.header.Authorization: Bearer ${RUN_TEST_TOKEN}
+  sse:
+    image: mcp/sse-server
+    ports:
+      - 8080:8080
+    labels:
+      mcp.url: http://localhost:8080/sse
+`), 0644)
+
+	t.Run("image", func(t *testing.T) {
+		server, err := resolveRunServer(context.Background(), &bytes.Buffer{}, composePath, "github")
+		if err != nil {
+			t.Fatalf("resolveRunServer failed: %v", err)
+		}
+		expected := []string{"run", "-i", "--rm", "-e", "GITHUB_PERSONAL_ACCESS_TOKEN=secret", "ghcr.io/github/github-mcp-server"}
+		if server.Command != "docker" || !reflect.DeepEqual(server.Args, expected) {
+			t.Errorf("Unexpected server: %+v", server)
+		}
+	})
+
+	t.Run("container serving http", func(t *testing.T) {
+		var out bytes.Buffer
+		server, err := resolveRunServer(context.Background(), &out, composePath, "sse")
+		if err != nil {
+			t.Fatalf("resolveRunServer failed: %v", err)
+		}
+		expected := []string{"run", "-i", "--rm", "-p", "8080:8080", "mcp/sse-server"}
+		if server.Command != "docker" || !reflect.DeepEqual(server.Args, expected) {
+			t.Errorf("Unexpected server: %+v", server)
+		}
+		if !strings.Contains(out.String(), "Serving sse at http://localhost:8080/sse") {
+			t.Errorf("Expected the URL to be printed, got: %s", out.String())
+		}
+	})
+
+	t.Run("unset variable warns", func(t *testing.T) {
+		var warnings bytes.Buffer
+		server, err := resolveRunServer(context.Background(), &warnings, composePath, "notes")
+		if err != nil {
+			t.Fatalf("resolveRunServer failed: %v", err)
+		}
+		if server.Command != "npx" {
+			t.Errorf("Unexpected server: %+v", server)
+		}
+		if !strings.Contains(warnings.String(), "RUN_TEST_WORKSPACE is not set (Notes workspace ID)") {
+			t.Errorf("Expected a warning, got: %s", warnings.String())
+		}
+	})
+
+	for _, name := range []string{"api", "missing"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := resolveRunServer(context.Background(), &bytes.Buffer{}, composePath, name)
+			if ExitCode(err) != exitCodeValidation {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExecServer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	var out bytes.Buffer
+	server := MCPServer{Command: "sh", Args: []string{"-c", `read line; echo "$line $RUN_TEST_GREETING"`}, Env: map[string]string{"RUN_TEST_GREETING": "world"}}
+	if err := execServer(context.Background(), server, strings.NewReader("hello\n"), &out, &bytes.Buffer{}); err != nil {
+		t.Fatalf("execServer failed: %v", err)
+	}
+	if out.String() != "hello world\n" {
+		t.Errorf("Expected stdio to be attached, got %q", out.String())
+	}
+
+	err := execServer(context.Background(), MCPServer{Command: "sh", Args: []string{"-c", "exit 3"}}, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	if ExitCode(err) != 3 {
+		t.Errorf("Expected the server's exit code 3, got %v", err)
+	}
+
+	// Canceling the context, as Ctrl-C does, stops the server
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	execServer(ctx, MCPServer{Command: "sh", Args: []string{"-c", "exec sleep 30"}}, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the server to stop when the context ended, took %v", elapsed)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// searchLimit is the maximum number of results to show
+var searchLimit int
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the MCP server registry",
+	Long: `Search the official MCP server registry for servers whose name matches the query.
+Each result shows how the server is distributed (npm, pypi, docker, or remote),
+its description, and the environment variables it requires.
+Results are cached; see 'mcp cache'. Use 'mcp config set registry-url <url>'
+to search a different registry.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if searchLimit < 1 {
+			return newValidationError("--limit must be at least 1")
+		}
+
+		client, err := newRegistryClient()
+		if err != nil {
+			return err
+		}
+		servers, err := client.Search(cmd.Context(), args[0], searchLimit)
+		if err != nil {
+			return err
+		}
+
+		displayRegistryServers(os.Stdout, servers)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20, "Maximum number of results")
+}
+
+// displayRegistryServers prints registry search results as a table
+func displayRegistryServers(w io.Writer, servers []registryServer) {
+	if len(servers) == 0 {
+		fmt.Fprintln(w, "No servers found")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tREQUIRED ENV\tDESCRIPTION")
+	fmt.Fprintln(tw, "----\t----\t------------\t-----------")
+	for _, server := range servers {
+		env := strings.Join(server.requiredEnvVars(), ", ")
+		if env == "" {
+			env = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", server.Name, server.packageType(), env,
+			TruncateDescription(server.Description, 60))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// serveTokenEnv is the environment variable 'mcp serve' reads its bearer
+// token from when --token isn't set, keeping it out of process listings
+const serveTokenEnv = "MCP_SERVE_TOKEN"
+
+var (
+	serveHost    string
+	servePort    int
+	serveToken   string
+	serveTimeout time.Duration
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve <server>",
+	Short: "Expose a local stdio server over HTTP",
+	Long: `Start a local server from the compose file and expose it over HTTP, so
+clients that only support remote servers can use it. Both the streamable HTTP
+transport (at /mcp) and the older SSE transport (at /sse) are served.
+Every request is forwarded to the one running server.
+With --token (or the ` + serveTokenEnv + ` environment variable), clients must send
+it as a bearer token in the Authorization header. Without a token, the server
+listens only on localhost unless --host is set.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		servers, err := resolveServers(cmd.Context(), composeFile, config, []string{name})
+		if err != nil {
+			return err
+		}
+		if servers[name].URL != "" {
+			return newValidationError("server '%s' is already a remote server", name)
+		}
+
+		token := serveToken
+		if token == "" {
+			token = os.Getenv(serveTokenEnv)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		metricsServer, err := startMetricsServer(metricsAddr)
+		if err != nil {
+			return err
+		}
+		if metricsServer != nil {
+			defer metricsServer.Close()
+		}
+
+		backend, err := startBackend(ctx, name, servers[name], serveTimeout)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		defer backend.client.Close()
+
+		addr := net.JoinHostPort(serveHost, strconv.Itoa(servePort))
+		if token == "" && serveHost != "localhost" && serveHost != "127.0.0.1" {
+			fmt.Fprintf(os.Stderr, "Warning: serving %s on %s without a token; anyone who can reach it can use it\n", name, addr)
+		}
+		return serveMCPHTTP(ctx, os.Stdout, addr, proxyHandler(backend, serveTimeout), token)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveHost, "host", "localhost", "Address to listen on")
+	serveCmd.Flags().IntVar(&servePort, "port", 8080, "Port to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token clients must send (default: $"+serveTokenEnv+")")
+	serveCmd.Flags().DurationVar(&serveTimeout, "timeout", defaultMCPTimeout, "How long the server gets to start and to answer a request")
+	addMetricsFlag(serveCmd)
+}
+
+// proxyHandler forwards every request and notification to a backend
+// The backend's session is already initialized, so clients are answered
+// with what it advertised instead.
+func proxyHandler(backend *mcpBackend, timeout time.Duration) mcpHandler {
+	return func(ctx context.Context, method string, params json.RawMessage) (any, error) {
+		// A missing params is forwarded as missing rather than null
+		var forwarded any
+		if len(params) > 0 {
+			forwarded = params
+		}
+
+		switch {
+		case method == "initialize":
+			return backend.info, nil
+		case method == "notifications/initialized":
+			return nil, nil
+		case strings.HasPrefix(method, "notifications/"):
+			backend.mu.Lock()
+			defer backend.mu.Unlock()
+			return nil, backend.client.transport.Notify(ctx, jsonrpcRequest{JSONRPC: "2.0", Method: method, Params: forwarded})
+		}
+		return backend.call(ctx, timeout, method, forwarded)
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProxyHandler(t *testing.T) {
+	backend, err := startBackend(context.Background(), "time", fakeToolServer(t, "get_time"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("startBackend failed: %v", err)
+	}
+	defer backend.client.Close()
+
+	server := httptest.NewServer(mcpHTTPHandler(proxyHandler(backend, 5*time.Second)))
+	defer server.Close()
+
+	var tools []mcpTool
+	var result mcpToolResult
+	err = withMCPSession(context.Background(), MCPServer{URL: server.URL}, 5*time.Second, func(ctx context.Context, client *mcpClient, info mcpInitializeResult) error {
+		if info.ServerInfo.Name != "fake" {
+			t.Errorf("Expected the backend's server info, got %+v", info)
+		}
+		var err error
+		if tools, err = client.ListTools(ctx); err != nil {
+			return err
+		}
+		result, err = client.CallTool(ctx, "get_time", map[string]any{"timezone": "UTC"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Session through the proxy failed: %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name != "get_time" {
+		t.Errorf("Expected the backend's tools unchanged, got %+v", tools)
+	}
+	if len(result.Content) != 1 || result.Content[0].Text != "get_time map[timezone:UTC]" {
+		t.Errorf("Unexpected tool result: %+v", result)
+	}
+}
+
+func TestMCPSSEServer(t *testing.T) {
+	sse := newMCPSSEServer(func(ctx context.Context, method string, params json.RawMessage) (any, error) {
+		return map[string]string{"method": method}, nil
+	}, "/messages")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sse", sse.stream)
+	mux.HandleFunc("/messages", sse.message)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/sse")
+	if err != nil {
+		t.Fatalf("Failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+
+	// readEvent returns the data of the next event of the given type
+	readEvent := func(event string) string {
+		t.Helper()
+		var data string
+		for {
+			line, err := events.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Event stream ended: %v", err)
+			}
+			line = strings.TrimRight(line, "\n")
+			if line == "" && data != "" {
+				return data
+			}
+			if strings.HasPrefix(line, "event: ") && line != "event: "+event {
+				t.Fatalf("Expected a %s event, got %q", event, line)
+			}
+			if strings.HasPrefix(line, "data: ") {
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}
+
+	endpoint := readEvent("endpoint")
+	if !strings.HasPrefix(endpoint, "/messages?sessionId=") {
+		t.Fatalf("Unexpected endpoint: %q", endpoint)
+	}
+
+	post, err := http.Post(server.URL+endpoint, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":7,"method":"tools/list"}`))
+	if err != nil {
+		t.Fatalf("Failed to post message: %v", err)
+	}
+	post.Body.Close()
+	if post.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected HTTP 202, got %d", post.StatusCode)
+	}
+
+	if data := readEvent("message"); data != `{"jsonrpc":"2.0","id":7,"result":{"method":"tools/list"}}` {
+		t.Errorf("Unexpected response event: %s", data)
+	}
+
+	unknown, err := http.Post(server.URL+"/messages?sessionId=missing", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Failed to post message: %v", err)
+	}
+	unknown.Body.Close()
+	if unknown.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected HTTP 404 for an unknown session, got %d", unknown.StatusCode)
+	}
+}
+
+func TestRequireBearerToken(t *testing.T) {
+	server := httptest.NewServer(requireBearerToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	defer server.Close()
+
+	for token, want := range map[string]int{"": http.StatusUnauthorized, "wrong": http.StatusUnauthorized, "secret": http.StatusOK} {
+		req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("With token %q expected HTTP %d, got %d", token, want, resp.StatusCode)
+		}
+	}
+}
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 )
@@ -14,50 +22,172 @@ var (
 	configFile   string
 	toolShortcut string
 	singleServer string
+	setPrint     bool
+	setFrozen    bool
+	setMerge     bool
+	setTools     []string
+	setAllTools  bool
+	setExclude   []string
+	setInteract  bool
+	setExpandEnv bool
+	setServers   []string
+	setStrict    bool
+	setOfferSave bool
+	setPull      bool
+	setCheck     bool
+	setRestart   bool
 )
 
 // setCmd represents the set command
 var setCmd = &cobra.Command{
-	Use:   "set [profile]",
+	Use:   "set [profile...]",
 	Short: "Set MCP configuration",
 	Long: `Set MCP configuration by writing an MCP JSON file using servers from the specified profile.
-If no profile is specified, it uses default servers.`,
-	Run: func(cmd *cobra.Command, args []string) {
+If no profile is specified, it uses default servers. Several profiles, given as
+separate arguments or comma-separated (work,research), are combined; a server
+in more than one of them is included once.
+The config file is checked to be writable before anything else is done, so a
+read-only location (e.g. managed by your organization or a sandboxed app) fails
+before any OAuth tokens are acquired. Use --print (or --stdout, or -c -) to
+print the config to stdout instead, e.g. to pipe it into jq, or -c to write it
+to another path.
+If the compose file has a lockfile (see 'mcp lock'), servers are deployed at
+their locked versions, and servers added or changed since are locked first.
+With --frozen, a missing or out-of-date lockfile is an error instead.
+The config file is replaced with the selected servers. With --merge, servers
+already in it that aren't defined in the compose file, e.g. added by hand, are
+kept; make this the default with 'mcp config set merge true'.
+Repeat -t (or use --all-tools for every tool found on this machine) to write
+the same servers to several tools at once. Each tool is checked separately,
+tools that can't be written or don't support remote servers are skipped, and a
+table shows the result for each.
+Use -s (repeated or comma-separated) to write only the named servers of the
+profile, and --exclude to leave servers of the profile out, without editing the
+compose file. A server with an mcp.tools label (e.g. kiro,q-cli) is only
+written to the tools it names.
+${VARS} in a local server's environment and arguments are written with their
+values, unless its mcp.env-mode label is passthrough, which leaves them for the
+client to resolve at runtime. --expand-env=true or --expand-env=false applies
+one mode to every server instead. For a tool with the vscode format, variables
+marked with an mcp.input.<VAR> label are written as ${input:<id>} references
+that VS Code prompts for.
+Variables the servers reference that aren't set are prompted for when run in a
+terminal (hidden for names with KEY, TOKEN, or SECRET), and warned about
+otherwise; --offer-save offers to save the answers to .env, and --strict fails
+instead. Fields a tool doesn't support, such as headers, are left out of its
+config with a warning, or fail with --strict.
+A config that is already up to date isn't rewritten. After writing, a table
+lists each server as added, updated, removed, or unchanged, with what changed
+in its command, env, and headers. With --check, nothing is written, and the
+command exits 1 if the config would change.
+With --pull, the image of each container server is pulled first, so a tool's
+first launch of it isn't held up by the download; make this the default with
+'mcp config set pull true'.
+With --restart-client, each tool whose config changed is made to load it:
+Claude Desktop is quit and reopened on macOS, and for other tools the steps
+to take are printed.
+With -i, every server is listed with its profiles and description, the
+profile's servers checked, and only the servers you confirm are written.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := loadComposeFile(composeFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading compose file: %v\n", err)
-			os.Exit(1)
+			return newConfigError("load compose file", composeFile, err)
 		}
 
 		// Load environment variables
 		envVars, err := loadEnvVars(composeFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading environment variables: %v\n", err)
-			os.Exit(1)
+			return newConfigError("load environment variables", composeFile, err)
 		}
 
-		var profile string
-		if len(args) > 0 {
-			profile = args[0]
+		profile := strings.Join(args, ",")
+
+		if cmd.Flags().Changed("expand-env") {
+			envModeOverride = envModePassthrough
+			if setExpandEnv {
+				envModeOverride = envModeExpand
+			}
 		}
 
-		// Determine the output file path
-		outputPath, err := getOutputPath(envVars)
+		// -c - prints like --print
+		if configFile == "-" {
+			configFile = ""
+			setPrint = true
+		}
+
+		tools, err := selectTools(setTools, setAllTools)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error determining output path: %v\n", err)
-			os.Exit(1)
+			return err
+		}
+		multiple := len(tools) > 1 || setAllTools
+		toolShortcut = ""
+		if !multiple && len(tools) == 1 {
+			toolShortcut = tools[0]
+		}
+
+		// Determine the output file paths, failing early if they can't be written
+		var outputPath string
+		var targets []toolTarget
+		switch {
+		case multiple:
+			if err := checkMultipleTools(setPrint); err != nil {
+				return err
+			}
+			targets = resolveToolTargets(tools)
+		case !setPrint:
+			outputPath, err = getOutputPath(envVars)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Filter servers based on profile
-		servers := filterServers(config, profile, false)
+		servers := enabledServers(filterServers(config, profile, false))
 
-		// If single server is specified, filter to just that server
-		if singleServer != "" {
-			if service, exists := servers[singleServer]; exists {
-				servers = map[string]Service{singleServer: service}
-			} else {
-				fmt.Fprintf(os.Stderr, "Server '%s' not found\n", singleServer)
-				os.Exit(1)
+		// If servers are specified, filter to just those servers
+		if len(setServers) > 0 {
+			servers, err = selectNamedServers(servers, setServers)
+			if err != nil {
+				return err
+			}
+		}
+
+		servers, err = excludeServers(servers, config.Services, setExclude)
+		if err != nil {
+			return err
+		}
+
+		// Leave out servers whose mcp.tools label doesn't name the tool
+		if !multiple {
+			servers = serversForTool(servers, toolShortcut)
+		}
+
+		// With --print, stdout is for the config
+		promptOut := os.Stdout
+		if setPrint {
+			promptOut = os.Stderr
+		}
+		if setInteract {
+			if !isTerminal(os.Stdin) {
+				return newValidationError("-i needs a terminal to prompt in")
+			}
+			servers, err = selectServers(os.Stdin, promptOut, config.Services, servers)
+			if err != nil {
+				return err
+			}
+		}
+
+		// A required variable that isn't set fails with its message rather than
+		// being asked for
+		if err := checkServersRequiredEnvVars(servers, envVars); err != nil {
+			return err
+		}
+
+		// Ask for the variables that aren't set instead of writing ${VAR} as is
+		if missing := missingEnvVarUsages(writtenEnvVarUsages(servers, envVars)); len(missing) > 0 {
+			hide := func(hidden bool) { setTerminalEcho(os.Stdin, !hidden) }
+			if err := resolveMissingEnvVars(os.Stdin, promptOut, composeFile, missing, envVars, isTerminal(os.Stdin), hide); err != nil {
+				return err
 			}
 		}
 
@@ -65,100 +195,290 @@ If no profile is specified, it uses default servers.`,
 		for name, service := range servers {
 			if IsRemoteServerWithEnvExpansion(service, envVars) {
 				if err := ValidateRemoteServerAuth(name, service); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
+					return &ValidationError{Err: err}
 				}
 			}
 		}
 
 		// Validate tool compatibility with remote servers
 		if err := ValidateToolSupportWithEnvExpansion(toolShortcut, servers, envVars); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return &ValidationError{Err: err}
+		}
+		if multiple {
+			checkToolTargetSupport(targets, servers, envVars)
+			if writableTargets(targets) == 0 {
+				printToolTargets(os.Stdout, targets)
+				return newValidationError("none of the tools can be written")
+			}
+		}
+
+		// Pin servers to their locked versions; with --print, stdout is for the config
+		progress := os.Stdout
+		if setPrint {
+			progress = os.Stderr
+		}
+		servers, err = applyLockFile(cmd.Context(), progress, composeFile, servers, envVars, setFrozen)
+		if err != nil {
+			return err
+		}
+
+		pull := setPull
+		if !cmd.Flags().Changed("pull") {
+			pull = pullByDefault()
+		}
+		if pull {
+			if err := pullServerImages(cmd.Context(), progress, servers, envVars); err != nil {
+				return err
+			}
 		}
 
 		// Convert to MCP JSON format
-		mcpConfig := convertToMCPConfig(servers, envVars)
+		mcpConfig, err := convertToMCPConfig(cmd.Context(), servers, envVars)
+		if err != nil {
+			return err
+		}
+
+		if setPrint {
+			return printMCPConfig(os.Stdout, mcpConfig)
+		}
+
+		merge := setMerge
+		if !cmd.Flags().Changed("merge") {
+			merge = mergeByDefault()
+		}
+		if multiple {
+			if setStrict {
+				checkToolTargetFields(targets, mcpConfig)
+			}
+			err := writeToolTargets(os.Stdout, targets, setCheck, func(target toolTarget, existing MCPConfig) (MCPConfig, []string, string, error) {
+				return buildToolConfig(mcpConfig, target, existing, config.Services, envVars, merge)
+			})
+			if err != nil {
+				return err
+			}
+			printErr := printToolTargets(os.Stdout, targets)
+			if !setCheck {
+				printChangeSummary(os.Stdout, targetServerChanges(targets))
+			}
+			if setRestart {
+				if err := restartChangedClients(cmd.Context(), os.Stdout, targets); err != nil {
+					return err
+				}
+			}
+			if printErr != nil {
+				return printErr
+			}
+			if setCheck && changedTargets(targets) > 0 {
+				return &ExitError{Code: exitCodeDifferent}
+			}
+			return nil
+		}
+
+		prepared, err := prepareToolConfig(mcpConfig, toolShortcut, outputPath, config.Services, envVars, merge)
+		if err != nil {
+			return err
+		}
+		// Leave out what the tool doesn't understand, or fail with --strict
+		if len(prepared.Stripped) > 0 {
+			if setStrict {
+				return unsupportedFieldsError(toolShortcut, prepared.Stripped)
+			}
+			printStrippedFields(os.Stdout, toolShortcut, prepared.Stripped)
+		}
+		mcpConfig, kept, unmanaged := prepared.Config, prepared.Disabled, prepared.Unmanaged
+
+		// Leave a config that is already up to date alone
+		existing, err := readMCPConfig(outputPath)
+		if err != nil {
+			return newConfigError("load MCP config", outputPath, err)
+		}
+		changes := compareMCPConfigs(existing, mcpConfig)
+		upToDate, err := toolConfigUpToDate(mcpConfig, outputPath, toolShortcut)
+		if err != nil {
+			return newConfigError("load MCP config", outputPath, err)
+		}
+		switch {
+		case upToDate:
+			if !setCheck {
+				if err := recordManagedServers(outputPath, mcpConfig, unmanaged); err != nil {
+					return newConfigError("save state", "", err)
+				}
+			}
+			fmt.Printf("No changes to %s\n", outputPath)
+			return nil
+		case setCheck:
+			fmt.Printf("%s would change: %s\n", outputPath, changes)
+			printConfigChanges(os.Stdout, changes)
+			return &ExitError{Code: exitCodeDifferent}
+		}
+
+		if err := autoBackup(os.Stdout, outputPath); err != nil {
+			return err
+		}
 
 		// Write to file
-		if err := writeMCPConfig(mcpConfig, outputPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing MCP config: %v\n", err)
-			os.Exit(1)
+		if err := writeToolConfig(mcpConfig, outputPath, toolShortcut); err != nil {
+			return newConfigError("write MCP config", outputPath, err)
+		}
+		if err := recordManagedServers(outputPath, mcpConfig, unmanaged); err != nil {
+			return newConfigError("save state", "", err)
 		}
 
-		fmt.Printf("Wrote %s\n", outputPath)
+		fmt.Printf("Wrote %s: %s\n", outputPath, changes)
+		printChangeSummary(os.Stdout, summarizeServerChanges(toolShortcut, existing, mcpConfig))
+		printDisabledServers(os.Stdout, kept)
+		if len(unmanaged) > 0 {
+			fmt.Printf("Kept unmanaged: %s\n", strings.Join(unmanaged, ", "))
+		}
+		if setRestart && toolShortcut != "" {
+			return restartClient(cmd.Context(), os.Stdout, toolShortcut, runtime.GOOS)
+		}
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(setCmd)
-	setCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to write the MCP JSON configuration file")
-	setCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Tool shortcut (q-cli, claude-desktop, cursor, kiro)")
-	setCmd.Flags().StringVarP(&singleServer, "server", "s", "", "Specify a single server to include")
+	setCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to write the MCP JSON configuration file, or - for stdout")
+	setCmd.Flags().StringSliceVarP(&setTools, "tool", "t", nil, "Tool shortcut (q-cli, q-ide, claude-desktop, cursor, kiro); repeat to write several tools")
+	setCmd.Flags().BoolVar(&setAllTools, "all-tools", false, "Write every tool found on this machine")
+	setCmd.Flags().StringSliceVarP(&setServers, "server", "s", nil, "Include only this server; repeat to include several")
+	setCmd.Flags().StringSliceVar(&setExclude, "exclude", nil, "Leave a server out; repeat to exclude several")
+	setCmd.Flags().BoolVarP(&setInteract, "interactive", "i", false, "Choose the servers to write from a checklist")
+	setCmd.Flags().BoolVar(&setStrict, "strict", false, "Fail instead of prompting for unset variables or leaving out fields a tool doesn't support")
+	setCmd.Flags().BoolVar(&setOfferSave, "offer-save", false, "Offer to save the values typed for unset variables to .env")
+	setCmd.Flags().BoolVar(&setExpandEnv, "expand-env", true, "Write ${VARS} with their values (true) or leave them for the client (false), overriding mcp.env-mode labels")
+	setCmd.Flags().BoolVar(&setPrint, "print", false, "Print the MCP JSON configuration to stdout instead of writing it")
+	setCmd.Flags().BoolVar(&setPrint, "stdout", false, "Same as --print")
+	setCmd.Flags().BoolVar(&setCheck, "check", false, "Only report whether the config would change, exiting 1 if it would")
+	setCmd.Flags().BoolVar(&setFrozen, "frozen", false, "Fail if the lockfile is missing or out of date instead of updating it")
+	setCmd.Flags().BoolVar(&setPull, "pull", false, "Pull the images of container servers before writing the config (default from the pull setting)")
+	setCmd.Flags().BoolVar(&setRestart, "restart-client", false, "Make each tool whose config changed load it, restarting it where possible")
+	setCmd.Flags().BoolVar(&setMerge, "merge", false, "Keep servers in the config file that aren't in the compose file (default from the merge setting)")
+	addEnvOverrideFlag(setCmd)
+	setCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+	setCmd.RegisterFlagCompletionFunc("server", completeServerNames)
+	setCmd.RegisterFlagCompletionFunc("exclude", completeServerNames)
+	setCmd.MarkFlagsMutuallyExclusive("server", "exclude")
+	setCmd.MarkFlagsMutuallyExclusive("server", "interactive")
+	setCmd.MarkFlagsMutuallyExclusive("strict", "offer-save")
+	setCmd.MarkFlagsMutuallyExclusive("check", "print")
+	setCmd.MarkFlagsMutuallyExclusive("restart-client", "check")
+	setCmd.MarkFlagsMutuallyExclusive("restart-client", "print")
 }
 
+// resolveMissingEnvVars handles the variables set would write as ${VAR}
+// because they aren't set: with --strict it fails, when interactive it prompts
+// for them, adding the answers to envVars and, with --offer-save, offering to
+// save them to .env, and otherwise it warns about each one
+func resolveMissingEnvVars(in io.Reader, w io.Writer, composePath string, missing []envVarUsage, envVars map[string]string, interactive bool, hide func(bool)) error {
+	envPath := filepath.Join(filepath.Dir(composePath), ".env")
+	if setStrict {
+		var names []string
+		for _, usage := range missing {
+			names = append(names, fmt.Sprintf("%s (%s)", usage.Name, strings.Join(usage.Servers, ", ")))
+		}
+		return newValidationError("environment variables not set: %s; set them in your environment or in %s", strings.Join(names, ", "), envPath)
+	}
+	if !interactive {
+		for _, usage := range missing {
+			fmt.Fprintf(w, "Warning: %s is not set; writing ${%s} as is for %s\n", usage.Name, usage.Name, strings.Join(usage.Servers, ", "))
+		}
+		return nil
+	}
+
+	fmt.Fprintf(w, "%s not set (leave empty to write ${VAR} as is):\n", pluralize(len(missing), "variable"))
+	reader := bufio.NewReader(in)
+	values := promptMissingEnvVars(reader, w, missing, hide)
+	var names, lines []string
+	for _, usage := range missing {
+		if value, ok := values[usage.Name]; ok {
+			envVars[usage.Name] = value
+			names = append(names, usage.Name)
+			lines = append(lines, usage.Name+"="+quoteEnvValue(value))
+		}
+	}
+	if !setOfferSave || len(lines) == 0 {
+		return nil
+	}
+
+	answer := prompt(reader, w, fmt.Sprintf("Save %s to %s? [y/N] ", strings.Join(names, ", "), envPath))
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		return nil
+	}
+	if err := appendEnvFile(envPath, lines); err != nil {
+		return newConfigError("write env file", envPath, err)
+	}
+	fmt.Fprintf(w, "Saved %s to %s\n", strings.Join(names, ", "), envPath)
+	return nil
+}
+
+// notWritableHint explains what to do when a tool config can't be written
+const notWritableHint = "it may be managed by your organization or a sandboxed app; use -c <path> to write the config elsewhere, or 'mcp set --print' to print it"
+
+// getOutputPath resolves the MCP JSON file to write, checks that it is
+// writable, and creates its directory
 func getOutputPath(envVars map[string]string) (string, error) {
+	path, err := resolveOutputPath(envVars)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkConfigWritable(path); err != nil {
+		return "", newConfigError("write MCP config", path, fmt.Errorf("%s is not writable (%w); %s", path, err, notWritableHint))
+	}
+
+	// Create directory if it doesn't exist
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", newConfigError("create config directory", dir, err)
+	}
+
+	return path, nil
+}
+
+// resolveOutputPath resolves the MCP JSON file from --config, --tool, or the
+// default tool in the CLI config, without touching the filesystem
+func resolveOutputPath(envVars map[string]string) (string, error) {
 	if configFile != "" {
 		return expandEnvVars(configFile, envVars), nil
 	}
 
 	if toolShortcut != "" {
-		path := getPlatformToolPath(toolShortcut)
+		path, err := getScopedToolPath(toolShortcut, configScope)
+		if err != nil {
+			return "", newConfigError("resolve config path for "+toolShortcut, "", err)
+		}
 		if path == "" {
-			return "", fmt.Errorf("unknown tool shortcut: %s", toolShortcut)
+			return "", newValidationError("unknown tool shortcut: %s", toolShortcut)
 		}
-
-		// Create directory if it doesn't exist
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return "", err
+		if format := getToolFormat(toolShortcut); !isSupportedFormat(format) {
+			return "", newValidationError("tool '%s' uses unsupported format '%s' (supported: %s)",
+				toolShortcut, format, strings.Join(supportedFormats, ", "))
 		}
-
 		return path, nil
 	}
 
 	// Check if there's a default tool configured in the config file
-	configDir := getConfigDir()
-	configPath := filepath.Join(configDir, "config.json")
-
-	if _, err := os.Stat(configPath); err == nil {
-		data, err := os.ReadFile(configPath)
-		if err == nil {
-			var config CLIConfig
-			if err := json.Unmarshal(data, &config); err == nil && config.Tool != "" {
-				// Create directory if it doesn't exist
-				dir := filepath.Dir(config.Tool)
-				if err := os.MkdirAll(dir, 0755); err != nil {
-					return "", err
-				}
-				return config.Tool, nil
-			}
-		}
+	if config, err := loadCLIConfig(); err == nil && config.Tool != "" {
+		return config.Tool, nil
 	}
 
-	return "", fmt.Errorf("either --config or --tool must be specified, or set a default tool with 'mcp config set tool <path>'")
+	return "", newValidationError("either --config or --tool must be specified, or set a default tool with 'mcp config set tool <path>'")
 }
 
-func convertToMCPConfig(servers map[string]Service, envVars map[string]string) MCPConfig {
+func convertToMCPConfig(ctx context.Context, servers map[string]Service, envVars map[string]string) (MCPConfig, error) {
 	mcpServers := make(map[string]MCPServer)
 
 	// Get the container tool from config, default to "docker"
-	containerTool := "docker"
-	configDir := getConfigDir()
-	configPath := filepath.Join(configDir, "config.json")
-
-	if _, err := os.Stat(configPath); err == nil {
-		data, err := os.ReadFile(configPath)
-		if err == nil {
-			var config CLIConfig
-			if err := json.Unmarshal(data, &config); err == nil && config.ContainerTool != "" {
-				containerTool = config.ContainerTool
-			}
-		}
-	}
+	containerTool := getContainerTool()
 
+	if err := checkServersRequiredEnvVars(servers, envVars); err != nil {
+		return MCPConfig{}, err
+	}
 	for name, service := range servers {
 		var mcpServer MCPServer
+		expand := serviceEnvExpander(service, envVars)
 
 		if IsRemoteServerWithEnvExpansion(service, envVars) {
 			// Remote server - use HTTP-based configuration
@@ -180,8 +500,7 @@ func convertToMCPConfig(servers map[string]Service, envVars map[string]string) M
 				// Headers-based authentication
 				headers, err := ExtractHeaders(service, serviceEnvVars)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error extracting headers for '%s': %v\n", name, err)
-					os.Exit(1)
+					return MCPConfig{}, newValidationError("error extracting headers for '%s': %w", name, err)
 				}
 				// Always set headers, even if empty (for servers with no auth)
 				if headers == nil {
@@ -192,14 +511,12 @@ func convertToMCPConfig(servers map[string]Service, envVars map[string]string) M
 				// OAuth-based authentication
 				oauthConfig, err := ExtractOAuthConfig(service, serviceEnvVars)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error extracting OAuth config for '%s': %v\n", name, err)
-					os.Exit(1)
+					return MCPConfig{}, newValidationError("error extracting OAuth config for '%s': %w", name, err)
 				}
 
-				accessToken, err := AcquireAccessTokenWithFeedback(name, oauthConfig)
+				accessToken, err := AcquireAccessTokenWithFeedback(ctx, name, oauthConfig)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Failed to acquire access token for '%s': %v\n", name, err)
-					os.Exit(1)
+					return MCPConfig{}, &AuthError{Server: name, Err: err}
 				}
 
 				// Set Authorization header with Bearer token
@@ -207,26 +524,18 @@ func convertToMCPConfig(servers map[string]Service, envVars map[string]string) M
 					"Authorization": fmt.Sprintf("Bearer %s", accessToken),
 				}
 			}
+		} else if url := GetServiceURL(service, envVars); url != "" {
+			// Container serving HTTP on its mapped ports; the client connects
+			// to it, so it must be started separately, e.g. with 'mcp run'
+			mcpServer.Type = "http"
+			mcpServer.URL = url
 		} else if service.Image != "" {
 			// Container-based server
 			mcpServer.Command = containerTool
-			args := []string{"run", "-i", "--rm"}
-
-			// Add environment variables with expanded values
-			for key, value := range service.Environment {
-				expandedValue := expandEnvVars(value, envVars)
-				args = append(args, "-e", fmt.Sprintf("%s=%s", key, expandedValue))
+			args, err := containerRunArgs(service, expand)
+			if err != nil {
+				return MCPConfig{}, newValidationError("server '%s': %w", name, err)
 			}
-
-			// Add volume mounts with expanded values
-			for _, volume := range service.Volumes {
-				expandedVolume := expandEnvVars(volume, envVars)
-				args = append(args, "-v", expandedVolume)
-			}
-
-			// Expand image name if it contains env vars
-			expandedImage := expandEnvVars(service.Image, envVars)
-			args = append(args, expandedImage)
 			mcpServer.Args = args
 		} else {
 			// Command-based server
@@ -237,7 +546,7 @@ func convertToMCPConfig(servers map[string]Service, envVars map[string]string) M
 					// Expand environment variables in args
 					expandedArgs := make([]string, 0, len(parts)-1)
 					for _, arg := range parts[1:] {
-						expandedArgs = append(expandedArgs, expandEnvVars(arg, envVars))
+						expandedArgs = append(expandedArgs, expand(arg))
 					}
 					mcpServer.Args = expandedArgs
 				}
@@ -245,26 +554,521 @@ func convertToMCPConfig(servers map[string]Service, envVars map[string]string) M
 		}
 
 		// Add environment variables with expanded values (only for local servers)
-		if !IsRemoteServerWithEnvExpansion(service, envVars) && len(service.Environment) > 0 {
+		if mcpServer.URL == "" && len(service.Environment) > 0 {
 			expandedEnv := make(map[string]string)
 			for key, value := range service.Environment {
 				// Expand environment variables in the output JSON
-				expandedEnv[key] = expandEnvVars(value, envVars)
+				expandedEnv[key] = expand(value)
 			}
 			mcpServer.Env = expandedEnv
 		}
 
+		// Left out later for tools that don't support them
+		mcpServer.AutoApprove = GetAutoApprove(service)
+		mcpServer.RequestTimeout = GetTimeout(service, timeoutLabel)
+		mcpServer.InitTimeout = GetTimeout(service, initTimeoutLabel)
+
 		mcpServers[name] = mcpServer
 	}
 
-	return MCPConfig{MCPServers: mcpServers}
+	return MCPConfig{MCPServers: mcpServers}, nil
 }
 
-func writeMCPConfig(config MCPConfig, path string) error {
+// containerRunArgs returns the container tool arguments that run an
+// image-based service: its entrypoint, working directory, user, extra
+// arguments, environment, volumes, and ports, then its image and the rest of
+// its entrypoint
+func containerRunArgs(service Service, expand func(string) string) ([]string, error) {
+	extra, err := GetDockerArgs(service)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"run", "-i", "--rm"}
+	if len(service.Entrypoint) > 0 {
+		args = append(args, "--entrypoint", expand(service.Entrypoint[0]))
+	}
+	if service.WorkingDir != "" {
+		args = append(args, "-w", expand(service.WorkingDir))
+	}
+	if service.User != "" {
+		args = append(args, "-u", expand(service.User))
+	}
+	for _, arg := range extra {
+		args = append(args, expand(arg))
+	}
+
+	// Add environment variables with expanded values
+	for _, key := range sortedKeys(service.Environment) {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", key, expand(service.Environment[key])))
+	}
+
+	// Add volume mounts and published ports with expanded values
+	for _, volume := range service.Volumes {
+		args = append(args, "-v", expand(volume))
+	}
+	for _, port := range service.Ports {
+		args = append(args, "-p", expand(port))
+	}
+
+	// Expand image name if it contains env vars
+	args = append(args, expand(service.Image))
+	for _, arg := range entrypointArgs(service) {
+		args = append(args, expand(arg))
+	}
+	return args, nil
+}
+
+// entrypointArgs returns the rest of a service's entrypoint after the
+// executable, which is passed after the image
+func entrypointArgs(service Service) []string {
+	if len(service.Entrypoint) < 2 {
+		return nil
+	}
+	return service.Entrypoint[1:]
+}
+
+// serviceEnvExpander returns how ${VARS} in a local service's environment and
+// arguments are written: expanded from envVars, or left as they are for the
+// client to resolve when the service's env mode is passthrough
+func serviceEnvExpander(service Service, envVars map[string]string) func(string) string {
+	if GetEnvMode(service) == envModePassthrough {
+		return func(value string) string { return value }
+	}
+	return func(value string) string { return expandEnvVars(value, envVars) }
+}
+
+// checkConfigWritable reports whether a config file can be written without
+// modifying it: an existing file must open for writing, otherwise its nearest
+// existing directory must allow creating files
+func checkConfigWritable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return checkWritable(existingParent(filepath.Dir(path)))
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// selectNamedServers returns the named servers, which must be among servers;
+// a name that isn't is reported with the closest names and those available
+func selectNamedServers(servers map[string]Service, names []string) (map[string]Service, error) {
+	result := make(map[string]Service, len(names))
+	for _, name := range names {
+		service, exists := servers[name]
+		if !exists {
+			available := make([]string, 0, len(servers))
+			for candidate := range servers {
+				available = append(available, candidate)
+			}
+			sort.Strings(available)
+			return nil, serverNotFoundError(name, available)
+		}
+		result[name] = service
+	}
+	return result, nil
+}
+
+// serverNotFoundError reports an unknown server name, suggesting the
+// available names that are a likely typo of it
+func serverNotFoundError(name string, available []string) error {
+	var similar []string
+	for _, candidate := range available {
+		if editDistance(strings.ToLower(name), strings.ToLower(candidate)) <= 2 ||
+			strings.Contains(strings.ToLower(candidate), strings.ToLower(name)) {
+			similar = append(similar, candidate)
+		}
+	}
+	message := fmt.Sprintf("server '%s' not found", name)
+	if len(similar) > 0 {
+		message += fmt.Sprintf("; did you mean %s?", strings.Join(similar, " or "))
+	}
+	if len(available) > 0 {
+		message += fmt.Sprintf(" (available: %s)", strings.Join(available, ", "))
+	}
+	return newValidationError("%s", message)
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr := make([]int, len(br)+1)
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(br)]
+}
+
+// excludeServers returns servers without the excluded ones, which must be
+// defined in the compose file so typos aren't silently ignored
+func excludeServers(servers, defined map[string]Service, excluded []string) (map[string]Service, error) {
+	if len(excluded) == 0 {
+		return servers, nil
+	}
+	result := make(map[string]Service, len(servers))
+	for name, service := range servers {
+		result[name] = service
+	}
+	for _, name := range excluded {
+		if _, ok := defined[name]; !ok {
+			return nil, newValidationError("excluded server '%s' not found", name)
+		}
+		delete(result, name)
+	}
+	return result, nil
+}
+
+// selectServers shows a checklist of every server with the selected ones
+// checked, and returns the servers checked when the user confirms
+// Numbers toggle servers, a and n check all and none, and an empty line (or
+// the end of input) confirms.
+func selectServers(in io.Reader, w io.Writer, all, selected map[string]Service) (map[string]Service, error) {
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checked := make(map[string]bool, len(selected))
+	for name := range selected {
+		checked[name] = true
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		printServerChecklist(w, names, all, checked)
+		answer := prompt(reader, w, "Toggle servers by number, a for all, n for none, Enter to confirm: ")
+		if answer == "" {
+			break
+		}
+		for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ' ' || r == ',' }) {
+			switch field {
+			case "a", "n":
+				for _, name := range names {
+					checked[name] = field == "a"
+				}
+				continue
+			}
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(names) {
+				fmt.Fprintf(w, "Ignored %q: not a server number\n", field)
+				continue
+			}
+			checked[names[n-1]] = !checked[names[n-1]]
+		}
+	}
+
+	result := make(map[string]Service)
+	for _, name := range names {
+		if checked[name] {
+			result[name] = all[name]
+		}
+	}
+	if len(result) == 0 {
+		return nil, newValidationError("no servers selected")
+	}
+	return result, nil
+}
+
+// printServerChecklist prints the numbered servers with a box showing whether each is checked
+func printServerChecklist(w io.Writer, names []string, services map[string]Service, checked map[string]bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, name := range names {
+		box := "[ ]"
+		if checked[name] {
+			box = "[x]"
+		}
+		profiles := strings.Join(GetProfiles(services[name]), ",")
+		if profiles == "" {
+			profiles = "default"
+		}
+		fmt.Fprintf(tw, "%s %d\t%s\t%s\t%s\n", box, i+1, name, profiles, GetDescription(services[name]))
+	}
+	tw.Flush()
+}
+
+// preparedToolConfig is the config 'mcp set' writes to a tool, with what was
+// left out of it or kept in it on the way
+type preparedToolConfig struct {
+	Config    MCPConfig
+	Stripped  []strippedField // fields the tool doesn't support
+	Disabled  []string        // servers kept off with 'mcp disable'
+	Unmanaged []string        // servers mcp didn't write, kept when merging
+}
+
+// prepareToolConfig turns converted servers into the config 'mcp set' writes
+// to the tool config at path: servers the tool is left out of and fields it
+// doesn't support are dropped, disabled servers kept off, unmanaged servers
+// kept when merging, and inputs added if the tool prompts for them
+func prepareToolConfig(mcpConfig MCPConfig, tool, path string, services map[string]Service, envVars map[string]string, merge bool) (preparedToolConfig, error) {
+	mcpConfig = configForTool(mcpConfig, services, tool)
+	mcpConfig, stripped := stripUnsupportedFields(mcpConfig, tool)
+	toolConfig, kept, err := applyDisabledServers(mcpConfig, path, tool)
+	if err != nil {
+		return preparedToolConfig{}, err
+	}
+	var unmanaged []string
+	if merge {
+		toolConfig, unmanaged, err = mergeUnmanagedServers(toolConfig, path, services)
+		if err != nil {
+			return preparedToolConfig{}, err
+		}
+	}
+	return preparedToolConfig{
+		Config:    applyToolInputs(toolConfig, tool, services, envVars),
+		Stripped:  stripped,
+		Disabled:  kept,
+		Unmanaged: unmanaged,
+	}, nil
+}
+
+// buildToolConfig returns the config 'mcp set' writes to one of several
+// tools, the unmanaged servers it keeps, and a summary of the changes to its
+// existing config
+func buildToolConfig(mcpConfig MCPConfig, target toolTarget, existing MCPConfig, services map[string]Service, envVars map[string]string, merge bool) (MCPConfig, []string, string, error) {
+	prepared, err := prepareToolConfig(mcpConfig, target.Tool, target.Path, services, envVars, merge)
+	if err != nil {
+		return MCPConfig{}, nil, "", err
+	}
+
+	changes := compareMCPConfigs(existing, prepared.Config)
+	result := changes.String()
+	if len(prepared.Disabled) > 0 {
+		result += fmt.Sprintf(", %d kept disabled", len(prepared.Disabled))
+	}
+	if len(prepared.Unmanaged) > 0 {
+		result += fmt.Sprintf(", %d unmanaged kept", len(prepared.Unmanaged))
+	}
+	for _, field := range prepared.Stripped {
+		result += fmt.Sprintf(", left out %s", field)
+	}
+	return prepared.Config, prepared.Unmanaged, result, nil
+}
+
+// mergeByDefault reports whether 'mcp set' merges without --merge, per the
+// merge setting of the CLI config
+func mergeByDefault() bool {
+	config, err := loadCLIConfig()
+	return err == nil && config.Merge
+}
+
+// mergeUnmanagedServers adds the servers of the existing config at path that
+// mcp didn't write, returning their sorted names. Without a record of what it
+// wrote (see recordManagedServers), servers not defined in the compose file
+// are taken to be unmanaged.
+// Servers mcp wrote that aren't selected any more are still removed.
+func mergeUnmanagedServers(config MCPConfig, path string, services map[string]Service) (MCPConfig, []string, error) {
+	existing, err := readMCPConfig(path)
+	if err != nil {
+		return config, nil, newConfigError("load MCP config", path, err)
+	}
+
+	owned := managedServerFunc(path, services)
+	result := MCPConfig{MCPServers: make(map[string]MCPServer, len(config.MCPServers))}
+	for name, server := range config.MCPServers {
+		result.MCPServers[name] = server
+	}
+	var unmanaged []string
+	for name, server := range existing.MCPServers {
+		if _, written := config.MCPServers[name]; written || owned(name) {
+			continue
+		}
+		result.MCPServers[name] = server
+		unmanaged = append(unmanaged, name)
+	}
+	sort.Strings(unmanaged)
+	return result, unmanaged, nil
+}
+
+// printConfigChanges lists the servers added (+), updated (~), and removed (-)
+func printConfigChanges(w io.Writer, changes syncChanges) {
+	printSyncChanges(w, "+", changes.Added)
+	printSyncChanges(w, "~", changes.Updated)
+	printSyncChanges(w, "-", changes.Removed)
+}
+
+// printMCPConfig writes an MCP config as indented JSON
+func printMCPConfig(w io.Writer, config MCPConfig) error {
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return err
 	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// strippedField is a field of a server entry left out for a tool that
+// doesn't support it
+type strippedField struct {
+	Server string
+	Field  string // a capability, e.g. headers
+}
+
+func (f strippedField) String() string {
+	return fmt.Sprintf("%s (server '%s')", f.Field, f.Server)
+}
+
+// stripUnsupportedFields removes the fields of each server entry that a tool
+// doesn't support, returning the config and the fields removed, by server
+// Remote servers themselves are checked by ValidateToolSupport. Auto-approved
+// tools are moved to alwaysAllow for tools that call the field that, and the
+// mcp.timeout label is written in the tool's unit.
+func stripUnsupportedFields(config MCPConfig, tool string) (MCPConfig, []strippedField) {
+	if tool == "" {
+		return config, nil
+	}
+	result := MCPConfig{MCPServers: make(map[string]MCPServer, len(config.MCPServers)), Inputs: config.Inputs}
+	var stripped []strippedField
+	for name, server := range config.MCPServers {
+		if len(server.Headers) > 0 && !toolSupports(tool, capabilityHeaders) {
+			server.Headers = nil
+			stripped = append(stripped, strippedField{Server: name, Field: capabilityHeaders})
+		}
+		if len(server.Env) > 0 && !toolSupports(tool, capabilityEnv) {
+			server.Env = nil
+			stripped = append(stripped, strippedField{Server: name, Field: capabilityEnv})
+		}
+		if server.Disabled && !toolSupports(tool, capabilityDisabled) {
+			server.Disabled = false
+			stripped = append(stripped, strippedField{Server: name, Field: capabilityDisabled})
+		}
+		if len(server.AutoApprove) > 0 {
+			switch {
+			case !toolSupports(tool, capabilityAutoApprove):
+				server.AutoApprove = nil
+				stripped = append(stripped, strippedField{Server: name, Field: capabilityAutoApprove})
+			case toolAutoApproveField(tool) == autoApproveFieldAlwaysAllow:
+				server.AlwaysAllow, server.AutoApprove = server.AutoApprove, nil
+			}
+		}
+		if server.RequestTimeout > 0 {
+			if unit := toolTimeoutUnit(tool); unit > 0 {
+				server.Timeout = int(server.RequestTimeout / unit)
+			} else {
+				stripped = append(stripped, strippedField{Server: name, Field: capabilityTimeout})
+			}
+		}
+		result.MCPServers[name] = server
+	}
+	sort.Slice(stripped, func(i, j int) bool {
+		if stripped[i].Server != stripped[j].Server {
+			return stripped[i].Server < stripped[j].Server
+		}
+		return stripped[i].Field < stripped[j].Field
+	})
+	return result, stripped
+}
+
+// printStrippedFields warns about the fields left out for a tool
+func printStrippedFields(w io.Writer, tool string, stripped []strippedField) {
+	for _, field := range stripped {
+		fmt.Fprintf(w, "Warning: %s doesn't support %s; left it out\n", tool, field)
+	}
+}
+
+// unsupportedFieldsError reports the fields a tool doesn't support, for --strict
+func unsupportedFieldsError(tool string, stripped []strippedField) error {
+	fields := make([]string, 0, len(stripped))
+	for _, field := range stripped {
+		fields = append(fields, field.String())
+	}
+	return newValidationError("tool '%s' doesn't support %s", tool, strings.Join(fields, ", "))
+}
+
+// applyToolInputs moves the values of mcp.input.<VAR> variables to inputs
+// the tool prompts for, if its config format supports them
+func applyToolInputs(config MCPConfig, tool string, servers map[string]Service, envVars map[string]string) MCPConfig {
+	if adapter := toolAdapter(tool); tool == "" || adapter == nil || !adapter.SupportsInputs() {
+		return config
+	}
+	return applyConfigInputs(config, servers, envVars)
+}
+
+// writeToolConfig writes the servers of config to a tool's config file in the
+// layout of the tool's adapter, or as MCP JSON if tool is empty
+func writeToolConfig(config MCPConfig, path, tool string) error {
+	return writeConfigFile(path, toolConfigAdapter(tool), config)
+}
+
+// toolConfigAdapter returns the adapter of a tool's config file, or the
+// standard one if tool is empty
+func toolConfigAdapter(tool string) ToolAdapter {
+	if tool != "" && toolAdapter(tool) != nil {
+		return toolAdapter(tool)
+	}
+	return configFormats["standard"]
+}
+
+// toolConfigUpToDate reports whether writeToolConfig would leave a tool's
+// config file exactly as it is
+func toolConfigUpToDate(config MCPConfig, path, tool string) (bool, error) {
+	current, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	data, err := renderConfigFile(path, toolConfigAdapter(tool), config)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(current, data), nil
+}
+
+// writeFileAtomic replaces a file by writing a temporary file next to it and
+// renaming it into place, so a crash mid-write never leaves a tool with a
+// truncated config. An existing file keeps its permissions, and a symlink
+// (e.g. from a dotfile manager) stays a symlink to the replaced file.
+func writeFileAtomic(path string, data []byte) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if os.IsPermission(err) {
+		// A writable file in a read-only directory can still be written in place
+		return os.WriteFile(path, data, mode)
+	}
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
 
-	return os.WriteFile(path, data, 0644)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestWriteMCPConfig(t *testing.T) {
@@ -29,7 +34,7 @@ func TestWriteMCPConfig(t *testing.T) {
 	testPath := filepath.Join(tempDir, "test-config.json")
 
 	t.Run("successful write", func(t *testing.T) {
-		err := writeMCPConfig(config, testPath)
+		err := writeToolConfig(config, testPath, "")
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
@@ -64,9 +69,30 @@ func TestWriteMCPConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("keeps other settings", func(t *testing.T) {
+		path := filepath.Join(tempDir, "claude_desktop_config.json")
+		os.WriteFile(path, []byte(`{"globalShortcut":"Ctrl+Space","mcpServers":{"old":{"command":"old"}},"preferences":{"theme":"dark"}}`), 0644)
+		if err := writeToolConfig(config, path, ""); err != nil {
+			t.Fatalf("writeToolConfig failed: %v", err)
+		}
+
+		var written map[string]any
+		data, _ := os.ReadFile(path)
+		if err := json.Unmarshal(data, &written); err != nil {
+			t.Fatalf("Failed to parse config file: %v", err)
+		}
+		if written["globalShortcut"] != "Ctrl+Space" || !reflect.DeepEqual(written["preferences"], map[string]any{"theme": "dark"}) {
+			t.Errorf("Expected the other settings to be kept, got %s", data)
+		}
+		servers := written["mcpServers"].(map[string]any)
+		if _, ok := servers["old"]; ok || servers["test-server"] == nil {
+			t.Errorf("Expected the servers to be replaced, got %s", data)
+		}
+	})
+
 	t.Run("write to invalid path", func(t *testing.T) {
 		invalidPath := filepath.Join("/invalid/path/that/does/not/exist", "config.json")
-		err := writeMCPConfig(config, invalidPath)
+		err := writeToolConfig(config, invalidPath, "")
 		if err == nil {
 			t.Error("Expected error for invalid path")
 		}
@@ -181,7 +207,10 @@ func TestConvertToMCPConfig(t *testing.T) {
 			},
 		}
 
-		result := convertToMCPConfig(servers, envVars)
+		result, err := convertToMCPConfig(context.Background(), servers, envVars)
+		if err != nil {
+			t.Fatalf("convertToMCPConfig failed: %v", err)
+		}
 
 		if len(result.MCPServers) != 1 {
 			t.Errorf("Expected 1 server, got %d", len(result.MCPServers))
@@ -221,7 +250,10 @@ func TestConvertToMCPConfig(t *testing.T) {
 			},
 		}
 
-		result := convertToMCPConfig(servers, envVars)
+		result, err := convertToMCPConfig(context.Background(), servers, envVars)
+		if err != nil {
+			t.Fatalf("convertToMCPConfig failed: %v", err)
+		}
 
 		server, exists := result.MCPServers["container-server"]
 		if !exists {
@@ -257,7 +289,10 @@ func TestConvertToMCPConfig(t *testing.T) {
 			},
 		}
 
-		result := convertToMCPConfig(servers, envVars)
+		result, err := convertToMCPConfig(context.Background(), servers, envVars)
+		if err != nil {
+			t.Fatalf("convertToMCPConfig failed: %v", err)
+		}
 
 		server, exists := result.MCPServers["remote-server"]
 		if !exists {
@@ -280,7 +315,10 @@ func TestConvertToMCPConfig(t *testing.T) {
 	t.Run("empty servers", func(t *testing.T) {
 		servers := map[string]Service{}
 
-		result := convertToMCPConfig(servers, envVars)
+		result, err := convertToMCPConfig(context.Background(), servers, envVars)
+		if err != nil {
+			t.Fatalf("convertToMCPConfig failed: %v", err)
+		}
 
 		if len(result.MCPServers) != 0 {
 			t.Errorf("Expected 0 servers, got %d", len(result.MCPServers))
@@ -441,7 +479,10 @@ func TestConvertToMCPConfigAdvanced(t *testing.T) {
 			},
 		}
 
-		result := convertToMCPConfig(servers, envVars)
+		result, err := convertToMCPConfig(context.Background(), servers, envVars)
+		if err != nil {
+			t.Fatalf("convertToMCPConfig failed: %v", err)
+		}
 
 		server, exists := result.MCPServers["podman-server"]
 		if !exists {
@@ -483,7 +524,10 @@ func TestConvertToMCPConfigAdvanced(t *testing.T) {
 			},
 		}
 
-		result := convertToMCPConfig(servers, envVars)
+		result, err := convertToMCPConfig(context.Background(), servers, envVars)
+		if err != nil {
+			t.Fatalf("convertToMCPConfig failed: %v", err)
+		}
 
 		if len(result.MCPServers) != 3 {
 			t.Errorf("Expected 3 servers, got %d", len(result.MCPServers))
@@ -519,7 +563,10 @@ func TestConvertToMCPConfigAdvanced(t *testing.T) {
 			},
 		}
 
-		result := convertToMCPConfig(servers, envVars)
+		result, err := convertToMCPConfig(context.Background(), servers, envVars)
+		if err != nil {
+			t.Fatalf("convertToMCPConfig failed: %v", err)
+		}
 
 		server := result.MCPServers["complex-server"]
 		if server.Command != "python" {
@@ -542,3 +589,625 @@ func TestConvertToMCPConfigAdvanced(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckConfigWritable(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "mcp.json")
+	os.WriteFile(file, []byte("{}"), 0644)
+
+	if err := checkConfigWritable(file); err != nil {
+		t.Errorf("Expected existing file to be writable: %v", err)
+	}
+	if err := checkConfigWritable(filepath.Join(dir, "settings", "nested", "mcp.json")); err != nil {
+		t.Errorf("Expected new file in a writable directory to be writable: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "settings")); !os.IsNotExist(err) {
+		t.Error("Checking should not create directories")
+	}
+	if err := checkConfigWritable(dir); err == nil {
+		t.Error("Expected an error for a directory")
+	}
+	if err := checkConfigWritable(filepath.Join(file, "mcp.json")); err == nil {
+		t.Error("Expected an error below a regular file")
+	}
+
+	t.Run("read-only file", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root can write read-only files")
+		}
+		readOnly := filepath.Join(dir, "managed.json")
+		os.WriteFile(readOnly, []byte("{}"), 0444)
+
+		originalConfigFile := configFile
+		defer func() { configFile = originalConfigFile }()
+		configFile = readOnly
+
+		_, err := getOutputPath(map[string]string{})
+		if ExitCode(err) != exitCodeConfig || !strings.Contains(err.Error(), "--print") {
+			t.Errorf("Expected a config error suggesting --print, got %v", err)
+		}
+	})
+}
+
+func TestPrintMCPConfig(t *testing.T) {
+	var out bytes.Buffer
+	config := MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx", Args: []string{"mcp-server-time"}}}}
+	if err := printMCPConfig(&out, config); err != nil {
+		t.Fatalf("printMCPConfig failed: %v", err)
+	}
+
+	var printed MCPConfig
+	if err := json.Unmarshal(out.Bytes(), &printed); err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %v", out.String(), err)
+	}
+	if !reflect.DeepEqual(printed, config) {
+		t.Errorf("Expected %+v, got %+v", config, printed)
+	}
+}
+
+func TestMergeUnmanagedServers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	existing := MCPConfig{MCPServers: map[string]MCPServer{
+		"time":   {Command: "uvx", Args: []string{"mcp-server-time==0.5.0"}},
+		"github": {Command: "npx", Args: []string{"@modelcontextprotocol/server-github"}},
+		"manual": {Command: "node", Args: []string{"/opt/manual/index.js"}},
+	}}
+	if err := writeToolConfig(existing, path, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// github is managed but not selected, so it is removed
+	services := map[string]Service{"time": {Command: "uvx mcp-server-time"}, "github": {Command: "npx @modelcontextprotocol/server-github"}}
+	selected := MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx", Args: []string{"mcp-server-time"}}}}
+	merged, unmanaged, err := mergeUnmanagedServers(selected, path, services)
+	if err != nil {
+		t.Fatalf("mergeUnmanagedServers failed: %v", err)
+	}
+	want := MCPConfig{MCPServers: map[string]MCPServer{
+		"time":   selected.MCPServers["time"],
+		"manual": existing.MCPServers["manual"],
+	}}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("Expected %+v, got %+v", want, merged)
+	}
+	if !reflect.DeepEqual(unmanaged, []string{"manual"}) {
+		t.Errorf("Expected manual to be kept, got %v", unmanaged)
+	}
+
+	t.Run("missing config", func(t *testing.T) {
+		merged, unmanaged, err := mergeUnmanagedServers(selected, filepath.Join(t.TempDir(), "mcp.json"), services)
+		if err != nil || len(unmanaged) != 0 || !reflect.DeepEqual(merged, selected) {
+			t.Errorf("Expected the selected servers only, got %+v, %v, %v", merged, unmanaged, err)
+		}
+	})
+
+	t.Run("recorded servers", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		// mcp wrote time and an old server; github was added by hand
+		if err := recordManagedServers(path, MCPConfig{MCPServers: map[string]MCPServer{"time": {}, "old": {}}}, nil); err != nil {
+			t.Fatal(err)
+		}
+		existing.MCPServers["old"] = MCPServer{Command: "old"}
+		if err := writeToolConfig(existing, path, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		merged, unmanaged, err := mergeUnmanagedServers(selected, path, services)
+		if err != nil {
+			t.Fatalf("mergeUnmanagedServers failed: %v", err)
+		}
+		if !reflect.DeepEqual(unmanaged, []string{"github", "manual"}) {
+			t.Errorf("Expected the hand-added servers to be kept, got %v", unmanaged)
+		}
+		if _, ok := merged.MCPServers["old"]; ok {
+			t.Errorf("Expected the server mcp wrote to be removed, got %+v", merged)
+		}
+
+		if err := recordManagedServers(path, merged, unmanaged); err != nil {
+			t.Fatal(err)
+		}
+		if managed, known := managedServerNames(path); !known || !reflect.DeepEqual(managed, map[string]bool{"time": true}) {
+			t.Errorf("Expected only time to be recorded, got %v (known %v)", managed, known)
+		}
+		cleared, kept := clearedConfig(merged, path)
+		if !reflect.DeepEqual(kept, []string{"github", "manual"}) || len(cleared.MCPServers) != 2 {
+			t.Errorf("Expected clear to keep the hand-added servers, got %+v", cleared)
+		}
+	})
+}
+
+func TestExcludeServers(t *testing.T) {
+	defined := map[string]Service{"time": {}, "github": {}, "slack": {}, "other": {}}
+	servers := map[string]Service{"time": {}, "github": {}, "slack": {}}
+
+	result, err := excludeServers(servers, defined, []string{"github", "other"})
+	if err != nil {
+		t.Fatalf("excludeServers failed: %v", err)
+	}
+	if expected := (map[string]Service{"time": {}, "slack": {}}); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	if len(servers) != 3 {
+		t.Errorf("Expected the input to be left alone, got %v", servers)
+	}
+
+	if _, err := excludeServers(servers, defined, []string{"gihtub"}); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error for an unknown server, got %v", err)
+	}
+}
+
+func TestSelectServers(t *testing.T) {
+	all := map[string]Service{
+		"brave":  {Image: "mcp/brave-search", Labels: map[string]string{"mcp.profile": "research", "mcp.description": "Web search"}},
+		"github": {Command: "npx -y @modelcontextprotocol/server-github", Labels: map[string]string{"mcp.profile": "programming"}},
+		"time":   {Command: "uvx mcp-server-time"},
+	}
+	selected := map[string]Service{"github": all["github"], "time": all["time"]}
+
+	var out bytes.Buffer
+	result, err := selectServers(strings.NewReader("1 3 x 9\n\n"), &out, all, selected)
+	if err != nil {
+		t.Fatalf("selectServers failed: %v", err)
+	}
+	if expected := (map[string]Service{"brave": all["brave"], "github": all["github"]}); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected brave and github, got %v", result)
+	}
+	for _, want := range []string{
+		"[ ] 1  brave   research     Web search\n",
+		"[x] 2  github  programming  \n",
+		"[x] 3  time    default      \n",
+		"[x] 1  brave",
+		"[ ] 3  time",
+		`Ignored "x": not a server number`,
+		`Ignored "9": not a server number`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	t.Run("none selected", func(t *testing.T) {
+		_, err := selectServers(strings.NewReader("n\n"), &bytes.Buffer{}, all, selected)
+		if ExitCode(err) != exitCodeValidation {
+			t.Errorf("Expected a validation error, got %v", err)
+		}
+	})
+
+	t.Run("all at end of input", func(t *testing.T) {
+		result, err := selectServers(strings.NewReader("a"), &bytes.Buffer{}, all, selected)
+		if err != nil || len(result) != 3 {
+			t.Errorf("Expected every server, got %v, %v", result, err)
+		}
+	})
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mcp.json")
+	os.WriteFile(path, []byte("old"), 0600)
+
+	if err := writeFileAtomic(path, []byte("new")); err != nil {
+		t.Fatalf("writeFileAtomic failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Errorf("Expected the file to be replaced, got %q", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the file to keep mode 0600, got %v", info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected no temporary files to be left, got %v", entries)
+	}
+
+	t.Run("symlink", func(t *testing.T) {
+		link := filepath.Join(dir, "link.json")
+		if err := os.Symlink(path, link); err != nil {
+			t.Skip("symlinks not supported")
+		}
+		if err := writeFileAtomic(link, []byte("linked")); err != nil {
+			t.Fatalf("writeFileAtomic failed: %v", err)
+		}
+		if info, _ := os.Lstat(link); info.Mode()&os.ModeSymlink == 0 {
+			t.Error("Expected the symlink to be kept")
+		}
+		if data, _ := os.ReadFile(path); string(data) != "linked" {
+			t.Errorf("Expected the symlink's target to be written, got %q", data)
+		}
+	})
+}
+
+func TestConvertToMCPConfigEnvMode(t *testing.T) {
+	envVars := map[string]string{"API_KEY": "secret", "DATA": "/data"}
+	servers := map[string]Service{
+		"expanded": {
+			Command:     "npx server --key ${API_KEY}",
+			Environment: map[string]string{"API_KEY": "${API_KEY}"},
+		},
+		"passthrough": {
+			Command:     "npx server --key ${API_KEY}",
+			Environment: map[string]string{"API_KEY": "${API_KEY}"},
+			Labels:      map[string]string{"mcp.env-mode": "passthrough"},
+		},
+		"image": {
+			Image:       "mcp/server",
+			Environment: map[string]string{"API_KEY": "${API_KEY}"},
+			Volumes:     []string{"${DATA}:/data"},
+			Labels:      map[string]string{"mcp.env-mode": "passthrough"},
+		},
+	}
+
+	config, err := convertToMCPConfig(context.Background(), servers, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+	if server := config.MCPServers["expanded"]; server.Args[2] != "secret" || server.Env["API_KEY"] != "secret" {
+		t.Errorf("Expected the values to be expanded, got %+v", server)
+	}
+	if server := config.MCPServers["passthrough"]; server.Args[2] != "${API_KEY}" || server.Env["API_KEY"] != "${API_KEY}" {
+		t.Errorf("Expected the references to be passed through, got %+v", server)
+	}
+	if args := config.MCPServers["image"].Args; !reflect.DeepEqual(args[3:], []string{"-e", "API_KEY=${API_KEY}", "-v", "${DATA}:/data", "mcp/server"}) {
+		t.Errorf("Expected the container references to be passed through, got %v", args)
+	}
+
+	t.Run("override", func(t *testing.T) {
+		envModeOverride = envModeExpand
+		defer func() { envModeOverride = "" }()
+		config, err := convertToMCPConfig(context.Background(), servers, envVars)
+		if err != nil {
+			t.Fatalf("convertToMCPConfig failed: %v", err)
+		}
+		if server := config.MCPServers["passthrough"]; server.Env["API_KEY"] != "secret" {
+			t.Errorf("Expected --expand-env to override the label, got %+v", server)
+		}
+	})
+}
+
+func TestSelectNamedServers(t *testing.T) {
+	servers := map[string]Service{"github": {}, "fetch": {}, "time": {}}
+
+	result, err := selectNamedServers(servers, []string{"github", "fetch"})
+	if err != nil {
+		t.Fatalf("selectNamedServers failed: %v", err)
+	}
+	if expected := (map[string]Service{"github": {}, "fetch": {}}); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	_, err = selectNamedServers(servers, []string{"github", "gihtub"})
+	if ExitCode(err) != exitCodeValidation {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	if want := "server 'gihtub' not found; did you mean github? (available: fetch, github, time)"; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+
+	_, err = selectNamedServers(servers, []string{"slack"})
+	if err == nil || strings.Contains(err.Error(), "did you mean") || !strings.Contains(err.Error(), "available: fetch, github, time") {
+		t.Errorf("Expected only the available servers, got %v", err)
+	}
+}
+
+func TestResolveMissingEnvVars(t *testing.T) {
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	servers := map[string]Service{
+		"github": {
+			Command:     "npx -y @modelcontextprotocol/server-github",
+			Environment: map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "${GITHUB_TOKEN}", "GITHUB_HOST": "${GITHUB_HOST}"},
+			Labels:      map[string]string{"mcp.env-doc.GITHUB_TOKEN": "GitHub PAT"},
+		},
+		"passthrough": {
+			Command:     "npx -y example-mcp",
+			Environment: map[string]string{"EXAMPLE_KEY": "${EXAMPLE_KEY}"},
+			Labels:      map[string]string{"mcp.env-mode": "passthrough"},
+		},
+	}
+	missing := missingEnvVarUsages(writtenEnvVarUsages(servers, map[string]string{}))
+	if len(missing) != 2 || missing[0].Name != "GITHUB_HOST" || missing[1].Name != "GITHUB_TOKEN" {
+		t.Fatalf("Expected GITHUB_HOST and GITHUB_TOKEN to be missing, got %+v", missing)
+	}
+
+	originalStrict, originalOfferSave := setStrict, setOfferSave
+	t.Cleanup(func() { setStrict, setOfferSave = originalStrict, originalOfferSave })
+
+	t.Run("prompts and saves", func(t *testing.T) {
+		setStrict, setOfferSave = false, true
+		var hidden []bool
+		envVars := map[string]string{}
+		var out bytes.Buffer
+		err := resolveMissingEnvVars(strings.NewReader("\nghp_secret\ny\n"), &out, composePath, missing, envVars, true, func(hide bool) { hidden = append(hidden, hide) })
+		if err != nil {
+			t.Fatalf("resolveMissingEnvVars failed: %v", err)
+		}
+		if _, ok := envVars["GITHUB_HOST"]; ok || envVars["GITHUB_TOKEN"] != "ghp_secret" {
+			t.Errorf("Expected only GITHUB_TOKEN to be set, got %v", envVars)
+		}
+		if !reflect.DeepEqual(hidden, []bool{true, false}) {
+			t.Errorf("Expected input hidden only for GITHUB_TOKEN, got %v", hidden)
+		}
+		if !strings.Contains(out.String(), "GITHUB_TOKEN (GitHub PAT) for github: ") {
+			t.Errorf("Expected the prompt to show the variable's docs, got:\n%s", out.String())
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, ".env"))
+		if string(data) != "GITHUB_TOKEN=ghp_secret\n" {
+			t.Errorf("Expected the value saved to .env, got %q", data)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		setStrict, setOfferSave = true, false
+		err := resolveMissingEnvVars(strings.NewReader(""), io.Discard, composePath, missing, map[string]string{}, true, nil)
+		if err == nil || !strings.Contains(err.Error(), "GITHUB_TOKEN (github)") || ExitCode(err) != exitCodeValidation {
+			t.Errorf("Expected a validation error naming GITHUB_TOKEN, got %v", err)
+		}
+	})
+
+	t.Run("not interactive", func(t *testing.T) {
+		setStrict, setOfferSave = false, false
+		var out bytes.Buffer
+		if err := resolveMissingEnvVars(strings.NewReader(""), &out, composePath, missing, map[string]string{}, false, nil); err != nil {
+			t.Fatalf("resolveMissingEnvVars failed: %v", err)
+		}
+		if !strings.Contains(out.String(), "Warning: GITHUB_TOKEN is not set; writing ${GITHUB_TOKEN} as is for github") {
+			t.Errorf("Expected a warning, got:\n%s", out.String())
+		}
+	})
+}
+
+func TestStripUnsupportedFields(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	os.MkdirAll(filepath.Join(os.Getenv("HOME"), ".config", "mcp"), 0755)
+	saveCLIConfig(CLIConfig{Tools: map[string]CustomTool{"local-only": {Path: "/tmp/mcp.json"}}})
+
+	config := MCPConfig{MCPServers: map[string]MCPServer{
+		"api":  {Type: "http", URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "Bearer x"}},
+		"time": {Command: "uvx", Args: []string{"mcp-server-time"}, Env: map[string]string{"TZ": "UTC"}, Disabled: true},
+	}}
+
+	if result, stripped := stripUnsupportedFields(config, "kiro"); len(stripped) != 0 || !reflect.DeepEqual(result, config) {
+		t.Errorf("Expected kiro to support every field, got %v", stripped)
+	}
+
+	result, stripped := stripUnsupportedFields(config, "cursor")
+	if expected := []strippedField{{Server: "time", Field: capabilityDisabled}}; !reflect.DeepEqual(stripped, expected) {
+		t.Errorf("Expected %v, got %v", expected, stripped)
+	}
+	if result.MCPServers["time"].Disabled || result.MCPServers["time"].Env["TZ"] != "UTC" || !config.MCPServers["time"].Disabled {
+		t.Errorf("Expected only the disabled flag stripped from a copy, got %+v", result.MCPServers["time"])
+	}
+
+	_, stripped = stripUnsupportedFields(config, "local-only")
+	err := unsupportedFieldsError("local-only", stripped)
+	if ExitCode(err) != exitCodeValidation || err.Error() != "tool 'local-only' doesn't support headers (server 'api'), disabled (server 'time')" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, stripped := stripUnsupportedFields(config, ""); stripped != nil {
+		t.Errorf("Expected nothing stripped without a tool, got %v", stripped)
+	}
+}
+
+func TestAutoApprove(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	os.MkdirAll(filepath.Join(os.Getenv("HOME"), ".config", "mcp"), 0755)
+	saveCLIConfig(CLIConfig{Tools: map[string]CustomTool{
+		"cline": {Path: "/tmp/cline.json", SupportsAutoApprove: true, AutoApproveField: "alwaysAllow"},
+	}})
+
+	services := map[string]Service{
+		"time":  {Command: "uvx mcp-server-time", Labels: map[string]string{"mcp.auto-approve": "get_current_time, convert_time"}},
+		"fetch": {Command: "uvx mcp-server-fetch", Labels: map[string]string{"mcp.auto-approve": "fetch,*"}},
+	}
+	config, err := convertToMCPConfig(context.Background(), services, map[string]string{})
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+	if got := config.MCPServers["time"].AutoApprove; !reflect.DeepEqual(got, []string{"get_current_time", "convert_time"}) {
+		t.Errorf("Unexpected autoApprove: %v", got)
+	}
+	if got := config.MCPServers["fetch"].AutoApprove; !reflect.DeepEqual(got, []string{"*"}) {
+		t.Errorf("Expected * to approve every tool, got %v", got)
+	}
+
+	kiro, stripped := stripUnsupportedFields(config, "kiro")
+	if len(stripped) != 0 || len(kiro.MCPServers["time"].AutoApprove) != 2 {
+		t.Errorf("Expected kiro to keep autoApprove, got %+v, %v", kiro.MCPServers["time"], stripped)
+	}
+
+	cline, _ := stripUnsupportedFields(config, "cline")
+	if server := cline.MCPServers["time"]; server.AutoApprove != nil || len(server.AlwaysAllow) != 2 {
+		t.Errorf("Expected alwaysAllow for cline, got %+v", server)
+	}
+
+	cursor, stripped := stripUnsupportedFields(config, "cursor")
+	if cursor.MCPServers["time"].AutoApprove != nil || len(stripped) != 2 || stripped[0].Field != capabilityAutoApprove {
+		t.Errorf("Expected autoApprove left out for cursor, got %+v, %v", cursor.MCPServers["time"], stripped)
+	}
+}
+
+func TestTimeoutLabels(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	os.MkdirAll(filepath.Join(os.Getenv("HOME"), ".config", "mcp"), 0755)
+	saveCLIConfig(CLIConfig{Tools: map[string]CustomTool{"cline": {Path: "/tmp/cline.json", TimeoutUnit: "s"}}})
+
+	for value, expected := range map[string]time.Duration{"90": 90 * time.Second, "2m": 2 * time.Minute, " 1500ms ": 1500 * time.Millisecond} {
+		if got, err := parseTimeoutLabel(value); err != nil || got != expected {
+			t.Errorf("parseTimeoutLabel(%q) = %v, %v; expected %v", value, got, err, expected)
+		}
+	}
+	for _, value := range []string{"", "soon", "-5s", "0"} {
+		if _, err := parseTimeoutLabel(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+
+	services := map[string]Service{"time": {Command: "uvx mcp-server-time", Labels: map[string]string{"mcp.timeout": "2m", "mcp.init-timeout": "10s"}}}
+	config, err := convertToMCPConfig(context.Background(), services, map[string]string{})
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+	if server := config.MCPServers["time"]; server.RequestTimeout != 2*time.Minute || server.InitTimeout != 10*time.Second || server.Timeout != 0 {
+		t.Errorf("Unexpected timeouts: %+v", server)
+	}
+
+	for tool, expected := range map[string]int{"q-cli": 120000, "cline": 120} {
+		result, stripped := stripUnsupportedFields(config, tool)
+		if got := result.MCPServers["time"].Timeout; got != expected || len(stripped) != 0 {
+			t.Errorf("Expected a timeout of %d for %s, got %d, %v", expected, tool, got, stripped)
+		}
+	}
+	if _, stripped := stripUnsupportedFields(config, "cursor"); len(stripped) != 1 || stripped[0].Field != capabilityTimeout {
+		t.Errorf("Expected the timeout left out for cursor, got %v", stripped)
+	}
+}
+
+func TestContainerPortsAndURL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	envVars := map[string]string{"PORT": "8080"}
+
+	services := map[string]Service{
+		"stdio": {Image: "mcp/time", Ports: []string{"${PORT}:8080"}, Environment: map[string]string{"TZ": "UTC"}},
+		"sse": {
+			Image:       "mcp/sse-server",
+			Ports:       []string{"${PORT}:8080"},
+			Environment: map[string]string{"TZ": "UTC"},
+			Labels:      map[string]string{"mcp.url": "http://localhost:${PORT}/sse"},
+		},
+	}
+	config, err := convertToMCPConfig(context.Background(), services, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+
+	expected := []string{"run", "-i", "--rm", "-e", "TZ=UTC", "-p", "8080:8080", "mcp/time"}
+	if args := config.MCPServers["stdio"].Args; !reflect.DeepEqual(args, expected) {
+		t.Errorf("Expected %v, got %v", expected, args)
+	}
+	if server := config.MCPServers["sse"]; server.Type != "http" || server.URL != "http://localhost:8080/sse" || server.Command != "" || server.Env != nil {
+		t.Errorf("Expected the container's URL, got %+v", server)
+	}
+
+	if err := ValidateToolSupportWithEnvExpansion("claude-desktop", map[string]Service{"sse": services["sse"]}, envVars); err == nil {
+		t.Error("Expected a tool without remote support to be rejected")
+	}
+	if err := ValidateURLLabel("time", Service{Command: "uvx mcp-server-time", Labels: map[string]string{"mcp.url": "http://localhost:8080"}}, envVars); err == nil {
+		t.Error("Expected mcp.url to be rejected for a command-based server")
+	}
+	if err := ValidateURLLabel("sse", Service{Image: "mcp/sse-server", Labels: map[string]string{"mcp.url": "localhost:8080"}}, envVars); err == nil {
+		t.Error("Expected mcp.url to be rejected without a scheme")
+	}
+}
+
+func TestContainerEntrypointWorkingDirUser(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(path, []byte(`services:
+  shell:
+    image: mcp/hardened
+    entrypoint: /usr/local/bin/mcp-server --stdio
+    working_dir: ${WORKDIR}
+    user: "1000:1000"
+  listed:
+    image: mcp/hardened
+    entrypoint: ["/bin/sh", "-c", "exec mcp-server"]
+`), 0644)
+
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+	mcpConfig, err := convertToMCPConfig(context.Background(), config.Services, map[string]string{"WORKDIR": "/data"})
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+
+	expected := []string{"run", "-i", "--rm", "--entrypoint", "/usr/local/bin/mcp-server", "-w", "/data", "-u", "1000:1000", "mcp/hardened", "--stdio"}
+	if args := mcpConfig.MCPServers["shell"].Args; !reflect.DeepEqual(args, expected) {
+		t.Errorf("Expected %v, got %v", expected, args)
+	}
+	expected = []string{"run", "-i", "--rm", "--entrypoint", "/bin/sh", "mcp/hardened", "-c", "exec mcp-server"}
+	if args := mcpConfig.MCPServers["listed"].Args; !reflect.DeepEqual(args, expected) {
+		t.Errorf("Expected %v, got %v", expected, args)
+	}
+
+	// Importing the written command gives the service back
+	service, ok := parseContainerRun(mcpConfig.MCPServers["listed"].Args, nil)
+	if !ok || !reflect.DeepEqual(service.Entrypoint, config.Services["listed"].Entrypoint) {
+		t.Errorf("Expected the entrypoint to be imported, got %+v, %v", service, ok)
+	}
+}
+
+func TestDockerArgsLabel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for input, expected := range map[string][]string{
+		"--network host --add-host foo:1.2.3.4":   {"--network", "host", "--add-host", "foo:1.2.3.4"},
+		`--label "team=platform tools" -e 'A=$B'`: {"--label", "team=platform tools", "-e", "A=$B"},
+		`--name my\ server ""`:                    {"--name", "my server", ""},
+	} {
+		if got, err := shellFields(input); err != nil || !reflect.DeepEqual(got, expected) {
+			t.Errorf("shellFields(%q) = %q, %v; expected %q", input, got, err, expected)
+		}
+	}
+	if _, err := shellFields(`--label "unterminated`); err == nil {
+		t.Error("Expected an unterminated quote to be rejected")
+	}
+
+	path := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(path, []byte(`services:
+  label:
+    image: mcp/fetch
+    labels:
+      mcp.docker-args: --network host --add-host ${HOST_ALIAS}
+  block:
+    image: mcp/fetch
+    x-mcp:
+      docker-args: [--label, team=platform tools]
+`), 0644)
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+	mcpConfig, err := convertToMCPConfig(context.Background(), config.Services, map[string]string{"HOST_ALIAS": "foo:1.2.3.4"})
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+	expected := []string{"run", "-i", "--rm", "--network", "host", "--add-host", "foo:1.2.3.4", "mcp/fetch"}
+	if args := mcpConfig.MCPServers["label"].Args; !reflect.DeepEqual(args, expected) {
+		t.Errorf("Expected %v, got %v", expected, args)
+	}
+	expected = []string{"run", "-i", "--rm", "--label", "team=platform tools", "mcp/fetch"}
+	if args := mcpConfig.MCPServers["block"].Args; !reflect.DeepEqual(args, expected) {
+		t.Errorf("Expected %v, got %v", expected, args)
+	}
+
+	invalid := map[string]Service{"bad": {Image: "mcp/fetch", Labels: map[string]string{"mcp.docker-args": `--label "oops`}}}
+	if _, err := convertToMCPConfig(context.Background(), invalid, nil); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestClearVSCodeFormat(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalTools, originalAll := clearTools, clearAllTools
+	originalConfig, originalTool, originalCompose := configFile, toolShortcut, composeFile
+	defer func() {
+		clearTools, clearAllTools = originalTools, originalAll
+		configFile, toolShortcut, composeFile = originalConfig, originalTool, originalCompose
+	}()
+
+	path := setupVSCodeTool(t, map[string]MCPServer{
+		"time":   {Command: "uvx"},
+		"github": {Command: "npx"},
+	})
+	composeFile = filepath.Join(t.TempDir(), "mcp-compose.yml")
+	clearTools, clearAllTools, configFile = []string{"vscode"}, false, ""
+
+	if err := clearCmd.RunE(clearCmd, nil); err != nil {
+		t.Fatalf("clear failed: %v", err)
+	}
+	if servers := readVSCodeServers(t, path); len(servers) != 0 {
+		t.Errorf("Expected the servers to be cleared, got %v", servers)
+	}
+}
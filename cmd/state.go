@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// cliState is what the CLI remembers between runs, kept apart from the
+// user's settings in config.json
+type cliState struct {
+	// Disabled maps a tool config path to the servers disabled in it, with
+	// the entry each had when disabled so it can be restored. The entry is
+	// empty for a server of the compose file, which is converted again when
+	// enabled so its secrets aren't kept here.
+	Disabled map[string]map[string]MCPServer `json:"disabled,omitempty"`
+
+	// Managed maps a tool config path to the servers the CLI wrote to it, so
+	// servers added by hand can be told apart and left alone
+	Managed map[string][]string `json:"managed,omitempty"`
+}
+
+// getStatePath returns the path to the MCP CLI state file
+func getStatePath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "state.json"), nil
+}
+
+// loadState reads the MCP CLI state file
+// Returns an empty state if the file doesn't exist
+func loadState() (cliState, error) {
+	var state cliState
+
+	statePath, err := getStatePath()
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, fmt.Errorf("error reading state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("error parsing state file %s: %w", statePath, err)
+	}
+
+	return state, nil
+}
+
+// saveState writes the MCP CLI state file, creating its directory
+func saveState(state cliState) error {
+	statePath, err := getStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Entries of servers added by hand may hold secrets, so like the token
+	// cache the file is only readable by the user, even if written before
+	if err := os.WriteFile(statePath, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(statePath, 0600)
+}
+
+// stateKey identifies a tool config in the state file by its absolute path
+func stateKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// managedServerNames returns the servers the CLI wrote to the config at path,
+// and whether it knows: there is no record for a config it hasn't written
+// since it began keeping one
+func managedServerNames(path string) (map[string]bool, bool) {
+	state, err := loadState()
+	if err != nil {
+		return nil, false
+	}
+	names, ok := state.Managed[stateKey(path)]
+	if !ok {
+		return nil, false
+	}
+	managed := make(map[string]bool, len(names))
+	for _, name := range names {
+		managed[name] = true
+	}
+	return managed, true
+}
+
+// managedServerFunc returns a function reporting whether the CLI wrote a
+// server to the config at path. Without a record of what it wrote (see
+// recordManagedServers), servers defined in the compose file are taken to be
+// its own.
+func managedServerFunc(path string, services map[string]Service) func(string) bool {
+	if managed, known := managedServerNames(path); known {
+		return func(name string) bool { return managed[name] }
+	}
+	return func(name string) bool {
+		_, defined := services[name]
+		return defined
+	}
+}
+
+// recordManagedServers records the servers of config as written by the CLI
+// to the config at path, except the unmanaged ones it kept as they were
+func recordManagedServers(path string, config MCPConfig, unmanaged []string) error {
+	names := []string{}
+	for name := range config.MCPServers {
+		if !containsString(unmanaged, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	state, err := loadState()
+	if err != nil {
+		return err
+	}
+	if state.Managed == nil {
+		state.Managed = make(map[string][]string)
+	}
+	state.Managed[stateKey(path)] = names
+	return saveState(state)
+}
+
+// unmanagedServers returns the sorted servers of the config at path that the
+// CLI didn't write, or none if it has no record for the config
+func unmanagedServers(config MCPConfig, path string) []string {
+	managed, known := managedServerNames(path)
+	if !known {
+		return nil
+	}
+	var names []string
+	for name := range config.MCPServers {
+		if !managed[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
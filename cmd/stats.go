@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var statsJSON bool
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the servers in the compose file",
+	Long: `Report how many servers the compose file defines by type (local, container,
+or remote) and by profile, how many of them each deployed tool config has
+unchanged, different, or missing, and how many environment variables and
+secrets they reference, for an overview of large shared compose files.
+Servers without a profile count as "default"; a server with several profiles
+counts once for each. Use --json for machine-readable output.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+
+		var targets []string
+		for _, status := range getToolStatuses(getStatusTargets(getAllTools())) {
+			if status.Exists {
+				targets = append(targets, status.ToolName)
+			}
+		}
+
+		report := buildStatsReport(config.Services, targets, envVars)
+		if statsJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		printStatsReport(os.Stdout, report)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print the summary as JSON")
+}
+
+// statsReport is the summary printed by the stats command
+type statsReport struct {
+	Servers          int              `json:"servers"`
+	ByType           map[string]int   `json:"byType"`
+	ByProfile        map[string]int   `json:"byProfile"`
+	Tools            []toolStatsCount `json:"tools"`
+	Variables        int              `json:"variables"`
+	UnsetVariables   int              `json:"unsetVariables"`
+	Secrets          int              `json:"secrets"`
+	HardcodedSecrets int              `json:"hardcodedSecrets"`
+}
+
+// toolStatsCount counts the servers of one tool config by deployment status
+type toolStatsCount struct {
+	Tool          string `json:"tool"`
+	Configured    int    `json:"configured"`
+	Different     int    `json:"different"`
+	NotConfigured int    `json:"notConfigured"`
+	Extra         int    `json:"extra"`
+}
+
+// buildStatsReport counts the servers and checks them against each target's config
+func buildStatsReport(servers map[string]Service, targets []string, envVars map[string]string) statsReport {
+	report := statsReport{
+		Servers:   len(servers),
+		ByType:    map[string]int{"local": 0, "container": 0, "remote": 0},
+		ByProfile: make(map[string]int),
+		Tools:     []toolStatsCount{},
+	}
+
+	for _, service := range servers {
+		report.ByType[serverType(service)]++
+		for _, profile := range docsProfiles(service) {
+			report.ByProfile[profile]++
+		}
+	}
+
+	toolConfigs := getToolConfigs(targets)
+	for _, target := range targets {
+		count := toolStatsCount{Tool: target}
+		single := map[string]ToolConfig{target: toolConfigs[target]}
+		for name, service := range servers {
+			switch getServerStatus(name, service, single, envVars)[target].Status {
+			case "configured":
+				count.Configured++
+			case "different":
+				count.Different++
+			case "not-configured":
+				count.NotConfigured++
+			}
+		}
+		for name := range toolConfigs[target].Config.MCPServers {
+			if _, ok := servers[name]; !ok {
+				count.Extra++
+			}
+		}
+		report.Tools = append(report.Tools, count)
+	}
+
+	for _, usage := range collectEnvVarUsages(servers, envVars) {
+		report.Variables++
+		if !usage.Set {
+			report.UnsetVariables++
+		}
+		if secretKeyPattern.MatchString(usage.Name) {
+			report.Secrets++
+		}
+	}
+	report.HardcodedSecrets = len(findHardcodedSecrets(servers))
+
+	return report
+}
+
+// printStatsReport prints a stats report as sections of counts
+func printStatsReport(w io.Writer, report statsReport) {
+	fmt.Fprintf(w, "Servers: %d\n", report.Servers)
+
+	fmt.Fprintln(w, "\nBy type:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, kind := range []string{"local", "container", "remote"} {
+		fmt.Fprintf(tw, "  %s\t%d\n", kind, report.ByType[kind])
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nBy profile:")
+	profiles := make([]string, 0, len(report.ByProfile))
+	for profile := range report.ByProfile {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, profile := range profiles {
+		fmt.Fprintf(tw, "  %s\t%d\n", profile, report.ByProfile[profile])
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nBy tool:")
+	if len(report.Tools) == 0 {
+		fmt.Fprintln(w, "  No deployed tool configs found")
+	} else {
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  TOOL\tCONFIGURED\tDIFFERENT\tNOT CONFIGURED\tEXTRA")
+		fmt.Fprintln(tw, "  ----\t----------\t---------\t--------------\t-----")
+		for _, tool := range report.Tools {
+			fmt.Fprintf(tw, "  %s\t%d\t%d\t%d\t%d\n", tool.Tool, tool.Configured, tool.Different, tool.NotConfigured, tool.Extra)
+		}
+		tw.Flush()
+	}
+
+	fmt.Fprintln(w, "\nEnvironment:")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  variables referenced\t%d (%d not set)\n", report.Variables, report.UnsetVariables)
+	fmt.Fprintf(tw, "  secrets referenced\t%d\n", report.Secrets)
+	fmt.Fprintf(tw, "  hard-coded secrets\t%d\n", report.HardcodedSecrets)
+	tw.Flush()
+}
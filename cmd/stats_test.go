@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildStatsReport(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	servers := map[string]Service{
+		"github": {
+			Command:     "npx -y @modelcontextprotocol/server-github",
+			Environment: map[string]string{"GITHUB_TOKEN": "${GITHUB_TOKEN}", "GITHUB_HOST": "${GITHUB_HOST}"},
+			Labels:      map[string]string{"mcp.profile": "programming,default"},
+		},
+		"postgres": {
+			Image:       "mcp/postgres",
+			Environment: map[string]string{"PASSWORD": "hunter2"},
+			Labels:      map[string]string{"mcp.profile": "data"},
+		},
+		"time": {Command: "uvx mcp-server-time"},
+		"api":  {Command: "https://api.example.com/mcp"},
+	}
+
+	kiroPath, err := getPlatformToolPath("kiro")
+	if err != nil {
+		t.Fatalf("Failed to resolve kiro path: %v", err)
+	}
+	os.MkdirAll(filepath.Dir(kiroPath), 0755)
+	deployed := MCPConfig{MCPServers: map[string]MCPServer{
+		"time":  {Command: "uvx", Args: []string{"mcp-server-time"}},
+		"fetch": {Command: "uvx", Args: []string{"mcp-server-fetch"}},
+	}}
+	if err := writeToolConfig(deployed, kiroPath, ""); err != nil {
+		t.Fatalf("Failed to write kiro config: %v", err)
+	}
+
+	report := buildStatsReport(servers, []string{"kiro"}, map[string]string{"GITHUB_TOKEN": "x"})
+
+	if report.Servers != 4 {
+		t.Errorf("Expected 4 servers, got %d", report.Servers)
+	}
+	if want := map[string]int{"local": 2, "container": 1, "remote": 1}; !reflect.DeepEqual(report.ByType, want) {
+		t.Errorf("Expected by type %v, got %v", want, report.ByType)
+	}
+	if want := map[string]int{"default": 3, "programming": 1, "data": 1}; !reflect.DeepEqual(report.ByProfile, want) {
+		t.Errorf("Expected by profile %v, got %v", want, report.ByProfile)
+	}
+	if want := []toolStatsCount{{Tool: "kiro", Configured: 1, NotConfigured: 3, Extra: 1}}; !reflect.DeepEqual(report.Tools, want) {
+		t.Errorf("Expected tools %+v, got %+v", want, report.Tools)
+	}
+	if report.Variables != 2 || report.UnsetVariables != 1 || report.Secrets != 1 || report.HardcodedSecrets != 1 {
+		t.Errorf("Unexpected environment counts: %+v", report)
+	}
+
+	var out bytes.Buffer
+	printStatsReport(&out, report)
+	for _, want := range []string{"Servers: 4", "  container  1", "  variables referenced  2 (1 not set)", "  kiro  1"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -2,38 +2,286 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the status command, chosen for CI gating
+const (
+	statusExitInSync = 0
+	statusExitDrift  = 1
+	statusExitError  = 2
 )
 
+var statusJSON bool
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status [profile]",
+	Short: "Check deployed tool configs for drift",
+	Long: `Check whether the servers of a profile are deployed unchanged to a tool.
+Without -t, every tool with an existing config file is checked.
+If no profile is specified, it uses default servers.
+Servers that are deployed but not part of the profile also count as drift,
+unless the merge setting keeps the ones added by hand.
+Exits 0 when everything is configured, 1 when drift is detected, and 2 on errors,
+so it can gate CI or dotfile automation. Use --json for machine-readable output.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+
+		err := runStatus(os.Stdout, composeFile, profile)
+
+		// Any failure other than drift is an error for CI purposes
+		var exitErr *ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			return &ExitError{Code: statusExitError, Err: err}
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Only check this tool (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Print the result as JSON")
+	addEnvOverrideFlag(statusCmd)
+	statusCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// statusReport is the result of the status command
+type statusReport struct {
+	InSync bool               `json:"inSync"`
+	Tools  []toolStatusReport `json:"tools"`
+}
+
+// toolStatusReport describes the drift of one tool config
+type toolStatusReport struct {
+	Tool    string               `json:"tool"`
+	Path    string               `json:"path"`
+	InSync  bool                 `json:"inSync"`
+	Servers []serverStatusReport `json:"servers"`
+}
+
+// serverStatusReport describes one server in a tool config
+type serverStatusReport struct {
+	Name        string   `json:"name"`
+	Status      string   `json:"status"` // "configured", "not-configured", "different", "disabled", or "extra"
+	Differences []string `json:"differences,omitempty"`
+}
+
+// runStatus checks the profile's servers against the targeted tool configs
+// and prints the report. Returns a silent ExitError with statusExitDrift on drift.
+func runStatus(w io.Writer, composePath, profile string) error {
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return newConfigError("load environment variables", composePath, err)
+	}
+
+	targets, err := statusCheckTargets()
+	if err != nil {
+		return err
+	}
+
+	servers := filterServers(config, profile, false)
+	if err := checkServersRequiredEnvVars(enabledServers(servers), envVars); err != nil {
+		return err
+	}
+	report, err := buildStatusReport(targets, servers, envVars)
+	if err != nil {
+		return err
+	}
+
+	if statusJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	} else {
+		printStatusReport(w, report)
+	}
+
+	if !report.InSync {
+		return &ExitError{Code: statusExitDrift}
+	}
+	return nil
+}
+
+// statusCheckTargets returns the tool configs the status command checks:
+// the -t tool, or every tool whose config file exists
+func statusCheckTargets() ([]string, error) {
+	if toolShortcut != "" {
+		path, err := getScopedToolPath(toolShortcut, configScope)
+		if err != nil {
+			return nil, &ValidationError{Err: err}
+		}
+		if path == "" {
+			return nil, newValidationError("unknown tool shortcut: %s", toolShortcut)
+		}
+		if configScope == scopeProject {
+			return []string{projectTarget(toolShortcut)}, nil
+		}
+		return []string{toolShortcut}, nil
+	}
+
+	var targets []string
+	for _, status := range getToolStatuses(getStatusTargets(getAllTools())) {
+		if status.Exists {
+			targets = append(targets, status.ToolName)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, newValidationError("no deployed tool configs found; use -t to choose a tool")
+	}
+	return targets, nil
+}
+
+// buildStatusReport compares the servers with each target's deployed config
+func buildStatusReport(targets []string, servers map[string]Service, envVars map[string]string) (statusReport, error) {
+	toolConfigs := getToolConfigs(targets)
+	report := statusReport{InSync: true}
+
+	var names []string
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, target := range targets {
+		toolConfig := toolConfigs[target]
+		if toolConfig.Error != "" {
+			return statusReport{}, newConfigError("load tool config", toolConfig.Path, errors.New(toolConfig.Error))
+		}
+
+		toolReport := toolStatusReport{Tool: target, Path: toolConfig.Path, InSync: true}
+		single := map[string]ToolConfig{target: toolConfig}
+
+		for _, name := range names {
+			status := getServerStatus(name, servers[name], single, envVars)[target]
+			toolReport.Servers = append(toolReport.Servers, serverStatusReport{
+				Name:        name,
+				Status:      status.Status,
+				Differences: status.Differences,
+			})
+			if !serverInSync(status.Status, status.Differences) {
+				toolReport.InSync = false
+			}
+		}
+
+		// Servers deployed outside the profile would be removed by 'mcp set',
+		// except the unmanaged ones it keeps when merging
+		var unmanaged []string
+		if mergeByDefault() {
+			unmanaged = unmanagedServers(toolConfig.Config, toolConfig.Path)
+		}
+		var extra []string
+		for name := range toolConfig.Config.MCPServers {
+			if _, ok := servers[name]; !ok && !containsString(unmanaged, name) {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		for _, name := range extra {
+			toolReport.Servers = append(toolReport.Servers, serverStatusReport{Name: name, Status: "extra"})
+			toolReport.InSync = false
+		}
+
+		if !toolReport.InSync {
+			report.InSync = false
+		}
+		report.Tools = append(report.Tools, toolReport)
+	}
+
+	return report, nil
+}
+
+// serverInSync reports whether a server's deployment matches the compose
+// file: configured as defined, or left out if disabled
+func serverInSync(status string, differences []string) bool {
+	return status == "configured" || (status == "disabled" && len(differences) == 0)
+}
+
+// printStatusReport prints a status report as text, listing only servers that
+// drifted or are disabled
+func printStatusReport(w io.Writer, report statusReport) {
+	for _, tool := range report.Tools {
+		if tool.InSync {
+			fmt.Fprintf(w, "✓ %s (%s): in sync\n", tool.Tool, tool.Path)
+		} else {
+			fmt.Fprintf(w, "✗ %s (%s): drift detected\n", tool.Tool, tool.Path)
+		}
+		for _, server := range tool.Servers {
+			if server.Status == "configured" {
+				continue
+			}
+			fmt.Fprintf(w, "    %s: %s\n", server.Name, server.Status)
+			for _, difference := range server.Differences {
+				fmt.Fprintf(w, "      - %s\n", difference)
+			}
+		}
+	}
+}
+
 // loadToolConfig reads the MCP config file for a given tool shortcut
+// The shortcut may carry a scope suffix (e.g. "cursor:project")
 // Returns parsed MCPConfig or error if file doesn't exist
 // Handles missing files gracefully (returns empty config)
 func loadToolConfig(toolShortcut string) (MCPConfig, string, error) {
-	path := getPlatformToolPath(toolShortcut)
+	tool, scope := splitTarget(toolShortcut)
+	path, err := getScopedToolPath(tool, scope)
+	if err != nil {
+		return MCPConfig{}, "", fmt.Errorf("error resolving config path: %w", err)
+	}
 	if path == "" {
 		return MCPConfig{}, "", fmt.Errorf("unknown tool shortcut: %s", toolShortcut)
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return MCPConfig{}, path, nil // Return empty config for missing file
+	config, err := readMCPConfig(path)
+	if err != nil {
+		return MCPConfig{}, path, err
 	}
 
-	// Read and parse the config file
+	return config, path, nil
+}
+
+// readMCPConfig reads and parses an MCP JSON config file, or a VS Code or Zed
+// config through its adapter if that is the layout the file uses
+// Returns an empty config if the file doesn't exist
+func readMCPConfig(path string) (MCPConfig, error) {
 	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return MCPConfig{}, nil
+	}
 	if err != nil {
-		return MCPConfig{}, path, fmt.Errorf("error reading config file: %w", err)
+		return MCPConfig{}, fmt.Errorf("error reading config file: %w", err)
 	}
 
 	var config MCPConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		return MCPConfig{}, path, fmt.Errorf("error parsing config file: %w", err)
+		return MCPConfig{}, fmt.Errorf("error parsing config file: %w", err)
+	}
+	if config.MCPServers == nil {
+		if format, err := detectConfigFormat(path); err == nil && format != "standard" {
+			return readToolConfigFile(path, format)
+		}
 	}
 
-	return config, path, nil
+	return config, nil
 }
 
 // getToolConfigs loads MCP configs for all specified tools
@@ -66,6 +314,25 @@ func getToolConfigs(tools []string) map[string]ToolConfig {
 	return result
 }
 
+// getToolStatuses summarizes the config file of each tool (or "tool:scope" target)
+// Returns one ToolStatus per target in the given order
+func getToolStatuses(targets []string) []ToolStatus {
+	toolConfigs := getToolConfigs(targets)
+
+	result := make([]ToolStatus, 0, len(targets))
+	for _, target := range targets {
+		toolConfig := toolConfigs[target]
+		result = append(result, ToolStatus{
+			ToolName:    target,
+			ConfigPath:  toolConfig.Path,
+			Exists:      toolConfig.Exists || (toolConfig.Path != "" && fileExists(toolConfig.Path)),
+			ServerCount: len(toolConfig.Config.MCPServers),
+			Error:       toolConfig.Error,
+		})
+	}
+	return result
+}
+
 // fileExists checks if a file exists
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
@@ -75,8 +342,8 @@ func fileExists(path string) bool {
 // compareServerConfig compares a service from compose file with deployed server config
 // Returns status: "configured", "not-configured", "different", "unknown"
 // Returns list of differences (command mismatch, missing env vars, etc.)
-// Handles both local and remote servers
-func compareServerConfig(serverName string, composeService Service, deployedServer MCPServer, envVars map[string]string) (string, []string) {
+// Handles both local and remote servers, as written to tool (empty for MCP JSON)
+func compareServerConfig(serverName string, composeService Service, deployedServer MCPServer, tool string, envVars map[string]string) (string, []string) {
 	// If deployed server doesn't exist (empty struct), it's not configured
 	if deployedServer.Command == "" && deployedServer.URL == "" {
 		return "not-configured", nil
@@ -89,7 +356,7 @@ func compareServerConfig(serverName string, composeService Service, deployedServ
 	}
 
 	// Compare local servers
-	return compareLocalServers(serverName, composeService, deployedServer, envVars)
+	return compareLocalServers(serverName, composeService, deployedServer, tool, envVars)
 }
 
 // compareRemoteServers compares remote server configs
@@ -149,22 +416,11 @@ func compareRemoteServers(composeService Service, deployedServer MCPServer, envV
 // compareLocalServers compares local server configs
 // Checks command, args, env vars
 // Handles container vs command differences
-func compareLocalServers(serverName string, composeService Service, deployedServer MCPServer, envVars map[string]string) (string, []string) {
+func compareLocalServers(serverName string, composeService Service, deployedServer MCPServer, tool string, envVars map[string]string) (string, []string) {
 	var differences []string
 
 	// Get container tool from config
-	containerTool := "docker"
-	configDir := getConfigDir()
-	configPath := filepath.Join(configDir, "config.json")
-	if _, err := os.Stat(configPath); err == nil {
-		data, err := os.ReadFile(configPath)
-		if err == nil {
-			var config CLIConfig
-			if err := json.Unmarshal(data, &config); err == nil && config.ContainerTool != "" {
-				containerTool = config.ContainerTool
-			}
-		}
-	}
+	containerTool := getContainerTool()
 
 	// Handle container-based servers
 	if composeService.Image != "" {
@@ -190,6 +446,7 @@ func compareLocalServers(serverName string, composeService Service, deployedServ
 		for key, value := range composeService.Environment {
 			expectedEnv[key] = expandEnvVars(value, envVars)
 		}
+		expectedEnv = toolServerEnv(tool, serverName, composeService, expectedEnv, envVars)
 		if !compareEnvVars(expectedEnv, deployedServer.Env) {
 			differences = append(differences, "environment variables mismatch")
 		}
@@ -229,6 +486,7 @@ func compareLocalServers(serverName string, composeService Service, deployedServ
 		for key, value := range composeService.Environment {
 			expectedEnv[key] = expandEnvVars(value, envVars)
 		}
+		expectedEnv = toolServerEnv(tool, serverName, composeService, expectedEnv, envVars)
 		if !compareEnvVars(expectedEnv, deployedServer.Env) {
 			differences = append(differences, "environment variables mismatch")
 		}
@@ -241,6 +499,13 @@ func compareLocalServers(serverName string, composeService Service, deployedServ
 	return "configured", nil
 }
 
+// toolServerEnv returns the environment 'mcp set' writes for a server to a
+// tool, which may prompt for some variables instead, see applyToolInputs
+func toolServerEnv(tool, serverName string, composeService Service, env map[string]string, envVars map[string]string) map[string]string {
+	config := MCPConfig{MCPServers: map[string]MCPServer{serverName: {Env: env}}}
+	return applyToolInputs(config, tool, map[string]Service{serverName: composeService}, envVars).MCPServers[serverName].Env
+}
+
 // compareHeaders compares two header maps
 func compareHeaders(expected, actual map[string]string) bool {
 	if len(expected) != len(actual) {
@@ -294,6 +559,16 @@ func getServerStatus(serverName string, composeService Service, toolConfigs map[
 	result := make(map[string]ServerStatus)
 
 	for tool, toolConfig := range toolConfigs {
+		// A server turned off with mcp.disabled is meant to be left out
+		if IsServiceDisabled(composeService) {
+			status := ServerStatus{Status: "disabled", Tool: tool, ConfigPath: toolConfig.Path}
+			if _, exists := toolConfig.Config.MCPServers[serverName]; exists {
+				status.Differences = []string{"still deployed, but disabled with mcp.disabled"}
+			}
+			result[tool] = status
+			continue
+		}
+
 		if !toolConfig.Exists {
 			result[tool] = ServerStatus{
 				Status:     "not-configured",
@@ -325,7 +600,8 @@ func getServerStatus(serverName string, composeService Service, toolConfigs map[
 		}
 
 		// Compare the server configs
-		status, differences := compareServerConfig(serverName, composeService, deployedServer, envVars)
+		name, _ := splitTarget(tool)
+		status, differences := compareServerConfig(serverName, composeService, deployedServer, name, envVars)
 		result[tool] = ServerStatus{
 			Status:      status,
 			Tool:        tool,
@@ -339,9 +615,15 @@ func getServerStatus(serverName string, composeService Service, toolConfigs map[
 
 // normalizeToolName normalizes tool names for display
 func normalizeToolName(tool string) string {
+	if name, scope := splitTarget(tool); scope != scopeUser {
+		return normalizeToolName(name) + " (" + strings.ToUpper(scope) + ")"
+	}
+
 	switch tool {
 	case "q-cli":
 		return "Q-CLI"
+	case "q-ide":
+		return "Q-IDE"
 	case "claude-desktop":
 		return "CLAUDE"
 	case "cursor":
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -298,6 +300,11 @@ func TestNormalizeToolName(t *testing.T) {
 			tool:     "q-cli",
 			expected: "Q-CLI",
 		},
+		{
+			name:     "q-ide",
+			tool:     "q-ide",
+			expected: "Q-IDE",
+		},
 		{
 			name:     "claude-desktop",
 			tool:     "claude-desktop",
@@ -313,6 +320,11 @@ func TestNormalizeToolName(t *testing.T) {
 			tool:     "kiro",
 			expected: "KIRO",
 		},
+		{
+			name:     "project scope",
+			tool:     "cursor:project",
+			expected: "CURSOR (PROJECT)",
+		},
 		{
 			name:     "unknown tool",
 			tool:     "unknown",
@@ -397,7 +409,7 @@ func TestCompareServerConfig(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			status, _ := compareServerConfig(tt.serverName, tt.composeService, tt.deployedServer, envVars)
+			status, _ := compareServerConfig(tt.serverName, tt.composeService, tt.deployedServer, "", envVars)
 			if status != tt.expectedStatus {
 				t.Errorf("compareServerConfig() status = %q, want %q", status, tt.expectedStatus)
 			}
@@ -730,7 +742,7 @@ func TestCompareLocalServers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			status, differences := compareLocalServers(tt.serverName, tt.composeService, tt.deployedServer, envVars)
+			status, differences := compareLocalServers(tt.serverName, tt.composeService, tt.deployedServer, "", envVars)
 
 			if status != tt.expectedStatus {
 				t.Errorf("Expected status %s, got %s", tt.expectedStatus, status)
@@ -823,3 +835,174 @@ func TestGetServerStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestGetToolStatuses(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+
+	// Deploy a config with two servers for cursor, and an invalid one for kiro
+	cursorPath, _ := getPlatformToolPath("cursor")
+	if err := os.MkdirAll(filepath.Dir(cursorPath), 0755); err != nil {
+		t.Fatalf("Failed to create cursor dir: %v", err)
+	}
+	if err := os.WriteFile(cursorPath, []byte(`{"mcpServers":{"a":{"command":"x"},"b":{"command":"y"}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write cursor config: %v", err)
+	}
+	kiroPath, _ := getPlatformToolPath("kiro")
+	if err := os.MkdirAll(filepath.Dir(kiroPath), 0755); err != nil {
+		t.Fatalf("Failed to create kiro dir: %v", err)
+	}
+	if err := os.WriteFile(kiroPath, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to write kiro config: %v", err)
+	}
+
+	statuses := getToolStatuses([]string{"cursor", "kiro", "q-cli"})
+	if len(statuses) != 3 {
+		t.Fatalf("Expected 3 statuses, got %d", len(statuses))
+	}
+
+	if s := statuses[0]; s.ToolName != "cursor" || !s.Exists || s.ServerCount != 2 || s.ConfigPath != cursorPath {
+		t.Errorf("Unexpected cursor status: %+v", s)
+	}
+	if s := statuses[1]; s.ToolName != "kiro" || !s.Exists || s.Error == "" {
+		t.Errorf("Expected kiro to exist with a parse error, got %+v", s)
+	}
+	if s := statuses[2]; s.ToolName != "q-cli" || s.Exists || s.ServerCount != 0 {
+		t.Errorf("Expected q-cli to be missing, got %+v", s)
+	}
+}
+
+func TestRunStatus(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	originalTool, originalJSON := toolShortcut, statusJSON
+	defer func() { toolShortcut, statusJSON = originalTool, originalJSON }()
+	toolShortcut, statusJSON = "kiro", false
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+`), 0644)
+
+	kiroPath, _ := getPlatformToolPath("kiro")
+	os.MkdirAll(filepath.Dir(kiroPath), 0755)
+
+	t.Run("in sync", func(t *testing.T) {
+		writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{
+			"time": {Command: "uvx", Args: []string{"mcp-server-time"}},
+		}}, kiroPath, "")
+
+		var out bytes.Buffer
+		if err := runStatus(&out, composePath, ""); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.Contains(out.String(), "✓ kiro") {
+			t.Errorf("Expected in-sync output, got:\n%s", out.String())
+		}
+	})
+
+	t.Run("drift", func(t *testing.T) {
+		writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{
+			"time":  {Command: "uvx", Args: []string{"mcp-server-time", "--local-timezone=UTC"}},
+			"stale": {Command: "stale"},
+		}}, kiroPath, "")
+
+		statusJSON = true
+		defer func() { statusJSON = false }()
+
+		var out bytes.Buffer
+		err := runStatus(&out, composePath, "")
+		if ExitCode(err) != statusExitDrift {
+			t.Fatalf("Expected exit code %d, got %v", statusExitDrift, err)
+		}
+
+		var report statusReport
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatalf("Expected JSON output, got %v:\n%s", err, out.String())
+		}
+		if report.InSync || len(report.Tools) != 1 {
+			t.Fatalf("Unexpected report: %+v", report)
+		}
+		servers := report.Tools[0].Servers
+		if len(servers) != 2 || servers[0].Status != "different" || servers[1].Name != "stale" || servers[1].Status != "extra" {
+			t.Errorf("Unexpected servers: %+v", servers)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabledPath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+		os.WriteFile(disabledPath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+    labels:
+      mcp.disabled: "true"
+`), 0644)
+
+		// Left out as intended
+		writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{}}, kiroPath, "")
+		var out bytes.Buffer
+		if err := runStatus(&out, disabledPath, ""); err != nil {
+			t.Fatalf("Expected a disabled server to be in sync, got %v", err)
+		}
+		if !strings.Contains(out.String(), "✓ kiro") || !strings.Contains(out.String(), "    time: disabled\n") {
+			t.Errorf("Expected time to be reported as disabled, got:\n%s", out.String())
+		}
+
+		// Still deployed
+		writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{
+			"time": {Command: "uvx", Args: []string{"mcp-server-time"}},
+		}}, kiroPath, "")
+		out.Reset()
+		if err := runStatus(&out, disabledPath, ""); ExitCode(err) != statusExitDrift {
+			t.Fatalf("Expected drift, got %v", err)
+		}
+		if !strings.Contains(out.String(), "still deployed, but disabled with mcp.disabled") {
+			t.Errorf("Expected the deployed disabled server to be reported, got:\n%s", out.String())
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		toolShortcut = "unknown-tool"
+		defer func() { toolShortcut = "kiro" }()
+
+		originalComposeFile := composeFile
+		defer func() { composeFile = originalComposeFile }()
+		composeFile = composePath
+
+		// Errors exit 2 regardless of their class
+		err := statusCmd.RunE(statusCmd, nil)
+		if ExitCode(err) != statusExitError {
+			t.Errorf("Expected exit code %d, got %d (%v)", statusExitError, ExitCode(err), err)
+		}
+	})
+}
+
+func TestRunStatusVSCodeInputs(t *testing.T) {
+	composePath, path := setupVSCodeInputs(t)
+	originalTool, originalJSON := toolShortcut, statusJSON
+	defer func() { toolShortcut, statusJSON = originalTool, originalJSON }()
+	toolShortcut, statusJSON = "vscode", false
+
+	setVSCodeInputs(t, composePath, path)
+	var out bytes.Buffer
+	if err := runStatus(&out, composePath, ""); err != nil {
+		t.Fatalf("Expected the inputs 'mcp set' wrote to be in sync, got %v:\n%s", err, out.String())
+	}
+}
+
+func TestRunStatusMergeSkipsUnmanaged(t *testing.T) {
+	composePath, _ := setupHandAddedServer(t)
+	originalTool, originalJSON := toolShortcut, statusJSON
+	defer func() { toolShortcut, statusJSON = originalTool, originalJSON }()
+	toolShortcut, statusJSON = "cursor", false
+
+	var out bytes.Buffer
+	if err := runStatus(&out, composePath, ""); err != nil {
+		t.Fatalf("Expected a hand-added server kept by merging to be in sync, got %v:\n%s", err, out.String())
+	}
+	if strings.Contains(out.String(), "mine") {
+		t.Errorf("Expected the hand-added server not to be reported, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// serverChange is one row of the summary printed after writing a tool config
+type serverChange struct {
+	Server string
+	Action string // added, updated, removed, or unchanged
+	Tool   string
+	Notes  string // what is notable about the change, e.g. "command uvx -> npx"
+}
+
+// summarizeServerChanges lists what happened to each server of a tool config
+// written over before, sorted by server name
+func summarizeServerChanges(tool string, before, after MCPConfig) []serverChange {
+	var rows []serverChange
+	for name, server := range after.MCPServers {
+		old, exists := before.MCPServers[name]
+		if !exists {
+			rows = append(rows, serverChange{name, "added", tool, describeServer(server)})
+		} else if _, changed := diffServerFields(serverFields(old), serverFields(server)); changed {
+			rows = append(rows, serverChange{name, "updated", tool, describeServerUpdate(old, server)})
+		} else {
+			rows = append(rows, serverChange{name, "unchanged", tool, ""})
+		}
+	}
+	for name, server := range before.MCPServers {
+		if _, exists := after.MCPServers[name]; !exists {
+			rows = append(rows, serverChange{name, "removed", tool, describeServer(server)})
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Server < rows[j].Server })
+	return rows
+}
+
+// describeServer summarizes a server entry, e.g. "uvx, 2 env" or
+// "https://example.com/mcp, 1 header"
+func describeServer(server MCPServer) string {
+	parts := []string{server.Command}
+	if server.URL != "" {
+		parts = []string{server.URL}
+	}
+	if len(server.Env) > 0 {
+		parts = append(parts, fmt.Sprintf("%d env", len(server.Env)))
+	}
+	if len(server.Headers) > 0 {
+		parts = append(parts, pluralize(len(server.Headers), "header"))
+	}
+	if server.Disabled {
+		parts = append(parts, "disabled")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeServerUpdate lists the notable differences between two entries of
+// a server, e.g. "command uvx -> npx, env 1 -> 2"
+func describeServerUpdate(old, server MCPServer) string {
+	var notes []string
+	changed := func(name, from, to string) {
+		if from != to {
+			notes = append(notes, fmt.Sprintf("%s %s -> %s", name, from, to))
+		}
+	}
+
+	changed("type", old.Type, server.Type)
+	changed("command", old.Command, server.Command)
+	if old.URL != server.URL {
+		notes = append(notes, "url")
+	}
+	if !slices.Equal(old.Args, server.Args) {
+		notes = append(notes, "args")
+	}
+	if len(old.Env) != len(server.Env) {
+		changed("env", fmt.Sprint(len(old.Env)), fmt.Sprint(len(server.Env)))
+	} else if !maps.Equal(old.Env, server.Env) {
+		notes = append(notes, "env")
+	}
+	if len(old.Headers) != len(server.Headers) {
+		changed("headers", fmt.Sprint(len(old.Headers)), fmt.Sprint(len(server.Headers)))
+	} else if !maps.Equal(old.Headers, server.Headers) {
+		notes = append(notes, "headers")
+	}
+	if old.Disabled != server.Disabled {
+		if server.Disabled {
+			notes = append(notes, "disabled")
+		} else {
+			notes = append(notes, "enabled")
+		}
+	}
+	return strings.Join(notes, ", ")
+}
+
+// printChangeSummary prints a table of server changes, or nothing if there
+// are none
+func printChangeSummary(w io.Writer, rows []serverChange) {
+	if len(rows) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tACTION\tTOOL\tCHANGES")
+	fmt.Fprintln(tw, "------\t------\t----\t-------")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", row.Server, row.Action, orDash(row.Tool), orDash(row.Notes))
+	}
+	tw.Flush()
+}
+
+// orDash returns s, or "-" for an empty table cell
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeServerChanges(t *testing.T) {
+	before := MCPConfig{MCPServers: map[string]MCPServer{
+		"time":   {Command: "uvx", Args: []string{"mcp-server-time"}},
+		"github": {Command: "npx", Env: map[string]string{"TOKEN": "a"}},
+		"stale":  {Type: "http", URL: "https://old.example.com/mcp"},
+	}}
+	after := MCPConfig{MCPServers: map[string]MCPServer{
+		"time":   {Command: "uvx", Args: []string{"mcp-server-time"}},
+		"github": {Command: "docker", Env: map[string]string{"TOKEN": "a", "ORG": "b"}},
+		"api":    {Type: "http", URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "x"}},
+	}}
+
+	rows := summarizeServerChanges("cursor", before, after)
+	expected := []serverChange{
+		{"api", "added", "cursor", "https://example.com/mcp, 1 header"},
+		{"github", "updated", "cursor", "command npx -> docker, env 1 -> 2"},
+		{"stale", "removed", "cursor", "https://old.example.com/mcp"},
+		{"time", "unchanged", "cursor", ""},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rows)
+	}
+
+	var out bytes.Buffer
+	printChangeSummary(&out, rows)
+	for _, want := range []string{
+		"SERVER  ACTION     TOOL    CHANGES\n",
+		"github  updated    cursor  command npx -> docker, env 1 -> 2\n",
+		"time    unchanged  cursor  -\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	printChangeSummary(&out, nil)
+	if out.Len() != 0 {
+		t.Errorf("Expected no table without changes, got %q", out.String())
+	}
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync [profile]",
+	Short: "Update every configured tool at once",
+	Long: `Regenerate the MCP configuration of every supported tool found on this machine.
+A tool is synced when its config file exists or the tool is installed (its config
+directory exists and is its own, not a shared one such as your home directory).
+If no profile is specified, it uses default servers.
+With 'mcp config set merge true', servers added to a tool by hand are kept, as
+with 'mcp set --merge'.
+Tools that don't support the profile's remote servers, or whose config file
+isn't writable, are skipped.
+Prints a summary of added, updated, and removed servers for each tool, then a
+table of what changed in each server.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+		servers := enabledServers(filterServers(config, profile, false))
+
+		// Validate remote servers have required auth configuration (OAuth or headers)
+		for name, service := range servers {
+			if IsRemoteServerWithEnvExpansion(service, envVars) {
+				if err := ValidateRemoteServerAuth(name, service); err != nil {
+					return &ValidationError{Err: err}
+				}
+			}
+		}
+
+		tools := detectInstalledTools()
+		if len(tools) == 0 {
+			return newValidationError("no supported tools found on this machine; use 'mcp set -t <tool>' to configure one")
+		}
+
+		// Skip read-only configs before acquiring any OAuth tokens
+		tools = writableTools(os.Stdout, tools)
+		if len(tools) == 0 {
+			return newValidationError("no tool config on this machine is writable; use 'mcp set --print' to print the config instead")
+		}
+
+		// Convert once so OAuth tokens are only acquired once
+		mcpConfig, err := convertToMCPConfig(cmd.Context(), servers, envVars)
+		if err != nil {
+			return err
+		}
+
+		return syncTools(os.Stdout, tools, servers, config.Services, envVars, mcpConfig)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+}
+
+// syncChanges lists the servers a sync adds, updates, and removes in one tool config
+type syncChanges struct {
+	Added   []string
+	Updated []string
+	Removed []string
+}
+
+// String summarizes the changes, e.g. "1 added, 0 updated, 2 removed"
+func (c syncChanges) String() string {
+	return fmt.Sprintf("%d added, %d updated, %d removed", len(c.Added), len(c.Updated), len(c.Removed))
+}
+
+// detectInstalledTools returns the tools whose config file exists or that
+// appear to be installed, in the order of getAllTools
+func detectInstalledTools() []string {
+	var tools []string
+	for _, tool := range getAllTools() {
+		path, err := getScopedToolPath(tool, configScope)
+		if err != nil || path == "" {
+			continue
+		}
+		if fileExists(path) || isToolInstalled(tool, path) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// writableTools returns the tools whose config file can be written, printing
+// a line for each one that is skipped
+func writableTools(w io.Writer, tools []string) []string {
+	var writable []string
+	for _, tool := range tools {
+		path, err := getScopedToolPath(tool, configScope)
+		if err != nil || path == "" {
+			continue
+		}
+		if err := checkConfigWritable(path); err != nil {
+			fmt.Fprintf(w, "Skipped %s: %s is not writable (%v)\n", tool, path, err)
+			continue
+		}
+		writable = append(writable, tool)
+	}
+	return writable
+}
+
+// isToolInstalled reports whether the directory a tool keeps its config in
+// exists, unless it is one many tools share, such as the home directory
+func isToolInstalled(tool, path string) bool {
+	// Workspace-level configs only count when the file itself exists
+	if tool == "q-ide" || configScope == scopeProject {
+		return false
+	}
+
+	dir := filepath.Dir(path)
+	// Kiro keeps its config in a settings subdirectory that may not exist yet
+	if tool == "kiro" {
+		dir = filepath.Dir(dir)
+	}
+
+	if sharedConfigDir(dir) {
+		return false
+	}
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// sharedConfigDir reports whether dir is a directory that exists regardless
+// of which tools are installed: the home directory or its parent, the
+// platform's config directories, the working directory, or the root
+func sharedConfigDir(dir string) bool {
+	dirs, err := getPlatformDirs()
+	if err != nil {
+		return true
+	}
+	shared := []string{
+		dirs.home,
+		filepath.Dir(dirs.home),
+		dirs.config,
+		dirs.appData,
+		filepath.Join(dirs.home, "Library"),
+		filepath.Join(dirs.home, "Library", "Application Support"),
+	}
+	if workDir, err := os.Getwd(); err == nil {
+		shared = append(shared, workDir)
+	}
+
+	dir = filepath.Clean(dir)
+	if dir == filepath.Dir(dir) {
+		return true
+	}
+	for _, other := range shared {
+		if dir == filepath.Clean(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncTools writes mcpConfig to each tool and prints a per-tool summary,
+// then a table of the changes to each server. services are all the servers
+// of the compose file, which merging keeps apart from unmanaged ones.
+func syncTools(w io.Writer, tools []string, servers, services map[string]Service, envVars map[string]string, mcpConfig MCPConfig) error {
+	merge := mergeByDefault()
+	var summary []serverChange
+	for _, tool := range tools {
+		if err := ValidateToolSupportWithEnvExpansion(tool, serversForTool(servers, tool), envVars); err != nil {
+			fmt.Fprintf(w, "Skipped %s: %v\n", tool, err)
+			continue
+		}
+
+		path, err := getScopedToolPath(tool, configScope)
+		if err != nil {
+			return newConfigError("resolve config path for "+tool, "", err)
+		}
+		if format := getToolFormat(tool); !isSupportedFormat(format) {
+			fmt.Fprintf(w, "Skipped %s: unsupported format '%s'\n", tool, format)
+			continue
+		}
+
+		existing, err := readMCPConfig(path)
+		if err != nil {
+			return newConfigError("load tool config", path, err)
+		}
+		prepared, err := prepareToolConfig(mcpConfig, tool, path, services, envVars, merge)
+		if err != nil {
+			return err
+		}
+		toolConfig := prepared.Config
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return newConfigError("create config directory", filepath.Dir(path), err)
+		}
+		if err := writeToolConfig(toolConfig, path, tool); err != nil {
+			countError("sync")
+			return newConfigError("write MCP config", path, err)
+		}
+		if err := recordManagedServers(path, toolConfig, prepared.Unmanaged); err != nil {
+			return newConfigError("save state", "", err)
+		}
+		metrics.Inc(metricSyncsTotal, map[string]string{"tool": tool})
+
+		changes := compareMCPConfigs(existing, toolConfig)
+		fmt.Fprintf(w, "Synced %s (%s): %s\n", tool, path, changes)
+		printSyncChanges(w, "+", changes.Added)
+		printSyncChanges(w, "~", changes.Updated)
+		printSyncChanges(w, "-", changes.Removed)
+		printDisabledServers(w, prepared.Disabled)
+		if len(prepared.Unmanaged) > 0 {
+			fmt.Fprintf(w, "Kept unmanaged: %s\n", strings.Join(prepared.Unmanaged, ", "))
+		}
+		for _, field := range prepared.Stripped {
+			fmt.Fprintf(w, "  left out %s\n", field)
+		}
+		summary = append(summary, summarizeServerChanges(tool, existing, toolConfig)...)
+	}
+
+	printChangeSummary(w, summary)
+	return nil
+}
+
+// compareMCPConfigs classifies the servers that differ between two configs
+func compareMCPConfigs(before, after MCPConfig) syncChanges {
+	var changes syncChanges
+
+	for name, server := range after.MCPServers {
+		old, exists := before.MCPServers[name]
+		if !exists {
+			changes.Added = append(changes.Added, name)
+		} else if _, changed := diffServerFields(serverFields(old), serverFields(server)); changed {
+			changes.Updated = append(changes.Updated, name)
+		}
+	}
+	for name := range before.MCPServers {
+		if _, exists := after.MCPServers[name]; !exists {
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Updated)
+	sort.Strings(changes.Removed)
+	return changes
+}
+
+// printSyncChanges prints one indented line per server name with the given marker
+func printSyncChanges(w io.Writer, marker string, names []string) {
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s %s\n", marker, name)
+	}
+}
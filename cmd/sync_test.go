@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectInstalledTools(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(t.TempDir())
+
+	// Cursor is installed but has no config yet; Q CLI has a config file
+	os.MkdirAll(filepath.Join(home, ".cursor"), 0755)
+	qPath := filepath.Join(home, ".aws", "amazonq", "mcp.json")
+	os.MkdirAll(filepath.Dir(qPath), 0755)
+	os.WriteFile(qPath, []byte(`{"mcpServers":{}}`), 0644)
+	// Kiro's settings directory is created lazily
+	os.MkdirAll(filepath.Join(home, ".kiro"), 0755)
+
+	tools := detectInstalledTools()
+	expected := []string{"q-cli", "cursor", "kiro"}
+	if !reflect.DeepEqual(tools, expected) {
+		t.Errorf("Expected %v, got %v", expected, tools)
+	}
+}
+
+func TestCompareMCPConfigs(t *testing.T) {
+	before := MCPConfig{MCPServers: map[string]MCPServer{
+		"same":    {Command: "uvx", Args: []string{"same"}},
+		"changed": {Command: "uvx", Args: []string{"old"}},
+		"gone":    {Command: "gone"},
+	}}
+	after := MCPConfig{MCPServers: map[string]MCPServer{
+		"same":    {Command: "uvx", Args: []string{"same"}},
+		"changed": {Command: "uvx", Args: []string{"new"}},
+		"new":     {Command: "new"},
+	}}
+
+	changes := compareMCPConfigs(before, after)
+	expected := syncChanges{
+		Added:   []string{"new"},
+		Updated: []string{"changed"},
+		Removed: []string{"gone"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestSyncTools(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cursorPath := filepath.Join(home, ".cursor", "mcp.json")
+	os.MkdirAll(filepath.Dir(cursorPath), 0755)
+	writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{
+		"stale": {Command: "stale"},
+	}}, cursorPath, "")
+
+	servers := map[string]Service{
+		"time":   {Command: "uvx mcp-server-time"},
+		"remote": {Command: "https://example.com/mcp", Labels: map[string]string{"mcp.headers": "{}"}},
+	}
+	mcpConfig := MCPConfig{MCPServers: map[string]MCPServer{
+		"time":   {Command: "uvx", Args: []string{"mcp-server-time"}},
+		"remote": {Type: "http", URL: "https://example.com/mcp"},
+	}}
+
+	var out bytes.Buffer
+	if err := syncTools(&out, []string{"cursor", "claude-desktop"}, servers, servers, map[string]string{}, mcpConfig); err != nil {
+		t.Fatalf("syncTools failed: %v", err)
+	}
+	output := out.String()
+
+	if !strings.Contains(output, "Synced cursor ("+cursorPath+"): 2 added, 0 updated, 1 removed") {
+		t.Errorf("Expected cursor summary, got:\n%s", output)
+	}
+	if !strings.Contains(output, "  - stale") {
+		t.Errorf("Expected removed server to be listed, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Skipped claude-desktop") {
+		t.Errorf("Expected claude-desktop to be skipped for remote servers, got:\n%s", output)
+	}
+
+	written, _ := readMCPConfig(cursorPath)
+	if !reflect.DeepEqual(written, mcpConfig) {
+		t.Errorf("Expected cursor config to be replaced, got %+v", written)
+	}
+}
+
+func TestSyncToolsVSCodeInputs(t *testing.T) {
+	composePath, path := setupVSCodeInputs(t)
+	config, _ := loadComposeFile(composePath)
+	envVars, _ := loadEnvVars(composePath)
+	mcpConfig, err := convertToMCPConfig(context.Background(), config.Services, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+
+	if err := syncTools(&bytes.Buffer{}, []string{"vscode"}, config.Services, config.Services, envVars, mcpConfig); err != nil {
+		t.Fatalf("syncTools failed: %v", err)
+	}
+	checkVSCodeInputs(t, path)
+}
+
+// setupHandAddedServer writes a compose file with one server and a Cursor
+// config with that server as 'mcp set' wrote it plus one added by hand, with
+// the merge setting on, returning the compose file's and config's paths
+func setupHandAddedServer(t *testing.T) (string, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	os.MkdirAll(filepath.Join(home, ".config", "mcp"), 0755)
+	saveCLIConfig(CLIConfig{Merge: true})
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  time:
+    command: uvx mcp-server-time
+`), 0644)
+
+	cursorPath := filepath.Join(home, ".cursor", "mcp.json")
+	os.MkdirAll(filepath.Dir(cursorPath), 0755)
+	written := MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx", Args: []string{"mcp-server-time"}}}}
+	recordManagedServers(cursorPath, written, nil)
+	written.MCPServers["mine"] = MCPServer{Command: "mine"}
+	writeToolConfig(written, cursorPath, "cursor")
+	return composePath, cursorPath
+}
+
+func TestSyncToolsMergeKeepsUnmanaged(t *testing.T) {
+	composePath, cursorPath := setupHandAddedServer(t)
+	config, _ := loadComposeFile(composePath)
+	mcpConfig, err := convertToMCPConfig(context.Background(), config.Services, map[string]string{})
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := syncTools(&out, []string{"cursor"}, config.Services, config.Services, map[string]string{}, mcpConfig); err != nil {
+		t.Fatalf("syncTools failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Kept unmanaged: mine") {
+		t.Errorf("Expected the hand-added server to be kept, got:\n%s", out.String())
+	}
+	written, _ := readMCPConfig(cursorPath)
+	if _, ok := written.MCPServers["mine"]; !ok {
+		t.Errorf("Expected the hand-added server to stay in the config, got %+v", written.MCPServers)
+	}
+	if got := unmanagedServers(written, cursorPath); !reflect.DeepEqual(got, []string{"mine"}) {
+		t.Errorf("Expected the hand-added server to stay unmanaged, got %v", got)
+	}
+}
+
+func TestDetectInstalledToolsSharedDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(t.TempDir())
+	os.MkdirAll(filepath.Join(home, ".config", "mcp"), 0755)
+	os.MkdirAll(filepath.Join(home, ".mytool"), 0755)
+	saveCLIConfig(CLIConfig{Tools: map[string]CustomTool{
+		"in-home":   {Path: filepath.Join(home, "tool-mcp.json")},
+		"in-config": {Path: filepath.Join(home, ".config", "tool-mcp.json")},
+		"own-dir":   {Path: filepath.Join(home, ".mytool", "mcp.json")},
+	}})
+
+	tools := detectInstalledTools()
+	if !reflect.DeepEqual(tools, []string{"own-dir"}) {
+		t.Errorf("Expected only the tool with its own directory, got %v", tools)
+	}
+
+	// A config file counts wherever it is
+	os.WriteFile(filepath.Join(home, "tool-mcp.json"), []byte(`{"mcpServers":{}}`), 0644)
+	if tools := detectInstalledTools(); !reflect.DeepEqual(tools, []string{"in-home", "own-dir"}) {
+		t.Errorf("Expected the tool with a config file to be found, got %v", tools)
+	}
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// toolTarget is one tool config written by 'mcp set' or 'mcp clear' with
+// several -t flags or --all-tools
+type toolTarget struct {
+	Tool    string
+	Path    string
+	Result  string         // what was written, e.g. "2 added, 0 updated, 1 removed"
+	Skipped string         // why the tool wasn't written, if it wasn't
+	Changed bool           // whether the tool's config changed, or would with --check
+	Servers []serverChange // what happened to each server when written
+}
+
+// selectTools returns the tools named with -t, in order and without
+// duplicates, or every tool found on this machine with all
+func selectTools(names []string, all bool) ([]string, error) {
+	if all {
+		if len(names) > 0 {
+			return nil, newValidationError("--all-tools can't be combined with -t")
+		}
+		tools := detectInstalledTools()
+		if len(tools) == 0 {
+			return nil, newValidationError("no supported tools found on this machine; use -t <tool> to configure one")
+		}
+		return tools, nil
+	}
+
+	var tools []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(tools, name) {
+			tools = append(tools, name)
+		}
+	}
+	return tools, nil
+}
+
+// checkMultipleTools rejects the flags that only make sense for a single config file
+func checkMultipleTools(print bool) error {
+	if configFile != "" {
+		return newValidationError("-c writes a single config file and can't be combined with several tools")
+	}
+	if print {
+		return newValidationError("--print can't be combined with several tools")
+	}
+	return nil
+}
+
+// resolveToolTargets resolves the config path of each tool, marking the tools
+// that can't be written as skipped, so nothing is written or acquired for them
+func resolveToolTargets(tools []string) []toolTarget {
+	targets := make([]toolTarget, 0, len(tools))
+	for _, tool := range tools {
+		target := toolTarget{Tool: tool}
+		path, err := getScopedToolPath(tool, configScope)
+		switch {
+		case err != nil:
+			target.Skipped = err.Error()
+		case path == "":
+			target.Skipped = "unknown tool shortcut"
+		case !isSupportedFormat(getToolFormat(tool)):
+			target.Skipped = fmt.Sprintf("unsupported format '%s'", getToolFormat(tool))
+		default:
+			target.Path = path
+			if err := checkConfigWritable(path); err != nil {
+				target.Skipped = fmt.Sprintf("not writable (%v)", err)
+			}
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// checkToolTargetSupport marks the tools that can't run the servers as skipped
+func checkToolTargetSupport(targets []toolTarget, servers map[string]Service, envVars map[string]string) {
+	for i := range targets {
+		if targets[i].Skipped != "" {
+			continue
+		}
+		if err := ValidateToolSupportWithEnvExpansion(targets[i].Tool, serversForTool(servers, targets[i].Tool), envVars); err != nil {
+			targets[i].Skipped = err.Error()
+		}
+	}
+}
+
+// checkToolTargetFields marks the tools that don't support every field of the
+// servers as skipped, for --strict
+func checkToolTargetFields(targets []toolTarget, config MCPConfig) {
+	for i := range targets {
+		if targets[i].Skipped != "" {
+			continue
+		}
+		if _, stripped := stripUnsupportedFields(config, targets[i].Tool); len(stripped) > 0 {
+			targets[i].Skipped = unsupportedFieldsError(targets[i].Tool, stripped).Error()
+		}
+	}
+}
+
+// writableTargets counts the targets that haven't been skipped
+func writableTargets(targets []toolTarget) int {
+	count := 0
+	for _, target := range targets {
+		if target.Skipped == "" {
+			count++
+		}
+	}
+	return count
+}
+
+// writeToolTargets writes the config build returns for each target that
+// isn't skipped and would change, backing up each file first if auto-backup
+// is on; with check, nothing is written
+// build gets the target's current config and returns the config to write, the
+// servers in it that mcp doesn't manage, and a description of the result.
+func writeToolTargets(w io.Writer, targets []toolTarget, check bool, build func(target toolTarget, existing MCPConfig) (MCPConfig, []string, string, error)) error {
+	for i, target := range targets {
+		if target.Skipped != "" {
+			continue
+		}
+		existing, err := readMCPConfig(target.Path)
+		if err != nil {
+			return newConfigError("load tool config", target.Path, err)
+		}
+		config, unmanaged, result, err := build(target, existing)
+		if err != nil {
+			return err
+		}
+
+		upToDate, err := toolConfigUpToDate(config, target.Path, target.Tool)
+		if err != nil {
+			return newConfigError("load tool config", target.Path, err)
+		}
+		switch {
+		case upToDate:
+			targets[i].Result = "no changes"
+			if !check {
+				if err := recordManagedServers(target.Path, config, unmanaged); err != nil {
+					return newConfigError("save state", "", err)
+				}
+			}
+			continue
+		case check:
+			targets[i].Result = "would change: " + result
+			targets[i].Changed = true
+			continue
+		}
+
+		if err := autoBackupTool(w, target.Path, target.Tool); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target.Path), 0755); err != nil {
+			return newConfigError("create config directory", filepath.Dir(target.Path), err)
+		}
+		if err := writeToolConfig(config, target.Path, target.Tool); err != nil {
+			return newConfigError("write MCP config", target.Path, err)
+		}
+		if err := recordManagedServers(target.Path, config, unmanaged); err != nil {
+			return newConfigError("save state", "", err)
+		}
+		targets[i].Result = result
+		targets[i].Changed = true
+		targets[i].Servers = summarizeServerChanges(target.Tool, existing, config)
+	}
+	return nil
+}
+
+// targetServerChanges collects the server changes of every written target
+func targetServerChanges(targets []toolTarget) []serverChange {
+	var rows []serverChange
+	for _, target := range targets {
+		rows = append(rows, target.Servers...)
+	}
+	return rows
+}
+
+// changedTargets counts the targets whose config changed, or would with --check
+func changedTargets(targets []toolTarget) int {
+	count := 0
+	for _, target := range targets {
+		if target.Changed {
+			count++
+		}
+	}
+	return count
+}
+
+// printToolTargets prints a table of each tool's result, returning a silent
+// ExitError if any tool was skipped
+func printToolTargets(w io.Writer, targets []toolTarget) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOOL\tPATH\tRESULT")
+	fmt.Fprintln(tw, "----\t----\t------")
+	for _, target := range targets {
+		result := target.Result
+		if target.Skipped != "" {
+			result = "skipped: " + target.Skipped
+		}
+		path := target.Path
+		if path == "" {
+			path = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", target.Tool, path, result)
+	}
+	tw.Flush()
+
+	if skipped := len(targets) - writableTargets(targets); skipped > 0 {
+		fmt.Fprintf(w, "\n%s of %d skipped\n", pluralize(skipped, "tool"), len(targets))
+		return &ExitError{Code: exitCodeError}
+	}
+	return nil
+}
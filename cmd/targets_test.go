@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelectTools(t *testing.T) {
+	tools, err := selectTools([]string{"kiro", "cursor", "kiro", " q-cli"}, false)
+	if err != nil {
+		t.Fatalf("selectTools failed: %v", err)
+	}
+	if expected := []string{"kiro", "cursor", "q-cli"}; !reflect.DeepEqual(tools, expected) {
+		t.Errorf("Expected %v, got %v", expected, tools)
+	}
+
+	if _, err := selectTools([]string{"kiro"}, true); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected -t with --all-tools to be rejected, got %v", err)
+	}
+
+	t.Run("all tools", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		t.Chdir(t.TempDir())
+		if _, err := selectTools(nil, true); ExitCode(err) != exitCodeValidation {
+			t.Errorf("Expected an error without installed tools, got %v", err)
+		}
+		os.MkdirAll(filepath.Join(home, ".cursor"), 0755)
+		if tools, err := selectTools(nil, true); err != nil || !reflect.DeepEqual(tools, []string{"cursor"}) {
+			t.Errorf("Expected [cursor], got %v, %v", tools, err)
+		}
+	})
+}
+
+func TestWriteToolTargets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cursorPath := filepath.Join(home, ".cursor", "mcp.json")
+	os.MkdirAll(filepath.Dir(cursorPath), 0755)
+	writeToolConfig(MCPConfig{MCPServers: map[string]MCPServer{"stale": {Command: "stale"}}}, cursorPath, "")
+
+	services := map[string]Service{
+		"time":   {Command: "uvx mcp-server-time"},
+		"remote": {Command: "https://example.com/mcp", Labels: map[string]string{"mcp.headers": "{}"}},
+	}
+	mcpConfig := MCPConfig{MCPServers: map[string]MCPServer{
+		"time":   {Command: "uvx", Args: []string{"mcp-server-time"}},
+		"remote": {Type: "http", URL: "https://example.com/mcp"},
+	}}
+
+	targets := resolveToolTargets([]string{"cursor", "claude-desktop", "nope"})
+	checkToolTargetSupport(targets, services, map[string]string{})
+	if writableTargets(targets) != 1 {
+		t.Fatalf("Expected only cursor to be writable, got %+v", targets)
+	}
+
+	var out bytes.Buffer
+	err := writeToolTargets(&out, targets, false, func(target toolTarget, existing MCPConfig) (MCPConfig, []string, string, error) {
+		return buildToolConfig(mcpConfig, target, existing, services, nil, false)
+	})
+	if err != nil {
+		t.Fatalf("writeToolTargets failed: %v", err)
+	}
+	if err := printToolTargets(&out, targets); ExitCode(err) != exitCodeError {
+		t.Errorf("Expected a silent exit for the skipped tools, got %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"2 added, 0 updated, 1 removed\n",
+		"skipped: tool 'claude-desktop' does not support remote MCP servers",
+		"nope            -",
+		"skipped: unknown tool shortcut",
+		"2 tools of 3 skipped",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in:\n%s", want, output)
+		}
+	}
+
+	written, err := readMCPConfig(cursorPath)
+	if err != nil || !reflect.DeepEqual(written, mcpConfig) {
+		t.Errorf("Expected %+v to be written, got %+v, %v", mcpConfig, written, err)
+	}
+}
+
+func TestWriteToolTargetsCheck(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cursorPath := filepath.Join(home, ".cursor", "mcp.json")
+	os.MkdirAll(filepath.Dir(cursorPath), 0755)
+
+	mcpConfig := MCPConfig{MCPServers: map[string]MCPServer{"time": {Command: "uvx", Args: []string{"mcp-server-time"}}}}
+	build := func(target toolTarget, existing MCPConfig) (MCPConfig, []string, string, error) {
+		return buildToolConfig(mcpConfig, target, existing, map[string]Service{"time": {}}, nil, false)
+	}
+
+	// With check, nothing is written
+	targets := resolveToolTargets([]string{"cursor"})
+	if err := writeToolTargets(&bytes.Buffer{}, targets, true, build); err != nil {
+		t.Fatalf("writeToolTargets failed: %v", err)
+	}
+	if changedTargets(targets) != 1 || targets[0].Result != "would change: 1 added, 0 updated, 0 removed" {
+		t.Errorf("Expected cursor to be reported as changing, got %+v", targets)
+	}
+	if fileExists(cursorPath) {
+		t.Error("Expected --check to leave the config alone")
+	}
+
+	targets = resolveToolTargets([]string{"cursor"})
+	writeToolTargets(&bytes.Buffer{}, targets, false, build)
+	if changedTargets(targets) != 1 || !fileExists(cursorPath) {
+		t.Fatalf("Expected the config to be written, got %+v", targets)
+	}
+	info, _ := os.Stat(cursorPath)
+
+	// Writing the same servers again is a no-op, with or without check
+	for _, check := range []bool{false, true} {
+		targets = resolveToolTargets([]string{"cursor"})
+		if err := writeToolTargets(&bytes.Buffer{}, targets, check, build); err != nil {
+			t.Fatalf("writeToolTargets failed: %v", err)
+		}
+		if changedTargets(targets) != 0 || targets[0].Result != "no changes" {
+			t.Errorf("Expected no changes (check %v), got %+v", check, targets)
+		}
+	}
+	if after, _ := os.Stat(cursorPath); !after.ModTime().Equal(info.ModTime()) {
+		t.Error("Expected an up-to-date config not to be rewritten")
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"sort"
+)
+
+// serverTemplates is the built-in catalog of common MCP servers used by
+// 'mcp add --template'. Environment values are ${VAR} placeholders that are
+// resolved from the environment or .env file at deploy time.
+var serverTemplates = map[string]Service{
+	"aws-docs": {
+		Command:     "uvx awslabs.aws-documentation-mcp-server@latest",
+		Environment: map[string]string{"FASTMCP_LOG_LEVEL": "ERROR"},
+		Labels: map[string]string{
+			"mcp.profile":     "programming",
+			"mcp.description": "Search and read AWS documentation",
+		},
+	},
+	"brave": {
+		Image:       "mcp/brave-search",
+		Environment: map[string]string{"BRAVE_API_KEY": "${BRAVE_API_KEY}"},
+		Labels: map[string]string{
+			"mcp.profile":     "research",
+			"mcp.description": "Web and local search using the Brave Search API",
+		},
+	},
+	"fetch": {
+		Command: "uvx mcp-server-fetch",
+		Labels: map[string]string{
+			"mcp.description": "Fetch web pages and convert them to markdown",
+		},
+	},
+	"filesystem": {
+		Command: "npx -y @modelcontextprotocol/server-filesystem ${HOME}/projects",
+		Labels: map[string]string{
+			"mcp.profile":     "programming",
+			"mcp.description": "Read and write files in allowed directories",
+		},
+	},
+	"git": {
+		Command: "uvx mcp-server-git",
+		Labels: map[string]string{
+			"mcp.profile":     "programming",
+			"mcp.description": "Read, search, and manipulate git repositories",
+		},
+	},
+	"github": {
+		Command:     "npx -y @modelcontextprotocol/server-github",
+		Environment: map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "${GITHUB_PERSONAL_ACCESS_TOKEN}"},
+		Labels: map[string]string{
+			"mcp.profile":     "programming",
+			"mcp.description": "GitHub repository, issue, and pull request management",
+		},
+	},
+	"memory": {
+		Command: "npx -y @modelcontextprotocol/server-memory",
+		Labels: map[string]string{
+			"mcp.description": "Persistent knowledge graph memory",
+		},
+	},
+	"postgres": {
+		Command: "npx -y @modelcontextprotocol/server-postgres ${POSTGRES_URL}",
+		Labels: map[string]string{
+			"mcp.profile":     "database",
+			"mcp.description": "Read-only access to a PostgreSQL database",
+		},
+	},
+	"sequential-thinking": {
+		Command: "npx -y @modelcontextprotocol/server-sequential-thinking",
+		Labels: map[string]string{
+			"mcp.description": "Structured step-by-step problem solving",
+		},
+	},
+	"slack": {
+		Command: "npx -y @modelcontextprotocol/server-slack",
+		Environment: map[string]string{
+			"SLACK_BOT_TOKEN": "${SLACK_BOT_TOKEN}",
+			"SLACK_TEAM_ID":   "${SLACK_TEAM_ID}",
+		},
+		Labels: map[string]string{
+			"mcp.profile":     "communication",
+			"mcp.description": "Read and post messages in Slack workspaces",
+		},
+	},
+	"sqlite": {
+		Command: "uvx mcp-server-sqlite --db-path ${SQLITE_DB_PATH}",
+		Labels: map[string]string{
+			"mcp.profile":     "database",
+			"mcp.description": "Query and manage a SQLite database",
+		},
+	},
+	"time": {
+		Command: "uvx mcp-server-time",
+		Labels: map[string]string{
+			"mcp.description": "Current time and timezone conversion",
+		},
+	},
+}
+
+// getTemplateNames returns the names of the built-in server templates in sorted order
+func getTemplateNames() []string {
+	names := make([]string, 0, len(serverTemplates))
+	for name := range serverTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getTemplate returns a copy of a built-in server template
+func getTemplate(name string) (Service, bool) {
+	template, ok := serverTemplates[name]
+	if !ok {
+		return Service{}, false
+	}
+	return copyService(template), true
+}
+
+// copyService returns a deep copy of a service so callers can modify it
+func copyService(service Service) Service {
+	result := service
+	result.Environment = copyStringMap(service.Environment)
+	result.Labels = copyStringMap(service.Labels)
+	result.Volumes = append([]string(nil), service.Volumes...)
+	result.Ports = append([]string(nil), service.Ports...)
+	result.Entrypoint = append([]string(nil), service.Entrypoint...)
+	result.Profiles = append([]string(nil), service.Profiles...)
+	return result
+}
+
+// copyStringMap returns a copy of a string map, or nil for an empty map
+func copyStringMap(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
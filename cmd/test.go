@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	testAllServers bool
+	testTimeout    time.Duration
+)
+
+// testCmd represents the test command
+var testCmd = &cobra.Command{
+	Use:   "test [server...]",
+	Short: "Check that servers answer the MCP handshake",
+	Long: `Start each server (or connect to its URL, for remote servers), send an MCP
+initialize request, and report whether it responds, with its name, version,
+protocol version, and advertised capabilities.
+Servers are resolved exactly as 'mcp set' would write them, so OAuth tokens are
+acquired for remote servers that need them. With the -a flag, every server in
+the compose file is tested.
+Exits 1 if any server fails the handshake.`,
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !testAllServers {
+			return newValidationError("specify the servers to test, or use --all")
+		}
+
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		names := args
+		if testAllServers {
+			names = nil
+			for name := range config.Services {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+
+		servers, err := resolveServers(cmd.Context(), composeFile, config, names)
+		if err != nil {
+			return err
+		}
+
+		results := testServers(cmd.Context(), names, servers, timeoutFlag(cmd, testTimeout))
+		return reportTestResults(os.Stdout, results)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+	testCmd.Flags().BoolVarP(&testAllServers, "all", "a", false, "Test every server in the compose file")
+	testCmd.Flags().DurationVar(&testTimeout, "timeout", defaultMCPTimeout, "How long each server gets to respond")
+}
+
+// testResult is the outcome of one server's handshake
+type testResult struct {
+	Name   string
+	Result mcpInitializeResult
+	Err    error
+}
+
+// resolveServers converts the named compose services like 'mcp set' would
+func resolveServers(ctx context.Context, composePath string, config *ComposeConfig, names []string) (map[string]MCPServer, error) {
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return nil, newConfigError("load environment variables", composePath, err)
+	}
+
+	selected := make(map[string]Service)
+	for _, name := range names {
+		service, exists := config.Services[name]
+		if !exists {
+			return nil, newValidationError("server '%s' not found in %s", name, composePath)
+		}
+		selected[name] = service
+	}
+
+	resolved, err := convertToMCPConfig(ctx, selected, envVars)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.MCPServers, nil
+}
+
+// timeoutFlag returns the value of a command's --timeout flag if it was
+// given, or 0 for withMCPSession to use the server's own timeout
+func timeoutFlag(cmd *cobra.Command, timeout time.Duration) time.Duration {
+	if cmd.Flags().Changed("timeout") {
+		return timeout
+	}
+	return 0
+}
+
+// testServers runs the handshake against each server in turn
+func testServers(ctx context.Context, names []string, servers map[string]MCPServer, timeout time.Duration) []testResult {
+	results := make([]testResult, 0, len(names))
+	for _, name := range names {
+		result, err := handshake(ctx, servers[name], timeout)
+		results = append(results, testResult{Name: name, Result: result, Err: err})
+	}
+	return results
+}
+
+// handshake connects to a server, initializes a session, and closes it
+func handshake(ctx context.Context, server MCPServer, timeout time.Duration) (mcpInitializeResult, error) {
+	var result mcpInitializeResult
+	err := withMCPSession(ctx, server, timeout, func(ctx context.Context, client *mcpClient, info mcpInitializeResult) error {
+		result = info
+		return nil
+	})
+	return result, err
+}
+
+// reportTestResults prints each result and returns a silent ExitError if any failed
+func reportTestResults(w io.Writer, results []testResult) error {
+	failures := 0
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(w, "✗ %s: %v\n", r.Name, r.Err)
+			failures++
+			continue
+		}
+
+		info := r.Result.ServerInfo.Name
+		if r.Result.ServerInfo.Version != "" {
+			info += " " + r.Result.ServerInfo.Version
+		}
+		fmt.Fprintf(w, "✓ %s: %s (protocol %s)\n", r.Name, info, r.Result.ProtocolVersion)
+
+		capabilities := "none"
+		if names := r.Result.capabilityNames(); len(names) > 0 {
+			capabilities = strings.Join(names, ", ")
+		}
+		fmt.Fprintf(w, "    capabilities: %s\n", capabilities)
+	}
+
+	fmt.Fprintf(w, "\n%d passed, %d failed\n", len(results)-failures, failures)
+	if failures > 0 {
+		return &ExitError{Code: exitCodeError}
+	}
+	return nil
+}
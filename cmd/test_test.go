@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestReportTestResults(t *testing.T) {
+	var ok testResult
+	ok.Name = "time"
+	ok.Result.ProtocolVersion = "2025-06-18"
+	ok.Result.ServerInfo.Name = "mcp-time"
+	ok.Result.ServerInfo.Version = "1.2.0"
+
+	var out bytes.Buffer
+	err := reportTestResults(&out, []testResult{ok, {Name: "github", Err: errors.New("server exited: missing token")}})
+	if ExitCode(err) != exitCodeError {
+		t.Errorf("Expected exit code %d, got %v", exitCodeError, err)
+	}
+
+	expected := `✓ time: mcp-time 1.2.0 (protocol 2025-06-18)
+    capabilities: none
+✗ github: server exited: missing token
+
+1 passed, 1 failed
+`
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	if err := reportTestResults(&bytes.Buffer{}, []testResult{ok}); err != nil {
+		t.Errorf("Expected no error when all pass, got %v", err)
+	}
+}
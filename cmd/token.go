@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var tokenShowRaw bool
+
+// tokenCmd represents the token command
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage OAuth access tokens for remote servers",
+	Long: `Acquire, inspect, and clear the OAuth access tokens of remote servers that use
+the client credentials flow, independently of 'mcp set'.
+Tokens are saved in ~/.config/mcp/tokens.json, readable only by you, keyed by
+the server's client ID and token endpoint.`,
+}
+
+var tokenAcquireCmd = &cobra.Command{
+	Use:               "acquire <server>",
+	Short:             "Fetch and save an access token for a server",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oauthConfig, err := serverOAuthConfig(composeFile, args[0])
+		if err != nil {
+			return err
+		}
+		return acquireServerToken(cmd.Context(), os.Stdout, args[0], oauthConfig)
+	},
+}
+
+var tokenShowCmd = &cobra.Command{
+	Use:   "show <server>",
+	Short: "Show a server's saved access token",
+	Long: `Show a server's saved access token with its type, scopes, and expiry. Scopes and
+expiry are also read from the token itself when it is a JWT. The token is
+masked; use --raw to print only the token, for scripts.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oauthConfig, err := serverOAuthConfig(composeFile, args[0])
+		if err != nil {
+			return err
+		}
+		tokens, err := loadTokens()
+		if err != nil {
+			return newConfigError("load tokens", "", err)
+		}
+		token, ok := tokens[tokenKey(oauthConfig)]
+		if !ok {
+			return fmt.Errorf("no token saved for '%s' (run 'mcp token acquire %s')", args[0], args[0])
+		}
+		if tokenShowRaw {
+			fmt.Println(token.AccessToken)
+			return nil
+		}
+		printToken(os.Stdout, args[0], token, time.Now())
+		return nil
+	},
+}
+
+var tokenClearCmd = &cobra.Command{
+	Use:               "clear <server>",
+	Short:             "Delete a server's saved access token",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oauthConfig, err := serverOAuthConfig(composeFile, args[0])
+		if err != nil {
+			return err
+		}
+		tokens, err := loadTokens()
+		if err != nil {
+			return newConfigError("load tokens", "", err)
+		}
+		key := tokenKey(oauthConfig)
+		if _, ok := tokens[key]; !ok {
+			fmt.Printf("No token saved for '%s'\n", args[0])
+			return nil
+		}
+		delete(tokens, key)
+		if err := saveTokens(tokens); err != nil {
+			return newConfigError("save tokens", "", err)
+		}
+		fmt.Printf("Cleared token for '%s'\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenAcquireCmd)
+	tokenCmd.AddCommand(tokenShowCmd)
+	tokenCmd.AddCommand(tokenClearCmd)
+	tokenShowCmd.Flags().BoolVar(&tokenShowRaw, "raw", false, "Print only the access token")
+}
+
+// savedToken is an access token saved by 'mcp token acquire'
+type savedToken struct {
+	AccessToken string     `json:"access_token"`
+	TokenType   string     `json:"token_type,omitempty"`
+	Scope       string     `json:"scope,omitempty"`
+	AcquiredAt  time.Time  `json:"acquired_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// tokenKey identifies a server's tokens by the OAuth client they are issued to
+func tokenKey(config OAuthConfig) string {
+	return config.ClientID + "@" + config.TokenURL
+}
+
+// serverOAuthConfig resolves the OAuth client credentials of a remote server
+func serverOAuthConfig(composePath, name string) (OAuthConfig, error) {
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return OAuthConfig{}, newConfigError("load compose file", composePath, err)
+	}
+	service, exists := config.Services[name]
+	if !exists {
+		return OAuthConfig{}, newValidationError("server '%s' not found in %s", name, composePath)
+	}
+
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return OAuthConfig{}, newConfigError("load environment variables", composePath, err)
+	}
+	if !IsRemoteServerWithEnvExpansion(service, envVars) || UsesHeadersAuth(service) {
+		return OAuthConfig{}, newValidationError("server '%s' does not use OAuth (only remote servers with mcp.grant-type do)", name)
+	}
+	if err := ValidateRemoteServerAuth(name, service); err != nil {
+		return OAuthConfig{}, &ValidationError{Err: err}
+	}
+
+	// Service environment variables can be referenced by the OAuth labels
+	serviceEnvVars := make(map[string]string)
+	for k, v := range envVars {
+		serviceEnvVars[k] = v
+	}
+	for key, value := range service.Environment {
+		serviceEnvVars[key] = expandEnvVars(value, envVars)
+	}
+
+	oauthConfig, err := ExtractOAuthConfig(service, serviceEnvVars)
+	if err != nil {
+		return OAuthConfig{}, newValidationError("error extracting OAuth config for '%s': %w", name, err)
+	}
+	return oauthConfig, nil
+}
+
+// acquireServerToken requests a token and saves it
+func acquireServerToken(ctx context.Context, w io.Writer, name string, oauthConfig OAuthConfig) error {
+	fmt.Fprintf(os.Stderr, "acquiring access token for '%s'...\n", name)
+	resp, err := requestAccessToken(ctx, oauthConfig)
+	if err != nil {
+		return &AuthError{Server: name, Err: err}
+	}
+
+	now := time.Now()
+	token := savedToken{AccessToken: resp.AccessToken, TokenType: resp.TokenType, Scope: resp.Scope, AcquiredAt: now}
+	if resp.ExpiresIn > 0 {
+		expires := now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+		token.ExpiresAt = &expires
+	}
+
+	tokens, err := loadTokens()
+	if err != nil {
+		return newConfigError("load tokens", "", err)
+	}
+	tokens[tokenKey(oauthConfig)] = token
+	if err := saveTokens(tokens); err != nil {
+		return newConfigError("save tokens", "", err)
+	}
+
+	printToken(w, name, token, now)
+	return nil
+}
+
+// printToken describes a saved token with the token itself masked
+func printToken(w io.Writer, name string, token savedToken, now time.Time) {
+	claims := jwtClaims(token.AccessToken)
+
+	scope := token.Scope
+	if scope == "" {
+		scope = claimScope(claims)
+	}
+	if scope == "" {
+		scope = "-"
+	}
+
+	expires := token.ExpiresAt
+	if expires == nil {
+		if exp, ok := claims["exp"].(float64); ok {
+			t := time.Unix(int64(exp), 0)
+			expires = &t
+		}
+	}
+	expiry := "unknown"
+	if expires != nil {
+		if remaining := expires.Sub(now).Round(time.Second); remaining > 0 {
+			expiry = fmt.Sprintf("%s (in %s)", expires.Local().Format(time.DateTime), remaining)
+		} else {
+			expiry = fmt.Sprintf("%s (expired)", expires.Local().Format(time.DateTime))
+		}
+	}
+
+	tokenType := token.TokenType
+	if tokenType == "" {
+		tokenType = "-"
+	}
+
+	fmt.Fprintf(w, "Server:   %s\n", name)
+	fmt.Fprintf(w, "Token:    %s\n", maskToken(token.AccessToken))
+	fmt.Fprintf(w, "Type:     %s\n", tokenType)
+	fmt.Fprintf(w, "Scopes:   %s\n", scope)
+	fmt.Fprintf(w, "Acquired: %s\n", token.AcquiredAt.Local().Format(time.DateTime))
+	fmt.Fprintf(w, "Expires:  %s\n", expiry)
+}
+
+// maskToken shows only the start of a token, enough to tell tokens apart
+func maskToken(token string) string {
+	if len(token) <= 12 {
+		return strings.Repeat("*", len(token))
+	}
+	return token[:8] + "..." + fmt.Sprintf(" (%d characters)", len(token))
+}
+
+// jwtClaims decodes the payload of a JWT without verifying it, or returns
+// nil if the token isn't a JWT
+func jwtClaims(token string) map[string]any {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil
+	}
+	var claims map[string]any
+	if json.Unmarshal(payload, &claims) != nil {
+		return nil
+	}
+	return claims
+}
+
+// claimScope returns the scopes of a JWT's "scope" or "scp" claim as a
+// space-separated list
+func claimScope(claims map[string]any) string {
+	for _, name := range []string{"scope", "scp"} {
+		switch v := claims[name].(type) {
+		case string:
+			return v
+		case []any:
+			var scopes []string
+			for _, s := range v {
+				if str, ok := s.(string); ok {
+					scopes = append(scopes, str)
+				}
+			}
+			return strings.Join(scopes, " ")
+		}
+	}
+	return ""
+}
+
+// getTokensPath returns the path to the saved tokens file
+func getTokensPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "tokens.json"), nil
+}
+
+// loadTokens reads the saved tokens
+// Returns no tokens if the file doesn't exist
+func loadTokens() (map[string]savedToken, error) {
+	tokens := make(map[string]savedToken)
+
+	path, err := getTokensPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tokens, nil
+		}
+		return nil, fmt.Errorf("error reading tokens file: %w", err)
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("error parsing tokens file %s: %w", path, err)
+	}
+	return tokens, nil
+}
+
+// saveTokens writes the saved tokens, readable only by the user
+func saveTokens(tokens map[string]savedToken) error {
+	path, err := getTokensPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, 0600)
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerTokenLifecycle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "my-client" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"abcdefghijklmnopqrstuvwxyz","token_type":"Bearer","expires_in":3600,"scope":"read write"}`))
+	}))
+	defer server.Close()
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(fmt.Sprintf(`services:
+  api:
+    command: https://api.example.com/mcp
+    labels:
+      mcp.grant-type: client_credentials
+      mcp.token-endpoint: %s
+      mcp.client-id: my-client
+      mcp.client-secret: secret
+  time:
+    command: uvx mcp-server-time
+`, server.URL)), 0644)
+
+	oauthConfig, err := serverOAuthConfig(composePath, "api")
+	if err != nil {
+		t.Fatalf("serverOAuthConfig failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := acquireServerToken(context.Background(), &out, "api", oauthConfig); err != nil {
+		t.Fatalf("acquireServerToken failed: %v", err)
+	}
+	for _, want := range []string{"Token:    abcdefgh... (26 characters)", "Type:     Bearer", "Scopes:   read write", "(in 1h0m0s)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "abcdefghijklmnopqrstuvwxyz") {
+		t.Error("The token must be masked")
+	}
+
+	tokens, err := loadTokens()
+	if err != nil || tokens[tokenKey(oauthConfig)].AccessToken != "abcdefghijklmnopqrstuvwxyz" {
+		t.Fatalf("Expected the token to be saved, got %v (%v)", tokens, err)
+	}
+	path, _ := getTokensPath()
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the tokens file to be private, got %v", info.Mode().Perm())
+	}
+
+	if _, err := serverOAuthConfig(composePath, "time"); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error for a local server, got %v", err)
+	}
+
+	oauthConfig.ClientID = "wrong"
+	if err := acquireServerToken(context.Background(), &out, "api", oauthConfig); ExitCode(err) != exitCodeAuth {
+		t.Errorf("Expected an auth error, got %v", err)
+	}
+}
+
+func TestPrintTokenJWTClaims(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"scp":["mcp:read","mcp:tools"],"exp":%d}`, now.Add(-time.Minute).Unix())))
+	token := savedToken{AccessToken: "eyJhbGciOiJub25lIn0." + payload + ".sig", AcquiredAt: now.Add(-time.Hour)}
+
+	var out bytes.Buffer
+	printToken(&out, "api", token, now)
+	for _, want := range []string{"Scopes:   mcp:read mcp:tools", "(expired)", "Type:     -"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	printToken(&out, "api", savedToken{AccessToken: "opaque", AcquiredAt: now}, now)
+	if !strings.Contains(out.String(), "Token:    ******") || !strings.Contains(out.String(), "Expires:  unknown") {
+		t.Errorf("Unexpected output for an opaque token:\n%s", out.String())
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -20,7 +21,11 @@ func TestToolShortcutPaths(t *testing.T) {
 
 	for _, tool := range tools {
 		// Test path from getPlatformToolPath
-		path := getPlatformToolPath(tool)
+		path, err := getPlatformToolPath(tool)
+		if err != nil {
+			t.Errorf("Tool shortcut '%s' returned error: %v", tool, err)
+			continue
+		}
 		if path == "" {
 			t.Errorf("Tool shortcut '%s' returned empty path", tool)
 			continue
@@ -182,7 +187,10 @@ func TestMCPConfigurationFormatConsistency(t *testing.T) {
 	}
 
 	// Generate MCP configuration
-	mcpConfig := convertToMCPConfig(servers, envVars)
+	mcpConfig, err := convertToMCPConfig(context.Background(), servers, envVars)
+	if err != nil {
+		t.Fatalf("convertToMCPConfig failed: %v", err)
+	}
 
 	// Verify structure is consistent regardless of tool
 	if len(mcpConfig.MCPServers) != 2 {
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	toolsJSON    bool
+	toolsTimeout time.Duration
+)
+
+// toolsCmd represents the tools command
+var toolsCmd = &cobra.Command{
+	Use:   "tools <server>",
+	Short: "List the tools a server exposes",
+	Long: `Connect to a server from the compose file and list the tools it exposes, with
+their descriptions and a summary of their input arguments (* marks required ones).
+Local servers are started and remote servers are connected to exactly as
+'mcp set' would configure them, including acquiring OAuth tokens.
+Use --json to print the tools/list response for scripts.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+
+		servers, err := resolveServers(cmd.Context(), composeFile, config, args)
+		if err != nil {
+			return err
+		}
+
+		var tools []mcpTool
+		err = withMCPSession(cmd.Context(), servers[args[0]], timeoutFlag(cmd, toolsTimeout), func(ctx context.Context, client *mcpClient, info mcpInitializeResult) error {
+			if _, ok := info.Capabilities["tools"]; !ok {
+				return nil
+			}
+			tools, err = client.ListTools(ctx)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		if toolsJSON {
+			if tools == nil {
+				tools = []mcpTool{}
+			}
+			data, err := json.MarshalIndent(map[string]any{"tools": tools}, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		displayTools(os.Stdout, tools)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(toolsCmd)
+	toolsCmd.Flags().BoolVar(&toolsJSON, "json", false, "Print the tools as JSON")
+	toolsCmd.Flags().DurationVar(&toolsTimeout, "timeout", defaultMCPTimeout, "How long the server gets to respond")
+}
+
+// displayTools prints tools as a table sorted by name
+func displayTools(w io.Writer, tools []mcpTool) {
+	if len(tools) == 0 {
+		fmt.Fprintln(w, "No tools found")
+		return
+	}
+
+	sorted := append([]mcpTool(nil), tools...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tARGUMENTS\tDESCRIPTION")
+	fmt.Fprintln(tw, "----\t---------\t-----------")
+	for _, tool := range sorted {
+		// Only the first line of multi-line descriptions fits in a table
+		desc := strings.TrimSpace(tool.Description)
+		if i := strings.IndexByte(desc, '\n'); i >= 0 {
+			desc = strings.TrimSpace(desc[:i])
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", tool.Name, schemaSummary(tool.InputSchema), TruncateDescription(desc, 60))
+	}
+	tw.Flush()
+}
+
+// schemaSummary describes a JSON Schema object's properties as
+// "name*: type, ...", marking required properties with *
+func schemaSummary(schema map[string]any) string {
+	properties, _ := schema["properties"].(map[string]any)
+	if len(properties) == 0 {
+		return "-"
+	}
+
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]any); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		label := name
+		if required[name] {
+			label += "*"
+		}
+		property, _ := properties[name].(map[string]any)
+		parts = append(parts, label+": "+schemaType(property))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// schemaType returns the type of a JSON Schema property, joining union types with |
+func schemaType(property map[string]any) string {
+	switch t := property["type"].(type) {
+	case string:
+		return t
+	case []any:
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		if len(types) > 0 {
+			return strings.Join(types, "|")
+		}
+	}
+	return "any"
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSchemaSummary(t *testing.T) {
+	var schema map[string]any
+	json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string"},
+			"limit": {"type": "integer"},
+			"tags": {"type": ["array", "null"]},
+			"options": {}
+		},
+		"required": ["path"]
+	}`), &schema)
+
+	expected := "limit: integer, options: any, path*: string, tags: array|null"
+	if summary := schemaSummary(schema); summary != expected {
+		t.Errorf("Expected %q, got %q", expected, summary)
+	}
+	if summary := schemaSummary(map[string]any{"type": "object"}); summary != "-" {
+		t.Errorf("Expected - for no arguments, got %q", summary)
+	}
+}
+
+func TestDisplayTools(t *testing.T) {
+	var out bytes.Buffer
+	displayTools(&out, []mcpTool{
+		{Name: "search", Description: "Search issues\nSupports GitHub search syntax.", InputSchema: map[string]any{
+			"properties": map[string]any{"query": map[string]any{"type": "string"}},
+			"required":   []any{"query"},
+		}},
+		{Name: "get_me", Description: "Get the authenticated user"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a header and 2 rows, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[2], "get_me") || !strings.Contains(lines[3], "query*: string") {
+		t.Errorf("Expected sorted rows with argument summaries, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Supports GitHub") {
+		t.Errorf("Expected only the first line of descriptions, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	displayTools(&out, nil)
+	if out.String() != "No tools found\n" {
+		t.Errorf("Unexpected output for no tools: %q", out.String())
+	}
+}
@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -10,10 +15,70 @@ import (
 // ComposeConfig represents the structure of a docker-compose.yml file
 type ComposeConfig struct {
 	Services map[string]Service `yaml:"services"`
+	Include  []ComposeInclude   `yaml:"include"`
+
+	// defaults are the labels of the top-level x-mcp block
+	defaults map[string]string
+}
+
+// UnmarshalYAML decodes a compose file and the settings of its top-level
+// x-mcp block, which loadComposeFile applies once extends are resolved
+func (c *ComposeConfig) UnmarshalYAML(value *yaml.Node) error {
+	// plainConfig has ComposeConfig's fields without this method
+	type plainConfig ComposeConfig
+	if err := value.Decode((*plainConfig)(c)); err != nil {
+		return err
+	}
+
+	block := mappingValue(value, xMCPKey)
+	if block == nil {
+		return nil
+	}
+	var err error
+	c.defaults, err = xMCPLabels(block)
+	return err
+}
+
+// applyXMCPDefaults sets the labels of a top-level x-mcp block on a service
+// that doesn't set them itself
+func applyXMCPDefaults(service *Service, defaults map[string]string) {
+	for label, v := range defaults {
+		if _, ok := service.Labels[label]; !ok {
+			setServiceLabel(service, label, v)
+		}
+	}
 }
 
-// loadComposeFile loads and parses the compose file
+// loadComposeFile loads and parses the compose file. For the compose file
+// given with -f, the override files given with further -f flags are merged
+// on it in order, as Docker Compose does: a service in a later file
+// overrides the same service in earlier ones field by field, with its
+// environment and labels merged by key.
 func loadComposeFile(path string) (*ComposeConfig, error) {
+	config, err := parseComposeFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if path == composeFile {
+		for _, overridePath := range composeOverrideFiles {
+			override, err := parseComposeFile(overridePath, nil)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", overridePath, err)
+			}
+			mergeComposeConfigs(config, override)
+		}
+	}
+
+	for name, service := range config.Services {
+		applyXMCPDefaults(&service, config.defaults)
+		config.Services[name] = service
+	}
+	return config, nil
+}
+
+// parseComposeFile parses one compose file and resolves its includes and
+// extends; including lists the files including it, see resolveIncludes
+func parseComposeFile(path string, including []string) (*ComposeConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -23,11 +88,33 @@ func loadComposeFile(path string) (*ComposeConfig, error) {
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
-
+	if err := resolveIncludes(&config, path, including); err != nil {
+		return nil, err
+	}
+	if err := resolveExtends(&config, path); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
 
+// mergeComposeConfigs merges the services and top-level x-mcp settings of an
+// override file into config
+func mergeComposeConfigs(config, override *ComposeConfig) {
+	if config.Services == nil && len(override.Services) > 0 {
+		config.Services = make(map[string]Service, len(override.Services))
+	}
+	for name, service := range override.Services {
+		if base, ok := config.Services[name]; ok {
+			service = mergeServices(base, service)
+		}
+		config.Services[name] = service
+	}
+	config.defaults = mergeStringMaps(config.defaults, override.defaults)
+}
+
 // filterServers filters servers based on profile
+// A comma-separated profile such as "work,research" selects the union of the
+// profiles; a server in several of them is included once, as defined.
 func filterServers(config *ComposeConfig, profile string, all bool) map[string]Service {
 	result := make(map[string]Service)
 
@@ -36,26 +123,18 @@ func filterServers(config *ComposeConfig, profile string, all bool) map[string]S
 		return config.Services
 	}
 
-	for name, service := range config.Services {
-		// Check if this is a default server (no profile or has "default" in profile)
-		isDefault := false
-		profileStr, hasProfile := service.Labels["mcp.profile"]
-
-		if !hasProfile {
-			// No profile specified, consider it default
-			isDefault = true
-		} else {
-			// Check if it has "default" in its profile
-			profiles := strings.Split(profileStr, ",")
-			for _, p := range profiles {
-				if strings.TrimSpace(p) == "default" {
-					isDefault = true
-					break
-				}
-			}
+	var profiles []string
+	for _, p := range strings.Split(profile, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
 		}
+	}
 
-		if profile == "" {
+	for name, service := range config.Services {
+		// Default servers have no profile or have "default" in their profiles
+		isDefault := IsDefaultServer(service)
+
+		if len(profiles) == 0 {
 			// Only include default servers when no specific profile is requested
 			if isDefault {
 				result[name] = service
@@ -69,14 +148,11 @@ func filterServers(config *ComposeConfig, profile string, all bool) map[string]S
 				continue
 			}
 
-			// Check if server has the requested profile
-			if hasProfile {
-				profiles := strings.Split(profileStr, ",")
-				for _, p := range profiles {
-					if strings.TrimSpace(p) == profile {
-						result[name] = service
-						break
-					}
+			// Check if server has one of the requested profiles
+			for _, p := range GetProfiles(service) {
+				if slices.Contains(profiles, p) {
+					result[name] = service
+					break
 				}
 			}
 		}
@@ -92,11 +168,199 @@ type Service struct {
 	Environment map[string]string `yaml:"environment"`
 	Labels      map[string]string `yaml:"labels"`
 	Volumes     []string          `yaml:"volumes"`
+	Ports       []string          `yaml:"ports"`
+	Entrypoint  []string          `yaml:"entrypoint"`
+	WorkingDir  string            `yaml:"working_dir"`
+	User        string            `yaml:"user"`
+	Profiles    []string          `yaml:"profiles"`
+	Extends     *ServiceExtends   `yaml:"extends,omitempty"`
+}
+
+// UnmarshalYAML decodes a service, accepting the list form Docker Compose
+// allows for environment and labels as well as the map form, and an
+// entrypoint given as a string as well as a list
+func (s *Service) UnmarshalYAML(value *yaml.Node) error {
+	// plainService has Service's fields without this method
+	type plainService Service
+	value, err := resolveMergeKeys(value)
+	if err != nil {
+		return err
+	}
+	if value.Kind != yaml.MappingNode {
+		return value.Decode((*plainService)(s))
+	}
+
+	rest := *value
+	rest.Content = nil
+	var environment, labels, entrypoint, block *yaml.Node
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		switch value.Content[i].Value {
+		case "environment":
+			environment = value.Content[i+1]
+		case "entrypoint":
+			entrypoint = value.Content[i+1]
+		case "labels":
+			labels = value.Content[i+1]
+		case xMCPKey:
+			block = value.Content[i+1]
+		default:
+			rest.Content = append(rest.Content, value.Content[i], value.Content[i+1])
+		}
+	}
+	if err := rest.Decode((*plainService)(s)); err != nil {
+		return err
+	}
+
+	// A bare variable takes its value from the host, written as ${KEY} so it
+	// is resolved like any other variable; a bare label is empty
+	if environment != nil {
+		s.Environment, err = decodeComposeMap(environment, "environment", func(key string) string { return "${" + key + "}" })
+		if err != nil {
+			return err
+		}
+	}
+	if labels != nil {
+		s.Labels, err = decodeComposeMap(labels, "labels", func(string) string { return "" })
+		if err != nil {
+			return err
+		}
+	}
+	if entrypoint != nil {
+		if entrypoint.Kind == yaml.SequenceNode {
+			err = entrypoint.Decode(&s.Entrypoint)
+		} else {
+			var line string
+			err = entrypoint.Decode(&line)
+			s.Entrypoint = strings.Fields(line)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Settings in x-mcp take precedence over the labels they stand for
+	if block != nil {
+		settings, err := xMCPLabels(block)
+		if err != nil {
+			return err
+		}
+		for label, v := range settings {
+			setServiceLabel(s, label, v)
+		}
+	}
+	return nil
+}
+
+// xMCPKey is the compose extension holding MCP settings, on a service or at
+// the top level for every service
+const xMCPKey = "x-mcp"
+
+// xMCPLabelPrefixes maps the x-mcp keys holding a map to the label family
+// their entries stand for
+var xMCPLabelPrefixes = map[string]string{
+	"headers": "mcp.header.",
+	"env-doc": envDocLabelPrefix,
+	"inputs":  inputLabelPrefix,
+}
+
+// xMCPLabels translates an x-mcp block into the mcp.* labels it stands for:
+// description becomes mcp.description, lists such as tools: [kiro, q-cli]
+// are joined with commas (docker-args with spaces, one argument per item),
+// headers, env-doc, and inputs become the label families they name, and the
+// keys of an auth block are flattened.
+func xMCPLabels(node *yaml.Node) (map[string]string, error) {
+	node, err := resolveMergeKeys(node)
+	if err != nil {
+		return nil, err
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: %s must be a mapping", node.Line, xMCPKey)
+	}
+
+	labels := make(map[string]string)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		switch {
+		case key == "auth":
+			auth, err := xMCPLabels(value)
+			if err != nil {
+				return nil, err
+			}
+			maps.Copy(labels, auth)
+		case xMCPLabelPrefixes[key] != "":
+			var entries map[string]string
+			if err := value.Decode(&entries); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", xMCPKey, key, err)
+			}
+			for name, v := range entries {
+				labels[xMCPLabelPrefixes[key]+name] = v
+			}
+		case key == "docker-args" && value.Kind == yaml.SequenceNode:
+			// Each item is one argument, quoted to be split back out
+			var args []string
+			if err := value.Decode(&args); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", xMCPKey, key, err)
+			}
+			for i, arg := range args {
+				args[i] = shellQuote(arg)
+			}
+			labels[dockerArgsLabel] = strings.Join(args, " ")
+		case value.Kind == yaml.SequenceNode:
+			var items []string
+			if err := value.Decode(&items); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", xMCPKey, key, err)
+			}
+			labels["mcp."+key] = strings.Join(items, ",")
+		default:
+			var v string
+			if err := value.Decode(&v); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", xMCPKey, key, err)
+			}
+			labels["mcp."+key] = v
+		}
+	}
+	return labels, nil
+}
+
+// decodeComposeMap decodes environment or labels in either form: a map, or a
+// list of KEY=value entries, where bare gives the value of a bare KEY
+func decodeComposeMap(node *yaml.Node, field string, bare func(key string) string) (map[string]string, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.SequenceNode {
+		var m map[string]string
+		err := node.Decode(&m)
+		return m, err
+	}
+
+	m := make(map[string]string, len(node.Content))
+	for _, item := range node.Content {
+		if item.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: %s entries must be KEY=value strings", item.Line, field)
+		}
+		key, value, ok := strings.Cut(item.Value, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("line %d: %s entry %q has no name", item.Line, field, item.Value)
+		}
+		if !ok {
+			value = bare(key)
+		}
+		m[key] = value
+	}
+	return m, nil
 }
 
 // MCPConfig represents the MCP JSON configuration format
 type MCPConfig struct {
 	MCPServers map[string]MCPServer `json:"mcpServers"`
+
+	// Inputs are the values VS Code prompts for, see applyConfigInputs
+	Inputs []ConfigInput `json:"inputs,omitempty"`
 }
 
 // MCPServer represents a single MCP server in the JSON configuration
@@ -110,12 +374,49 @@ type MCPServer struct {
 	Type    string            `json:"type,omitempty"`
 	URL     string            `json:"url,omitempty"`
 	Headers map[string]string `json:"headers,omitempty"`
+
+	// Disabled is the native flag some tools use to turn a server off
+	Disabled bool `json:"disabled,omitempty"`
+
+	// AutoApprove lists the tools run without asking first, or "*" for all;
+	// tools such as Cline and Roo Code call it AlwaysAllow
+	AutoApprove []string `json:"autoApprove,omitempty"`
+	AlwaysAllow []string `json:"alwaysAllow,omitempty"`
+
+	// Timeout bounds each request, in the tool's unit: milliseconds for
+	// Amazon Q, seconds for tools such as Cline
+	Timeout int `json:"timeout,omitempty"`
+
+	// RequestTimeout and InitTimeout come from the mcp.timeout and
+	// mcp.init-timeout labels, for the CLI's own client and to set Timeout
+	RequestTimeout time.Duration `json:"-"`
+	InitTimeout    time.Duration `json:"-"`
 }
 
 // CLIConfig represents the structure of the MCP CLI config file
 type CLIConfig struct {
-	Tool          string `json:"tool,omitempty"`
-	ContainerTool string `json:"container-tool,omitempty"`
+	Tool          string                `json:"tool,omitempty"`
+	ContainerTool string                `json:"container-tool,omitempty"`
+	ComposeFile   string                `json:"compose-file,omitempty"`
+	CacheTTL      string                `json:"cache-ttl,omitempty"`
+	RegistryURL   string                `json:"registry-url,omitempty"`
+	AutoBackup    bool                  `json:"auto-backup,omitempty"`
+	LintDisable   string                `json:"lint-disable,omitempty"`
+	Merge         bool                  `json:"merge,omitempty"`
+	Pull          bool                  `json:"pull,omitempty"`
+	Tools         map[string]CustomTool `json:"tools,omitempty"`
+}
+
+// CustomTool represents a user-defined tool shortcut in the MCP CLI config file
+type CustomTool struct {
+	Path                string `json:"path"`
+	ProjectPath         string `json:"projectPath,omitempty"`
+	Format              string `json:"format,omitempty"`
+	SupportsRemote      bool   `json:"supportsRemote,omitempty"`
+	SupportsDisabled    bool   `json:"supportsDisabled,omitempty"`
+	SupportsAutoApprove bool   `json:"supportsAutoApprove,omitempty"`
+	AutoApproveField    string `json:"autoApproveField,omitempty"`
+	TimeoutUnit         string `json:"timeoutUnit,omitempty"`
 }
 
 // OAuthConfig represents OAuth 2.0 client credentials configuration
@@ -131,11 +432,12 @@ type OAuthResponse struct {
 	AccessToken string `json:"access_token"`
 	TokenType   string `json:"token_type"`
 	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
 }
 
 // ServerStatus represents the status of a server in a specific tool
 type ServerStatus struct {
-	Status      string   // "configured", "not-configured", "different", "unknown"
+	Status      string   // "configured", "not-configured", "different", "disabled", "unknown"
 	Tool        string   // tool shortcut name
 	Differences []string // list of differences if status is "different"
 	ConfigPath  string   // path to the config file
@@ -148,6 +450,7 @@ type ToolStatus struct {
 	ConfigPath  string
 	Exists      bool
 	ServerCount int
+	Error       string // error message if the config could not be read
 }
 
 // ToolConfig represents a tool's configuration with metadata
@@ -168,6 +471,408 @@ func GetDescription(service Service) string {
 	return ""
 }
 
+// GetProfiles parses the comma-separated "mcp.profile" label of a service,
+// or its Docker Compose profiles list if it has no label.
+// Entries are trimmed, and empty or repeated entries are dropped, so values
+// like "default , ,programming," yield only real profile names.
+// Returns nil if neither is set or they have no entries.
+func GetProfiles(service Service) []string {
+	entries := service.Profiles
+	if label, ok := service.Labels["mcp.profile"]; ok {
+		entries = strings.Split(label, ",")
+	}
+
+	var profiles []string
+	seen := make(map[string]bool)
+	for _, p := range entries {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// IsDefaultServer reports whether a service belongs to the default profile,
+// either because it has no profiles or because "default" is one of them
+func IsDefaultServer(service Service) bool {
+	profiles := GetProfiles(service)
+	if len(profiles) == 0 {
+		return true
+	}
+	for _, p := range profiles {
+		if p == "default" {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateProfileLabel checks the "mcp.profile" label of a service for
+// empty entries, repeated profiles, and profile names containing whitespace.
+// The error names the offending service and label value.
+func ValidateProfileLabel(name string, service Service) error {
+	label, ok := service.Labels["mcp.profile"]
+	if !ok {
+		return nil
+	}
+
+	var problems []string
+	seen := make(map[string]bool)
+	empty := false
+	for _, p := range strings.Split(label, ",") {
+		p = strings.TrimSpace(p)
+		switch {
+		case p == "":
+			empty = true
+		case strings.ContainsAny(p, " \t\n"):
+			problems = append(problems, fmt.Sprintf("profile '%s' contains whitespace", p))
+		case seen[p]:
+			problems = append(problems, fmt.Sprintf("profile '%s' is listed more than once", p))
+		}
+		seen[p] = true
+	}
+	if empty {
+		problems = append([]string{"empty profile entry"}, problems...)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return newValidationError("service '%s': invalid mcp.profile label %q: %s", name, label, strings.Join(problems, ", "))
+}
+
+// Values of the "mcp.env-mode" label: whether ${VARS} in a server's
+// environment and arguments are written with their values, or left for the
+// client to resolve at runtime
+const (
+	envModeExpand      = "expand"
+	envModePassthrough = "passthrough"
+)
+
+// envModeOverride, when set (by 'mcp set --expand-env'), applies to every
+// server instead of its "mcp.env-mode" label
+var envModeOverride string
+
+// GetEnvMode returns the environment mode of a service: the override if set,
+// else its "mcp.env-mode" label, else expand.
+func GetEnvMode(service Service) string {
+	if envModeOverride != "" {
+		return envModeOverride
+	}
+	if strings.TrimSpace(service.Labels["mcp.env-mode"]) == envModePassthrough {
+		return envModePassthrough
+	}
+	return envModeExpand
+}
+
+// ValidateEnvModeLabel checks that the "mcp.env-mode" label of a service is
+// expand or passthrough.
+func ValidateEnvModeLabel(name string, service Service) error {
+	mode, ok := service.Labels["mcp.env-mode"]
+	if !ok {
+		return nil
+	}
+	switch strings.TrimSpace(mode) {
+	case envModeExpand, envModePassthrough:
+		return nil
+	}
+	return newValidationError("service '%s': invalid mcp.env-mode label %q: must be %s or %s", name, mode, envModeExpand, envModePassthrough)
+}
+
+// GetServiceTools returns the tools named in the "mcp.tools" label of a
+// service, or nil if it has none and is written to every tool
+func GetServiceTools(service Service) []string {
+	var tools []string
+	for _, tool := range strings.Split(service.Labels["mcp.tools"], ",") {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// ServiceForTool reports whether a service is written to a tool's config per
+// its "mcp.tools" label. Every service is written when there is no tool, e.g.
+// with --print.
+func ServiceForTool(service Service, tool string) bool {
+	tools := GetServiceTools(service)
+	return tool == "" || len(tools) == 0 || slices.Contains(tools, tool)
+}
+
+// IsServiceDisabled reports whether a service is turned off with an
+// "mcp.disabled" label, keeping it in the compose file but out of tool configs
+func IsServiceDisabled(service Service) bool {
+	disabled, err := strconv.ParseBool(strings.TrimSpace(service.Labels["mcp.disabled"]))
+	return err == nil && disabled
+}
+
+// enabledServers returns the servers that aren't turned off with an
+// "mcp.disabled" label
+func enabledServers(servers map[string]Service) map[string]Service {
+	result := make(map[string]Service, len(servers))
+	for name, service := range servers {
+		if !IsServiceDisabled(service) {
+			result[name] = service
+		}
+	}
+	return result
+}
+
+// ValidateDisabledLabel checks that the "mcp.disabled" label of a service is
+// a boolean.
+func ValidateDisabledLabel(name string, service Service) error {
+	label, ok := service.Labels["mcp.disabled"]
+	if !ok {
+		return nil
+	}
+	if _, err := strconv.ParseBool(strings.TrimSpace(label)); err != nil {
+		return newValidationError("service '%s': invalid mcp.disabled label %q: must be true or false", name, label)
+	}
+	return nil
+}
+
+// dockerArgsLabel holds extra arguments for the container tool's run command
+const dockerArgsLabel = "mcp.docker-args"
+
+// GetDockerArgs returns the arguments in the "mcp.docker-args" label of a
+// service, split as a shell would, e.g. "--network host --add-host foo:1.2.3.4"
+func GetDockerArgs(service Service) ([]string, error) {
+	label := service.Labels[dockerArgsLabel]
+	if label == "" {
+		return nil, nil
+	}
+	args, err := shellFields(label)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s label: %w", dockerArgsLabel, err)
+	}
+	return args, nil
+}
+
+// ValidateDockerArgsLabel checks that the "mcp.docker-args" label of a
+// service, if set, is on an image-based service and splits into arguments
+func ValidateDockerArgsLabel(name string, service Service) error {
+	if _, ok := service.Labels[dockerArgsLabel]; !ok {
+		return nil
+	}
+	if service.Image == "" && service.Extends == nil {
+		return newValidationError("service '%s': %s is only used by image-based servers", name, dockerArgsLabel)
+	}
+	if _, err := GetDockerArgs(service); err != nil {
+		return newValidationError("service '%s': %v", name, err)
+	}
+	return nil
+}
+
+// shellFields splits s into arguments as a POSIX shell would, honoring single
+// and double quotes and backslash escapes but expanding nothing
+func shellFields(s string) ([]string, error) {
+	var (
+		fields  []string
+		field   strings.Builder
+		inField bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			// Within double quotes, a backslash only escapes \, ", `, and $
+			if quote == '"' && !strings.ContainsRune("\\\"`$", r) {
+				field.WriteRune('\\')
+			}
+			field.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inField = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				field.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inField = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in %q", s)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
+
+// GetServiceURL returns the expanded "mcp.url" label of an image-based
+// service, the endpoint its container serves MCP on, or "" if it has none
+func GetServiceURL(service Service, envVars map[string]string) string {
+	if service.Image == "" {
+		return ""
+	}
+	return expandEnvVars(service.Labels["mcp.url"], envVars)
+}
+
+// ValidateURLLabel checks that the "mcp.url" label of a service, if set, is
+// on an image-based service and is an http:// or https:// URL
+func ValidateURLLabel(name string, service Service, envVars map[string]string) error {
+	label, ok := service.Labels["mcp.url"]
+	if !ok {
+		return nil
+	}
+	if service.Image == "" {
+		return newValidationError("service '%s': mcp.url is only used by image-based servers (set command to the URL for a remote server)", name)
+	}
+	url := expandEnvVars(label, envVars)
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return newValidationError("service '%s': invalid mcp.url label %q: must be an http:// or https:// URL", name, label)
+	}
+	return nil
+}
+
+// ValidateTransportLabel checks that the "mcp.transport" label of a service,
+// if set, is stdio or http and matches its command
+func ValidateTransportLabel(name string, service Service, envVars map[string]string) error {
+	label, ok := service.Labels["mcp.transport"]
+	if !ok {
+		return nil
+	}
+	remote := IsRemoteServerWithEnvExpansion(service, envVars) || GetServiceURL(service, envVars) != ""
+	switch strings.TrimSpace(label) {
+	case "stdio":
+		if remote {
+			return newValidationError("service '%s': mcp.transport is stdio, but the command is a URL", name)
+		}
+	case "http":
+		if !remote {
+			return newValidationError("service '%s': mcp.transport is http, but neither the command nor mcp.url is an http:// or https:// URL", name)
+		}
+	default:
+		return newValidationError("service '%s': invalid mcp.transport label %q: must be stdio or http", name, label)
+	}
+	return nil
+}
+
+// GetAutoApprove returns the tools named in the "mcp.auto-approve" label of
+// a service, or ["*"] if it approves all of them
+func GetAutoApprove(service Service) []string {
+	var tools []string
+	for _, tool := range strings.Split(service.Labels["mcp.auto-approve"], ",") {
+		tool = strings.TrimSpace(tool)
+		if tool == "*" {
+			return []string{"*"}
+		}
+		if tool != "" && !slices.Contains(tools, tool) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// Labels bounding how long a server may take to respond to each request,
+// and to start and initialize
+const (
+	timeoutLabel     = "mcp.timeout"
+	initTimeoutLabel = "mcp.init-timeout"
+)
+
+// parseTimeoutLabel parses a timeout label: a duration such as 90s or 2m,
+// or a number of seconds
+func parseTimeoutLabel(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.Atoi(value); err == nil {
+		value = fmt.Sprintf("%ds", seconds)
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("must be a positive duration such as 90s or 2m, or a number of seconds")
+	}
+	return timeout, nil
+}
+
+// GetTimeout returns the duration of a timeout label of a service, or 0 if
+// it isn't set or is invalid
+func GetTimeout(service Service, label string) time.Duration {
+	value, ok := service.Labels[label]
+	if !ok {
+		return 0
+	}
+	timeout, _ := parseTimeoutLabel(value)
+	return timeout
+}
+
+// ValidateTimeoutLabel checks a timeout label of a service ("mcp.timeout"
+// or "mcp.init-timeout").
+func ValidateTimeoutLabel(name string, service Service, label string) error {
+	value, ok := service.Labels[label]
+	if !ok {
+		return nil
+	}
+	if _, err := parseTimeoutLabel(value); err != nil {
+		return newValidationError("service '%s': invalid %s label %q: %v", name, label, value, err)
+	}
+	return nil
+}
+
+// serversForTool returns the servers written to a tool's config per their
+// "mcp.tools" labels
+func serversForTool(servers map[string]Service, tool string) map[string]Service {
+	result := make(map[string]Service, len(servers))
+	for name, service := range servers {
+		if ServiceForTool(service, tool) {
+			result[name] = service
+		}
+	}
+	return result
+}
+
+// configForTool returns config without the servers whose "mcp.tools" label
+// leaves the tool out
+func configForTool(config MCPConfig, services map[string]Service, tool string) MCPConfig {
+	result := config
+	result.MCPServers = make(map[string]MCPServer, len(config.MCPServers))
+	for name, server := range config.MCPServers {
+		if service, ok := services[name]; !ok || ServiceForTool(service, tool) {
+			result.MCPServers[name] = server
+		}
+	}
+	return result
+}
+
+// ValidateToolsLabel checks that the "mcp.tools" label of a service only
+// names known tool shortcuts.
+func ValidateToolsLabel(name string, service Service) error {
+	label, ok := service.Labels["mcp.tools"]
+	if !ok {
+		return nil
+	}
+	tools := GetServiceTools(service)
+	if len(tools) == 0 {
+		return newValidationError("service '%s': mcp.tools label is empty", name)
+	}
+	known := getAllTools()
+	for _, tool := range tools {
+		if !slices.Contains(known, tool) {
+			return newValidationError("service '%s': invalid mcp.tools label %q: unknown tool '%s' (supported: %s)", name, label, tool, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
 // MaxDescriptionLength is the maximum length for truncated descriptions
 const MaxDescriptionLength = 60
 
@@ -3,6 +3,8 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -382,6 +384,21 @@ func TestFilterServers(t *testing.T) {
 		}
 	})
 
+	t.Run("filter by several profiles", func(t *testing.T) {
+		for _, profile := range []string{"programming,research", "research, programming", "programming,research,programming"} {
+			result := filterServers(config, profile, false)
+			expectedServers := []string{"default-server", "no-profile-server", "multi-profile-server", "programming-server", "research-server"}
+			if len(result) != len(expectedServers) {
+				t.Errorf("%s: expected %d servers, got %d", profile, len(expectedServers), len(result))
+			}
+			for _, serverName := range expectedServers {
+				if _, exists := result[serverName]; !exists {
+					t.Errorf("%s: expected server %s to be included", profile, serverName)
+				}
+			}
+		}
+	})
+
 	t.Run("filter by non-existent profile", func(t *testing.T) {
 		result := filterServers(config, "non-existent", false)
 
@@ -435,3 +452,345 @@ func TestFilterServers(t *testing.T) {
 		}
 	})
 }
+
+// TestGetProfiles tests that malformed mcp.profile labels don't create phantom profiles
+func TestGetProfiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		profiles  []string
+		expected  []string
+		isDefault bool
+	}{
+		{"no label", map[string]string{}, nil, nil, true},
+		{"single profile", map[string]string{"mcp.profile": "programming"}, nil, []string{"programming"}, false},
+		{"empty entries", map[string]string{"mcp.profile": "default , ,programming"}, nil, []string{"default", "programming"}, true},
+		{"trailing comma", map[string]string{"mcp.profile": "research,"}, nil, []string{"research"}, false},
+		{"duplicates", map[string]string{"mcp.profile": "research, research"}, nil, []string{"research"}, false},
+		{"only separators", map[string]string{"mcp.profile": " , ,"}, nil, nil, true},
+		{"compose profiles", map[string]string{}, []string{"research", "work"}, []string{"research", "work"}, false},
+		{"label wins over compose profiles", map[string]string{"mcp.profile": "default"}, []string{"research"}, []string{"default"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := Service{Labels: tt.labels, Profiles: tt.profiles}
+			if got := GetProfiles(service); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("GetProfiles() = %v, expected %v", got, tt.expected)
+			}
+			if got := IsDefaultServer(service); got != tt.isDefault {
+				t.Errorf("IsDefaultServer() = %v, expected %v", got, tt.isDefault)
+			}
+		})
+	}
+}
+
+// TestValidateProfileLabel tests that malformed profile labels are reported with the service name
+func TestValidateProfileLabel(t *testing.T) {
+	tests := []struct {
+		name    string
+		label   string
+		wantErr string
+	}{
+		{"valid", "default,programming", ""},
+		{"valid with spaces", "default, programming", ""},
+		{"empty entry", "default , ,programming", "empty profile entry"},
+		{"trailing comma", "research,", "empty profile entry"},
+		{"duplicate", "research,research", "profile 'research' is listed more than once"},
+		{"whitespace in name", "deep research", "profile 'deep research' contains whitespace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := Service{Labels: map[string]string{"mcp.profile": tt.label}}
+			err := ValidateProfileLabel("my-server", service)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "service 'my-server'") {
+				t.Errorf("Expected error naming my-server and containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestServiceForTool tests that the mcp.tools label limits the tools a server is written to
+func TestServiceForTool(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	services := map[string]Service{
+		"everywhere": {Command: "uvx time"},
+		"kiro-only":  {Command: "uvx fetch", Labels: map[string]string{"mcp.tools": "kiro, q-cli"}},
+	}
+
+	if got := serversForTool(services, "cursor"); len(got) != 1 || got["everywhere"].Command == "" {
+		t.Errorf("Expected only 'everywhere' for cursor, got %v", got)
+	}
+	if got := serversForTool(services, "q-cli"); len(got) != 2 {
+		t.Errorf("Expected both servers for q-cli, got %v", got)
+	}
+	if got := serversForTool(services, ""); len(got) != 2 {
+		t.Errorf("Expected both servers without a tool, got %v", got)
+	}
+
+	config := MCPConfig{MCPServers: map[string]MCPServer{
+		"everywhere": {Command: "uvx"},
+		"kiro-only":  {Command: "uvx"},
+		"by-hand":    {Command: "node"},
+	}}
+	if got := configForTool(config, services, "cursor"); len(got.MCPServers) != 2 || len(config.MCPServers) != 3 {
+		t.Errorf("Expected kiro-only left out of a copy of the config, got %v", got.MCPServers)
+	}
+
+	if err := ValidateToolsLabel("kiro-only", services["kiro-only"]); err != nil {
+		t.Errorf("Expected a valid label, got %v", err)
+	}
+	bad := Service{Labels: map[string]string{"mcp.tools": "kiro,vim"}}
+	if err := ValidateToolsLabel("x", bad); err == nil || !strings.Contains(err.Error(), "unknown tool 'vim'") {
+		t.Errorf("Expected an unknown tool error, got %v", err)
+	}
+}
+
+// TestIsServiceDisabled tests the mcp.disabled label
+func TestIsServiceDisabled(t *testing.T) {
+	servers := map[string]Service{
+		"on":       {Command: "a"},
+		"off":      {Command: "b", Labels: map[string]string{"mcp.disabled": "true"}},
+		"explicit": {Command: "c", Labels: map[string]string{"mcp.disabled": "false"}},
+	}
+	if got := enabledServers(servers); len(got) != 2 || got["off"].Command != "" {
+		t.Errorf("Expected 'off' to be left out, got %v", got)
+	}
+
+	if err := ValidateDisabledLabel("off", servers["off"]); err != nil {
+		t.Errorf("Expected a valid label, got %v", err)
+	}
+	bad := Service{Labels: map[string]string{"mcp.disabled": "yes"}}
+	if err := ValidateDisabledLabel("x", bad); err == nil || !strings.Contains(err.Error(), "must be true or false") {
+		t.Errorf("Expected an invalid label error, got %v", err)
+	}
+}
+
+// TestListFormEnvironment tests that the list form of environment is read like the map form
+func TestListFormEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(path, []byte(`services:
+  list:
+    command: uvx mcp-server-time
+    environment:
+      - TZ=Europe/Berlin
+      - "QUERY=a=b"
+      - HOME_DIR
+  map:
+    command: uvx mcp-server-time
+    environment:
+      TZ: UTC
+`), 0644)
+
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+	expected := map[string]string{"TZ": "Europe/Berlin", "QUERY": "a=b", "HOME_DIR": "${HOME_DIR}"}
+	if got := config.Services["list"].Environment; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if got := config.Services["map"].Environment; !reflect.DeepEqual(got, map[string]string{"TZ": "UTC"}) {
+		t.Errorf("Expected the map form unchanged, got %v", got)
+	}
+	if config.Services["list"].Command != "uvx mcp-server-time" {
+		t.Errorf("Expected the other fields decoded, got %+v", config.Services["list"])
+	}
+
+	os.WriteFile(path, []byte("services:\n  bad:\n    command: x\n    environment:\n      - =oops\n"), 0644)
+	if _, err := loadComposeFile(path); err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Errorf("Expected an error on line 5, got %v", err)
+	}
+}
+
+// TestListFormLabels tests that the list form of labels is read like the map form
+func TestListFormLabels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(path, []byte(`services:
+  search:
+    image: mcp/brave-search
+    labels:
+      - mcp.profile=work,research
+      - "mcp.description=Search the web = fast"
+      - com.example.internal
+`), 0644)
+
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+	service := config.Services["search"]
+	expected := map[string]string{
+		"mcp.profile":          "work,research",
+		"mcp.description":      "Search the web = fast",
+		"com.example.internal": "",
+	}
+	if !reflect.DeepEqual(service.Labels, expected) {
+		t.Errorf("Expected %v, got %v", expected, service.Labels)
+	}
+	if profiles := GetProfiles(service); !reflect.DeepEqual(profiles, []string{"work", "research"}) {
+		t.Errorf("Expected the profiles to be read, got %v", profiles)
+	}
+
+	problems, err := validateComposeFile(path, map[string]string{}, "")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("Expected no problems, got %+v, %v", problems, err)
+	}
+}
+
+func TestXMCPBlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(path, []byte(`x-mcp:
+  profile: work
+  timeout: 30s
+services:
+  search:
+    image: mcp/brave-search
+    labels:
+      mcp.description: From the label
+      mcp.profile: research
+    x-mcp:
+      description: Search the web
+      tools: [kiro, q-cli]
+      auto-approve:
+        - brave_web_search
+  remote:
+    command: https://example.com/mcp
+    x-mcp:
+      transport: http
+      auth:
+        grant-type: client_credentials
+        token-endpoint: https://example.com/token
+        client-id: id
+        client-secret: secret
+      headers:
+        X-Team: platform
+`), 0644)
+
+	config, err := loadComposeFile(path)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+	expected := map[string]string{
+		"mcp.description":  "Search the web",
+		"mcp.profile":      "research",
+		"mcp.tools":        "kiro,q-cli",
+		"mcp.auto-approve": "brave_web_search",
+		"mcp.timeout":      "30s",
+	}
+	if labels := config.Services["search"].Labels; !reflect.DeepEqual(labels, expected) {
+		t.Errorf("Expected %v, got %v", expected, labels)
+	}
+	expected = map[string]string{
+		"mcp.transport":      "http",
+		"mcp.grant-type":     "client_credentials",
+		"mcp.token-endpoint": "https://example.com/token",
+		"mcp.client-id":      "id",
+		"mcp.client-secret":  "secret",
+		"mcp.header.X-Team":  "platform",
+		"mcp.profile":        "work",
+		"mcp.timeout":        "30s",
+	}
+	if labels := config.Services["remote"].Labels; !reflect.DeepEqual(labels, expected) {
+		t.Errorf("Expected %v, got %v", expected, labels)
+	}
+
+	problems, err := validateComposeFile(path, map[string]string{}, "")
+	if err != nil {
+		t.Fatalf("validateComposeFile failed: %v", err)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0].Message, "both OAuth labels and headers") {
+		t.Errorf("Expected only the OAuth and header conflict, got %+v", problems)
+	}
+
+	os.WriteFile(path, []byte(`services:
+  search:
+    image: mcp/brave-search
+    x-mcp:
+      transport: http
+`), 0644)
+	problems, err = validateComposeFile(path, map[string]string{}, "")
+	if err != nil || len(problems) != 1 || !strings.Contains(problems[0].Message, "mcp.transport is http") {
+		t.Errorf("Expected a transport mismatch, got %+v, %v", problems, err)
+	}
+}
+
+func TestComposeOverrideFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yml")
+	os.WriteFile(base, []byte(`x-mcp:
+  profile: work
+services:
+  search:
+    image: mcp/brave-search
+    environment:
+      BRAVE_API_KEY: ${BRAVE_API_KEY}
+      LOG_LEVEL: info
+    labels:
+      mcp.description: Search the web
+  time:
+    command: uvx mcp-server-time
+`), 0644)
+	team := filepath.Join(dir, "team.yml")
+	os.WriteFile(team, []byte(`services:
+  search:
+    environment:
+      LOG_LEVEL: debug
+  fetch:
+    command: uvx mcp-server-fetch
+`), 0644)
+	local := filepath.Join(dir, "local-override.yml")
+	os.WriteFile(local, []byte(`x-mcp:
+  profile: personal
+services:
+  time:
+    command: uvx mcp-server-time --local-timezone UTC
+`), 0644)
+
+	oldFile, oldOverrides := composeFile, composeOverrideFiles
+	t.Cleanup(func() { composeFile, composeOverrideFiles = oldFile, oldOverrides })
+	composeFile, composeOverrideFiles = base, []string{team, local}
+
+	config, err := loadComposeFile(base)
+	if err != nil {
+		t.Fatalf("loadComposeFile failed: %v", err)
+	}
+	if len(config.Services) != 3 {
+		t.Errorf("Expected 3 services, got %v", config.Services)
+	}
+
+	search := config.Services["search"]
+	if search.Image != "mcp/brave-search" || GetDescription(search) != "Search the web" {
+		t.Errorf("Expected search to keep the base fields, got %+v", search)
+	}
+	if expected := map[string]string{"BRAVE_API_KEY": "${BRAVE_API_KEY}", "LOG_LEVEL": "debug"}; !reflect.DeepEqual(search.Environment, expected) {
+		t.Errorf("Expected %v, got %v", expected, search.Environment)
+	}
+	if command := config.Services["time"].Command; command != "uvx mcp-server-time --local-timezone UTC" {
+		t.Errorf("Expected the last file's command, got %q", command)
+	}
+	if profiles := GetProfiles(config.Services["fetch"]); !reflect.DeepEqual(profiles, []string{"personal"}) {
+		t.Errorf("Expected the last file's x-mcp defaults, got %v", profiles)
+	}
+
+	// Other compose files are loaded on their own
+	config, err = loadComposeFile(team)
+	if err != nil || len(config.Services) != 2 {
+		t.Errorf("Expected only team.yml's services, got %v, %v", config, err)
+	}
+
+	composeOverrideFiles = []string{filepath.Join(dir, "missing.yml")}
+	if _, err := loadComposeFile(base); err == nil || !strings.Contains(err.Error(), "missing.yml") {
+		t.Errorf("Expected an error naming the missing override file, got %v", err)
+	}
+}
@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// Package registries queried for the latest versions; replaced in tests
+var (
+	npmRegistryURL = "https://registry.npmjs.org"
+	pypiURL        = "https://pypi.org"
+	dockerHubURL   = "https://hub.docker.com"
+)
+
+var (
+	updateCheck bool
+	updateApply bool
+)
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update [server...]",
+	Short: "Check for newer versions of pinned server packages and images",
+	Long: `Look up the latest version of each server's pinned npx or uvx package on npm
+or PyPI, and of its pinned Docker Hub image tag, and report the servers that
+have a newer one. Unpinned servers (no version, or "latest") are skipped.
+With --check, it exits with status 1 if any updates are available, for CI.
+With --apply, it rewrites the pins in the compose file, keeping its comments
+and formatting; run 'mcp set' afterwards to deploy them.
+Without arguments, every server in the compose file is checked.`,
+	ValidArgsFunction: completeServerNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if updateCheck && updateApply {
+			return newValidationError("--check and --apply can't be used together")
+		}
+
+		config, err := loadComposeFile(composeFile)
+		if err != nil {
+			return newConfigError("load compose file", composeFile, err)
+		}
+		names := args
+		if len(names) == 0 {
+			for name := range config.Services {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, ok := config.Services[name]; !ok {
+				return newValidationError("server '%s' not found in %s", name, composeFile)
+			}
+		}
+
+		cache, err := newHTTPCache()
+		if err != nil {
+			return err
+		}
+		var pins []pinnedPackage
+		for _, name := range names {
+			if pin, ok := findPinnedPackage(name, config.Services[name]); ok {
+				pins = append(pins, pin)
+			}
+		}
+		checkLatestVersions(cmd.Context(), cache, pins)
+		updates := printUpdates(os.Stdout, pins)
+
+		switch {
+		case updateApply && updates > 0:
+			if err := applyUpdates(os.Stdout, composeFile, pins); err != nil {
+				return err
+			}
+			fmt.Println("Run 'mcp set' to deploy the new versions")
+		case updateCheck && updates > 0:
+			return &ExitError{Code: exitCodeDifferent}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().BoolVar(&updateCheck, "check", false, "Exit with status 1 if any updates are available")
+	updateCmd.Flags().BoolVar(&updateApply, "apply", false, "Rewrite the compose file with the new versions")
+}
+
+// pinnedPackage is the versioned package or image a server runs
+type pinnedPackage struct {
+	Server  string
+	Kind    string // npm, pypi, or docker
+	Name    string
+	Current string
+	Latest  string // empty until checked, or if the check failed
+	Err     error
+
+	field     string // compose key holding the pin: command or image
+	pin       string // the pinned reference as written in field
+	separator string // between Name and the version in pin
+}
+
+// newer reports whether a newer version than the pinned one was found
+func (p pinnedPackage) newer() bool {
+	if p.Latest == "" {
+		return false
+	}
+	latest, _ := parseVersion(p.Latest)
+	current, _ := parseVersion(p.Current)
+	return compareVersions(latest, current) > 0
+}
+
+// findPinnedPackage returns the pinned npx or uvx package, or image tag, of a
+// service, reporting false if it has none
+func findPinnedPackage(name string, service Service) (pinnedPackage, bool) {
+	pin, ok := findPackage(name, service)
+	if !ok {
+		return pinnedPackage{}, false
+	}
+	if _, ok := parseVersion(pin.Current); !ok {
+		return pinnedPackage{}, false
+	}
+	return pin, true
+}
+
+// findPackage returns the npx or uvx package, or image, a service runs, with
+// Current set to its version if it has one, reporting false if it runs neither
+func findPackage(name string, service Service) (pinnedPackage, bool) {
+	if IsRemoteServer(service) {
+		return pinnedPackage{}, false
+	}
+	if service.Image != "" {
+		image := service.Image
+		// Digests are already exact; variables can't be resolved here
+		if strings.ContainsAny(image, "@$") {
+			return pinnedPackage{}, false
+		}
+		pin := pinnedPackage{Server: name, Kind: "docker", Name: image, field: "image", pin: image, separator: ":"}
+		if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+			pin.Name, pin.Current = image[:i], image[i+1:]
+		}
+		return pin, true
+	}
+
+	parts := strings.Fields(service.Command)
+	if len(parts) < 2 {
+		return pinnedPackage{}, false
+	}
+	runner := filepath.Base(parts[0])
+	if runner != "npx" && runner != "uvx" {
+		return pinnedPackage{}, false
+	}
+
+	// The package is the first argument that isn't a flag, or uvx's --from
+	var pkg string
+	for i := 1; i < len(parts); i++ {
+		if runner == "uvx" && parts[i] == "--from" && i+1 < len(parts) {
+			pkg = parts[i+1]
+			break
+		}
+		if !strings.HasPrefix(parts[i], "-") {
+			pkg = parts[i]
+			break
+		}
+	}
+	if pkg == "" || strings.Contains(pkg, "$") {
+		return pinnedPackage{}, false
+	}
+
+	pin := pinnedPackage{Server: name, Name: pkg, field: "command", pin: pkg}
+	if runner == "npx" {
+		pin.Kind, pin.separator = "npm", "@"
+		// Skip the @ of scoped packages
+		if i := strings.LastIndex(pkg, "@"); i > 0 {
+			pin.Name, pin.Current = pkg[:i], pkg[i+1:]
+		}
+	} else {
+		pin.Kind, pin.separator = "pypi", "=="
+		for _, separator := range []string{"==", "@"} {
+			if name, version, ok := strings.Cut(pkg, separator); ok {
+				pin.Name, pin.Current, pin.separator = name, version, separator
+				break
+			}
+		}
+	}
+	return pin, true
+}
+
+// checkLatestVersions looks up the latest version of each pinned package,
+// recording failures on the package rather than stopping
+func checkLatestVersions(ctx context.Context, cache *httpCache, pins []pinnedPackage) {
+	for i := range pins {
+		pins[i].Latest, pins[i].Err = latestVersion(ctx, cache, pins[i])
+	}
+}
+
+// latestVersion returns the newest release of a package from its registry
+func latestVersion(ctx context.Context, cache *httpCache, pin pinnedPackage) (string, error) {
+	switch pin.Kind {
+	case "npm":
+		// Scoped packages keep their @ but escape the slash
+		var result struct {
+			Version string `json:"version"`
+		}
+		err := getJSON(ctx, cache, npmRegistryURL+"/"+strings.Replace(pin.Name, "/", "%2F", 1)+"/latest", &result)
+		return result.Version, err
+	case "pypi":
+		var result struct {
+			Info struct {
+				Version string `json:"version"`
+			} `json:"info"`
+		}
+		err := getJSON(ctx, cache, pypiURL+"/pypi/"+url.PathEscape(pin.Name)+"/json", &result)
+		return result.Info.Version, err
+	case "docker":
+		return latestDockerHubTag(ctx, cache, pin.Name, pin.Current)
+	}
+	return "", fmt.Errorf("unknown package kind %s", pin.Kind)
+}
+
+// latestDockerHubTag returns the highest version tag of an image shaped like
+// current (same "v" prefix and number of components), so an image pinned to
+// 1.2 isn't moved to 1.3.0 or to a variant like 1.3-alpine
+func latestDockerHubTag(ctx context.Context, cache *httpCache, image, current string) (string, error) {
+	repo, err := dockerHubRepo(image)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Results []struct {
+			Name string `json:"name"`
+		} `json:"results"`
+	}
+	if err := getJSON(ctx, cache, dockerHubURL+"/v2/repositories/"+repo+"/tags?page_size=100&ordering=last_updated", &result); err != nil {
+		return "", err
+	}
+
+	currentVersion, _ := parseVersion(current)
+	prefixed := strings.HasPrefix(current, "v")
+	latest, newest := "", []int(nil)
+	for _, tag := range result.Results {
+		version, ok := parseVersion(tag.Name)
+		if !ok || len(version) != len(currentVersion) || strings.HasPrefix(tag.Name, "v") != prefixed {
+			continue
+		}
+		if latest == "" || compareVersions(version, newest) > 0 {
+			latest, newest = tag.Name, version
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no version tags found for %s", image)
+	}
+	return latest, nil
+}
+
+// dockerHubRepo returns the Docker Hub repository of an image name without
+// its tag, e.g. library/node for node
+func dockerHubRepo(image string) (string, error) {
+	if first, _, ok := strings.Cut(image, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return "", fmt.Errorf("only Docker Hub images can be checked")
+	}
+	if !strings.Contains(image, "/") {
+		return "library/" + image, nil
+	}
+	return image, nil
+}
+
+// getJSON fetches a URL through the cache and decodes its JSON body into v
+func getJSON(ctx context.Context, cache *httpCache, rawURL string, v any) error {
+	body, err := cache.Get(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decode %s: %w", rawURL, err)
+	}
+	return nil
+}
+
+// parseVersion parses a release version like 1.2.3 or v1.2, reporting false
+// for anything else, including pre-releases and tags like "latest"
+func parseVersion(s string) ([]int, bool) {
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	version := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		version = append(version, n)
+	}
+	return version, true
+}
+
+// compareVersions compares two parsed versions component by component,
+// treating missing components as 0
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// printUpdates prints a table of the pinned packages and returns how many
+// have a newer version
+func printUpdates(w io.Writer, pins []pinnedPackage) int {
+	if len(pins) == 0 {
+		fmt.Fprintln(w, "No servers with pinned versions found")
+		return 0
+	}
+
+	updates := 0
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tPACKAGE\tCURRENT\tLATEST\tSTATUS")
+	fmt.Fprintln(tw, "------\t-------\t-------\t------\t------")
+	for _, pin := range pins {
+		status := "up to date"
+		switch {
+		case pin.Err != nil:
+			status = "error: " + pin.Err.Error()
+		case pin.newer():
+			status = "update available"
+			updates++
+		}
+		fmt.Fprintf(tw, "%s\t%s (%s)\t%s\t%s\t%s\n", pin.Server, pin.Name, pin.Kind, pin.Current, pin.Latest, status)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n%s available\n", pluralize(updates, "update"))
+	return updates
+}
+
+// applyUpdates rewrites the pins that have a newer version in the compose file
+func applyUpdates(w io.Writer, path string, pins []pinnedPackage) error {
+	doc, err := loadComposeDocument(path)
+	if err != nil {
+		return newConfigError("load compose file", path, err)
+	}
+
+	services := servicesNode(doc, false)
+	var messages []string
+	for _, pin := range pins {
+		if !pin.newer() {
+			continue
+		}
+		node := mappingValue(mappingValue(services, pin.Server), pin.field)
+		if node == nil || !strings.Contains(node.Value, pin.pin) {
+			return newConfigError("update "+pin.Server, path, fmt.Errorf("%s no longer contains %s", pin.field, pin.pin))
+		}
+		updated := pin.Name + pin.separator + pin.Latest
+		node.Value = strings.Replace(node.Value, pin.pin, updated, 1)
+		messages = append(messages, fmt.Sprintf("Updated %s: %s -> %s", pin.Server, pin.pin, updated))
+	}
+
+	if err := saveComposeDocument(path, doc); err != nil {
+		return newConfigError("write compose file", path, err)
+	}
+	for _, message := range messages {
+		fmt.Fprintln(w, message)
+	}
+	return nil
+}
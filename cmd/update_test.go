@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakePackageRegistries points the npm, PyPI, and Docker Hub URLs at one test server
+func fakePackageRegistries(t *testing.T, handler http.HandlerFunc) {
+	registry := httptest.NewServer(handler)
+	t.Cleanup(registry.Close)
+	for _, url := range []*string{&npmRegistryURL, &pypiURL, &dockerHubURL} {
+		saved := *url
+		*url = registry.URL
+		t.Cleanup(func() { *url = saved })
+	}
+}
+
+func TestFindPinnedPackage(t *testing.T) {
+	tests := []struct {
+		name    string
+		service Service
+		want    string // kind name current, or empty if not pinned
+	}{
+		{"npm", Service{Command: "npx -y @modelcontextprotocol/server-github@1.2.0"}, "npm @modelcontextprotocol/server-github 1.2.0"},
+		{"npm unpinned", Service{Command: "npx -y @modelcontextprotocol/server-github"}, ""},
+		{"npm latest", Service{Command: "npx -y mcp-time@latest"}, ""},
+		{"uvx", Service{Command: "uvx mcp-server-fetch==0.6.2"}, "pypi mcp-server-fetch 0.6.2"},
+		{"uvx from", Service{Command: "uvx --from awslabs-core@1.0 awslabs.core"}, "pypi awslabs-core 1.0"},
+		{"image", Service{Image: "mcp/github:v1.4"}, "docker mcp/github v1.4"},
+		{"image latest", Service{Image: "mcp/github:latest"}, ""},
+		{"image registry port", Service{Image: "localhost:5000/github"}, ""},
+		{"image digest", Service{Image: "mcp/github@sha256:abc"}, ""},
+		{"other command", Service{Command: "node server.js"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pin, ok := findPinnedPackage("server", tt.service)
+			got := ""
+			if ok {
+				got = pin.Kind + " " + pin.Name + " " + pin.Current
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "1.10.0", -1},
+		{"v2", "1.9.9", 1},
+		{"1.2", "1.2.0", 0},
+	}
+	for _, tt := range tests {
+		a, _ := parseVersion(tt.a)
+		b, _ := parseVersion(tt.b)
+		if got := compareVersions(a, b); got != tt.want {
+			t.Errorf("compareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	for _, s := range []string{"latest", "1.2.0-beta.1", "", "1..2"} {
+		if _, ok := parseVersion(s); ok {
+			t.Errorf("Expected %q not to parse as a version", s)
+		}
+	}
+}
+
+func TestUpdateApply(t *testing.T) {
+	fakePackageRegistries(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/@modelcontextprotocol%2Fserver-github/latest":
+			w.Write([]byte(`{"version": "1.3.0"}`))
+		case "/pypi/mcp-server-fetch/json":
+			w.Write([]byte(`{"info": {"version": "0.6.2"}}`))
+		case "/v2/repositories/mcp/time/tags":
+			w.Write([]byte(`{"results": [{"name": "latest"}, {"name": "v2.1"}, {"name": "v2.0-alpine"}, {"name": "2.5"}, {"name": "v1.9"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	now := time.Now()
+	cache := newTestHTTPCache(t, &now)
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	compose := `services:
+  # GitHub tools
+  github:
+    command: npx -y @modelcontextprotocol/server-github@1.2.0 --verbose
+  fetch:
+    command: uvx mcp-server-fetch==0.6.2
+  time:
+    image: mcp/time:v2.0
+  missing:
+    command: npx -y not-published@1.0.0
+`
+	if err := os.WriteFile(composePath, []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pins []pinnedPackage
+	for _, name := range []string{"fetch", "github", "missing", "time"} {
+		pin, _ := findPinnedPackage(name, config.Services[name])
+		pins = append(pins, pin)
+	}
+	checkLatestVersions(context.Background(), cache, pins)
+
+	var out bytes.Buffer
+	if updates := printUpdates(&out, pins); updates != 2 {
+		t.Errorf("Expected 2 updates, got %d:\n%s", updates, out.String())
+	}
+	if pins[2].Err == nil || !strings.Contains(out.String(), "error:") {
+		t.Errorf("Expected the unpublished package to fail, got:\n%s", out.String())
+	}
+
+	if err := applyUpdates(&out, composePath, pins); err != nil {
+		t.Fatalf("applyUpdates failed: %v", err)
+	}
+	data, err := os.ReadFile(composePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# GitHub tools",
+		"command: npx -y @modelcontextprotocol/server-github@1.3.0 --verbose",
+		"command: uvx mcp-server-fetch==0.6.2",
+		"image: mcp/time:v2.1",
+		"command: npx -y not-published@1.0.0",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected compose file to contain %q, got:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// knownLabels lists the mcp.* labels the CLI understands
+var knownLabels = map[string]bool{
+	"mcp.profile":        true,
+	"mcp.description":    true,
+	"mcp.grant-type":     true,
+	"mcp.token-endpoint": true,
+	"mcp.client-id":      true,
+	"mcp.client-secret":  true,
+	"mcp.docs":           true,
+	"mcp.env-mode":       true,
+	"mcp.tools":          true,
+	"mcp.disabled":       true,
+	"mcp.auto-approve":   true,
+	"mcp.transport":      true,
+	"mcp.url":            true,
+	dockerArgsLabel:      true,
+	timeoutLabel:         true,
+	initTimeoutLabel:     true,
+}
+
+// knownLabelPrefixes lists the mcp.* label families that take a name suffix
+var knownLabelPrefixes = []string{"mcp.header.", envDocLabelPrefix, inputLabelPrefix}
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the compose file for errors",
+	Long: `Check the mcp-compose.yml file for structural errors:
+services with neither command nor image, invalid mcp.* labels, unresolved
+environment variables, conflicting OAuth and header labels, and server names
+that collide after normalization.
+With the -t flag, it also checks that the tool supports every remote server.
+Each problem is printed with its line number, and the command exits non-zero
+if any are found, which makes it suitable for CI.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envVars, err := loadEnvVars(composeFile)
+		if err != nil {
+			return newConfigError("load environment variables", composeFile, err)
+		}
+
+		problems, err := validateComposeFile(composeFile, envVars, toolShortcut)
+		if err != nil {
+			return err
+		}
+		return reportProblems(os.Stdout, composeFile, problems)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Also check remote server support for this tool (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	validateCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// composeProblem is a validation error at a line of the compose file
+type composeProblem struct {
+	Line    int
+	Message string
+}
+
+// reportProblems prints each problem as "path:line: message" and returns a
+// ValidationError if there were any
+func reportProblems(w io.Writer, path string, problems []composeProblem) error {
+	if len(problems) == 0 {
+		fmt.Fprintf(w, "%s is valid\n", path)
+		return nil
+	}
+
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s:%d: %s\n", path, p.Line, p.Message)
+	}
+
+	noun := "problems"
+	if len(problems) == 1 {
+		noun = "problem"
+	}
+	return newValidationError("%d %s found in %s", len(problems), noun, path)
+}
+
+// validateComposeFile checks a compose file and returns its problems in line order
+// Returns an error only if the file can't be read
+func validateComposeFile(path string, envVars map[string]string, tool string) ([]composeProblem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newConfigError("load compose file", path, err)
+	}
+	return validateComposeData(data, envVars, tool), nil
+}
+
+// validateComposeData checks compose file contents and returns its problems in line order
+func validateComposeData(data []byte, envVars map[string]string, tool string) []composeProblem {
+	doc, err := parseComposeDocument(data)
+	if err != nil {
+		return []composeProblem{{Line: yamlErrorLine(err), Message: err.Error()}}
+	}
+
+	// Services may come from included files, which are checked on their own
+	includes := mappingValue(doc.Content[0], "include") != nil
+	services := servicesNode(doc, false)
+	if services == nil {
+		if includes {
+			return nil
+		}
+		return []composeProblem{{Line: 1, Message: "no services defined"}}
+	}
+	if services.Kind != yaml.MappingNode {
+		return []composeProblem{{Line: services.Line, Message: "services must be a mapping of server names to definitions"}}
+	}
+
+	var problems []composeProblem
+	add := func(line int, format string, args ...interface{}) {
+		problems = append(problems, composeProblem{Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// A top-level x-mcp block sets defaults for every service
+	var defaults map[string]string
+	if block := mappingValue(doc.Content[0], xMCPKey); block != nil {
+		if defaults, err = xMCPLabels(block); err != nil {
+			add(block.Line, "%v", err)
+		}
+	}
+
+	seen := make(map[string]*yaml.Node)
+	remoteServers := make(map[string]Service)
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		keyNode, valueNode := services.Content[i], services.Content[i+1]
+		name := keyNode.Value
+
+		// Tools key servers by name, so names that only differ in case collide
+		normalized := strings.ToLower(strings.TrimSpace(name))
+		if first, ok := seen[normalized]; ok {
+			add(keyNode.Line, "service '%s': duplicates service '%s' on line %d", name, first.Value, first.Line)
+		} else {
+			seen[normalized] = keyNode
+		}
+
+		var service Service
+		if err := valueNode.Decode(&service); err != nil {
+			add(keyNode.Line, "service '%s': %v", name, err)
+			continue
+		}
+		applyXMCPDefaults(&service, defaults)
+
+		// A service that extends another, or overrides an included one, may
+		// take its command or image from it
+		if service.Extends != nil {
+			if service.Extends.File == "" && !includes && mappingValue(services, service.Extends.Service) == nil {
+				add(nodeLine(valueNode, keyNode.Line, "extends"), "service '%s': extends unknown service '%s'", name, service.Extends.Service)
+			}
+		} else if service.Command == "" && service.Image == "" && !includes {
+			add(keyNode.Line, "service '%s': neither command nor image is set", name)
+		}
+
+		labelLine := func(label string) int {
+			return nodeLine(valueNode, keyNode.Line, "labels", label)
+		}
+
+		for _, label := range sortedKeys(service.Labels) {
+			if strings.HasPrefix(label, "mcp.") && !isKnownLabel(label) {
+				add(labelLine(label), "service '%s': unknown label '%s'", name, label)
+			}
+		}
+		if err := ValidateProfileLabel(name, service); err != nil {
+			add(labelLine("mcp.profile"), "%v", err)
+		}
+		if err := ValidateEnvModeLabel(name, service); err != nil {
+			add(labelLine("mcp.env-mode"), "%v", err)
+		}
+		if err := ValidateToolsLabel(name, service); err != nil {
+			add(labelLine("mcp.tools"), "%v", err)
+		}
+		if err := ValidateDisabledLabel(name, service); err != nil {
+			add(labelLine("mcp.disabled"), "%v", err)
+		}
+		if err := ValidateTransportLabel(name, service, envVars); err != nil {
+			add(labelLine("mcp.transport"), "%v", err)
+		}
+		if err := ValidateURLLabel(name, service, envVars); err != nil {
+			add(labelLine("mcp.url"), "%v", err)
+		}
+		if err := ValidateDockerArgsLabel(name, service); err != nil {
+			add(labelLine(dockerArgsLabel), "%v", err)
+		}
+		if service.Image == "" && service.Extends == nil && !includes {
+			for key, set := range map[string]bool{
+				"ports":       len(service.Ports) > 0,
+				"entrypoint":  len(service.Entrypoint) > 0,
+				"working_dir": service.WorkingDir != "",
+				"user":        service.User != "",
+			} {
+				if set {
+					add(nodeLine(valueNode, keyNode.Line, key), "service '%s': %s is only used by image-based servers", name, key)
+				}
+			}
+		}
+		for _, label := range []string{timeoutLabel, initTimeoutLabel} {
+			if err := ValidateTimeoutLabel(name, service, label); err != nil {
+				add(labelLine(label), "%v", err)
+			}
+		}
+
+		// Passed-through variables are resolved by the client, not from .env
+		passthrough := GetEnvMode(service) == envModePassthrough && !IsRemoteServerWithEnvExpansion(service, envVars)
+		docs := GetEnvDocs(service)
+		inputs := GetInputs(service)
+		// Disabled servers aren't written, so their variables needn't be set
+		disabled := IsServiceDisabled(service)
+		for _, ref := range unresolvedEnvVars(service, envVars) {
+			if disabled || (passthrough && ref.path[0] != "labels") {
+				continue
+			}
+			// Inputs in the environment may be left for VS Code to prompt for
+			if _, ok := inputs[ref.name]; ok && ref.path[0] == "environment" {
+				continue
+			}
+			line := nodeLine(valueNode, keyNode.Line, ref.path...)
+			doc := docs[ref.name]
+			if doc == "" {
+				// The message of ${VAR:?message} documents the variable too
+				doc = ref.message
+			}
+			if doc != "" {
+				add(line, "service '%s': environment variable '%s' is not set (%s)", name, ref.name, doc)
+			} else {
+				add(line, "service '%s': environment variable '%s' is not set", name, ref.name)
+			}
+		}
+
+		if IsRemoteServerWithEnvExpansion(service, envVars) {
+			if err := ValidateRemoteServerAuth(name, service); err != nil {
+				add(keyNode.Line, "%v", err)
+			}
+			remoteServers[name] = service
+		} else if UsesHeadersAuth(service) || service.Labels["mcp.grant-type"] != "" {
+			add(keyNode.Line, "service '%s': authentication labels are only used by remote servers (command must be an http:// or https:// URL)", name)
+		}
+	}
+
+	if tool != "" && len(remoteServers) > 0 && !toolSupportsRemote(tool) {
+		for i := 0; i+1 < len(services.Content); i += 2 {
+			if name := services.Content[i].Value; remoteServers[name].Command != "" && ServiceForTool(remoteServers[name], tool) {
+				add(services.Content[i].Line, "remote server '%s' is not supported by %s (supported: %s)",
+					name, tool, strings.Join(getRemoteSupportedTools(), ", "))
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+	return problems
+}
+
+// isKnownLabel reports whether an mcp.* label is understood by the CLI
+func isKnownLabel(label string) bool {
+	if knownLabels[label] {
+		return true
+	}
+	for _, prefix := range knownLabelPrefixes {
+		if strings.HasPrefix(label, prefix) && len(label) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeLine returns the line of the key at path within a mapping node, falling
+// back to the deepest key found (or fallback) when the path doesn't exist
+func nodeLine(node *yaml.Node, fallback int, path ...string) int {
+	line := fallback
+	for _, key := range path {
+		// Entries of list-form environment and labels are KEY=value
+		if node != nil && node.Kind == yaml.SequenceNode {
+			for _, item := range node.Content {
+				if name, _, _ := strings.Cut(item.Value, "="); strings.TrimSpace(name) == key {
+					return item.Line
+				}
+			}
+			break
+		}
+		i := mappingIndex(node, key)
+		if i < 0 {
+			break
+		}
+		line = node.Content[i].Line
+		node = node.Content[i+1]
+	}
+	return line
+}
+
+// yamlLinePattern extracts the line number from yaml.v3 error messages
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// yamlErrorLine returns the line a YAML error refers to, or 1 if unknown
+func yamlErrorLine(err error) int {
+	if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+		var line int
+		fmt.Sscanf(match[1], "%d", &line)
+		return line
+	}
+	return 1
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateComposeData(t *testing.T) {
+	tests := []struct {
+		name     string
+		compose  string
+		tool     string
+		expected []string // "line: message fragment"
+	}{
+		{
+			name: "valid file",
+			compose: `services:
+  time:
+    command: uvx mcp-server-time
+    labels:
+      mcp.profile: default, programming
+      mcp.description: Time server
+  github:
+    image: mcp/github
+    environment:
+      GITHUB_TOKEN: ${GITHUB_TOKEN}
+`,
+		},
+		{
+			name: "missing command and image",
+			compose: `services:
+  broken:
+    environment:
+      FOO: bar
+`,
+			expected: []string{"2: service 'broken': neither command nor image is set"},
+		},
+		{
+			name: "invalid labels",
+			compose: `services:
+  time:
+    command: uvx mcp-server-time
+    labels:
+      mcp.profil: default
+      mcp.profile: "default , ,programming"
+`,
+			expected: []string{
+				"5: service 'time': unknown label 'mcp.profil'",
+				"6: service 'time': invalid mcp.profile label",
+			},
+		},
+		{
+			name: "unresolved variables",
+			compose: `services:
+  github:
+    image: mcp/github
+    environment:
+      GITHUB_TOKEN: ${MISSING_TOKEN}
+`,
+			expected: []string{"5: service 'github': environment variable 'MISSING_TOKEN' is not set"},
+		},
+		{
+			name: "env modes",
+			compose: `services:
+  github:
+    image: mcp/github
+    environment:
+      GITHUB_TOKEN: ${MISSING_TOKEN}
+    labels:
+      mcp.env-mode: passthrough
+  time:
+    command: uvx mcp-server-time
+    labels:
+      mcp.env-mode: literal
+`,
+			expected: []string{"11: service 'time': invalid mcp.env-mode label \"literal\""},
+		},
+		{
+			name: "conflicting auth labels",
+			compose: `services:
+  api:
+    command: https://api.example.com/mcp
+    labels:
+      mcp.grant-type: client_credentials
+      mcp.header.Authorization: Bearer token
+`,
+			expected: []string{"2: remote server 'api' cannot have both OAuth labels and headers labels"},
+		},
+		{
+			name: "duplicate names after normalization",
+			compose: `services:
+  GitHub:
+    image: mcp/github
+  github:
+    image: mcp/github
+`,
+			expected: []string{"4: service 'github': duplicates service 'GitHub' on line 2"},
+		},
+		{
+			name: "remote server with unsupported tool",
+			compose: `services:
+  api:
+    command: https://api.example.com/mcp
+    labels:
+      mcp.header.X-Empty: ""
+`,
+			tool:     "claude-desktop",
+			expected: []string{"2: remote server 'api' is not supported by claude-desktop"},
+		},
+		{
+			name:     "syntax error",
+			compose:  "services:\n  time: [\n",
+			expected: []string{"2: yaml: line 2:"},
+		},
+	}
+
+	envVars := map[string]string{"GITHUB_TOKEN": "token"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := validateComposeData([]byte(tt.compose), envVars, tt.tool)
+
+			var got []string
+			for _, p := range problems {
+				got = append(got, fmt.Sprintf("%d: %s", p.Line, p.Message))
+			}
+
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %d problems, got %d: %q", len(tt.expected), len(got), got)
+			}
+			for i, want := range tt.expected {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("Problem %d: expected prefix %q, got %q", i, want, got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReportProblems(t *testing.T) {
+	var out bytes.Buffer
+	if err := reportProblems(&out, "mcp-compose.yml", nil); err != nil {
+		t.Errorf("Expected no error without problems, got %v", err)
+	}
+
+	out.Reset()
+	err := reportProblems(&out, "mcp-compose.yml", []composeProblem{{Line: 3, Message: "bad"}})
+	if ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected validation exit code, got %d", ExitCode(err))
+	}
+	if out.String() != "mcp-compose.yml:3: bad\n" {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchDebounce is how long the files must be quiet before a re-sync,
+// so an editor's burst of writes triggers only one
+const defaultWatchDebounce = 300 * time.Millisecond
+
+var watchDebounce time.Duration
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch [profile]",
+	Short: "Re-sync a tool config whenever the compose file or .env changes",
+	Long: `Write a tool's MCP configuration like 'mcp set', then keep watching the
+mcp-compose.yml and .env files and rewrite it whenever they change, printing
+the servers added, updated, and removed by each sync.
+Changes are debounced so a burst of saves triggers a single sync. Errors in the
+compose file are reported and watching continues, so it can be fixed in place.
+Stop with Ctrl-C.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		metricsServer, err := startMetricsServer(metricsAddr)
+		if err != nil {
+			return err
+		}
+		if metricsServer != nil {
+			defer metricsServer.Close()
+		}
+
+		resync := func() {
+			if err := syncToolConfig(ctx, os.Stdout, composeFile, profile); err != nil {
+				countError("watch")
+				fmt.Fprintf(os.Stdout, "%s Error: %v\n", time.Now().Format(time.TimeOnly), err)
+			}
+		}
+
+		resync()
+		envPath := filepath.Join(filepath.Dir(composeFile), ".env")
+		paths := append([]string{composeFile}, composeOverrideFiles...)
+		fmt.Printf("Watching %s and %s for changes (Ctrl-C to stop)\n", strings.Join(paths, ", "), envPath)
+		return watchFiles(ctx, append(paths, envPath), watchDebounce, resync)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to write the MCP JSON configuration file")
+	watchCmd.Flags().StringVarP(&toolShortcut, "tool", "t", "", "Tool shortcut (q-cli, q-ide, claude-desktop, cursor, kiro)")
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", defaultWatchDebounce, "How long to wait after a change before syncing")
+	addMetricsFlag(watchCmd)
+	watchCmd.RegisterFlagCompletionFunc("tool", completeToolNames)
+}
+
+// syncToolConfig writes the profile's servers to the tool config like
+// 'mcp set', through the same prepareToolConfig, and prints what changed,
+// skipping the write if nothing did
+func syncToolConfig(ctx context.Context, w io.Writer, composePath, profile string) error {
+	config, err := loadComposeFile(composePath)
+	if err != nil {
+		return newConfigError("load compose file", composePath, err)
+	}
+
+	envVars, err := loadEnvVars(composePath)
+	if err != nil {
+		return newConfigError("load environment variables", composePath, err)
+	}
+
+	outputPath, err := getOutputPath(envVars)
+	if err != nil {
+		return err
+	}
+
+	servers := serversForTool(enabledServers(filterServers(config, profile, false)), toolShortcut)
+	for name, service := range servers {
+		if IsRemoteServerWithEnvExpansion(service, envVars) {
+			if err := ValidateRemoteServerAuth(name, service); err != nil {
+				return &ValidationError{Err: err}
+			}
+		}
+	}
+	if err := ValidateToolSupportWithEnvExpansion(toolShortcut, servers, envVars); err != nil {
+		return &ValidationError{Err: err}
+	}
+
+	mcpConfig, err := convertToMCPConfig(ctx, servers, envVars)
+	if err != nil {
+		return err
+	}
+	prepared, err := prepareToolConfig(mcpConfig, toolShortcut, outputPath, config.Services, envVars, mergeByDefault())
+	if err != nil {
+		return err
+	}
+	printStrippedFields(w, toolShortcut, prepared.Stripped)
+	mcpConfig = prepared.Config
+
+	existing, err := readMCPConfig(outputPath)
+	if err != nil {
+		return newConfigError("load tool config", outputPath, err)
+	}
+	upToDate, err := toolConfigUpToDate(mcpConfig, outputPath, toolShortcut)
+	if err != nil {
+		return newConfigError("load tool config", outputPath, err)
+	}
+
+	now := time.Now().Format(time.TimeOnly)
+	if upToDate {
+		fmt.Fprintf(w, "%s %s is up to date\n", now, outputPath)
+		if err := recordManagedServers(outputPath, mcpConfig, prepared.Unmanaged); err != nil {
+			return newConfigError("save state", "", err)
+		}
+		return nil
+	}
+
+	changes := compareMCPConfigs(existing, mcpConfig)
+	if err := writeToolConfig(mcpConfig, outputPath, toolShortcut); err != nil {
+		return newConfigError("write MCP config", outputPath, err)
+	}
+	if err := recordManagedServers(outputPath, mcpConfig, prepared.Unmanaged); err != nil {
+		return newConfigError("save state", "", err)
+	}
+	metrics.Inc(metricSyncsTotal, map[string]string{"tool": toolShortcut})
+	fmt.Fprintf(w, "%s Synced %s: %d added, %d updated, %d removed\n",
+		now, outputPath, len(changes.Added), len(changes.Updated), len(changes.Removed))
+	printSyncChanges(w, "+", changes.Added)
+	printSyncChanges(w, "~", changes.Updated)
+	printSyncChanges(w, "-", changes.Removed)
+	return nil
+}
+
+// watchFiles calls onChange after any of paths is written, created, removed,
+// or renamed, once no further change has happened for debounce, until ctx ends
+// The directories are watched rather than the files, so editors that save by
+// replacing the file and files that don't exist yet are both picked up.
+func watchFiles(ctx context.Context, paths []string, debounce time.Duration, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]bool)
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		watched[abs] = true
+
+		dir := filepath.Dir(abs)
+		if err := watcher.Add(dir); err != nil {
+			return newConfigError("watch directory", dir, err)
+		}
+	}
+
+	// The timer starts stopped and is reset by every relevant change
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if watched[filepath.Clean(event.Name)] && !event.Has(fsnotify.Chmod) {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("watch files: %w", err)
+		case <-timer.C:
+			onChange()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSyncToolConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalConfig, originalTool := configFile, toolShortcut
+	defer func() { configFile, toolShortcut = originalConfig, originalTool }()
+
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	configFile = filepath.Join(dir, "out", "mcp.json")
+	toolShortcut = ""
+	os.WriteFile(composePath, []byte("services:\n  time:\n    command: uvx mcp-server-time\n"), 0644)
+
+	var out bytes.Buffer
+	if err := syncToolConfig(context.Background(), &out, composePath, ""); err != nil {
+		t.Fatalf("syncToolConfig failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Synced "+configFile+": 1 added, 0 updated, 0 removed\n  + time\n") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	syncToolConfig(context.Background(), &out, composePath, "")
+	if !strings.Contains(out.String(), "is up to date") {
+		t.Errorf("Expected no changes, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	os.WriteFile(composePath, []byte("services:\n  time:\n    command: uvx mcp-server-time --local-timezone UTC\n  fetch:\n    command: uvx mcp-server-fetch\n"), 0644)
+	syncToolConfig(context.Background(), &out, composePath, "")
+	if !strings.Contains(out.String(), "1 added, 1 updated, 0 removed\n  + fetch\n  ~ time\n") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	os.WriteFile(composePath, []byte("services: [\n"), 0644)
+	if err := syncToolConfig(context.Background(), &out, composePath, ""); ExitCode(err) != exitCodeConfig {
+		t.Errorf("Expected config error for a broken compose file, got %v", err)
+	}
+}
+
+func TestSyncToolConfigVSCodeFormat(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalConfig, originalTool := configFile, toolShortcut
+	defer func() { configFile, toolShortcut = originalConfig, originalTool }()
+
+	path := setupVSCodeTool(t, map[string]MCPServer{"manual": {Command: "node"}})
+	configFile, toolShortcut = "", "vscode"
+	saveCLIConfig(CLIConfig{Merge: true, Tools: map[string]CustomTool{
+		"vscode": {Path: path, Format: "vscode", SupportsRemote: true},
+	}})
+
+	composePath := filepath.Join(t.TempDir(), "mcp-compose.yml")
+	os.WriteFile(composePath, []byte(`services:
+  example:
+    image: example/mcp
+    environment:
+      API_KEY: ${API_KEY}
+    labels:
+      mcp.input.API_KEY: Example API key
+`), 0644)
+	t.Setenv("API_KEY", "secret")
+
+	var out bytes.Buffer
+	if err := syncToolConfig(context.Background(), &out, composePath, ""); err != nil {
+		t.Fatalf("syncToolConfig failed: %v", err)
+	}
+	servers := readVSCodeServers(t, path)
+	if _, ok := servers["manual"]; !ok {
+		t.Errorf("Expected the unmanaged server to be kept when merging, got %v", servers)
+	}
+	if env := servers["example"].Env["API_KEY"]; env != "${input:api-key}" {
+		t.Errorf("Expected API_KEY to become an input, got %q", env)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"type": "stdio"`) || !strings.Contains(string(data), `"id": "api-key"`) {
+		t.Errorf("Expected the VS Code layout with inputs, got:\n%s", data)
+	}
+}
+
+func TestWatchMetrics(t *testing.T) {
+	if watchCmd.Flags().Lookup("metrics-addr") == nil {
+		t.Fatal("Expected watch to have a --metrics-addr flag")
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	originalConfig, originalTool := configFile, toolShortcut
+	defer func() { configFile, toolShortcut = originalConfig, originalTool }()
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	configFile, toolShortcut = filepath.Join(dir, "mcp.json"), ""
+	os.WriteFile(composePath, []byte("services:\n  time:\n    command: uvx mcp-server-time\n"), 0644)
+
+	labels := map[string]string{"tool": ""}
+	before := metrics.Value(metricSyncsTotal, labels)
+	syncToolConfig(context.Background(), &bytes.Buffer{}, composePath, "")
+	syncToolConfig(context.Background(), &bytes.Buffer{}, composePath, "")
+	if got := metrics.Value(metricSyncsTotal, labels); got != before+1 {
+		t.Errorf("Expected only the sync that wrote the config to be counted, got %v more", got-before)
+	}
+}
+
+func TestWatchFilesDebounces(t *testing.T) {
+	dir := t.TempDir()
+	composePath := filepath.Join(dir, "mcp-compose.yml")
+	envPath := filepath.Join(dir, ".env")
+	os.WriteFile(composePath, []byte("services: {}\n"), 0644)
+
+	var calls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- watchFiles(ctx, []string{composePath, envPath}, 100*time.Millisecond, func() { calls.Add(1) })
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// Unrelated files in the same directory are ignored
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644)
+	time.Sleep(200 * time.Millisecond)
+	if n := calls.Load(); n != 0 {
+		t.Errorf("Expected no sync for unrelated files, got %d", n)
+	}
+
+	// A burst of saves, including a file that didn't exist, triggers one sync
+	os.WriteFile(composePath, []byte("services:\n"), 0644)
+	os.WriteFile(envPath, []byte("A=1\n"), 0644)
+	os.WriteFile(composePath, []byte("services: {}\n"), 0644)
+	time.Sleep(400 * time.Millisecond)
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected 1 sync after a burst of changes, got %d", n)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("watchFiles failed: %v", err)
+	}
+}
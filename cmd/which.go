@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var whichPath bool
+
+// whichCmd represents the which command
+var whichCmd = &cobra.Command{
+	Use:   "which [tool]",
+	Short: "Show where tool configs are written",
+	Long: `Show the config path of one tool, or of every built-in and custom tool, on this
+platform: its user-level and project-level (in the current directory) paths,
+whether each file exists, and which one the --scope flag selects (marked *).
+Use --path with a tool to print only the selected path, for scripts.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeToolNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tools := getAllTools()
+		if len(args) > 0 {
+			tools = args
+		} else if whichPath {
+			return newValidationError("--path requires a tool")
+		}
+
+		locations, err := toolLocations(tools)
+		if err != nil {
+			return err
+		}
+
+		if whichPath {
+			for _, location := range locations {
+				if location.Selected {
+					fmt.Println(location.Path)
+					return nil
+				}
+			}
+			return newValidationError("tool '%s' does not support %s-scoped configuration", args[0], configScope)
+		}
+
+		displayToolLocations(os.Stdout, locations)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+	whichCmd.Flags().BoolVar(&whichPath, "path", false, "Print only the path the selected scope uses")
+}
+
+// toolLocation is where one tool keeps its config in one scope
+type toolLocation struct {
+	Tool     string
+	Scope    string
+	Path     string
+	Exists   bool
+	Selected bool // the location --scope selects
+}
+
+// toolLocations resolves the user and project config paths of each tool
+func toolLocations(tools []string) ([]toolLocation, error) {
+	var locations []toolLocation
+	for _, tool := range tools {
+		path, err := getPlatformToolPath(tool)
+		if err != nil {
+			return nil, newConfigError("resolve config path for "+tool, "", err)
+		}
+		if path == "" {
+			return nil, newValidationError("unknown tool shortcut: %s", tool)
+		}
+
+		// q-ide is already workspace-scoped, so both scopes use the same file
+		if tool == "q-ide" {
+			locations = append(locations, toolLocation{Tool: tool, Scope: "workspace", Path: path, Exists: fileExists(path), Selected: true})
+			continue
+		}
+		locations = append(locations, toolLocation{Tool: tool, Scope: scopeUser, Path: path, Exists: fileExists(path), Selected: configScope == scopeUser})
+
+		if supportsProjectScope(tool) {
+			path, err := getScopedToolPath(tool, scopeProject)
+			if err != nil {
+				return nil, newConfigError("resolve config path for "+tool, "", err)
+			}
+			locations = append(locations, toolLocation{Tool: tool, Scope: scopeProject, Path: path, Exists: fileExists(path), Selected: configScope == scopeProject})
+		}
+	}
+	return locations, nil
+}
+
+// displayToolLocations prints the locations as a table
+func displayToolLocations(w io.Writer, locations []toolLocation) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOOL\tSCOPE\tEXISTS\tPATH")
+	fmt.Fprintln(tw, "----\t-----\t------\t----")
+	for _, location := range locations {
+		scope := location.Scope
+		if location.Selected {
+			scope += "*"
+		}
+		exists := "no"
+		if location.Exists {
+			exists = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", location.Tool, scope, exists, location.Path)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n* used by 'mcp set' with --scope %s\n", configScope)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToolLocations(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	originalScope := configScope
+	defer func() { configScope = originalScope }()
+
+	kiroPath := filepath.Join(home, ".kiro", "settings", "mcp.json")
+	os.MkdirAll(filepath.Dir(kiroPath), 0755)
+	os.WriteFile(kiroPath, []byte(`{"mcpServers":{}}`), 0644)
+
+	configScope = scopeUser
+	locations, err := toolLocations([]string{"kiro", "claude-desktop", "q-ide"})
+	if err != nil {
+		t.Fatalf("toolLocations failed: %v", err)
+	}
+	if len(locations) != 4 {
+		t.Fatalf("Expected user and project locations for kiro, and one each for claude-desktop and q-ide, got %+v", locations)
+	}
+	if got := locations[0]; got.Path != kiroPath || !got.Exists || !got.Selected {
+		t.Errorf("Unexpected kiro user location: %+v", got)
+	}
+	if got := locations[1]; got.Scope != scopeProject || got.Exists || got.Selected {
+		t.Errorf("Unexpected kiro project location: %+v", got)
+	}
+	if got := locations[3]; got.Scope != "workspace" || !got.Selected {
+		t.Errorf("Unexpected q-ide location: %+v", got)
+	}
+
+	var out bytes.Buffer
+	displayToolLocations(&out, locations)
+	if !strings.Contains(out.String(), "kiro            user*       yes     "+kiroPath) {
+		t.Errorf("Unexpected table:\n%s", out.String())
+	}
+
+	configScope = scopeProject
+	locations, _ = toolLocations([]string{"kiro"})
+	if locations[0].Selected || !locations[1].Selected {
+		t.Errorf("Expected --scope project to select the project location, got %+v", locations)
+	}
+
+	if _, err := toolLocations([]string{"nope"}); ExitCode(err) != exitCodeValidation {
+		t.Errorf("Expected a validation error for an unknown tool, got %v", err)
+	}
+}
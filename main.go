@@ -1,31 +1,45 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"mcp/cmd"
 )
 
+// shutdownGracePeriod is how long in-flight work has to stop after a signal
+const shutdownGracePeriod = 3 * time.Second
+
 func main() {
-	//exit process immediately upon sigterm
-	handleSigTerms()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//cancel in-flight work upon sigterm, exit if it doesn't stop promptly
+	handleSigTerms(cancel)
 
 	//run
-	if err := cmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "%s\n", err)
-		os.Exit(1)
+	if err := cmd.Execute(ctx); err != nil {
+		os.Exit(cmd.ExitCode(err))
 	}
 }
 
-func handleSigTerms() {
+func handleSigTerms(cancel context.CancelFunc) {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
-		fmt.Println("received SIGTERM, exiting")
+		fmt.Fprintln(os.Stderr, "received SIGTERM, exiting")
+		cancel()
+
+		// exit on a second signal or once the grace period is over
+		select {
+		case <-c:
+		case <-time.After(shutdownGracePeriod):
+		}
 		os.Exit(1)
 	}()
 }
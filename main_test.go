@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"testing"
 	"time"
 )
@@ -11,7 +12,9 @@ func TestHandleSigTerms(t *testing.T) {
 	// We'll test that the function doesn't panic when called
 
 	// Call handleSigTerms - it should start a goroutine and return immediately
-	handleSigTerms()
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handleSigTerms(cancel)
 
 	// Give the goroutine a moment to start
 	time.Sleep(10 * time.Millisecond)
@@ -25,5 +28,7 @@ func TestMain(t *testing.T) {
 	// We can't easily test the main function directly because it calls os.Exit
 	// and cmd.Execute() which would require complex mocking
 	// Instead, we'll test that we can call handleSigTerms without panic
-	handleSigTerms()
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handleSigTerms(cancel)
 }